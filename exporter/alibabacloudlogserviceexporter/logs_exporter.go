@@ -0,0 +1,61 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+// newLogsExporter return a new LogSerice logs exporter.
+func newLogsExporter(logger *zap.Logger, cfg configmodels.Exporter) (component.LogsExporter, error) {
+
+	l := &logServiceLogsSender{
+		logger: logger,
+	}
+
+	var err error
+	if l.client, err = NewLogServiceClient(cfg.(*Config), logger); err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		l.pushLogsData)
+}
+
+type logServiceLogsSender struct {
+	logger *zap.Logger
+	client LogServiceClient
+}
+
+func (s *logServiceLogsSender) pushLogsData(
+	_ context.Context,
+	ld pdata.Logs,
+) (int, error) {
+	logs := logDataToLogServiceData(ld)
+	if len(logs) == 0 {
+		return 0, nil
+	}
+	if err := s.client.SendLogs(logs); err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	slsLogTimeUnixNano   = "timeUnixNano"
+	slsLogSeverityNumber = "severityNumber"
+	slsLogSeverityText   = "severityText"
+	slsLogName           = "name"
+	slsLogContent        = "content"
+	slsLogAttribute      = "attribute"
+	slsLogFlags          = "flags"
+	slsLogResource       = "resource"
+	slsLogHost           = "host"
+	slsLogService        = "service"
+	// shortcut for "otlp.instrumentation.library.name" "otlp.instrumentation.library.version"
+	slsLogInstrumentationName    = "otlp.name"
+	slsLogInstrumentationVersion = "otlp.version"
+)
+
+// logDataToLogServiceData translates log data into the LogService format, every
+// LogRecord is converted to one Log.
+func logDataToLogServiceData(ld pdata.Logs) []*sls.Log {
+	var logs []*sls.Log
+	resourceLogsSlice := ld.ResourceLogs()
+	for i := 0; i < resourceLogsSlice.Len(); i++ {
+		resourceLogs := resourceLogsSlice.At(i)
+		if resourceLogs.IsNil() {
+			continue
+		}
+		resourceContents := resourceToLogServiceContents(resourceLogs.Resource())
+		instrumentationLibraryLogsSlice := resourceLogs.InstrumentationLibraryLogs()
+		for j := 0; j < instrumentationLibraryLogsSlice.Len(); j++ {
+			instrumentationLibraryLogs := instrumentationLibraryLogsSlice.At(j)
+			if instrumentationLibraryLogs.IsNil() {
+				continue
+			}
+			libraryContents := instrumentationLibraryToLogServiceContents(instrumentationLibraryLogs.InstrumentationLibrary())
+			records := instrumentationLibraryLogs.Logs()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				if record.IsNil() {
+					continue
+				}
+				log := logRecordToLogServiceData(record)
+				log.Contents = append(log.Contents, libraryContents...)
+				log.Contents = append(log.Contents, resourceContents...)
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs
+}
+
+// resourceToLogServiceContents reports the host and service names in their own
+// fields and the other resource attributes as a JSON object.
+func resourceToLogServiceContents(resource pdata.Resource) []*sls.LogContent {
+	var hostName, serviceName string
+	fields := map[string]interface{}{}
+	if !resource.IsNil() {
+		resource.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+			switch key {
+			case conventions.AttributeHostHostname:
+				hostName = value.StringVal()
+			case conventions.AttributeServiceName:
+				serviceName = value.StringVal()
+			default:
+				fields[key] = tracetranslator.AttributeValueToString(value, false)
+			}
+		})
+	}
+	attributeBuffer, _ := json.Marshal(fields)
+
+	return []*sls.LogContent{
+		{
+			Key:   proto.String(slsLogHost),
+			Value: proto.String(hostName),
+		},
+		{
+			Key:   proto.String(slsLogService),
+			Value: proto.String(serviceName),
+		},
+		{
+			Key:   proto.String(slsLogResource),
+			Value: proto.String(string(attributeBuffer)),
+		},
+	}
+}
+
+func instrumentationLibraryToLogServiceContents(library pdata.InstrumentationLibrary) []*sls.LogContent {
+	var name, version string
+	if !library.IsNil() {
+		name = library.Name()
+		version = library.Version()
+	}
+	return []*sls.LogContent{
+		{
+			Key:   proto.String(slsLogInstrumentationName),
+			Value: proto.String(name),
+		},
+		{
+			Key:   proto.String(slsLogInstrumentationVersion),
+			Value: proto.String(version),
+		},
+	}
+}
+
+func logRecordToLogServiceData(record pdata.LogRecord) *sls.Log {
+	fields := map[string]interface{}{}
+	record.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+		fields[key] = tracetranslator.AttributeValueToString(value, false)
+	})
+	attributeBuffer, _ := json.Marshal(fields)
+
+	content := ""
+	if body := record.Body(); !body.IsNil() {
+		content = tracetranslator.AttributeValueToString(body, false)
+	}
+
+	contents := []*sls.LogContent{
+		{
+			Key:   proto.String(slsLogTimeUnixNano),
+			Value: proto.String(strconv.FormatUint(uint64(record.Timestamp()), 10)),
+		},
+		{
+			Key:   proto.String(slsLogSeverityNumber),
+			Value: proto.String(strconv.FormatInt(int64(record.SeverityNumber()), 10)),
+		},
+		{
+			Key:   proto.String(slsLogSeverityText),
+			Value: proto.String(record.SeverityText()),
+		},
+		{
+			Key:   proto.String(slsLogName),
+			Value: proto.String(record.Name()),
+		},
+		{
+			Key:   proto.String(slsLogContent),
+			Value: proto.String(content),
+		},
+		{
+			Key:   proto.String(slsLogAttribute),
+			Value: proto.String(string(attributeBuffer)),
+		},
+		{
+			Key:   proto.String(slsLogFlags),
+			Value: proto.String(strconv.FormatUint(uint64(record.Flags()), 16)),
+		},
+		{
+			Key:   proto.String(traceIDField),
+			Value: proto.String(record.TraceID().HexString()),
+		},
+		{
+			Key:   proto.String(spanIDField),
+			Value: proto.String(record.SpanID().HexString()),
+		},
+	}
+
+	// Log Service requires a __time__, records without a timestamp are stored at
+	// the time they are exported.
+	logTime := uint32(record.Timestamp() / 1e9)
+	if record.Timestamp() == 0 {
+		logTime = uint32(time.Now().Unix())
+	}
+	return &sls.Log{
+		Time:     proto.Uint32(logTime),
+		Contents: contents,
+	}
+}
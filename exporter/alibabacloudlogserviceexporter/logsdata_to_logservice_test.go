@@ -0,0 +1,111 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"testing"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestLogsDataToLogService(t *testing.T) {
+	ts := time.Unix(1574092046, int64(11*time.Millisecond))
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, "signup_aggregator")
+	rl.Resource().Attributes().InsertString(conventions.AttributeHostHostname, "xxx.et15")
+	rl.Resource().Attributes().InsertString(conventions.AttributeContainerName, "signup_aggregator")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	ill := rl.InstrumentationLibraryLogs().At(0)
+	ill.InstrumentationLibrary().InitEmpty()
+	ill.InstrumentationLibrary().SetName("collector")
+	ill.InstrumentationLibrary().SetVersion("v0.1.0")
+	ill.Logs().Resize(2)
+
+	record := ill.Logs().At(0)
+	record.SetTimestamp(pdata.TimestampUnixNano(ts.UnixNano()))
+	record.SetName("login")
+	record.SetSeverityNumber(pdata.SeverityNumberINFO)
+	record.SetSeverityText("Info")
+	record.Body().SetStringVal("user logged in")
+	record.Attributes().InsertString("user", "alice")
+	record.Attributes().InsertInt("attempts", 2)
+	record.SetTraceID(pdata.NewTraceID([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}))
+	record.SetSpanID(pdata.NewSpanID([]byte{0, 1, 2, 3, 4, 5, 6, 7}))
+	record.SetFlags(1)
+
+	// A record without timestamp nor body.
+	ill.Logs().At(1).SetName("empty")
+
+	logs := logDataToLogServiceData(ld)
+	require.Len(t, logs, 2)
+
+	assert.Equal(t, uint32(1574092046), logs[0].GetTime())
+	assert.Equal(t, map[string]string{
+		"timeUnixNano":   "1574092046011000000",
+		"severityNumber": "9",
+		"severityText":   "Info",
+		"name":           "login",
+		"content":        "user logged in",
+		"attribute":      `{"attempts":"2","user":"alice"}`,
+		"flags":          "1",
+		"traceID":        "000102030405060708090a0b0c0d0e0f",
+		"spanID":         "0001020304050607",
+		"otlp.name":      "collector",
+		"otlp.version":   "v0.1.0",
+		"host":           "xxx.et15",
+		"service":        "signup_aggregator",
+		"resource":       `{"container.name":"signup_aggregator"}`,
+	}, logContentsToMap(logs[0]))
+
+	assert.NotZero(t, logs[1].GetTime())
+	empty := logContentsToMap(logs[1])
+	assert.Equal(t, "empty", empty["name"])
+	assert.Equal(t, "", empty["content"])
+	assert.Equal(t, "{}", empty["attribute"])
+}
+
+func TestLogsDataToLogServiceWithoutResource(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+
+	logs := logDataToLogServiceData(ld)
+	require.Len(t, logs, 1)
+	contents := logContentsToMap(logs[0])
+	assert.Equal(t, "", contents["host"])
+	assert.Equal(t, "", contents["service"])
+	assert.Equal(t, "{}", contents["resource"])
+	assert.Equal(t, "", contents["otlp.name"])
+
+	assert.Nil(t, logDataToLogServiceData(pdata.NewLogs()))
+}
+
+func logContentsToMap(log *sls.Log) map[string]string {
+	contents := make(map[string]string, len(log.Contents))
+	for _, content := range log.Contents {
+		contents[content.GetKey()] = content.GetValue()
+	}
+	return contents
+}
@@ -18,11 +18,9 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 )
 
@@ -52,17 +50,13 @@ func (s *logServiceMetricsSender) pushMetricsData(
 	_ context.Context,
 	md pdata.Metrics,
 ) (int, error) {
-	ocmds := internaldata.MetricsToOC(md)
-	droppedTimeSeries := 0
-	var errs []error
-	for _, ocmd := range ocmds {
-		logs, dts := metricsDataToLogServiceData(s.logger, ocmd)
-		if len(logs) > 0 {
-			if err := s.client.SendLogs(logs); err != nil {
-				errs = append(errs, err)
-			}
-		}
-		droppedTimeSeries += dts
+	logs, droppedTimeSeries := metricsDataToLogServiceData(s.logger, md)
+	if len(logs) == 0 {
+		return droppedTimeSeries, nil
 	}
-	return droppedTimeSeries, componenterror.CombineErrors(errs)
+	if err := s.client.SendLogs(logs); err != nil {
+		_, numPoints := md.MetricAndDataPointCount()
+		return numPoints, err
+	}
+	return droppedTimeSeries, nil
 }
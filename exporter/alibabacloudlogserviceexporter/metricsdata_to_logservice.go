@@ -15,30 +15,25 @@
 package alibabacloudlogserviceexporter
 
 import (
-	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 
 	sls "github.com/aliyun/aliyun-log-go-sdk"
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/gogo/protobuf/proto"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"go.uber.org/zap"
 )
 
 const (
-	hostnameKey     = "hostname"
-	pidKey          = "pid"
-	resourceTypeKey = "resource_type"
-	metricNameKey   = "__name__"
-	labelsKey       = "__labels__"
-	timeNanoKey     = "__time_nano__"
-	valueKey        = "__value__"
+	metricNameKey = "__name__"
+	labelsKey     = "__labels__"
+	timeNanoKey   = "__time_nano__"
+	valueKey      = "__value__"
 	// same with : https://github.com/prometheus/common/blob/b5fe7d854c42dc7842e48d1ca58f60feae09d77b/expfmt/text_create.go#L445
 	infinityBoundValue = "+Inf"
 	bucketLabelKey     = "le"
-	summaryLabelKey    = "quantile"
 )
 
 type KeyValue struct {
@@ -57,15 +52,6 @@ func (kv *KeyValues) Swap(i, j int) {
 func (kv *KeyValues) Less(i, j int) bool { return kv.keyValues[i].Key < kv.keyValues[j].Key }
 func (kv *KeyValues) Sort()              { sort.Sort(kv) }
 
-func (kv *KeyValues) Replace(key, value string) {
-	findIndex := sort.Search(len(kv.keyValues), func(index int) bool {
-		return kv.keyValues[index].Key >= key
-	})
-	if findIndex < len(kv.keyValues) && kv.keyValues[findIndex].Key == key {
-		kv.keyValues[findIndex].Value = value
-	}
-}
-
 func (kv *KeyValues) AppendMap(mapVal map[string]string) {
 	for key, value := range mapVal {
 		kv.keyValues = append(kv.keyValues, KeyValue{
@@ -106,14 +92,32 @@ func (kv *KeyValues) labelToStringBuilder(sb *strings.Builder) {
 	}
 }
 
-func newMetricLog(nsec int64,
-	nameContent *sls.LogContent,
-	labelContent *sls.LogContent,
-	timeNanoContent *sls.LogContent,
-	valueContent *sls.LogContent) *sls.Log {
+func newMetricLogFromRaw(
+	name string,
+	labels KeyValues,
+	nsec int64,
+	value string) *sls.Log {
+	labels.Sort()
 	return &sls.Log{
-		Time:     proto.Uint32(uint32(nsec / 1e9)),
-		Contents: []*sls.LogContent{nameContent, labelContent, timeNanoContent, valueContent},
+		Time: proto.Uint32(uint32(nsec / 1e9)),
+		Contents: []*sls.LogContent{
+			{
+				Key:   proto.String(metricNameKey),
+				Value: proto.String(name),
+			},
+			{
+				Key:   proto.String(labelsKey),
+				Value: proto.String(labels.String()),
+			},
+			{
+				Key:   proto.String(timeNanoKey),
+				Value: proto.String(strconv.FormatInt(nsec, 10)),
+			},
+			{
+				Key:   proto.String(valueKey),
+				Value: proto.String(value),
+			},
+		},
 	}
 }
 
@@ -124,300 +128,197 @@ func min(l, r int) int {
 	return r
 }
 
-func appendDistributionValues(
-	logs []*sls.Log,
-	labels KeyValues,
-	nsec int64,
-	nameContent *sls.LogContent,
-	labelContent *sls.LogContent,
-	timeNanoContent *sls.LogContent,
-	distributionValue *metricspb.DistributionValue,
-) ([]*sls.Log, error) {
-
-	// Translating distribution values per symmetrical recommendations to Prometheus:
+func resourceToMetricLabels(labels *KeyValues, resource pdata.Resource) {
+	if resource.IsNil() {
+		return
+	}
+	resource.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+		labels.Append(key, tracetranslator.AttributeValueToString(value, false))
+	})
+}
 
-	// 1. The total count gets converted to a cumulative counter called
-	// <basename>_count.
-	// 2. The total sum gets converted to a cumulative counter called <basename>_sum
-	count := distributionValue.GetCount()
-	sum := distributionValue.GetSum()
-	logs = appendTotalAndSum(
-		logs,
-		nsec,
-		nameContent,
-		labelContent,
-		timeNanoContent,
-		count,
-		sum)
+func pointLabels(defaultLabels KeyValues, labelsMap pdata.StringMap) KeyValues {
+	labels := defaultLabels.Clone()
+	labelsMap.ForEach(func(key string, value pdata.StringValue) {
+		labels.Append(key, value.Value())
+	})
+	return labels
+}
 
-	// 3. Each histogram bucket is converted to a cumulative counter called
-	// <basename>_bucket and will include a dimension called upper_bound that
-	// specifies the maximum value in that bucket. This metric specifies the
-	// number of events with a value that is less than or equal to the upper
-	// bound.
-	metricNameContent := &sls.LogContent{
-		Key:   proto.String(metricNameKey),
-		Value: proto.String(nameContent.GetValue() + "_bucket"),
-	}
-	explicitBuckets := distributionValue.BucketOptions.GetExplicit()
-	if explicitBuckets == nil {
-		return logs, fmt.Errorf("unknown bucket options type for metric %s", nameContent.GetValue())
-	}
-	bounds := explicitBuckets.Bounds
-	boundsStr := make([]string, len(bounds)+1)
-	for i := 0; i < len(bounds); i++ {
-		boundsStr[i] = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+func intMetricsToLogs(name string, data pdata.IntDataPointSlice, defaultLabels KeyValues) (logs []*sls.Log, dropped int) {
+	for i := 0; i < data.Len(); i++ {
+		dataPoint := data.At(i)
+		if dataPoint.IsNil() || dataPoint.Timestamp() == 0 {
+			dropped++
+			continue
+		}
+		logs = append(logs,
+			newMetricLogFromRaw(name,
+				pointLabels(defaultLabels, dataPoint.LabelsMap()),
+				int64(dataPoint.Timestamp()),
+				strconv.FormatInt(dataPoint.Value(), 10)))
 	}
-	boundsStr[len(boundsStr)-1] = infinityBoundValue
+	return logs, dropped
+}
 
-	bucketCount := min(len(boundsStr), len(distributionValue.Buckets))
+func doubleMetricsToLogs(name string, data pdata.DoubleDataPointSlice, defaultLabels KeyValues) (logs []*sls.Log, dropped int) {
+	for i := 0; i < data.Len(); i++ {
+		dataPoint := data.At(i)
+		if dataPoint.IsNil() || dataPoint.Timestamp() == 0 {
+			dropped++
+			continue
+		}
+		logs = append(logs,
+			newMetricLogFromRaw(name,
+				pointLabels(defaultLabels, dataPoint.LabelsMap()),
+				int64(dataPoint.Timestamp()),
+				strconv.FormatFloat(dataPoint.Value(), 'g', -1, 64)))
+	}
+	return logs, dropped
+}
 
-	bucketLabels := labels.Clone()
-	bucketLabels.Append(bucketLabelKey, "")
-	bucketLabels.Sort()
-	for i := 0; i < bucketCount; i++ {
-		bucket := distributionValue.Buckets[i]
-		bucketLabels.Replace(bucketLabelKey, boundsStr[i])
+func intHistogramMetricsToLogs(name string, data pdata.IntHistogramDataPointSlice, defaultLabels KeyValues) (logs []*sls.Log, dropped int) {
+	for i := 0; i < data.Len(); i++ {
+		dataPoint := data.At(i)
+		if dataPoint.IsNil() || dataPoint.Timestamp() == 0 {
+			dropped++
+			continue
+		}
+		logs = appendHistogramValues(logs,
+			name,
+			pointLabels(defaultLabels, dataPoint.LabelsMap()),
+			int64(dataPoint.Timestamp()),
+			dataPoint.Count(),
+			strconv.FormatInt(dataPoint.Sum(), 10),
+			dataPoint.ExplicitBounds(),
+			dataPoint.BucketCounts())
+	}
+	return logs, dropped
+}
 
-		logs = append(
-			logs,
-			newMetricLog(nsec,
-				metricNameContent,
-				&sls.LogContent{
-					Key:   proto.String(labelsKey),
-					Value: proto.String(bucketLabels.String()),
-				},
-				timeNanoContent,
-				&sls.LogContent{
-					Key:   proto.String(valueKey),
-					Value: proto.String(strconv.FormatInt(bucket.Count, 10)),
-				},
-			))
+func doubleHistogramMetricsToLogs(name string, data pdata.DoubleHistogramDataPointSlice, defaultLabels KeyValues) (logs []*sls.Log, dropped int) {
+	for i := 0; i < data.Len(); i++ {
+		dataPoint := data.At(i)
+		if dataPoint.IsNil() || dataPoint.Timestamp() == 0 {
+			dropped++
+			continue
+		}
+		logs = appendHistogramValues(logs,
+			name,
+			pointLabels(defaultLabels, dataPoint.LabelsMap()),
+			int64(dataPoint.Timestamp()),
+			dataPoint.Count(),
+			strconv.FormatFloat(dataPoint.Sum(), 'g', -1, 64),
+			dataPoint.ExplicitBounds(),
+			dataPoint.BucketCounts())
 	}
-	return logs, nil
+	return logs, dropped
 }
 
-func appendSummaryValues(
+func appendHistogramValues(
 	logs []*sls.Log,
+	name string,
 	labels KeyValues,
 	nsec int64,
-	nameContent *sls.LogContent,
-	labelContent *sls.LogContent,
-	timeNanoContent *sls.LogContent,
-	summaryValue *metricspb.SummaryValue,
-) ([]*sls.Log, error) {
+	count uint64,
+	sum string,
+	bounds []float64,
+	bucketCounts []uint64,
+) []*sls.Log {
 
-	// Translating summary values per symmetrical recommendations to Prometheus:
+	// Translating histogram values per symmetrical recommendations to Prometheus:
 
 	// 1. The total count gets converted to a cumulative counter called
 	// <basename>_count.
 	// 2. The total sum gets converted to a cumulative counter called <basename>_sum
-	count := summaryValue.GetCount().GetValue()
-	sum := summaryValue.GetSum().GetValue()
-	logs = appendTotalAndSum(
-		logs,
-		nsec,
-		nameContent,
-		labelContent,
-		timeNanoContent,
-		count,
-		sum)
+	logs = append(logs,
+		newMetricLogFromRaw(name+"_count", labels.Clone(), nsec, strconv.FormatUint(count, 10)),
+		newMetricLogFromRaw(name+"_sum", labels.Clone(), nsec, sum))
 
-	// 3. Each quantile value is converted to a gauge called <basename>
-	// and will include a dimension called quantile that specifies the quantile.
-	percentiles := summaryValue.GetSnapshot().GetPercentileValues()
-	if percentiles == nil {
-		return logs, fmt.Errorf(
-			"unknown percentiles values for summary metric %q",
-			nameContent.GetValue())
+	// 3. Each histogram bucket is converted to a cumulative counter called
+	// <basename>_bucket and will include a dimension called le that
+	// specifies the maximum value in that bucket. This metric specifies the
+	// number of events with a value that is less than or equal to the upper
+	// bound, so the bucket counts are accumulated.
+	boundsStr := make([]string, len(bounds)+1)
+	for i := 0; i < len(bounds); i++ {
+		boundsStr[i] = strconv.FormatFloat(bounds[i], 'g', -1, 64)
 	}
-	// Adding the "quantile" dimension.
-	bucketLabels := labels.Clone()
-	bucketLabels.Append(summaryLabelKey, "")
-	bucketLabels.Sort()
-
-	for _, quantile := range percentiles {
-
-		bucketLabels.Replace(summaryLabelKey, strconv.FormatFloat(quantile.Percentile, 'g', -1, 64))
+	boundsStr[len(boundsStr)-1] = infinityBoundValue
 
-		logs = append(
-			logs,
-			newMetricLog(nsec,
-				nameContent,
-				&sls.LogContent{
-					Key:   proto.String(labelsKey),
-					Value: proto.String(bucketLabels.String()),
-				},
-				timeNanoContent,
-				&sls.LogContent{
-					Key:   proto.String(valueKey),
-					Value: proto.String(strconv.FormatFloat(quantile.Value, 'g', -1, 64)),
-				},
-			))
+	bucketCount := min(len(boundsStr), len(bucketCounts))
+	var cumulativeCount uint64
+	for i := 0; i < bucketCount; i++ {
+		cumulativeCount += bucketCounts[i]
+		bucketLabels := labels.Clone()
+		bucketLabels.Append(bucketLabelKey, boundsStr[i])
+		logs = append(logs,
+			newMetricLogFromRaw(name+"_bucket", bucketLabels, nsec, strconv.FormatUint(cumulativeCount, 10)))
 	}
-
-	return logs, nil
-}
-
-func appendTotalAndSum(
-	logs []*sls.Log,
-	nsec int64,
-	nameContent *sls.LogContent,
-	labelContent *sls.LogContent,
-	timeNanoContent *sls.LogContent,
-	count int64,
-	sum float64,
-) []*sls.Log {
-
-	logs = append(
-		logs,
-		newMetricLog(
-			nsec,
-			&sls.LogContent{
-				Key:   proto.String(metricNameKey),
-				Value: proto.String(nameContent.GetValue() + "_count"),
-			},
-			labelContent,
-			timeNanoContent,
-			&sls.LogContent{
-				Key:   proto.String(valueKey),
-				Value: proto.String(strconv.FormatInt(count, 10)),
-			}),
-		newMetricLog(
-			nsec,
-			&sls.LogContent{
-				Key:   proto.String(metricNameKey),
-				Value: proto.String(nameContent.GetValue() + "_sum"),
-			},
-			labelContent,
-			timeNanoContent,
-			&sls.LogContent{
-				Key:   proto.String(valueKey),
-				Value: proto.String(strconv.FormatFloat(sum, 'g', -1, 64)),
-			}))
-
 	return logs
 }
 
 func metricsDataToLogServiceData(
 	logger *zap.Logger,
-	md consumerdata.MetricsData,
+	md pdata.Metrics,
 ) (logs []*sls.Log, numDroppedTimeSeries int) {
-
-	var defaultLabels KeyValues
-	var err error
-	// Labels from Node and Resource.
-	// TODO: Options to add lib, service name, etc as dimensions?
-	//  Q.: what about resource type?
-
-	if hostname := md.Node.GetIdentifier().GetHostName(); hostname != "" {
-		defaultLabels.Append(hostnameKey, hostname)
-	}
-	if pid := int(md.Node.GetIdentifier().GetPid()); pid != 0 {
-		defaultLabels.Append(pidKey, strconv.Itoa(pid))
-	}
-	defaultLabels.AppendMap(md.Node.GetAttributes())
-
-	if resType := md.Resource.GetType(); resType != "" {
-		defaultLabels.Append(resourceTypeKey, resType)
-	}
-	defaultLabels.AppendMap(md.Resource.GetLabels())
-
-	for _, metric := range md.Metrics {
-		if metric == nil || metric.MetricDescriptor == nil {
-			logger.Warn("Received nil metrics data or nil descriptor for metrics")
-			numDroppedTimeSeries += len(metric.GetTimeseries())
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		if resourceMetrics.IsNil() {
 			continue
 		}
 
-		// Build the fixed parts for this metrics from the descriptor.
-		descriptor := metric.MetricDescriptor
-		metricNameContent := &sls.LogContent{
-			Key:   proto.String(metricNameKey),
-			Value: proto.String(descriptor.Name),
-		}
-
-		for _, series := range metric.Timeseries {
-			labels := defaultLabels.Clone()
-			labelCount := min(len(descriptor.LabelKeys), len(series.LabelValues))
-			for i := 0; i < labelCount; i++ {
-				labels.Append(descriptor.LabelKeys[i].GetKey(), series.LabelValues[i].GetValue())
-			}
-			labels.Sort()
+		// Labels from the Resource.
+		var defaultLabels KeyValues
+		resourceToMetricLabels(&defaultLabels, resourceMetrics.Resource())
 
-			labelsContent := &sls.LogContent{
-				Key:   proto.String(labelsKey),
-				Value: proto.String(labels.String()),
+		instrumentationLibraryMetricsSlice := resourceMetrics.InstrumentationLibraryMetrics()
+		for j := 0; j < instrumentationLibraryMetricsSlice.Len(); j++ {
+			instrumentationLibraryMetrics := instrumentationLibraryMetricsSlice.At(j)
+			if instrumentationLibraryMetrics.IsNil() {
+				continue
 			}
-
-			for _, dp := range series.Points {
-
-				var nsec int64
-				if dp.Timestamp != nil {
-					nsec = dp.Timestamp.Seconds*1e9 + int64(dp.Timestamp.Nanos)
-				} else {
-					numDroppedTimeSeries++
+			metrics := instrumentationLibraryMetrics.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					logger.Warn("Received nil metric")
 					continue
 				}
 
-				timeContent := &sls.LogContent{
-					Key:   proto.String(timeNanoKey),
-					Value: proto.String(strconv.FormatInt(nsec, 10)),
-				}
-
-				switch pv := dp.Value.(type) {
-				case *metricspb.Point_Int64Value:
-					valueContent := &sls.LogContent{
-						Key:   proto.String(valueKey),
-						Value: proto.String(strconv.FormatInt(pv.Int64Value, 10)),
-					}
-					logs = append(logs, newMetricLog(nsec, metricNameContent, labelsContent, timeContent, valueContent))
-				case *metricspb.Point_DoubleValue:
-					valueContent := &sls.LogContent{
-						Key:   proto.String(valueKey),
-						Value: proto.String(strconv.FormatFloat(pv.DoubleValue, 'g', -1, 64)),
-					}
-					logs = append(logs, newMetricLog(nsec, metricNameContent, labelsContent, timeContent, valueContent))
-
-				case *metricspb.Point_DistributionValue:
-					logs, err = appendDistributionValues(
-						logs,
-						labels,
-						nsec,
-						metricNameContent,
-						labelsContent,
-						timeContent,
-						pv.DistributionValue)
-					if err != nil {
-						numDroppedTimeSeries++
-						logger.Warn(
-							"Timeseries for distribution metric dropped",
-							zap.Error(err),
-							zap.String("Metric", descriptor.Name))
-					}
-				case *metricspb.Point_SummaryValue:
-					logs, err = appendSummaryValues(
-						logs,
-						labels,
-						nsec,
-						metricNameContent,
-						labelsContent,
-						timeContent,
-						pv.SummaryValue)
-					if err != nil {
-						numDroppedTimeSeries++
-						logger.Warn(
-							"Timeseries for summary metric dropped",
-							zap.Error(err),
-							zap.String("Metric", descriptor.Name))
-					}
+				var (
+					metricLogs []*sls.Log
+					dropped    int
+				)
+				switch metric.DataType() {
+				case pdata.MetricDataTypeIntGauge:
+					metricLogs, dropped = intMetricsToLogs(metric.Name(), metric.IntGauge().DataPoints(), defaultLabels)
+				case pdata.MetricDataTypeDoubleGauge:
+					metricLogs, dropped = doubleMetricsToLogs(metric.Name(), metric.DoubleGauge().DataPoints(), defaultLabels)
+				case pdata.MetricDataTypeIntSum:
+					metricLogs, dropped = intMetricsToLogs(metric.Name(), metric.IntSum().DataPoints(), defaultLabels)
+				case pdata.MetricDataTypeDoubleSum:
+					metricLogs, dropped = doubleMetricsToLogs(metric.Name(), metric.DoubleSum().DataPoints(), defaultLabels)
+				case pdata.MetricDataTypeIntHistogram:
+					metricLogs, dropped = intHistogramMetricsToLogs(metric.Name(), metric.IntHistogram().DataPoints(), defaultLabels)
+				case pdata.MetricDataTypeDoubleHistogram:
+					metricLogs, dropped = doubleHistogramMetricsToLogs(metric.Name(), metric.DoubleHistogram().DataPoints(), defaultLabels)
 				default:
 					numDroppedTimeSeries++
 					logger.Warn(
 						"Timeseries dropped to unexpected metric type",
-						zap.String("Metric", descriptor.Name))
+						zap.String("Metric", metric.Name()))
+					continue
 				}
-
+				if dropped > 0 {
+					logger.Warn(
+						"Data points without timestamp dropped",
+						zap.Int("count", dropped),
+						zap.String("Metric", metric.Name()))
+				}
+				logs = append(logs, metricLogs...)
+				numDroppedTimeSeries += dropped
 			}
 		}
 	}
@@ -16,193 +16,113 @@ package alibabacloudlogserviceexporter
 
 import (
 	"fmt"
-	"reflect"
-	"sort"
 	"testing"
 	"time"
 
-	sls "github.com/aliyun/aliyun-log-go-sdk"
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
-	"go.opentelemetry.io/collector/testutil/metricstestutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
 
 func TestMetricDataToLogService(t *testing.T) {
 	logger := zap.NewNop()
 
-	keys := []string{"k0", "k1"}
-	values := []string{"v0", "v1"}
-
-	unixSecs := int64(1574092046)
-	unixNSecs := int64(11 * time.Millisecond)
-	tsUnix := time.Unix(unixSecs, unixNSecs)
-
-	doubleVal := 1234.5678
-	doublePt := metricstestutil.Double(tsUnix, doubleVal)
-	int64Val := int64(123)
-	int64Pt := &metricspb.Point{
-		Timestamp: metricstestutil.Timestamp(tsUnix),
-		Value:     &metricspb.Point_Int64Value{Int64Value: int64Val},
-	}
-
-	distributionBounds := []float64{1, 2, 4}
-	distributionCounts := []int64{4, 2, 3, 7}
-	distributionTimeSeries := metricstestutil.Timeseries(
-		tsUnix,
-		values,
-		metricstestutil.DistPt(tsUnix, distributionBounds, distributionCounts))
-
-	summaryTimeSeries := metricstestutil.Timeseries(
-		tsUnix,
-		values,
-		metricstestutil.SummPt(
-			tsUnix,
-			11,
-			111,
-			[]float64{90, 95, 99, 99.9},
-			[]float64{100, 6, 4, 1}))
+	labels := map[string]string{"k0": "v0", "k1": "v1"}
+	ts := pdata.TimestampUnixNano(time.Unix(1574092046, int64(11*time.Millisecond)).UnixNano())
 
 	tests := []struct {
-		name                     string
-		metricsDataFn            func() consumerdata.MetricsData
-		wantNumDroppedTimeseries int
+		name     string
+		metricFn func() pdata.Metrics
 	}{
 		{
-			name: "nil_node_nil_resources_nil_metric",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Metrics: []*metricspb.Metric{
-						nil,
-					},
-				}
+			name: "empty",
+			metricFn: func() pdata.Metrics {
+				return newMetrics(nil)
 			},
 		},
 		{
-			name: "nil_node_nil_resources_no_dims",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Metrics: []*metricspb.Metric{
-						metricstestutil.Gauge("gauge_double_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, doublePt)),
-						metricstestutil.GaugeInt("gauge_int_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, int64Pt)),
-						metricstestutil.Cumulative("cumulative_double_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, doublePt)),
-						metricstestutil.CumulativeInt("cumulative_int_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, int64Pt)),
-					},
-				}
+			name: "no_resource_no_labels",
+			metricFn: func() pdata.Metrics {
+				md := newMetrics(nil)
+				metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+				appendIntGauge(metrics, "gauge_int", nil, ts, 123)
+				appendDoubleGauge(metrics, "gauge_double", nil, ts, 1234.5678)
+				appendIntSum(metrics, "sum_int", nil, ts, 123)
+				appendDoubleSum(metrics, "sum_double", nil, ts, 1234.5678)
+				return md
 			},
 		},
 		{
-			name: "nil_node_and_resources_with_dims",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Metrics: []*metricspb.Metric{
-						metricstestutil.Gauge("gauge_double_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, doublePt)),
-						metricstestutil.GaugeInt("gauge_int_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, int64Pt)),
-						metricstestutil.Cumulative("cumulative_double_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, doublePt)),
-						metricstestutil.CumulativeInt("cumulative_int_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, int64Pt)),
-					},
-				}
+			name: "no_resource_with_labels",
+			metricFn: func() pdata.Metrics {
+				md := newMetrics(nil)
+				metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+				appendIntGauge(metrics, "gauge_int", labels, ts, 123)
+				appendDoubleGauge(metrics, "gauge_double", labels, ts, 1234.5678)
+				appendIntSum(metrics, "sum_int", labels, ts, 123)
+				appendDoubleSum(metrics, "sum_double", labels, ts, 1234.5678)
+				return md
 			},
 		},
 		{
-			name: "with_node_resources_dims",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Node: &commonpb.Node{
-						Attributes: map[string]string{
-							"k/n0": "vn0",
-							"k/n1": "vn1",
-						},
-						Identifier: &commonpb.ProcessIdentifier{
-							HostName: "host",
-							Pid:      123,
-						},
-					},
-					Resource: &resourcepb.Resource{
-						Labels: map[string]string{
-							"k/r0": "vr0",
-							"k/r1": "vr1",
-						},
-						Type: "service",
-					},
-					Metrics: []*metricspb.Metric{
-						metricstestutil.Gauge("gauge_double_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, doublePt)),
-						metricstestutil.GaugeInt("gauge_int_with_dims", keys, metricstestutil.Timeseries(tsUnix, values, int64Pt)),
-					},
-				}
+			name: "with_resource_and_labels",
+			metricFn: func() pdata.Metrics {
+				md := newMetrics(map[string]string{
+					"host.hostname": "host",
+					"k/r0":          "vr0",
+				})
+				metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+				appendIntGauge(metrics, "gauge_int", labels, ts, 123)
+				appendDoubleGauge(metrics, "gauge_double", labels, ts, 1234.5678)
+				return md
 			},
 		},
 		{
-			name: "distributions",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Metrics: []*metricspb.Metric{
-						metricstestutil.GaugeDist("gauge_distrib", keys, distributionTimeSeries),
-						metricstestutil.CumulativeDist("cumulative_distrib", keys, distributionTimeSeries),
-					},
-				}
+			name: "histograms",
+			metricFn: func() pdata.Metrics {
+				md := newMetrics(nil)
+				metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+				appendIntHistogram(metrics, "histogram_int", labels, ts, 16, 40, []float64{1, 2, 4}, []uint64{4, 2, 3, 7})
+				appendDoubleHistogram(metrics, "histogram_double", labels, ts, 16, 40.5, []float64{1, 2, 4}, []uint64{4, 2, 3, 7})
+				return md
 			},
 		},
 		{
-			name: "summary",
-			metricsDataFn: func() consumerdata.MetricsData {
-				return consumerdata.MetricsData{
-					Metrics: []*metricspb.Metric{
-						metricstestutil.Summary("summary", keys, summaryTimeSeries),
-					},
-				}
+			name: "histogram_without_bounds",
+			metricFn: func() pdata.Metrics {
+				md := newMetrics(nil)
+				metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+				appendDoubleHistogram(metrics, "histogram_double", labels, ts, 3, 7.5, nil, []uint64{3})
+				return md
 			},
 		},
 	}
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLogs, gotNumDroppedTimeSeries := metricsDataToLogServiceData(logger, tt.metricsDataFn())
+			gotLogs, gotNumDroppedTimeSeries := metricsDataToLogServiceData(logger, tt.metricFn())
+			assert.Equal(t, 0, gotNumDroppedTimeSeries)
 			if i == 0 {
 				assert.Equal(t, len(gotLogs), 0)
 				return
 			}
-			if gotNumDroppedTimeSeries != tt.wantNumDroppedTimeseries {
-				t.Errorf("Converting metrics data to LogSerive data failed, (got : %d, want : %d)\n", gotNumDroppedTimeSeries, tt.wantNumDroppedTimeseries)
-				return
-			}
-			gotLogPairs := make([][]logKeyValuePair, 0, len(gotLogs))
 
+			gotLogPairs := make([][]logKeyValuePair, 0, len(gotLogs))
 			for _, log := range gotLogs {
 				pairs := make([]logKeyValuePair, 0, len(log.Contents))
-				//fmt.Println(log.GetTime())
 				for _, content := range log.Contents {
 					pairs = append(pairs, logKeyValuePair{
 						Key:   content.GetKey(),
 						Value: content.GetValue(),
 					})
-					//fmt.Printf("%s : %s\n", content.GetKey(), content.GetValue())
 				}
 				gotLogPairs = append(gotLogPairs, pairs)
-
-				//fmt.Println("#################")
 			}
-			//str, _ := json.Marshal(gotLogPairs)
-			//fmt.Println(string(str))
 
 			resultLogFile := fmt.Sprintf("./testdata/logservice_metric_data_%02d.json", i)
-
-			wantLogs := make([][]logKeyValuePair, 0, gotNumDroppedTimeSeries)
-
-			if err := loadFromJSON(resultLogFile, &wantLogs); err != nil {
-				t.Errorf("Failed load log key value pairs from %q: %v", resultLogFile, err)
-				return
-			}
-			for j := 0; j < gotNumDroppedTimeSeries; j++ {
-				sort.Sort(logKeyValuePairs(gotLogPairs[j]))
-				sort.Sort(logKeyValuePairs(wantLogs[j]))
-				if !reflect.DeepEqual(gotLogPairs[j], wantLogs[j]) {
-					t.Errorf("Unsuccessful conversion %d \nGot:\n\t%v\nWant:\n\t%v", i, gotLogPairs, wantLogs)
-				}
-			}
+			var wantLogs [][]logKeyValuePair
+			require.NoError(t, loadFromJSON(resultLogFile, &wantLogs))
+			assert.Equal(t, wantLogs, gotLogPairs)
 		})
 	}
 }
@@ -210,52 +130,18 @@ func TestMetricDataToLogService(t *testing.T) {
 func TestInvalidMetric(t *testing.T) {
 	logger := zap.NewNop()
 
-	unixSecs := int64(1574092046)
-	unixNSecs := int64(11 * time.Millisecond)
-	tsUnix := time.Unix(unixSecs, unixNSecs)
-
-	doubleVal := 1234.5678
-	doublePt := metricstestutil.Double(tsUnix, doubleVal)
-	metricData := &consumerdata.MetricsData{
-		Metrics: []*metricspb.Metric{
-			metricstestutil.Gauge("gauge_double_with_dims", nil, metricstestutil.Timeseries(tsUnix, nil, doublePt)),
-		},
-	}
-	// invalid timestamp
-	rawTS := metricData.Metrics[0].Timeseries[0].Points[0].Timestamp
-	metricData.Metrics[0].Timeseries[0].Points[0].Timestamp = nil
-	gotLogs, gotNumDroppedTimeSeries := metricsDataToLogServiceData(logger, *metricData)
-	metricData.Metrics[0].Timeseries[0].Points[0].Timestamp = rawTS
-	assert.Equal(t, gotNumDroppedTimeSeries, 1)
-	assert.Equal(t, len(gotLogs), 0)
-
-	// invalid distribution
-	rawValue := metricData.Metrics[0].Timeseries[0].Points[0].Value
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = &metricspb.Point_DistributionValue{
-		DistributionValue: &metricspb.DistributionValue{},
-	}
-	gotLogs, gotNumDroppedTimeSeries = metricsDataToLogServiceData(logger, *metricData)
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = rawValue
-	assert.Equal(t, gotNumDroppedTimeSeries, 1)
-	assert.Equal(t, len(gotLogs), 2)
-
-	// invalid summary
-	rawValue = metricData.Metrics[0].Timeseries[0].Points[0].Value
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = &metricspb.Point_SummaryValue{
-		SummaryValue: &metricspb.SummaryValue{},
-	}
-	gotLogs, gotNumDroppedTimeSeries = metricsDataToLogServiceData(logger, *metricData)
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = rawValue
-	assert.Equal(t, gotNumDroppedTimeSeries, 1)
-	assert.Equal(t, len(gotLogs), 2)
-
-	// invalid value type
-	rawValue = metricData.Metrics[0].Timeseries[0].Points[0].Value
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = nil
-	gotLogs, gotNumDroppedTimeSeries = metricsDataToLogServiceData(logger, *metricData)
-	metricData.Metrics[0].Timeseries[0].Points[0].Value = rawValue
-	assert.Equal(t, gotNumDroppedTimeSeries, 1)
-	assert.Equal(t, len(gotLogs), 0)
+	md := newMetrics(nil)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	// missing timestamp
+	appendDoubleGauge(metrics, "gauge_double", nil, 0, 1234.5678)
+	appendDoubleHistogram(metrics, "histogram_double", nil, 0, 1, 1, nil, []uint64{1})
+	// missing data type
+	metrics.Resize(metrics.Len() + 1)
+	metrics.At(metrics.Len() - 1).SetName("none")
+
+	gotLogs, gotNumDroppedTimeSeries := metricsDataToLogServiceData(logger, md)
+	assert.Equal(t, 3, gotNumDroppedTimeSeries)
+	assert.Len(t, gotLogs, 0)
 }
 
 func TestMetricCornerCases(t *testing.T) {
@@ -263,26 +149,103 @@ func TestMetricCornerCases(t *testing.T) {
 	assert.Equal(t, min(2, 1), 1)
 	assert.Equal(t, min(1, 1), 1)
 
-	nameContent := &sls.LogContent{
-		Key:   proto.String(metricNameKey),
-		Value: proto.String("test_name"),
-	}
-	labelsContent := &sls.LogContent{
-		Key:   proto.String(labelsKey),
-		Value: proto.String("test_labels"),
-	}
-	timeNanoContent := &sls.LogContent{
-		Key:   proto.String(timeNanoKey),
-		Value: proto.String("123"),
+	// More bucket counts than bounds, the extra counts are ignored.
+	logs := appendHistogramValues(nil, "test_name", KeyValues{}, 1, 3, "3", []float64{1}, []uint64{1, 1, 1})
+	require.Len(t, logs, 4)
+	assert.Equal(t, "le#$#1", logs[2].Contents[1].GetValue())
+	assert.Equal(t, "1", logs[2].Contents[3].GetValue())
+	assert.Equal(t, "le#$#+Inf", logs[3].Contents[1].GetValue())
+	assert.Equal(t, "2", logs[3].Contents[3].GetValue())
+}
+
+func newMetrics(resourceAttributes map[string]string) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	if resourceAttributes != nil {
+		rm.Resource().InitEmpty()
+		for k, v := range resourceAttributes {
+			rm.Resource().Attributes().InsertString(k, v)
+		}
 	}
-	distributionValue := &metricspb.DistributionValue{}
-	logs, err := appendDistributionValues(nil, KeyValues{}, 1, nameContent, labelsContent, timeNanoContent, distributionValue)
-	assert.Error(t, err)
-	assert.Equal(t, len(logs), 2)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	return md
+}
 
-	summaryValue := &metricspb.SummaryValue{}
-	logs, err = appendSummaryValues(nil, KeyValues{}, 1, nameContent, labelsContent, timeNanoContent, summaryValue)
-	assert.Error(t, err)
-	assert.Equal(t, len(logs), 2)
+func appendMetric(metrics pdata.MetricSlice, name string, dataType pdata.MetricDataType) pdata.Metric {
+	metrics.Resize(metrics.Len() + 1)
+	metric := metrics.At(metrics.Len() - 1)
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	return metric
+}
+
+func appendIntGauge(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano, value int64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeIntGauge)
+	metric.IntGauge().InitEmpty()
+	fillIntDataPoints(metric.IntGauge().DataPoints(), labels, ts, value)
+}
+
+func appendIntSum(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano, value int64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeIntSum)
+	metric.IntSum().InitEmpty()
+	fillIntDataPoints(metric.IntSum().DataPoints(), labels, ts, value)
+}
+
+func fillIntDataPoints(dps pdata.IntDataPointSlice, labels map[string]string, ts pdata.TimestampUnixNano, value int64) {
+	dps.Resize(1)
+	dp := dps.At(0)
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetTimestamp(ts)
+	dp.SetValue(value)
+}
+
+func appendDoubleGauge(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano, value float64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeDoubleGauge)
+	metric.DoubleGauge().InitEmpty()
+	fillDoubleDataPoints(metric.DoubleGauge().DataPoints(), labels, ts, value)
+}
+
+func appendDoubleSum(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano, value float64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeDoubleSum)
+	metric.DoubleSum().InitEmpty()
+	fillDoubleDataPoints(metric.DoubleSum().DataPoints(), labels, ts, value)
+}
+
+func fillDoubleDataPoints(dps pdata.DoubleDataPointSlice, labels map[string]string, ts pdata.TimestampUnixNano, value float64) {
+	dps.Resize(1)
+	dp := dps.At(0)
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetTimestamp(ts)
+	dp.SetValue(value)
+}
+
+func appendIntHistogram(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano,
+	count uint64, sum int64, bounds []float64, buckets []uint64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeIntHistogram)
+	metric.IntHistogram().InitEmpty()
+	dps := metric.IntHistogram().DataPoints()
+	dps.Resize(1)
+	dp := dps.At(0)
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetTimestamp(ts)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(buckets)
+}
 
+func appendDoubleHistogram(metrics pdata.MetricSlice, name string, labels map[string]string, ts pdata.TimestampUnixNano,
+	count uint64, sum float64, bounds []float64, buckets []uint64) {
+	metric := appendMetric(metrics, name, pdata.MetricDataTypeDoubleHistogram)
+	metric.DoubleHistogram().InitEmpty()
+	dps := metric.DoubleHistogram().DataPoints()
+	dps.Resize(1)
+	dp := dps.At(0)
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetTimestamp(ts)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(buckets)
 }
@@ -18,11 +18,9 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 )
 
@@ -52,16 +50,12 @@ func (s *logServiceTraceSender) pushTraceData(
 	_ context.Context,
 	td pdata.Traces,
 ) (int, error) {
-	octds := internaldata.TraceDataToOC(td)
-	var errs []error
-	for _, octd := range octds {
-		logs := traceDataToLogServiceData(octd)
-		if len(logs) > 0 {
-			if err := s.client.SendLogs(logs); err != nil {
-				errs = append(errs, err)
-			}
-		}
+	logs := traceDataToLogServiceData(td)
+	if len(logs) == 0 {
+		return 0, nil
 	}
-	return 0, componenterror.CombineErrors(errs)
-
+	if err := s.client.SendLogs(logs); err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
 }
@@ -15,19 +15,14 @@
 package alibabacloudlogserviceexporter
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"strconv"
-	"time"
 
 	sls "github.com/aliyun/aliyun-log-go-sdk"
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/gogo/protobuf/proto"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -44,239 +39,202 @@ const (
 	processTagsPrefix  = "process.tags."
 )
 
-const (
-	// Tags
-	opencensusLanguage        = "language"
-	opencensusExporterVersion = "exporter_version"
-	opencensusCoreLibVersion  = "core_lib_version"
-	opencensusResourceType    = "resource_type"
-)
-
-// traceDataToLogService translates trace data into the LogService format.
-func traceDataToLogServiceData(td consumerdata.TraceData) []*sls.Log {
-	logs := spansToLogServiceData(td.Spans)
-	tagContents := nodeAndResourceToLogContent(td.Node, td.Resource)
-
-	for _, log := range logs {
-		log.Contents = append(log.Contents, tagContents...)
+// traceDataToLogServiceData translates trace data into the LogService format.
+func traceDataToLogServiceData(td pdata.Traces) []*sls.Log {
+	var logs []*sls.Log
+	resourceSpansSlice := td.ResourceSpans()
+	for i := 0; i < resourceSpansSlice.Len(); i++ {
+		resourceSpans := resourceSpansSlice.At(i)
+		if resourceSpans.IsNil() {
+			continue
+		}
+		resourceContents := resourceToLogContents(resourceSpans.Resource())
+		instrumentationLibrarySpansSlice := resourceSpans.InstrumentationLibrarySpans()
+		for j := 0; j < instrumentationLibrarySpansSlice.Len(); j++ {
+			instrumentationLibrarySpans := instrumentationLibrarySpansSlice.At(j)
+			if instrumentationLibrarySpans.IsNil() {
+				continue
+			}
+			libraryContents := instrumentationLibraryToLogContents(instrumentationLibrarySpans.InstrumentationLibrary())
+			spans := instrumentationLibrarySpans.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				log := spanToLogServiceData(span)
+				log.Contents = append(log.Contents, libraryContents...)
+				log.Contents = append(log.Contents, resourceContents...)
+				logs = append(logs, log)
+			}
+		}
 	}
 	return logs
 }
 
-func nodeAndResourceToLogContent(node *commonpb.Node, resource *resourcepb.Resource) []*sls.LogContent {
-	if node == nil {
-		return []*sls.LogContent{}
+// resourceToLogContents converts the resource attributes to process tags, the
+// service name is reported in its own field.
+func resourceToLogContents(resource pdata.Resource) []*sls.LogContent {
+	if resource.IsNil() || resource.Attributes().Len() == 0 {
+		return nil
 	}
 
-	var contents []*sls.LogContent
-
-	for key, val := range node.Attributes {
+	var (
+		contents    []*sls.LogContent
+		serviceName string
+	)
+	resource.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+		if key == conventions.AttributeServiceName {
+			serviceName = value.StringVal()
+			return
+		}
 		contents = append(contents, &sls.LogContent{
 			Key:   proto.String(processTagsPrefix + key),
-			Value: proto.String(val),
+			Value: proto.String(tracetranslator.AttributeValueToString(value, false)),
 		})
-	}
-	if node.Identifier != nil {
-		if node.Identifier.HostName != "" {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + "hostname"),
-				Value: proto.String(node.Identifier.HostName),
-			})
-		}
-		if node.Identifier.Pid != 0 {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + "pid"),
-				Value: proto.String(strconv.Itoa(int(node.Identifier.Pid))),
-			})
-		}
-		if node.Identifier.StartTimestamp != nil && node.Identifier.StartTimestamp.Seconds != 0 {
-			startTimeStr := node.Identifier.StartTimestamp.AsTime().Format(time.RFC3339Nano)
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + "start.time"),
-				Value: proto.String(startTimeStr),
-			})
-		}
-	}
+	})
 
-	// Add OpenCensus library information as tags if available
-	ocLib := node.LibraryInfo
-	if ocLib != nil {
-		// Only add language if specified
-		if ocLib.Language != commonpb.LibraryInfo_LANGUAGE_UNSPECIFIED {
-			languageStr := ocLib.Language.String()
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + opencensusLanguage),
-				Value: proto.String(languageStr),
-			})
-		}
-		if ocLib.ExporterVersion != "" {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + opencensusExporterVersion),
-				Value: proto.String(ocLib.ExporterVersion),
-			})
-		}
-		if ocLib.CoreLibraryVersion != "" {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + opencensusCoreLibVersion),
-				Value: proto.String(ocLib.CoreLibraryVersion),
-			})
-		}
+	contents = append(contents, &sls.LogContent{
+		Key:   proto.String(serviceNameField),
+		Value: proto.String(serviceName),
+	})
+	return contents
+}
+
+func instrumentationLibraryToLogContents(library pdata.InstrumentationLibrary) []*sls.LogContent {
+	if library.IsNil() {
+		return nil
 	}
 
-	var serviceName string
-	if node.ServiceInfo != nil && node.ServiceInfo.Name != "" {
-		serviceName = node.ServiceInfo.Name
+	var contents []*sls.LogContent
+	if library.Name() != "" {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(tagsPrefix + tracetranslator.TagInstrumentationName),
+			Value: proto.String(library.Name()),
+		})
 	}
+	if library.Version() != "" {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(tagsPrefix + tracetranslator.TagInstrumentationVersion),
+			Value: proto.String(library.Version()),
+		})
+	}
+	return contents
+}
 
-	if resource != nil {
-		resourceType := resource.GetType()
-		if resourceType != "" {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + opencensusResourceType),
-				Value: proto.String(resourceType),
-			})
-		}
-		for k, v := range resource.GetLabels() {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(processTagsPrefix + k),
-				Value: proto.String(v),
-			})
-		}
+func spanToLogServiceData(span pdata.Span) *sls.Log {
+	contents := make([]*sls.LogContent, 0)
+	contents = append(contents,
+		&sls.LogContent{
+			Key:   proto.String(traceIDField),
+			Value: proto.String(span.TraceID().HexString()),
+		},
+		&sls.LogContent{
+			Key:   proto.String(spanIDField),
+			Value: proto.String(span.SpanID().HexString()),
+		})
+
+	if linksContent := linksToLogContents(span.Links()); linksContent != nil {
+		contents = append(contents, linksContent)
 	}
 
-	if serviceName == "" && len(contents) == 0 {
-		// No info to put in the process...
-		return nil
+	parentSpanID := span.ParentSpanID().HexString()
+	if parentSpanID == "" {
+		// set "0" if no ParentSpanId
+		parentSpanID = "0"
 	}
+	contents = append(contents,
+		&sls.LogContent{
+			Key:   proto.String(parentSpanIDField),
+			Value: proto.String(parentSpanID),
+		})
 
-	contents = append(contents, &sls.LogContent{
-		Key:   proto.String(serviceNameField),
-		Value: proto.String(serviceName),
+	startTime := timestampToEpochMicroseconds(span.StartTime())
+	contents = append(contents,
+		&sls.LogContent{
+			Key:   proto.String(startTimeField),
+			Value: proto.String(strconv.FormatInt(startTime, 10)),
+		},
+		&sls.LogContent{
+			Key:   proto.String(operationNameField),
+			Value: proto.String(span.Name()),
+		},
+		&sls.LogContent{
+			Key:   proto.String(durationField),
+			Value: proto.String(strconv.FormatInt(timestampToEpochMicroseconds(span.EndTime())-startTime, 10)),
+		})
+
+	attributes := span.Attributes()
+	attributes.ForEach(func(key string, value pdata.AttributeValue) {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(tagsPrefix + key),
+			Value: proto.String(tracetranslator.AttributeValueToString(value, false)),
+		})
 	})
-	return contents
-}
 
-func spansToLogServiceData(spans []*tracepb.Span) []*sls.Log {
-	if spans == nil {
-		return nil
+	if logContent := eventsToLogContent(span.Events()); logContent != nil {
+		contents = append(contents, logContent)
 	}
 
-	// Pre-allocate assuming that few, if any spans, are nil.
-	logs := make([]*sls.Log, 0, len(spans))
-	for _, span := range spans {
-		contents := make([]*sls.LogContent, 0)
-		traceID := hex.EncodeToString(span.TraceId[:])
-		spanID := hex.EncodeToString(span.SpanId[:])
-		contents = append(contents,
-			&sls.LogContent{
-				Key:   proto.String(traceIDField),
-				Value: proto.String(traceID),
-			})
-		contents = append(contents,
-			&sls.LogContent{
-				Key:   proto.String(spanIDField),
-				Value: proto.String(spanID),
-			})
-		linksContent := linksToLogContents(span.Links)
-		if linksContent != nil {
-			contents = append(contents, linksContent)
-		}
-		if len(span.ParentSpanId) != 0 {
-			parentSpanID := hex.EncodeToString(span.ParentSpanId[:])
-			contents = append(contents,
-				&sls.LogContent{
-					Key:   proto.String(parentSpanIDField),
-					Value: proto.String(parentSpanID),
-				})
-		} else {
-			// set "0" if no ParentSpanId
-			contents = append(contents,
-				&sls.LogContent{
-					Key:   proto.String(parentSpanIDField),
-					Value: proto.String("0"),
-				})
-		}
-		startTime := timestampToEpochMicroseconds(span.StartTime)
+	// Only add the "span.kind" tag if not set in the span attributes.
+	if _, ok := attributes.Get(tracetranslator.TagSpanKind); !ok {
 		contents = append(contents,
 			&sls.LogContent{
-				Key:   proto.String(startTimeField),
-				Value: proto.String(strconv.FormatInt(startTime, 10)),
+				Key:   proto.String(tagsPrefix + tracetranslator.TagSpanKind),
+				Value: proto.String(spanKindToStr(span.Kind())),
 			})
+	}
 
+	// Only add status tags if neither status.code and status.message are set in the span attributes.
+	_, hasStatusCode := attributes.Get(tracetranslator.TagStatusCode)
+	_, hasStatusMsg := attributes.Get(tracetranslator.TagStatusMsg)
+	if !hasStatusCode && !hasStatusMsg {
+		statusCode := int32(0)
+		statusMsg := ""
+		if status := span.Status(); !status.IsNil() {
+			statusCode = int32(status.Code())
+			statusMsg = status.Message()
+		}
 		contents = append(contents,
 			&sls.LogContent{
-				Key:   proto.String(operationNameField),
-				Value: proto.String(truncableStringToStr(span.Name)),
-			})
-
-		contents = append(contents,
+				Key:   proto.String(tagsPrefix + tracetranslator.TagStatusCode),
+				Value: proto.String(strconv.Itoa(int(statusCode))),
+			},
 			&sls.LogContent{
-				Key:   proto.String(durationField),
-				Value: proto.String(strconv.FormatInt(timestampToEpochMicroseconds(span.EndTime)-startTime, 10)),
-			})
-
-		for k, v := range span.GetAttributes().GetAttributeMap() {
-			contents = append(contents, &sls.LogContent{
-				Key:   proto.String(tagsPrefix + string(k)),
-				Value: proto.String(attributeValueToString(v)),
+				Key:   proto.String(tagsPrefix + tracetranslator.TagStatusMsg),
+				Value: proto.String(statusMsg),
 			})
-		}
-
-		if logContent := timeEventToLogContent(span.TimeEvents); logContent != nil {
-			contents = append(contents, logContent)
-		}
-
-		// Only add the "span.kind" tag if not set in the OC span attributes.
-		if !tracetranslator.OCAttributeKeyExist(span.Attributes, tracetranslator.TagSpanKind) {
-			contents = append(contents,
-				&sls.LogContent{
-					Key:   proto.String(tagsPrefix + tracetranslator.TagSpanKind),
-					Value: proto.String(spanKindToStr(span.Kind)),
-				})
-		}
-		// Only add status tags if neither status.code and status.message are set in the OC span attributes.
-		if !tracetranslator.OCAttributeKeyExist(span.Attributes, tracetranslator.TagStatusCode) &&
-			!tracetranslator.OCAttributeKeyExist(span.Attributes, tracetranslator.TagStatusMsg) {
-			contents = append(contents,
-				&sls.LogContent{
-					Key:   proto.String(tagsPrefix + tracetranslator.TagStatusCode),
-					Value: proto.String(strconv.Itoa(int(span.GetStatus().GetCode()))),
-				})
-			contents = append(contents,
-				&sls.LogContent{
-					Key:   proto.String(tagsPrefix + tracetranslator.TagStatusMsg),
-					Value: proto.String(span.GetStatus().GetMessage()),
-				})
-		}
-
-		logs = append(logs, &sls.Log{
-			Time:     proto.Uint32(uint32(span.GetEndTime().GetSeconds())),
-			Contents: contents,
-		})
 	}
 
-	return logs
+	return &sls.Log{
+		Time:     proto.Uint32(uint32(span.EndTime() / 1e9)),
+		Contents: contents,
+	}
 }
 
-func linksToLogContents(ocSpanLinks *tracepb.Span_Links) *sls.LogContent {
-	if ocSpanLinks == nil || ocSpanLinks.Link == nil {
+func linksToLogContents(links pdata.SpanLinkSlice) *sls.LogContent {
+	if links.Len() == 0 {
 		return nil
 	}
 
-	ocLinks := ocSpanLinks.Link
-
 	type linkSpanRef struct {
-		TraceID string
-		SpanID  string
-		RefType string
+		TraceID    string
+		SpanID     string
+		TraceState string            `json:",omitempty"`
+		Attributes map[string]string `json:",omitempty"`
 	}
-	spanRefs := make([]linkSpanRef, 0, len(ocLinks))
+	spanRefs := make([]linkSpanRef, 0, links.Len())
 
-	for _, ocLink := range ocLinks {
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		if link.IsNil() {
+			continue
+		}
 		spanRefs = append(spanRefs, linkSpanRef{
-			TraceID: hex.EncodeToString(ocLink.TraceId[:]),
-			SpanID:  hex.EncodeToString(ocLink.SpanId[:]),
-			RefType: ocLink.GetType().String(),
+			TraceID:    link.TraceID().HexString(),
+			SpanID:     link.SpanID().HexString(),
+			TraceState: string(link.TraceState()),
+			Attributes: attributesToStringMap(link.Attributes()),
 		})
 	}
 
@@ -287,64 +245,42 @@ func linksToLogContents(ocSpanLinks *tracepb.Span_Links) *sls.LogContent {
 	}
 }
 
-func attributeValueToString(v *tracepb.AttributeValue) string {
-	switch attribValue := v.Value.(type) {
-	case *tracepb.AttributeValue_StringValue:
-		return truncableStringToStr(attribValue.StringValue)
-	case *tracepb.AttributeValue_IntValue:
-		return strconv.FormatInt(attribValue.IntValue, 10)
-	case *tracepb.AttributeValue_BoolValue:
-		if attribValue.BoolValue {
-			return "true"
-		}
-		return "false"
-	case *tracepb.AttributeValue_DoubleValue:
-		return strconv.FormatFloat(attribValue.DoubleValue, 'g', -1, 64)
-	default:
-	}
-	return "<Unknown OpenCensus Attribute>"
-}
-
-func spanKindToStr(spanKind tracepb.Span_SpanKind) string {
-
+func spanKindToStr(spanKind pdata.SpanKind) string {
 	switch spanKind {
-	case tracepb.Span_CLIENT:
+	case pdata.SpanKindCLIENT:
 		return string(tracetranslator.OpenTracingSpanKindClient)
-	case tracepb.Span_SERVER:
+	case pdata.SpanKindSERVER:
 		return string(tracetranslator.OpenTracingSpanKindServer)
+	case pdata.SpanKindPRODUCER:
+		return string(tracetranslator.OpenTracingSpanKindProducer)
+	case pdata.SpanKindCONSUMER:
+		return string(tracetranslator.OpenTracingSpanKindConsumer)
 	}
 	return ""
 }
 
-func timeEventToLogContent(ocSpanTimeEvents *tracepb.Span_TimeEvents) *sls.LogContent {
-	if ocSpanTimeEvents == nil || ocSpanTimeEvents.TimeEvent == nil {
+func eventsToLogContent(events pdata.SpanEventSlice) *sls.LogContent {
+	if events.Len() == 0 {
 		return nil
 	}
 
-	ocTimeEvents := ocSpanTimeEvents.TimeEvent
-
 	type timeEvent struct {
+		Name   string `json:",omitempty"`
 		TimeUs int64
 		Fields map[string]string
 	}
 
-	// Assume that in general no time events are going to produce nil Jaeger logs.
-	timeEvents := make([]timeEvent, 0, len(ocTimeEvents))
-	for _, ocTimeEvent := range ocTimeEvents {
-		e := timeEvent{
-			TimeUs: timestampToEpochMicroseconds(ocTimeEvent.Time),
+	timeEvents := make([]timeEvent, 0, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() {
+			continue
 		}
-		switch teValue := ocTimeEvent.Value.(type) {
-		case *tracepb.Span_TimeEvent_Annotation_:
-			e.Fields = ocAnnotationToMap(teValue.Annotation)
-		case *tracepb.Span_TimeEvent_MessageEvent_:
-			e.Fields = ocMessageEventToMap(teValue.MessageEvent)
-		default:
-			msg := "An unknown OpenCensus TimeEvent type was detected when translating"
-			e.Fields = make(map[string]string)
-			e.Fields["error"] = msg
-		}
-		timeEvents = append(timeEvents, e)
+		timeEvents = append(timeEvents, timeEvent{
+			Name:   event.Name(),
+			TimeUs: timestampToEpochMicroseconds(event.Timestamp()),
+			Fields: attributesToStringMap(event.Attributes()),
+		})
 	}
 
 	// ignore marshal error
@@ -355,53 +291,17 @@ func timeEventToLogContent(ocSpanTimeEvents *tracepb.Span_TimeEvents) *sls.LogCo
 	}
 }
 
-func ocAnnotationToMap(annotation *tracepb.Span_TimeEvent_Annotation) map[string]string {
-	if annotation == nil {
-		return nil
-	}
-
-	keyVals := make(map[string]string)
-	for k, v := range annotation.GetAttributes().GetAttributeMap() {
-		keyVals[k] = attributeValueToString(v)
-	}
-
-	desc := truncableStringToStr(annotation.Description)
-	if desc != "" {
-		keyVals[tracetranslator.AnnotationDescriptionKey] = desc
-	}
-	return keyVals
-}
-
-func ocMessageEventToMap(msgEvent *tracepb.Span_TimeEvent_MessageEvent) map[string]string {
-	if msgEvent == nil {
+func attributesToStringMap(attributes pdata.AttributeMap) map[string]string {
+	if attributes.Len() == 0 {
 		return nil
 	}
-
-	keyVals := make(map[string]string)
-	keyVals[tracetranslator.MessageEventIDKey] = strconv.FormatUint(msgEvent.Id, 10)
-	keyVals[tracetranslator.MessageEventTypeKey] = msgEvent.Type.String()
-
-	// Some implementations always have these two fields as zeros.
-	if msgEvent.CompressedSize == 0 && msgEvent.UncompressedSize == 0 {
-		return keyVals
-	}
-
-	keyVals[tracetranslator.MessageEventCompressedSizeKey] = strconv.FormatUint(msgEvent.CompressedSize, 10)
-	keyVals[tracetranslator.MessageEventUncompressedSizeKey] = strconv.FormatUint(msgEvent.UncompressedSize, 10)
-
+	keyVals := make(map[string]string, attributes.Len())
+	attributes.ForEach(func(key string, value pdata.AttributeValue) {
+		keyVals[key] = tracetranslator.AttributeValueToString(value, false)
+	})
 	return keyVals
 }
 
-func truncableStringToStr(ts *tracepb.TruncatableString) string {
-	if ts == nil {
-		return ""
-	}
-	return ts.Value
-}
-
-func timestampToEpochMicroseconds(ts *timestamppb.Timestamp) int64 {
-	if ts == nil {
-		return 0
-	}
-	return ts.GetSeconds()*1e6 + int64(ts.GetNanos()/1e3)
+func timestampToEpochMicroseconds(ts pdata.TimestampUnixNano) int64 {
+	return int64(ts) / 1e3
 }
@@ -27,7 +27,10 @@ import (
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -52,7 +55,7 @@ func TestNilOCProtoNodeToLogServiceData(t *testing.T) {
 			},
 		},
 	}
-	got := traceDataToLogServiceData(nilNodeBatch)
+	got := traceDataToLogServiceData(internaldata.OCToTraceData(nilNodeBatch))
 	if len(got) == 0 {
 		t.Fatalf("Logs count must > 0")
 	}
@@ -68,7 +71,7 @@ func TestOCProtoToLogServiceData(t *testing.T) {
 	for i := 0; i < numOfFiles; i++ {
 		td := tds[i]
 
-		gotLogs := traceDataToLogServiceData(td)
+		gotLogs := traceDataToLogServiceData(internaldata.OCToTraceData(td))
 
 		gotLogPairs := make([][]logKeyValuePair, 0, len(gotLogs))
 
@@ -297,14 +300,14 @@ func TestOCStatusToJaegerThriftTags(t *testing.T) {
 	fakeTraceID := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
 	fakeSpanID := []byte{0, 1, 2, 3, 4, 5, 6, 7}
 	for i, c := range cases {
-		gotLogs := traceDataToLogServiceData(consumerdata.TraceData{
+		gotLogs := traceDataToLogServiceData(internaldata.OCToTraceData(consumerdata.TraceData{
 			Spans: []*tracepb.Span{{
 				TraceId:    fakeTraceID,
 				SpanId:     fakeSpanID,
 				Status:     c.haveStatus,
 				Attributes: c.haveAttributes,
 			}},
-		})
+		}))
 		gotLog := gotLogs[0]
 
 		var gotPairs []logKeyValuePair
@@ -495,77 +498,45 @@ func loadFromJSON(file string, obj interface{}) error {
 }
 
 func TestTraceCornerCases(t *testing.T) {
-	assert.Equal(t, truncableStringToStr(nil), "")
-	assert.Equal(t, timestampToEpochMicroseconds(nil), int64(0))
-	assert.Nil(t, ocMessageEventToMap(nil))
-	msgEvent := &tracepb.Span_TimeEvent_MessageEvent{}
-	assert.NotNil(t, ocMessageEventToMap(msgEvent))
-	msgEvent.UncompressedSize = 1
-	msgEvent.CompressedSize = 1
-	assert.NotNil(t, ocMessageEventToMap(msgEvent))
-	annotation := &tracepb.Span_TimeEvent_Annotation{}
-	assert.Nil(t, ocAnnotationToMap(nil))
-	assert.NotNil(t, ocAnnotationToMap(annotation))
-	annotation.Description = &tracepb.TruncatableString{
-		Value: "testDesc",
-	}
-	assert.NotNil(t, ocAnnotationToMap(annotation))
-
-	assert.Nil(t, timeEventToLogContent(nil))
-
-	assert.NotNil(t, timeEventToLogContent(&tracepb.Span_TimeEvents{
-		TimeEvent: []*tracepb.Span_TimeEvent{
-			{
-				Value: nil,
-			},
-		},
-	}))
-
-	assert.Equal(t, spanKindToStr(tracepb.Span_SpanKind(99)), "")
-	assert.Equal(t, spanKindToStr(tracepb.Span_CLIENT), string(tracetranslator.OpenTracingSpanKindClient))
-	assert.Equal(t, spanKindToStr(tracepb.Span_SERVER), string(tracetranslator.OpenTracingSpanKindServer))
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{}), "<Unknown OpenCensus Attribute>")
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{
-		Value: &tracepb.AttributeValue_StringValue{
-			StringValue: annotation.Description,
-		},
-	}), "testDesc")
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{
-		Value: &tracepb.AttributeValue_BoolValue{
-			BoolValue: true,
-		},
-	}), "true")
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{
-		Value: &tracepb.AttributeValue_BoolValue{
-			BoolValue: false,
-		},
-	}), "false")
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{
-		Value: &tracepb.AttributeValue_IntValue{
-			IntValue: 1,
-		},
-	}), "1")
-	assert.Equal(t, attributeValueToString(&tracepb.AttributeValue{
-		Value: &tracepb.AttributeValue_DoubleValue{
-			DoubleValue: 1,
-		},
-	}), "1")
-	rst := linksToLogContents(nil)
-	assert.Nil(t, rst)
-	rstLogs := spansToLogServiceData(nil)
-	assert.Nil(t, rstLogs)
-	rstLogContents := nodeAndResourceToLogContent(nil, nil)
-	assert.Equal(t, len(rstLogContents), 0)
-
-	rstLogContents = nodeAndResourceToLogContent(&commonpb.Node{}, &resourcepb.Resource{})
-	assert.Equal(t, len(rstLogContents), 0)
-
-	rstLogContents = nodeAndResourceToLogContent(&commonpb.Node{
-		LibraryInfo: &commonpb.LibraryInfo{
-			Language:           commonpb.LibraryInfo_GO_LANG,
-			ExporterVersion:    "v_1.0",
-			CoreLibraryVersion: "v0.1",
-		},
-	}, &resourcepb.Resource{})
-	assert.Equal(t, len(rstLogContents), 4)
+	assert.Equal(t, timestampToEpochMicroseconds(0), int64(0))
+	assert.Equal(t, timestampToEpochMicroseconds(1e9+1e3), int64(1e6+1))
+
+	assert.Nil(t, eventsToLogContent(pdata.NewSpanEventSlice()))
+	events := pdata.NewSpanEventSlice()
+	events.Resize(1)
+	events.At(0).SetName("event")
+	events.At(0).Attributes().InsertInt("count", 1)
+	eventsContent := eventsToLogContent(events)
+	require.NotNil(t, eventsContent)
+	assert.Equal(t, `[{"Name":"event","TimeUs":0,"Fields":{"count":"1"}}]`, eventsContent.GetValue())
+
+	assert.Nil(t, linksToLogContents(pdata.NewSpanLinkSlice()))
+	links := pdata.NewSpanLinkSlice()
+	links.Resize(1)
+	links.At(0).SetTraceID(pdata.NewTraceID([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}))
+	links.At(0).SetSpanID(pdata.NewSpanID([]byte{0, 1, 2, 3, 4, 5, 6, 7}))
+	linksContent := linksToLogContents(links)
+	require.NotNil(t, linksContent)
+	assert.Equal(t, `[{"TraceID":"000102030405060708090a0b0c0d0e0f","SpanID":"0001020304050607"}]`, linksContent.GetValue())
+
+	assert.Equal(t, spanKindToStr(pdata.SpanKindINTERNAL), "")
+	assert.Equal(t, spanKindToStr(pdata.SpanKindCLIENT), string(tracetranslator.OpenTracingSpanKindClient))
+	assert.Equal(t, spanKindToStr(pdata.SpanKindSERVER), string(tracetranslator.OpenTracingSpanKindServer))
+	assert.Equal(t, spanKindToStr(pdata.SpanKindPRODUCER), string(tracetranslator.OpenTracingSpanKindProducer))
+	assert.Equal(t, spanKindToStr(pdata.SpanKindCONSUMER), string(tracetranslator.OpenTracingSpanKindConsumer))
+
+	assert.Nil(t, traceDataToLogServiceData(pdata.NewTraces()))
+
+	assert.Nil(t, resourceToLogContents(pdata.NewResource()))
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InsertString("host.hostname", "host")
+	assert.Equal(t, len(resourceToLogContents(resource)), 2)
+
+	assert.Nil(t, instrumentationLibraryToLogContents(pdata.NewInstrumentationLibrary()))
+	library := pdata.NewInstrumentationLibrary()
+	library.InitEmpty()
+	library.SetName("library")
+	library.SetVersion("v0.1")
+	assert.Equal(t, len(instrumentationLibraryToLogContents(library)), 2)
 }
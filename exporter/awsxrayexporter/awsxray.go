@@ -16,7 +16,9 @@ package awsxrayexporter
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/xray"
 	"go.opentelemetry.io/collector/component"
@@ -34,15 +36,50 @@ const (
 )
 
 // NewTraceExporter creates an component.TraceExporterOld that converts to an X-Ray PutTraceSegments
-// request and then posts the request to the configured region's X-Ray endpoint.
+// request and then posts the request to the configured region's X-Ray endpoint, or forwards the
+// segment documents to an X-Ray daemon when the destination is "daemon".
 func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn connAttr) (component.TraceExporter, error) {
 	typeLog := zap.String("type", string(config.Type()))
 	nameLog := zap.String("name", config.Name())
-	awsConfig, session, err := GetAWSConfigSession(logger, cn, config.(*Config))
-	if err != nil {
-		return nil, err
+	cfg := config.(*Config)
+
+	var (
+		send     func(ctx context.Context, documents []*string) (int, error)
+		shutdown = func(context.Context) error { return logger.Sync() }
+		// maxDocumentSize is the size above which segments are split.
+		maxDocumentSize = translator.MaxSegmentDocumentSize
+	)
+	switch cfg.Destination {
+	case "", DestinationAPI:
+		awsConfig, session, err := GetAWSConfigSession(logger, cn, cfg)
+		if err != nil {
+			return nil, err
+		}
+		xrayClient := NewXRay(logger, awsConfig, session)
+		send = func(ctx context.Context, documents []*string) (int, error) {
+			return putTraceSegments(ctx, logger, xrayClient, documents)
+		}
+	case DestinationDaemon:
+		daemon, err := newDaemonClient(cfg.DaemonAddress)
+		if err != nil {
+			return nil, fmt.Errorf("%q config has an invalid \"daemon_address\" %q: %v", cfg.Name(), cfg.DaemonAddress, err)
+		}
+		send = func(_ context.Context, documents []*string) (int, error) {
+			return sendToDaemon(logger, daemon, documents)
+		}
+		// The documents share the datagram with the daemon header.
+		maxDocumentSize = maxDaemonSegmentDocumentSize
+		shutdown = func(context.Context) error {
+			if err := daemon.Close(); err != nil {
+				return err
+			}
+			return logger.Sync()
+		}
+	default:
+		return nil, fmt.Errorf("%q config has an unsupported \"destination\" %q, must be %q or %q",
+			cfg.Name(), cfg.Destination, DestinationAPI, DestinationDaemon)
 	}
-	xrayClient := NewXRay(logger, awsConfig, session)
+
 	return exporterhelper.NewTraceExporter(
 		config,
 		func(ctx context.Context, td pdata.Traces) (totalDroppedSpans int, err error) {
@@ -69,46 +106,87 @@ func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn connA
 							continue
 						}
 
-						document, localErr := translator.MakeSegmentDocumentString(span, resource,
-							config.(*Config).IndexedAttributes, config.(*Config).IndexAllAttributes)
+						spanDocuments, localErr := translator.MakeSegmentDocuments(span, resource,
+							cfg.IndexedAttributes, cfg.IndexAllAttributes, cfg.IndexedEventAttributes, maxDocumentSize)
 						if localErr != nil {
+							if localErr == translator.ErrSegmentTooLarge {
+								logger.Debug("dropping segment", zap.String("reason", reasonTooLarge),
+									zap.String("span_id", span.SpanID().HexString()))
+								recordRejectedSegments(ctx, reasonTooLarge, 1)
+							}
 							totalDroppedSpans++
 							continue
 						}
-						documents = append(documents, &document)
-					}
-				}
-			}
-			for offset := 0; offset < len(documents); offset += maxSegmentsPerPut {
-				nextOffset := offset + maxSegmentsPerPut
-				if nextOffset > td.SpanCount() {
-					nextOffset = td.SpanCount()
-				}
-				input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents[offset:nextOffset]}
-				logger.Debug("request: " + input.String())
-				output, localErr := xrayClient.PutTraceSegments(&input)
-				if localErr != nil {
-					logger.Debug("response error", zap.Error(localErr))
-					err = wrapErrorIfBadRequest(&localErr) // record error
-				}
-				if output != nil {
-					logger.Debug("response: " + output.String())
-					if output.UnprocessedTraceSegments != nil {
-						totalDroppedSpans += len(output.UnprocessedTraceSegments)
+						for d := range spanDocuments {
+							documents = append(documents, &spanDocuments[d])
+						}
 					}
 				}
-				if err != nil {
-					break
-				}
 			}
-			return totalDroppedSpans, err
+			dropped, err := send(ctx, documents)
+			return totalDroppedSpans + dropped, err
 		},
-		exporterhelper.WithShutdown(func(context.Context) error {
-			return logger.Sync()
-		}),
+		exporterhelper.WithShutdown(shutdown),
 	)
 }
 
+// putTraceSegments posts the documents in batches to the PutTraceSegments API and
+// records the documents X-Ray could not process.
+func putTraceSegments(ctx context.Context, logger *zap.Logger, xrayClient XRay, documents []*string) (dropped int, err error) {
+	for offset := 0; offset < len(documents); offset += maxSegmentsPerPut {
+		nextOffset := offset + maxSegmentsPerPut
+		if nextOffset > len(documents) {
+			nextOffset = len(documents)
+		}
+		input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents[offset:nextOffset]}
+		logger.Debug("request: " + input.String())
+		output, localErr := xrayClient.PutTraceSegments(&input)
+		if localErr != nil {
+			logger.Debug("response error", zap.Error(localErr))
+			err = wrapErrorIfBadRequest(&localErr) // record error
+		}
+		if output != nil {
+			logger.Debug("response: " + output.String())
+			dropped += recordUnprocessedSegments(ctx, output.UnprocessedTraceSegments)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return dropped, err
+}
+
+// recordUnprocessedSegments counts the segments rejected by X-Ray per error code
+// and returns the total.
+func recordUnprocessedSegments(ctx context.Context, unprocessed []*xray.UnprocessedTraceSegment) int {
+	rejected := make(map[string]int)
+	for _, segment := range unprocessed {
+		if segment == nil {
+			continue
+		}
+		reason := aws.StringValue(segment.ErrorCode)
+		if reason == "" {
+			reason = reasonUnknown
+		}
+		rejected[reason]++
+	}
+	for reason, count := range rejected {
+		recordRejectedSegments(ctx, reason, count)
+	}
+	return len(unprocessed)
+}
+
+// sendToDaemon forwards every document to the X-Ray daemon, stopping at the first failure.
+func sendToDaemon(logger *zap.Logger, daemon *daemonClient, documents []*string) (dropped int, err error) {
+	for i, document := range documents {
+		if err = daemon.PutSegmentDocument(*document); err != nil {
+			logger.Debug("failed to send segment to the X-Ray daemon", zap.Error(err))
+			return len(documents) - i, err
+		}
+	}
+	return 0, nil
+}
+
 func wrapErrorIfBadRequest(err *error) error {
 	_, ok := (*err).(awserr.RequestFailure)
 	if ok && (*err).(awserr.RequestFailure).StatusCode() < 500 {
@@ -17,17 +17,27 @@ package awsxrayexporter
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/xray"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
 
 func TestTraceExport(t *testing.T) {
@@ -38,6 +48,154 @@ func TestTraceExport(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestTraceExportToDaemon(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Destination = DestinationDaemon
+	config.DaemonAddress = conn.LocalAddr().String()
+	traceExporter, err := NewTraceExporter(config, zap.NewNop(), &Conn{})
+	require.NoError(t, err)
+	defer traceExporter.Shutdown(context.Background())
+
+	td := constructSpanData()
+	require.NoError(t, traceExporter.ConsumeTraces(context.Background(), td))
+
+	buf := make([]byte, 65536)
+	for i := 0; i < td.SpanCount(); i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		parts := strings.SplitN(string(buf[:n]), "\n", 2)
+		require.Len(t, parts, 2)
+		assert.Equal(t, `{"format":"json","version":1}`, parts[0])
+		var segment awsxray.Segment
+		require.NoError(t, json.Unmarshal([]byte(parts[1]), &segment))
+		assert.NoError(t, segment.Validate())
+	}
+}
+
+func TestTraceExportDropsSegmentsTooLarge(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Destination = DestinationDaemon
+	config.DaemonAddress = conn.LocalAddr().String()
+	// Annotations are never trimmed to make the document fit.
+	config.IndexedAttributes = []string{"large"}
+	traceExporter, err := NewTraceExporter(config, zap.NewNop(), &Conn{})
+	require.NoError(t, err)
+	defer traceExporter.Shutdown(context.Background())
+
+	td := constructSpanData()
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Attributes().InsertString("large", strings.Repeat("x", translator.MaxSegmentDocumentSize))
+	before := rejectedSegments(t, reasonTooLarge)
+	require.NoError(t, traceExporter.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, before+1, rejectedSegments(t, reasonTooLarge))
+}
+
+func TestDaemonClientSendsDocumentAtTheLimit(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	daemon, err := newDaemonClient(conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer daemon.Close()
+
+	document := strings.Repeat("x", maxDaemonSegmentDocumentSize)
+	require.NoError(t, daemon.PutSegmentDocument(document))
+
+	buf := make([]byte, 65536)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, daemonHeader+document, string(buf[:n]))
+}
+
+func TestTraceExportToDaemonTrimsSegmentsTooLargeForADatagram(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Destination = DestinationDaemon
+	config.DaemonAddress = conn.LocalAddr().String()
+	traceExporter, err := NewTraceExporter(config, zap.NewNop(), &Conn{})
+	require.NoError(t, err)
+	defer traceExporter.Shutdown(context.Background())
+
+	// The document of the span is exactly MaxSegmentDocumentSize bytes, it is
+	// accepted by the API but doesn't fit in a datagram with the header, so
+	// the attribute is trimmed from its metadata.
+	td := constructSpanData()
+	rspans := td.ResourceSpans().At(0)
+	span := rspans.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Attributes().InsertString("large", "")
+	documents, err := translator.MakeSegmentDocuments(span, rspans.Resource(), nil, false, nil, translator.MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	span.Attributes().UpsertString("large", strings.Repeat("x", translator.MaxSegmentDocumentSize-len(documents[0])))
+	documents, err = translator.MakeSegmentDocuments(span, rspans.Resource(), nil, false, nil, translator.MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	require.Len(t, documents[0], translator.MaxSegmentDocumentSize)
+
+	before := rejectedSegments(t, reasonTooLarge)
+	require.NoError(t, traceExporter.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, before, rejectedSegments(t, reasonTooLarge))
+
+	buf := make([]byte, 65536)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	parts := strings.SplitN(string(buf[:n]), "\n", 2)
+	require.Len(t, parts, 2)
+	var segment awsxray.Segment
+	require.NoError(t, json.Unmarshal([]byte(parts[1]), &segment))
+	assert.NotContains(t, segment.Metadata["default"], "large")
+}
+
+func TestTraceExporterUnsupportedDestination(t *testing.T) {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Destination = "s3"
+	traceExporter, err := NewTraceExporter(config, zap.NewNop(), &Conn{})
+	assert.EqualError(t, err, `"awsxray" config has an unsupported "destination" "s3", must be "api" or "daemon"`)
+	assert.Nil(t, traceExporter)
+}
+
+func TestPutTraceSegmentsRecordsUnprocessedSegments(t *testing.T) {
+	documents := make([]*string, maxSegmentsPerPut+1)
+	for i := range documents {
+		documents[i] = aws.String("{}")
+	}
+	client := &mockXRay{
+		output: &xray.PutTraceSegmentsOutput{
+			UnprocessedTraceSegments: []*xray.UnprocessedTraceSegment{
+				{ErrorCode: aws.String("ThrottledException")},
+				{ErrorCode: aws.String("ThrottledException")},
+				{},
+			},
+		},
+	}
+	throttled := rejectedSegments(t, "ThrottledException")
+	unknown := rejectedSegments(t, reasonUnknown)
+
+	dropped, err := putTraceSegments(context.Background(), zap.NewNop(), client, documents)
+	require.NoError(t, err)
+	require.Len(t, client.inputs, 2)
+	assert.Len(t, client.inputs[0].TraceSegmentDocuments, maxSegmentsPerPut)
+	assert.Len(t, client.inputs[1].TraceSegmentDocuments, 1)
+	assert.Equal(t, 6, dropped)
+	assert.Equal(t, throttled+4, rejectedSegments(t, "ThrottledException"))
+	assert.Equal(t, unknown+2, rejectedSegments(t, reasonUnknown))
+}
+
 func BenchmarkForTraceExporter(b *testing.B) {
 	traceExporter := initializeTraceExporter()
 	for i := 0; i < b.N; i++ {
@@ -174,6 +332,33 @@ func constructSpanAttributes(attributes map[string]interface{}) pdata.AttributeM
 	return attrs
 }
 
+type mockXRay struct {
+	inputs []*xray.PutTraceSegmentsInput
+	output *xray.PutTraceSegmentsOutput
+}
+
+func (m *mockXRay) PutTraceSegments(input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+	m.inputs = append(m.inputs, input)
+	return m.output, nil
+}
+
+func (m *mockXRay) PutTelemetryRecords(*xray.PutTelemetryRecordsInput) (*xray.PutTelemetryRecordsOutput, error) {
+	return &xray.PutTelemetryRecordsOutput{}, nil
+}
+
+func rejectedSegments(t *testing.T, reason string) int64 {
+	rows, err := view.RetrieveData(viewSegmentsRejected.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == reasonKey && tag.Value == reason {
+				return int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	return 0
+}
+
 func newTraceID() pdata.TraceID {
 	var r [16]byte
 	epoch := time.Now().Unix()
@@ -16,6 +16,13 @@ package awsxrayexporter
 
 import "go.opentelemetry.io/collector/config/configmodels"
 
+const (
+	// DestinationAPI sends segment documents with the PutTraceSegments API.
+	DestinationAPI = "api"
+	// DestinationDaemon forwards segment documents to an X-Ray daemon over UDP.
+	DestinationDaemon = "daemon"
+)
+
 // Config defines configuration for AWS X-Ray exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
@@ -46,4 +53,11 @@ type Config struct {
 	// Set to true to convert all OpenTelemetry attributes to X-Ray annotation (indexed) ignoring the IndexedAttributes option.
 	// Default value: false
 	IndexAllAttributes bool `mapstructure:"index_all_attributes"`
+	// Span events are converted to X-Ray metadata in the "events" namespace.
+	// Specify a list of event attribute names to also be converted to X-Ray annotations, which will be indexed.
+	IndexedEventAttributes []string `mapstructure:"indexed_event_attributes"`
+	// Where segment documents are sent, either "api" (PutTraceSegments) or "daemon".
+	Destination string `mapstructure:"destination"`
+	// UDP address of the X-Ray daemon, used when Destination is "daemon".
+	DaemonAddress string `mapstructure:"daemon_address"`
 }
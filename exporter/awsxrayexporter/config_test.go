@@ -38,7 +38,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Exporters), 2)
+	assert.Equal(t, len(cfg.Exporters), 3)
 
 	r0 := cfg.Exporters["awsxray"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -46,18 +46,25 @@ func TestLoadConfig(t *testing.T) {
 	r1 := cfg.Exporters["awsxray/customname"].(*Config)
 	assert.Equal(t, r1,
 		&Config{
-			ExporterSettings:      configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
-			NumberOfWorkers:       8,
-			Endpoint:              "",
-			RequestTimeoutSeconds: 30,
-			MaxRetries:            2,
-			NoVerifySSL:           false,
-			ProxyAddress:          "",
-			Region:                "eu-west-1",
-			LocalMode:             false,
-			ResourceARN:           "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
-			RoleARN:               "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
-			IndexedAttributes:     []string{"indexed_attr_0", "indexed_attr_1"},
-			IndexAllAttributes:    false,
+			ExporterSettings:       configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
+			NumberOfWorkers:        8,
+			Endpoint:               "",
+			RequestTimeoutSeconds:  30,
+			MaxRetries:             2,
+			NoVerifySSL:            false,
+			ProxyAddress:           "",
+			Region:                 "eu-west-1",
+			LocalMode:              false,
+			ResourceARN:            "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
+			RoleARN:                "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+			IndexedAttributes:      []string{"indexed_attr_0", "indexed_attr_1"},
+			IndexAllAttributes:     false,
+			IndexedEventAttributes: []string{"event_attr_0"},
+			Destination:            DestinationAPI,
+			DaemonAddress:          "127.0.0.1:2000",
 		})
+
+	r2 := cfg.Exporters["awsxray/daemon"].(*Config)
+	assert.Equal(t, DestinationDaemon, r2.Destination)
+	assert.Equal(t, "127.0.0.1:2100", r2.DaemonAddress)
 }
@@ -0,0 +1,65 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"net"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+)
+
+const (
+	// daemonHeader precedes every segment document sent to the X-Ray daemon.
+	daemonHeader = `{"format":"json","version":1}` + "\n"
+
+	// maxUDPPayloadSize is the largest payload of a UDP datagram over IPv4.
+	maxUDPPayloadSize = 65507
+)
+
+// maxDaemonSegmentDocumentSize is the largest segment document which fits in a
+// datagram along with the daemon header.
+var maxDaemonSegmentDocumentSize = minInt(translator.MaxSegmentDocumentSize, maxUDPPayloadSize-len(daemonHeader))
+
+// daemonClient forwards segment documents to an X-Ray daemon over UDP.
+type daemonClient struct {
+	conn net.Conn
+}
+
+func newDaemonClient(address string) (*daemonClient, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &daemonClient{conn: conn}, nil
+}
+
+// PutSegmentDocument sends one segment document in a single datagram. The
+// document must not be larger than maxDaemonSegmentDocumentSize.
+func (c *daemonClient) PutSegmentDocument(document string) error {
+	_, err := c.conn.Write([]byte(daemonHeader + strings.TrimSuffix(document, "\n")))
+	return err
+}
+
+func (c *daemonClient) Close() error {
+	return c.conn.Close()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
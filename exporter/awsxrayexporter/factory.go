@@ -16,7 +16,9 @@ package awsxrayexporter
 
 import (
 	"context"
+	"sync"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -27,8 +29,15 @@ const (
 	typeStr = "awsxray"
 )
 
+var once sync.Once
+
 // NewFactory creates a factory for AWS-Xray exporter.
 func NewFactory() component.ExporterFactory {
+	// register views for self-observability
+	once.Do(func() {
+		view.Register(viewSegmentsRejected)
+	})
+
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -51,6 +60,8 @@ func createDefaultConfig() configmodels.Exporter {
 		LocalMode:             false,
 		ResourceARN:           "",
 		RoleARN:               "",
+		Destination:           DestinationAPI,
+		DaemonAddress:         "127.0.0.1:2000",
 	}
 }
 
@@ -47,6 +47,8 @@ func TestCreateDefaultConfig(t *testing.T) {
 		LocalMode:             false,
 		ResourceARN:           "",
 		RoleARN:               "",
+		Destination:           DestinationAPI,
+		DaemonAddress:         "127.0.0.1:2000",
 	}, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 }
@@ -0,0 +1,55 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// reasonTooLarge is recorded for segments that exceed the X-Ray document size
+// limit even after their subsegments were split out.
+const reasonTooLarge = "too_large"
+
+// reasonUnknown is recorded for segments rejected by X-Ray without an error code.
+const reasonUnknown = "unknown"
+
+var (
+	mSegmentsRejected = stats.Int64("awsxrayexporter/segments_rejected", "Number of segment documents rejected per reason", stats.UnitDimensionless)
+
+	reasonKey = tag.MustNewKey("reason")
+)
+
+var viewSegmentsRejected = &view.View{
+	Name:        mSegmentsRejected.Name(),
+	Description: mSegmentsRejected.Description(),
+	Measure:     mSegmentsRejected,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{reasonKey},
+}
+
+func recordRejectedSegments(ctx context.Context, reason string, count int) {
+	if count <= 0 {
+		return
+	}
+	ctx, err := tag.New(ctx, tag.Upsert(reasonKey, reason))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mSegmentsRejected.M(int64(count)))
+}
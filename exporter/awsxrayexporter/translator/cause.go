@@ -135,26 +135,38 @@ func isClientError(code pdata.StatusCode) bool {
 }
 
 func parseException(exceptionType string, message string, stacktrace string, language string) []awsxray.Exception {
-	r := textproto.NewReader(bufio.NewReader(strings.NewReader(stacktrace)))
-
-	// Skip first line containing top level exception / message
-	r.ReadLine()
 	exceptions := make([]awsxray.Exception, 0, 1)
 	exceptions = append(exceptions, awsxray.Exception{
 		ID:      aws.String(hex.EncodeToString(newSegmentID().Bytes())),
 		Type:    aws.String(exceptionType),
 		Message: aws.String(message),
 	})
-	exception := &exceptions[0]
 
-	if language != "java" {
-		// Only support Java stack traces right now.
+	if stacktrace == "" {
 		return exceptions
 	}
 
-	if stacktrace == "" {
-		return exceptions
+	switch language {
+	case "java":
+		return fillJavaStacktrace(stacktrace, exceptions)
+	case "python":
+		return fillPythonStacktrace(stacktrace, exceptions)
+	case "go":
+		return fillGoStacktrace(stacktrace, exceptions)
+	case "nodejs":
+		return fillNodeJSStacktrace(stacktrace, exceptions)
+	case "dotnet":
+		return fillDotNetStacktrace(stacktrace, exceptions)
 	}
+	return exceptions
+}
+
+func fillJavaStacktrace(stacktrace string, exceptions []awsxray.Exception) []awsxray.Exception {
+	r := textproto.NewReader(bufio.NewReader(strings.NewReader(stacktrace)))
+
+	// Skip first line containing top level exception / message
+	r.ReadLine()
+	exception := &exceptions[0]
 
 	var line string
 	line, err := r.ReadLine()
@@ -243,3 +255,233 @@ func parseException(exceptionType string, message string, stacktrace string, lan
 	}
 	return exceptions
 }
+
+// pythonTraceback is one "Traceback (most recent call last):" block of a Python stacktrace.
+type pythonTraceback struct {
+	exceptionType string
+	message       string
+	stack         []awsxray.StackFrame
+}
+
+func fillPythonStacktrace(stacktrace string, exceptions []awsxray.Exception) []awsxray.Exception {
+	var tracebacks []pythonTraceback
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "Traceback (most recent call last):") {
+			tracebacks = append(tracebacks, pythonTraceback{stack: make([]awsxray.StackFrame, 0)})
+			continue
+		}
+		if len(tracebacks) == 0 || line == "" {
+			continue
+		}
+		traceback := &tracebacks[len(tracebacks)-1]
+		if strings.HasPrefix(line, "  File \"") {
+			if frame, ok := parsePythonFrame(strings.TrimSpace(line)); ok {
+				traceback.stack = append(traceback.stack, frame)
+			}
+		} else if !strings.HasPrefix(line, " ") && traceback.exceptionType == "" {
+			// The exception line follows the frames, source lines are indented.
+			traceback.exceptionType, traceback.message = splitTypeAndMessage(line)
+		}
+	}
+	if len(tracebacks) == 0 {
+		return exceptions
+	}
+
+	// Python prints chained exceptions oldest first and frames most recent call last,
+	// X-Ray expects the top level exception first and the innermost frame first.
+	exceptions[0].Stack = reverseStack(tracebacks[len(tracebacks)-1].stack)
+	for i := len(tracebacks) - 2; i >= 0; i-- {
+		exceptions = append(exceptions, awsxray.Exception{
+			ID:      aws.String(hex.EncodeToString(newSegmentID().Bytes())),
+			Type:    aws.String(tracebacks[i].exceptionType),
+			Message: aws.String(tracebacks[i].message),
+			Stack:   reverseStack(tracebacks[i].stack),
+		})
+		exceptions[len(exceptions)-2].Cause = exceptions[len(exceptions)-1].ID
+	}
+	return exceptions
+}
+
+// parsePythonFrame parses a frame such as `File "main.py", line 14, in greet`.
+func parsePythonFrame(line string) (awsxray.StackFrame, bool) {
+	rest := strings.TrimPrefix(line, "File \"")
+	quoteIdx := strings.IndexByte(rest, '"')
+	if quoteIdx < 0 {
+		return awsxray.StackFrame{}, false
+	}
+	path := rest[:quoteIdx]
+	rest = strings.TrimPrefix(rest[quoteIdx+1:], ", line ")
+
+	label := ""
+	if inIdx := strings.Index(rest, ", in "); inIdx >= 0 {
+		label = rest[inIdx+len(", in "):]
+		rest = rest[:inIdx]
+	}
+	lineNumber, _ := strconv.Atoi(rest)
+
+	return awsxray.StackFrame{
+		Path:  aws.String(path),
+		Label: aws.String(label),
+		Line:  aws.Int(lineNumber),
+	}, true
+}
+
+func fillGoStacktrace(stacktrace string, exceptions []awsxray.Exception) []awsxray.Exception {
+	exception := &exceptions[0]
+	exception.Stack = make([]awsxray.StackFrame, 0)
+
+	label := ""
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			continue
+		case line == "":
+			if len(exception.Stack) > 0 {
+				// Only the stack of the first goroutine is kept.
+				return exceptions
+			}
+		case strings.HasPrefix(line, "\t"):
+			// The location of the function on the previous line, e.g. "\t/src/main.go:10 +0x1d".
+			location := strings.TrimPrefix(line, "\t")
+			if offsetIdx := strings.LastIndex(location, " +0x"); offsetIdx >= 0 {
+				location = location[:offsetIdx]
+			}
+			path, lineNumber := splitPathAndLine(location)
+			exception.Stack = append(exception.Stack, awsxray.StackFrame{
+				Path:  aws.String(path),
+				Label: aws.String(label),
+				Line:  aws.Int(lineNumber),
+			})
+			label = ""
+		default:
+			label = strings.TrimPrefix(line, "created by ")
+			if idx := strings.Index(label, " in goroutine "); idx >= 0 {
+				label = label[:idx]
+			}
+			// Remove the arguments, e.g. "main.(*server).handle(0xc000010000, 0x1)".
+			if strings.HasSuffix(label, ")") {
+				if parenIdx := strings.LastIndexByte(label, '('); parenIdx > 0 {
+					label = label[:parenIdx]
+				}
+			}
+		}
+	}
+	return exceptions
+}
+
+func fillNodeJSStacktrace(stacktrace string, exceptions []awsxray.Exception) []awsxray.Exception {
+	exception := &exceptions[0]
+	exception.Stack = make([]awsxray.StackFrame, 0)
+
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "at ") {
+			// Exception line or message continuation.
+			continue
+		}
+		rest := line[len("at "):]
+
+		label := ""
+		location := rest
+		if parenIdx := strings.Index(rest, " ("); parenIdx >= 0 && strings.HasSuffix(rest, ")") {
+			label = rest[:parenIdx]
+			location = rest[parenIdx+len(" (") : len(rest)-1]
+		}
+
+		// Locations look like "/app/index.js:3:9", remove the column.
+		path, lineNumber := splitPathAndLine(location)
+		if columnPath, line := splitPathAndLine(path); line != 0 {
+			path, lineNumber = columnPath, line
+		}
+
+		exception.Stack = append(exception.Stack, awsxray.StackFrame{
+			Path:  aws.String(path),
+			Label: aws.String(label),
+			Line:  aws.Int(lineNumber),
+		})
+	}
+	return exceptions
+}
+
+func fillDotNetStacktrace(stacktrace string, exceptions []awsxray.Exception) []awsxray.Exception {
+	exceptions[0].Stack = make([]awsxray.StackFrame, 0)
+
+	// Inner exceptions are printed as " ---> Type: message" before their frames and end with
+	// "--- End of inner exception stack trace ---", after which the outer frames follow.
+	current := 0
+	var outer []int
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "---> "):
+			exceptionType, message := splitTypeAndMessage(line[len("---> "):])
+			exceptions = append(exceptions, awsxray.Exception{
+				ID:      aws.String(hex.EncodeToString(newSegmentID().Bytes())),
+				Type:    aws.String(exceptionType),
+				Message: aws.String(message),
+				Stack:   make([]awsxray.StackFrame, 0),
+			})
+			exceptions[current].Cause = exceptions[len(exceptions)-1].ID
+			outer = append(outer, current)
+			current = len(exceptions) - 1
+		case line == "--- End of inner exception stack trace ---":
+			if len(outer) > 0 {
+				current = outer[len(outer)-1]
+				outer = outer[:len(outer)-1]
+			}
+		case strings.HasPrefix(line, "at "):
+			label := line[len("at "):]
+			path := ""
+			lineNumber := 0
+			if inIdx := strings.LastIndex(label, " in "); inIdx >= 0 {
+				location := label[inIdx+len(" in "):]
+				label = label[:inIdx]
+				if lineIdx := strings.LastIndex(location, ":line "); lineIdx >= 0 {
+					lineNumber, _ = strconv.Atoi(location[lineIdx+len(":line "):])
+					location = location[:lineIdx]
+				}
+				path = location
+			}
+			// Remove the parameters, e.g. "App.Program.Main(String[] args)".
+			if parenIdx := strings.IndexByte(label, '('); parenIdx > 0 {
+				label = label[:parenIdx]
+			}
+			exceptions[current].Stack = append(exceptions[current].Stack, awsxray.StackFrame{
+				Path:  aws.String(path),
+				Label: aws.String(label),
+				Line:  aws.Int(lineNumber),
+			})
+		}
+	}
+	return exceptions
+}
+
+// splitTypeAndMessage splits an exception line such as "ValueError: bad value".
+func splitTypeAndMessage(line string) (string, string) {
+	if idx := strings.Index(line, ": "); idx >= 0 {
+		return line[:idx], line[idx+len(": "):]
+	}
+	return line, ""
+}
+
+// splitPathAndLine splits a "path:line" location, the line is 0 when it is missing.
+func splitPathAndLine(location string) (string, int) {
+	colonIdx := strings.LastIndexByte(location, ':')
+	if colonIdx < 0 {
+		return location, 0
+	}
+	lineNumber, err := strconv.Atoi(location[colonIdx+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:colonIdx], lineNumber
+}
+
+func reverseStack(stack []awsxray.StackFrame) []awsxray.StackFrame {
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return stack
+}
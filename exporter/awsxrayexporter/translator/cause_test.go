@@ -428,3 +428,115 @@ Caused by: java.lang.IllegalArgumentException: bad argument
 	assert.Equal(t, "NodeTestTask.java", *exceptions[1].Stack[1].Path)
 	assert.Equal(t, 0, *exceptions[1].Stack[1].Line)
 }
+
+func TestParseExceptionWithPythonStacktrace(t *testing.T) {
+	exceptionType := "ValueError"
+	message := "bad value"
+	stacktrace := `Traceback (most recent call last):
+  File "/app/main.py", line 5, in parse
+    return int(value)
+KeyError: 'value'
+
+The above exception was the direct cause of the following exception:
+
+Traceback (most recent call last):
+  File "/app/main.py", line 12, in <module>
+    handle()
+  File "/app/main.py", line 8, in handle
+    raise ValueError("bad value") from err
+ValueError: bad value`
+
+	exceptions := parseException(exceptionType, message, stacktrace, "python")
+	assert.Len(t, exceptions, 2)
+	assert.Equal(t, "ValueError", *exceptions[0].Type)
+	assert.Equal(t, "bad value", *exceptions[0].Message)
+	assert.Len(t, exceptions[0].Stack, 2)
+	assert.Equal(t, "handle", *exceptions[0].Stack[0].Label)
+	assert.Equal(t, "/app/main.py", *exceptions[0].Stack[0].Path)
+	assert.Equal(t, 8, *exceptions[0].Stack[0].Line)
+	assert.Equal(t, "<module>", *exceptions[0].Stack[1].Label)
+	assert.Equal(t, 12, *exceptions[0].Stack[1].Line)
+	assert.Equal(t, exceptions[1].ID, exceptions[0].Cause)
+	assert.Equal(t, "KeyError", *exceptions[1].Type)
+	assert.Equal(t, "'value'", *exceptions[1].Message)
+	assert.Len(t, exceptions[1].Stack, 1)
+	assert.Equal(t, "parse", *exceptions[1].Stack[0].Label)
+	assert.Equal(t, 5, *exceptions[1].Stack[0].Line)
+}
+
+func TestParseExceptionWithGoStacktrace(t *testing.T) {
+	exceptionType := "*errors.errorString"
+	message := "something went wrong"
+	stacktrace := `goroutine 1 [running]:
+main.(*server).handle(0xc000010000, 0x1)
+	/app/server.go:42 +0x1d
+main.main()
+	/app/main.go:10 +0x25
+created by main.start in goroutine 1
+	/app/main.go:20 +0x3a
+
+goroutine 2 [chan receive]:
+main.worker()
+	/app/worker.go:7 +0x10`
+
+	exceptions := parseException(exceptionType, message, stacktrace, "go")
+	assert.Len(t, exceptions, 1)
+	assert.Len(t, exceptions[0].Stack, 3)
+	assert.Equal(t, "main.(*server).handle", *exceptions[0].Stack[0].Label)
+	assert.Equal(t, "/app/server.go", *exceptions[0].Stack[0].Path)
+	assert.Equal(t, 42, *exceptions[0].Stack[0].Line)
+	assert.Equal(t, "main.main", *exceptions[0].Stack[1].Label)
+	assert.Equal(t, "/app/main.go", *exceptions[0].Stack[1].Path)
+	assert.Equal(t, 10, *exceptions[0].Stack[1].Line)
+	assert.Equal(t, "main.start", *exceptions[0].Stack[2].Label)
+	assert.Equal(t, 20, *exceptions[0].Stack[2].Line)
+}
+
+func TestParseExceptionWithNodeJSStacktrace(t *testing.T) {
+	exceptionType := "TypeError"
+	message := "Cannot read property 'id' of undefined"
+	stacktrace := `TypeError: Cannot read property 'id' of undefined
+    at getUser (/app/users.js:14:22)
+    at /app/index.js:5:3
+    at new Promise (<anonymous>)`
+
+	exceptions := parseException(exceptionType, message, stacktrace, "nodejs")
+	assert.Len(t, exceptions, 1)
+	assert.Len(t, exceptions[0].Stack, 3)
+	assert.Equal(t, "getUser", *exceptions[0].Stack[0].Label)
+	assert.Equal(t, "/app/users.js", *exceptions[0].Stack[0].Path)
+	assert.Equal(t, 14, *exceptions[0].Stack[0].Line)
+	assert.Equal(t, "", *exceptions[0].Stack[1].Label)
+	assert.Equal(t, "/app/index.js", *exceptions[0].Stack[1].Path)
+	assert.Equal(t, 5, *exceptions[0].Stack[1].Line)
+	assert.Equal(t, "new Promise", *exceptions[0].Stack[2].Label)
+	assert.Equal(t, "<anonymous>", *exceptions[0].Stack[2].Path)
+	assert.Equal(t, 0, *exceptions[0].Stack[2].Line)
+}
+
+func TestParseExceptionWithDotNetStacktrace(t *testing.T) {
+	exceptionType := "System.InvalidOperationException"
+	message := "outer"
+	stacktrace := `System.InvalidOperationException: outer
+ ---> System.ArgumentException: inner
+   at App.Program.Parse(String value) in /src/Program.cs:line 20
+   --- End of inner exception stack trace ---
+   at App.Program.Handle() in /src/Program.cs:line 12
+   at App.Program.Main(String[] args)`
+
+	exceptions := parseException(exceptionType, message, stacktrace, "dotnet")
+	assert.Len(t, exceptions, 2)
+	assert.Len(t, exceptions[0].Stack, 2)
+	assert.Equal(t, "App.Program.Handle", *exceptions[0].Stack[0].Label)
+	assert.Equal(t, "/src/Program.cs", *exceptions[0].Stack[0].Path)
+	assert.Equal(t, 12, *exceptions[0].Stack[0].Line)
+	assert.Equal(t, "App.Program.Main", *exceptions[0].Stack[1].Label)
+	assert.Equal(t, "", *exceptions[0].Stack[1].Path)
+	assert.Equal(t, 0, *exceptions[0].Stack[1].Line)
+	assert.Equal(t, exceptions[1].ID, exceptions[0].Cause)
+	assert.Equal(t, "System.ArgumentException", *exceptions[1].Type)
+	assert.Equal(t, "inner", *exceptions[1].Message)
+	assert.Len(t, exceptions[1].Stack, 1)
+	assert.Equal(t, "App.Program.Parse", *exceptions[1].Stack[0].Label)
+	assert.Equal(t, 20, *exceptions[1].Stack[0].Line)
+}
@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// eventsMetadataNamespace is the segment metadata namespace span events are written to.
+const eventsMetadataNamespace = "events"
+
+// makeEvents converts the span events, other than exceptions which are reported in the cause, to
+// metadata entries keyed by event name. Event attributes listed in indexedEventAttrs are also
+// returned as annotations.
+func makeEvents(span pdata.Span, indexedEventAttrs []string) (map[string]interface{}, map[string]interface{}) {
+	var (
+		annotations = map[string]interface{}{}
+		metadata    = map[string]interface{}{}
+	)
+
+	indexedKeys := map[string]bool{}
+	for _, name := range indexedEventAttrs {
+		indexedKeys[name] = true
+	}
+
+	for i := 0; i < span.Events().Len(); i++ {
+		event := span.Events().At(i)
+		if event.IsNil() || event.Name() == semconventions.AttributeExceptionEventName {
+			continue
+		}
+
+		entry := map[string]interface{}{
+			"timestamp": timestampToFloatSeconds(event.Timestamp()),
+		}
+		if event.Attributes().Len() > 0 {
+			entry["attributes"] = tracetranslator.AttributeMapToMap(event.Attributes())
+		}
+		events, _ := metadata[event.Name()].([]interface{})
+		metadata[event.Name()] = append(events, entry)
+
+		event.Attributes().ForEach(func(key string, value pdata.AttributeValue) {
+			if indexedKeys[key] {
+				annotations[fixAnnotationKey(key)] = annotationValue(value)
+			}
+		})
+	}
+
+	return annotations, metadata
+}
+
+// annotationValue converts an attribute value to one of the types supported by X-Ray annotations.
+func annotationValue(value pdata.AttributeValue) interface{} {
+	switch value.Type() {
+	case pdata.AttributeValueSTRING:
+		return value.StringVal()
+	case pdata.AttributeValueINT:
+		return value.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return value.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return value.BoolVal()
+	default:
+		return tracetranslator.AttributeValueToString(value, false)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestMakeSegmentWithEvents(t *testing.T) {
+	timestamp := pdata.TimestampUnixNano(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	span := constructServerSpan(newSegmentID(), "/orders", 0, "OK", map[string]interface{}{})
+	span.Events().Append(constructEvent("cache.miss", timestamp, map[string]interface{}{
+		"cache.key":   "orders/42",
+		"cache.shard": 3,
+	}))
+	span.Events().Append(constructEvent("cache.miss", timestamp, nil))
+	span.Events().Append(constructEvent(semconventions.AttributeExceptionEventName, timestamp, map[string]interface{}{
+		semconventions.AttributeExceptionType: "java.lang.IllegalStateException",
+	}))
+
+	segment := MakeSegment(span, pdata.NewResource(), nil, false, []string{"cache.shard"})
+
+	require.Contains(t, segment.Metadata, eventsMetadataNamespace)
+	events := segment.Metadata[eventsMetadataNamespace]
+	assert.Len(t, events, 1)
+	require.Len(t, events["cache.miss"], 2)
+	first := events["cache.miss"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(timestamp)/float64(time.Second), first["timestamp"])
+	assert.Equal(t, map[string]interface{}{"cache.key": "orders/42", "cache.shard": int64(3)}, first["attributes"])
+	second := events["cache.miss"].([]interface{})[1].(map[string]interface{})
+	assert.NotContains(t, second, "attributes")
+
+	assert.Equal(t, map[string]interface{}{"cache_shard": int64(3)}, segment.Annotations)
+}
+
+func TestMakeSegmentWithoutEvents(t *testing.T) {
+	span := constructServerSpan(newSegmentID(), "/orders", 0, "OK", map[string]interface{}{})
+
+	segment := MakeSegment(span, pdata.NewResource(), nil, false, []string{"cache.shard"})
+
+	assert.NotContains(t, segment.Metadata, eventsMetadataNamespace)
+	assert.Empty(t, segment.Annotations)
+}
+
+func TestMakeSegmentSpanAnnotationsTakePrecedence(t *testing.T) {
+	span := constructServerSpan(newSegmentID(), "/orders", 0, "OK", map[string]interface{}{
+		"tenant": "span",
+	})
+	span.Events().Append(constructEvent("login", 0, map[string]interface{}{
+		"tenant": "event",
+	}))
+
+	segment := MakeSegment(span, pdata.NewResource(), []string{"tenant"}, false, []string{"tenant"})
+
+	assert.Equal(t, "span", segment.Annotations["tenant"])
+}
+
+func constructEvent(name string, timestamp pdata.TimestampUnixNano, attributes map[string]interface{}) pdata.SpanEvent {
+	event := pdata.NewSpanEvent()
+	event.InitEmpty()
+	event.SetName(name)
+	event.SetTimestamp(timestamp)
+	constructSpanAttributes(attributes).CopyTo(event.Attributes())
+	return event
+}
@@ -66,8 +66,9 @@ var (
 )
 
 // MakeSegmentDocumentString converts an OpenTelemetry Span to an X-Ray Segment and then serialzies to JSON
-func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool) (string, error) {
-	segment := MakeSegment(span, resource, indexedAttrs, indexAllAttrs)
+func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool,
+	indexedEventAttrs []string) (string, error) {
+	segment := MakeSegment(span, resource, indexedAttrs, indexAllAttrs, indexedEventAttrs)
 	w := writers.borrow()
 	if err := w.Encode(segment); err != nil {
 		return "", err
@@ -77,8 +78,10 @@ func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexed
 	return jsonStr, nil
 }
 
-// MakeSegment converts an OpenTelemetry Span to an X-Ray Segment
-func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool) awsxray.Segment {
+// MakeSegment converts an OpenTelemetry Span to an X-Ray Segment. Span events other than exceptions are
+// written to the "events" metadata namespace, and the event attributes in indexedEventAttrs to annotations.
+func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool,
+	indexedEventAttrs []string) awsxray.Segment {
 	var (
 		traceID                                = convertToAmazonTraceID(span.TraceID())
 		startTime                              = timestampToFloatSeconds(span.StartTime())
@@ -91,6 +94,7 @@ func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string
 		service                                = makeService(resource)
 		sqlfiltered, sql                       = makeSQL(awsfiltered)
 		user, annotations, metadata            = makeXRayAttributes(sqlfiltered, indexedAttrs, indexAllAttrs)
+		eventAnnotations, eventMetadata        = makeEvents(span, indexedEventAttrs)
 		name                                   string
 		namespace                              string
 		segmentType                            string
@@ -166,6 +170,22 @@ func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string
 		segmentType = "subsegment"
 	}
 
+	if len(eventMetadata) > 0 {
+		if metadata == nil {
+			metadata = map[string]map[string]interface{}{}
+		}
+		metadata[eventsMetadataNamespace] = eventMetadata
+	}
+	for key, value := range eventAnnotations {
+		if annotations == nil {
+			annotations = map[string]interface{}{}
+		}
+		// Span attributes take precedence over event attributes with the same key.
+		if _, ok := annotations[key]; !ok {
+			annotations[key] = value
+		}
+	}
+
 	return awsxray.Segment{
 		ID:          awsxray.String(convertToAmazonSpanID(span.SpanID().Bytes())),
 		TraceID:     awsxray.String(traceID),
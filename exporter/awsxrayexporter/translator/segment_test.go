@@ -51,12 +51,12 @@ func TestClientSpanWithAwsSdkClient(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 	assert.Equal(t, "DynamoDB", *segment.Name)
 	assert.Equal(t, "aws", *segment.Namespace)
 	assert.Equal(t, "subsegment", *segment.Type)
 
-	jsonStr, err := MakeSegmentDocumentString(span, resource, nil, false)
+	jsonStr, err := MakeSegmentDocumentString(span, resource, nil, false, nil)
 
 	assert.NotNil(t, jsonStr)
 	assert.Nil(t, err)
@@ -83,7 +83,7 @@ func TestClientSpanWithPeerService(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 	assert.Equal(t, "cats-table", *segment.Name)
 }
 
@@ -107,7 +107,7 @@ func TestServerSpanWithInternalServerError(t *testing.T) {
 	timeEvents := constructTimedEventsWithSentMessageEvent(span.StartTime())
 	timeEvents.CopyTo(span.Events())
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.Cause)
@@ -132,7 +132,7 @@ func TestServerSpanNoParentId(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, 0, "OK", nil)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.Empty(t, segment.ParentID)
 }
@@ -147,7 +147,7 @@ func TestSpanWithNoStatus(t *testing.T) {
 	span.SetStartTime(pdata.TimestampUnixNano(time.Now().UnixNano()))
 	span.SetEndTime(pdata.TimestampUnixNano(time.Now().Add(10).UnixNano()))
 
-	segment := MakeSegment(span, pdata.NewResource(), nil, false)
+	segment := MakeSegment(span, pdata.NewResource(), nil, false, nil)
 	assert.NotNil(t, segment)
 }
 
@@ -167,7 +167,7 @@ func TestClientSpanWithDbComponent(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.SQL)
@@ -208,7 +208,7 @@ func TestClientSpanWithHttpHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "foo.com", *segment.Name)
@@ -227,7 +227,7 @@ func TestClientSpanWithoutHttpHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "bar.com", *segment.Name)
@@ -247,7 +247,7 @@ func TestClientSpanWithRpcHost(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructClientSpan(parentSpanID, spanName, 0, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, "com.foo.AnimalService", *segment.Name)
@@ -270,7 +270,7 @@ func TestSpanWithInvalidTraceId(t *testing.T) {
 	traceID[0] = 0x11
 	span.SetTraceID(pdata.NewTraceID(traceID))
 
-	jsonStr, err := MakeSegmentDocumentString(span, resource, nil, false)
+	jsonStr, err := MakeSegmentDocumentString(span, resource, nil, false, nil)
 
 	assert.NotNil(t, jsonStr)
 	assert.Nil(t, err)
@@ -330,7 +330,7 @@ func TestServerSpanWithNilAttributes(t *testing.T) {
 	timeEvents.CopyTo(span.Events())
 	pdata.NewAttributeMap().CopyTo(span.Attributes())
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.NotNil(t, segment.Cause)
@@ -347,7 +347,7 @@ func TestSpanWithAttributesDefaultNotIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, nil, false)
+	segment := MakeSegment(span, resource, nil, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 0, len(segment.Annotations))
@@ -365,7 +365,7 @@ func TestSpanWithAttributesPartlyIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, false)
+	segment := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 1, len(segment.Annotations))
@@ -383,7 +383,7 @@ func TestSpanWithAttributesAllIndexed(t *testing.T) {
 	resource := constructDefaultResource()
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, true)
+	segment := MakeSegment(span, resource, []string{"attr1@1", "not_exist"}, true, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, 2, len(segment.Annotations))
@@ -404,7 +404,7 @@ func TestOriginNotAws(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{}, false)
+	segment := MakeSegment(span, resource, []string{}, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Nil(t, segment.Origin)
@@ -422,7 +422,7 @@ func TestOriginEc2(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{}, false)
+	segment := MakeSegment(span, resource, []string{}, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginEC2, *segment.Origin)
@@ -441,7 +441,7 @@ func TestOriginEcs(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{}, false)
+	segment := MakeSegment(span, resource, []string{}, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginECS, *segment.Origin)
@@ -461,7 +461,7 @@ func TestOriginEb(t *testing.T) {
 	attrs.CopyTo(resource.Attributes())
 	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
 
-	segment := MakeSegment(span, resource, []string{}, false)
+	segment := MakeSegment(span, resource, []string{}, false, nil)
 
 	assert.NotNil(t, segment)
 	assert.Equal(t, OriginEB, *segment.Origin)
@@ -0,0 +1,184 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// MaxSegmentDocumentSize is the largest segment document, in bytes, accepted by AWS X-Ray.
+const MaxSegmentDocumentSize = 64 * 1024
+
+// ErrSegmentTooLarge is returned when a segment document exceeds the maximum size
+// and cannot be split any further.
+var ErrSegmentTooLarge = errors.New("segment document exceeds the maximum size and cannot be split")
+
+// MakeSegmentDocuments converts an OpenTelemetry Span to an X-Ray Segment and serializes it to one or
+// more JSON documents, splitting it when it exceeds maxSize bytes.
+func MakeSegmentDocuments(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool,
+	indexedEventAttrs []string, maxSize int) ([]string, error) {
+	return SplitSegment(MakeSegment(span, resource, indexedAttrs, indexAllAttrs, indexedEventAttrs), maxSize)
+}
+
+// SplitSegment serializes the segment to JSON. If the document is larger than maxSize bytes, usually
+// MaxSegmentDocumentSize, its embedded subsegments are moved into independent subsegment documents
+// which reference the segment through their trace and parent IDs. A document without subsegments
+// that is still too large, such as the segment of a span with large attributes or events, is
+// trimmed: its metadata entries are removed, largest first, and then the stack traces and chained
+// exceptions of its cause. ErrSegmentTooLarge is returned when the trimmed document is still too
+// large.
+func SplitSegment(segment awsxray.Segment, maxSize int) ([]string, error) {
+	document, err := encodeSegment(segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(document) <= maxSize {
+		return []string{document}, nil
+	}
+	if len(segment.Subsegments) == 0 {
+		document, err = trimSegment(segment, len(document), maxSize)
+		if err != nil {
+			return nil, err
+		}
+		return []string{document}, nil
+	}
+
+	subsegments := segment.Subsegments
+	segment.Subsegments = nil
+	documents, err := SplitSegment(segment, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, subsegment := range subsegments {
+		subsegment.TraceID = segment.TraceID
+		subsegment.ParentID = segment.ID
+		subsegment.Type = awsxray.String("subsegment")
+		subDocuments, err := SplitSegment(subsegment, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, subDocuments...)
+	}
+	return documents, nil
+}
+
+// trimSegment removes the metadata of the segment, whose document is size bytes long, one entry at a
+// time starting from the largest, until the document fits into maxSize bytes. If it still doesn't
+// fit, the stack frames of the exceptions of the cause are dropped and finally all but the first
+// exception. The segment is copied, the metadata and cause of the caller are not modified.
+func trimSegment(segment awsxray.Segment, size int, maxSize int) (string, error) {
+	type entry struct {
+		namespace string
+		key       string
+		size      int
+	}
+	var entries []entry
+	metadata := make(map[string]map[string]interface{}, len(segment.Metadata))
+	for namespace, values := range segment.Metadata {
+		metadata[namespace] = make(map[string]interface{}, len(values))
+		for key, value := range values {
+			metadata[namespace][key] = value
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			// The encoded key, value and their separators.
+			entries = append(entries, entry{namespace: namespace, key: key, size: len(key) + len(data) + 4})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].size != entries[j].size {
+			return entries[i].size > entries[j].size
+		}
+		if entries[i].namespace != entries[j].namespace {
+			return entries[i].namespace < entries[j].namespace
+		}
+		return entries[i].key < entries[j].key
+	})
+	segment.Metadata = metadata
+
+	// The size of the document is estimated from the removed entries, it is only encoded again
+	// once the estimate fits.
+	for _, e := range entries {
+		delete(metadata[e.namespace], e.key)
+		if len(metadata[e.namespace]) == 0 {
+			delete(metadata, e.namespace)
+		}
+		size -= e.size
+		if size > maxSize {
+			continue
+		}
+		document, err := encodeSegment(segment)
+		if err != nil {
+			return "", err
+		}
+		if len(document) <= maxSize {
+			return document, nil
+		}
+		size = len(document)
+	}
+
+	if segment.Cause == nil || len(segment.Cause.Exceptions) == 0 {
+		return "", ErrSegmentTooLarge
+	}
+	cause := *segment.Cause
+	cause.Exceptions = make([]awsxray.Exception, len(segment.Cause.Exceptions))
+	for i, exception := range segment.Cause.Exceptions {
+		if frames := int64(len(exception.Stack)); frames > 0 {
+			if exception.Truncated != nil {
+				frames += *exception.Truncated
+			}
+			exception.Truncated = &frames
+			exception.Stack = nil
+		}
+		cause.Exceptions[i] = exception
+	}
+	segment.Cause = &cause
+	document, err := encodeSegment(segment)
+	if err != nil {
+		return "", err
+	}
+	if len(document) <= maxSize {
+		return document, nil
+	}
+
+	if len(cause.Exceptions) > 1 {
+		cause.Exceptions = cause.Exceptions[:1]
+		cause.Exceptions[0].Cause = nil
+		document, err = encodeSegment(segment)
+		if err != nil {
+			return "", err
+		}
+		if len(document) <= maxSize {
+			return document, nil
+		}
+	}
+	return "", ErrSegmentTooLarge
+}
+
+func encodeSegment(segment awsxray.Segment) (string, error) {
+	w := writers.borrow()
+	defer writers.release(w)
+	if err := w.Encode(segment); err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
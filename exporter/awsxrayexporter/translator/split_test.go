@@ -0,0 +1,182 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	awsP "github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+func TestMakeSegmentDocumentsFitsInOneDocument(t *testing.T) {
+	span := constructWriterPoolSpan()
+
+	documents, err := MakeSegmentDocuments(span, pdata.NewResource(), nil, false, nil, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+
+	expected, err := MakeSegmentDocumentString(span, pdata.NewResource(), nil, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, documents[0])
+}
+
+func TestMakeSegmentDocumentsTrimsMetadata(t *testing.T) {
+	attributes := make(map[string]interface{})
+	attributes[semconventions.AttributeHTTPMethod] = "GET"
+	attributes["user"] = "alice"
+	attributes["small"] = "value"
+	attributes["large"] = strings.Repeat("x", MaxSegmentDocumentSize)
+	span := constructServerSpan(newSegmentID(), "/large", 0, "OK", attributes)
+	event := pdata.NewSpanEvent()
+	event.InitEmpty()
+	event.SetName("retry")
+	event.Attributes().InsertString("payload", strings.Repeat("y", MaxSegmentDocumentSize))
+	span.Events().Append(event)
+
+	documents, err := MakeSegmentDocuments(span, pdata.NewResource(), []string{"user"}, false, nil, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.LessOrEqual(t, len(documents[0]), MaxSegmentDocumentSize)
+
+	var segment awsxray.Segment
+	require.NoError(t, json.Unmarshal([]byte(documents[0]), &segment))
+	assert.Equal(t, "/large", *segment.Name)
+	assert.Equal(t, "alice", segment.Annotations["user"])
+	assert.Equal(t, "value", segment.Metadata["default"]["small"])
+	assert.NotContains(t, segment.Metadata["default"], "large")
+	assert.NotContains(t, segment.Metadata, eventsMetadataNamespace)
+}
+
+func TestMakeSegmentDocumentsTrimsCause(t *testing.T) {
+	var stacktrace strings.Builder
+	stacktrace.WriteString("java.lang.IllegalStateException: state is not legal\n")
+	for i := 0; i < 2000; i++ {
+		stacktrace.WriteString("\tat io.opentelemetry.sdk.trace.RecordEventsReadableSpanTest.recordException(RecordEventsReadableSpanTest.java:626)\n")
+	}
+	stacktrace.WriteString("Caused by: java.lang.IllegalArgumentException: bad argument")
+
+	event := pdata.NewSpanEvent()
+	event.InitEmpty()
+	event.SetName(semconventions.AttributeExceptionEventName)
+	event.Attributes().InsertString(semconventions.AttributeExceptionType, "java.lang.IllegalStateException")
+	event.Attributes().InsertString(semconventions.AttributeExceptionMessage, "bad state")
+	event.Attributes().InsertString(semconventions.AttributeExceptionStacktrace, stacktrace.String())
+	span := constructExceptionServerSpan(map[string]interface{}{}, pdata.StatusCodeInternalError)
+	span.Events().Append(event)
+
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKLanguage, "java")
+
+	documents, err := MakeSegmentDocuments(span, resource, nil, false, nil, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.LessOrEqual(t, len(documents[0]), MaxSegmentDocumentSize)
+
+	var segment awsxray.Segment
+	require.NoError(t, json.Unmarshal([]byte(documents[0]), &segment))
+	require.NotNil(t, segment.Cause)
+	require.Len(t, segment.Cause.Exceptions, 2)
+	assert.Equal(t, "java.lang.IllegalStateException", *segment.Cause.Exceptions[0].Type)
+	assert.Empty(t, segment.Cause.Exceptions[0].Stack)
+	assert.Equal(t, int64(2000), *segment.Cause.Exceptions[0].Truncated)
+	assert.Equal(t, segment.Cause.Exceptions[1].ID, segment.Cause.Exceptions[0].Cause)
+}
+
+func TestMakeSegmentDocumentsTooLarge(t *testing.T) {
+	attributes := make(map[string]interface{})
+	attributes[semconventions.AttributeHTTPMethod] = "GET"
+	attributes["large"] = strings.Repeat("x", MaxSegmentDocumentSize)
+	span := constructServerSpan(newSegmentID(), "/large", 0, "OK", attributes)
+
+	// Indexed attributes are written as annotations, which are never trimmed.
+	documents, err := MakeSegmentDocuments(span, pdata.NewResource(), []string{"large"}, false, nil, MaxSegmentDocumentSize)
+	assert.Equal(t, ErrSegmentTooLarge, err)
+	assert.Nil(t, documents)
+}
+
+func TestSplitSegmentMovesSubsegments(t *testing.T) {
+	large := strings.Repeat("x", MaxSegmentDocumentSize/2)
+	segment := awsxray.Segment{
+		Name:      awsxray.String("parent"),
+		ID:        awsxray.String("0123456789abcdef"),
+		TraceID:   awsxray.String("1-5f84c7a1-0123456789abcdef01234567"),
+		StartTime: awsP.Float64(1),
+		Subsegments: []awsxray.Segment{
+			{
+				Name:      awsxray.String("child-0"),
+				ID:        awsxray.String("1123456789abcdef"),
+				StartTime: awsP.Float64(1),
+				Metadata:  map[string]map[string]interface{}{"default": {"large": large}},
+			},
+			{
+				Name:      awsxray.String("child-1"),
+				ID:        awsxray.String("2123456789abcdef"),
+				StartTime: awsP.Float64(1),
+				Metadata:  map[string]map[string]interface{}{"default": {"large": large}},
+			},
+		},
+	}
+
+	documents, err := SplitSegment(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 3)
+	assert.Len(t, segment.Subsegments, 2, "input segment must not be modified")
+
+	var parent awsxray.Segment
+	require.NoError(t, json.Unmarshal([]byte(documents[0]), &parent))
+	assert.Equal(t, "parent", *parent.Name)
+	assert.Empty(t, parent.Subsegments)
+
+	for i, document := range documents[1:] {
+		assert.LessOrEqual(t, len(document), MaxSegmentDocumentSize)
+		var subsegment awsxray.Segment
+		require.NoError(t, json.Unmarshal([]byte(document), &subsegment))
+		assert.Equal(t, *segment.Subsegments[i].Name, *subsegment.Name)
+		assert.Equal(t, *segment.TraceID, *subsegment.TraceID)
+		assert.Equal(t, *segment.ID, *subsegment.ParentID)
+		assert.Equal(t, "subsegment", *subsegment.Type)
+	}
+}
+
+func TestSplitSegmentSubsegmentTooLarge(t *testing.T) {
+	segment := awsxray.Segment{
+		Name:      awsxray.String("parent"),
+		ID:        awsxray.String("0123456789abcdef"),
+		StartTime: awsP.Float64(1),
+		Subsegments: []awsxray.Segment{
+			{
+				Name:      awsxray.String("child"),
+				ID:        awsxray.String("1123456789abcdef"),
+				StartTime: awsP.Float64(1),
+				Annotations: map[string]interface{}{
+					"large": strings.Repeat("x", MaxSegmentDocumentSize),
+				},
+			},
+		},
+	}
+
+	documents, err := SplitSegment(segment, MaxSegmentDocumentSize)
+	assert.Equal(t, ErrSegmentTooLarge, err)
+	assert.Nil(t, documents)
+}
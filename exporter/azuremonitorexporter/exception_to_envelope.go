@@ -0,0 +1,108 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+// Transforms the exception events of a Span into AppInsights ExceptionData envelopes.
+// Events that are not named "exception" or that carry neither an exception type nor a message are skipped.
+// The envelopes are correlated with the Span so that they show up next to it in the Failures blade.
+func spanEventsToExceptionEnvelopes(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	span pdata.Span,
+	logger *zap.Logger) []*contracts.Envelope {
+
+	events := span.Events()
+	var envelopes []*contracts.Envelope
+
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() || event.Name() != conventions.AttributeExceptionEventName {
+			continue
+		}
+
+		if envelope := spanEventToExceptionEnvelope(resource, instrumentationLibrary, span, event, logger); envelope != nil {
+			envelopes = append(envelopes, envelope)
+		}
+	}
+
+	return envelopes
+}
+
+// Transforms a single exception event into an AppInsights ExceptionData envelope, or returns nil
+// if the event doesn't describe an exception
+func spanEventToExceptionEnvelope(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	span pdata.Span,
+	event pdata.SpanEvent,
+	logger *zap.Logger) *contracts.Envelope {
+
+	exceptionDetails := contracts.NewExceptionDetails()
+	exceptionData := contracts.NewExceptionData()
+	exceptionData.SeverityLevel = contracts.Error
+	exceptionData.Properties = make(map[string]string)
+	exceptionData.Measurements = make(map[string]float64)
+
+	event.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		switch k {
+		case conventions.AttributeExceptionType:
+			exceptionDetails.TypeName = v.StringVal()
+		case conventions.AttributeExceptionMessage:
+			exceptionDetails.Message = v.StringVal()
+		case conventions.AttributeExceptionStacktrace:
+			exceptionDetails.Stack = v.StringVal()
+		default:
+			setAttributeValueAsPropertyOrMeasurement(k, v, exceptionData.Properties, exceptionData.Measurements)
+		}
+	})
+
+	if exceptionDetails.TypeName == "" && exceptionDetails.Message == "" {
+		return nil
+	}
+
+	// The stack is not parsed into frames, AppInsights displays the raw stack instead
+	exceptionDetails.HasFullStack = exceptionDetails.Stack != ""
+	exceptionData.Exceptions = []*contracts.ExceptionDetails{exceptionDetails}
+
+	envelope := contracts.NewEnvelope()
+	envelope.Tags = make(map[string]string)
+	envelope.Name = exceptionData.EnvelopeName("")
+	envelope.Time = toTime(event.Timestamp()).Format(time.RFC3339Nano)
+	envelope.Tags[contracts.OperationId] = idToHex(span.TraceID().Bytes())
+	envelope.Tags[contracts.OperationParentId] = idToHex(span.SpanID().Bytes())
+
+	data := contracts.NewData()
+	data.BaseData = exceptionData
+	data.BaseType = exceptionData.BaseType()
+	envelope.Data = data
+
+	applyResourceAndInstrumentationLibrary(envelope, exceptionData.Properties, resource, instrumentationLibrary)
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(exceptionData.Sanitize, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope
+}
@@ -0,0 +1,109 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultExceptionEnvelopeName = "Microsoft.ApplicationInsights.Exception"
+	defaultExceptionType         = "java.lang.IllegalStateException"
+	defaultExceptionMessage      = "something went wrong"
+	defaultExceptionStacktrace   = "java.lang.IllegalStateException: something went wrong\n\tat com.example.Foo.bar(Foo.java:42)"
+)
+
+var (
+	defaultExceptionEventTime = pdata.TimestampUnixNano(30000000000)
+)
+
+// Tests that an exception event is mapped to ExceptionData correlated with its Span
+func TestSpanEventsToExceptionEnvelopes(t *testing.T) {
+	span := getDefaultHTTPServerSpan()
+	addSpanEvent(span, conventions.AttributeExceptionEventName, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionType:       pdata.NewAttributeValueString(defaultExceptionType),
+		conventions.AttributeExceptionMessage:    pdata.NewAttributeValueString(defaultExceptionMessage),
+		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString(defaultExceptionStacktrace),
+		"exception.escaped":                      pdata.NewAttributeValueBool(true),
+		"retries":                                pdata.NewAttributeValueInt(3),
+	})
+
+	envelopes := spanEventsToExceptionEnvelopes(defaultResource, defaultInstrumentationLibrary, span, zap.NewNop())
+	assert.Len(t, envelopes, 1)
+
+	envelope := envelopes[0]
+	assert.Equal(t, defaultExceptionEnvelopeName, envelope.Name)
+	assert.Equal(t, toTime(defaultExceptionEventTime).Format(time.RFC3339Nano), envelope.Time)
+	assert.Equal(t, defaultTraceIDAsHex, envelope.Tags[contracts.OperationId])
+	assert.Equal(t, defaultSpanIDAsHex, envelope.Tags[contracts.OperationParentId])
+	assert.Equal(t, defaultServiceNamespace+"."+defaultServiceName, envelope.Tags[contracts.CloudRole])
+	assert.Equal(t, defaultServiceInstance, envelope.Tags[contracts.CloudRoleInstance])
+
+	data := envelope.Data.(*contracts.Data)
+	assert.Equal(t, "ExceptionData", data.BaseType)
+	exceptionData := data.BaseData.(*contracts.ExceptionData)
+	assert.Equal(t, contracts.Error, exceptionData.SeverityLevel)
+	assert.Len(t, exceptionData.Exceptions, 1)
+
+	details := exceptionData.Exceptions[0]
+	assert.Equal(t, defaultExceptionType, details.TypeName)
+	assert.Equal(t, defaultExceptionMessage, details.Message)
+	assert.Equal(t, defaultExceptionStacktrace, details.Stack)
+	assert.True(t, details.HasFullStack)
+
+	assert.Equal(t, "true", exceptionData.Properties["exception.escaped"])
+	assert.Equal(t, float64(3), exceptionData.Measurements["retries"])
+	assert.Equal(t, defaultServiceName, exceptionData.Properties[conventions.AttributeServiceName])
+	assert.Equal(t, defaultInstrumentationLibraryName, exceptionData.Properties[instrumentationLibraryName])
+}
+
+// Tests that events which don't describe an exception are ignored
+func TestSpanEventsToExceptionEnvelopesSkipsOtherEvents(t *testing.T) {
+	span := getDefaultHTTPServerSpan()
+	addSpanEvent(span, "message", map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionMessage: pdata.NewAttributeValueString(defaultExceptionMessage),
+	})
+	addSpanEvent(span, conventions.AttributeExceptionEventName, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString(defaultExceptionStacktrace),
+	})
+	addSpanEvent(span, conventions.AttributeExceptionEventName, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionMessage: pdata.NewAttributeValueString(defaultExceptionMessage),
+	})
+
+	envelopes := spanEventsToExceptionEnvelopes(defaultResource, defaultInstrumentationLibrary, span, zap.NewNop())
+	assert.Len(t, envelopes, 1)
+
+	details := envelopes[0].Data.(*contracts.Data).BaseData.(*contracts.ExceptionData).Exceptions[0]
+	assert.Equal(t, defaultExceptionMessage, details.Message)
+	assert.Equal(t, "", details.Stack)
+	assert.False(t, details.HasFullStack)
+}
+
+// Appends an event to the span
+func addSpanEvent(span pdata.Span, name string, attributes map[string]pdata.AttributeValue) {
+	events := span.Events()
+	events.Resize(events.Len() + 1)
+	event := events.At(events.Len() - 1)
+	event.SetName(name)
+	event.SetTimestamp(defaultExceptionEventTime)
+	event.Attributes().InitFromMap(attributes)
+}
@@ -65,3 +65,53 @@ func TestCreateTraceExporterUsingBadConfig(t *testing.T) {
 	assert.Nil(t, exporter)
 	assert.NotNil(t, err)
 }
+
+func TestCreateMetricsExporterUsingSpecificTransportChannel(t *testing.T) {
+	// mock transport channel creation
+	f := factory{tChannel: &mockTransportChannel{}}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := f.createMetricsExporter(ctx, params, createDefaultConfig())
+	assert.NotNil(t, exporter)
+	assert.Nil(t, err)
+}
+
+func TestCreateLogsExporterUsingSpecificTransportChannel(t *testing.T) {
+	// mock transport channel creation
+	f := factory{tChannel: &mockTransportChannel{}}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := f.createLogsExporter(ctx, params, createDefaultConfig())
+	assert.NotNil(t, exporter)
+	assert.Nil(t, err)
+}
+
+func TestExportersShareTransportChannel(t *testing.T) {
+	f := factory{}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	_, err := f.createTraceExporter(ctx, params, createDefaultConfig())
+	assert.Nil(t, err)
+	tChannel := f.tChannel
+
+	_, err = f.createMetricsExporter(ctx, params, createDefaultConfig())
+	assert.Nil(t, err)
+	_, err = f.createLogsExporter(ctx, params, createDefaultConfig())
+	assert.Nil(t, err)
+	assert.Same(t, tChannel, f.tChannel)
+}
+
+func TestCreateMetricsAndLogsExporterUsingBadConfig(t *testing.T) {
+	f := factory{}
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	metricsExporter, err := f.createMetricsExporter(ctx, params, &badConfig{})
+	assert.Nil(t, metricsExporter)
+	assert.NotNil(t, err)
+
+	logsExporter, err := f.createLogsExporter(ctx, params, &badConfig{})
+	assert.Nil(t, logsExporter)
+	assert.NotNil(t, err)
+}
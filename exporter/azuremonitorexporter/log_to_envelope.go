@@ -0,0 +1,102 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	logRecordName         string = "log.name"
+	logRecordSeverityText string = "log.severity_text"
+)
+
+// Transforms a tuple of pdata.Resource, pdata.InstrumentationLibrary, pdata.LogRecord into an AppInsights contracts.Envelope
+// carrying MessageData. Log records that belong to a trace are correlated with the Span that emitted them.
+func logRecordToEnvelope(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	logRecord pdata.LogRecord,
+	logger *zap.Logger) *contracts.Envelope {
+
+	messageData := contracts.NewMessageData()
+	messageData.Message = tracetranslator.AttributeValueToString(logRecord.Body(), false)
+	messageData.SeverityLevel = severityNumberToSeverityLevel(logRecord.SeverityNumber())
+	messageData.Properties = make(map[string]string)
+
+	if logRecord.Name() != "" {
+		messageData.Properties[logRecordName] = logRecord.Name()
+	}
+
+	if logRecord.SeverityText() != "" {
+		messageData.Properties[logRecordSeverityText] = logRecord.SeverityText()
+	}
+
+	envelope := contracts.NewEnvelope()
+	envelope.Tags = make(map[string]string)
+	envelope.Name = messageData.EnvelopeName("")
+	envelope.Time = toTime(logRecord.Timestamp()).Format(time.RFC3339Nano)
+
+	if traceID := logRecord.TraceID().Bytes(); len(traceID) != 0 {
+		envelope.Tags[contracts.OperationId] = idToHex(traceID)
+	}
+
+	if spanID := logRecord.SpanID().Bytes(); len(spanID) != 0 {
+		envelope.Tags[contracts.OperationParentId] = idToHex(spanID)
+	}
+
+	data := contracts.NewData()
+	data.BaseData = messageData
+	data.BaseType = messageData.BaseType()
+	envelope.Data = data
+
+	applyResourceAndInstrumentationLibrary(envelope, messageData.Properties, resource, instrumentationLibrary)
+
+	// MessageData has no measurements, so every attribute becomes a property
+	logRecord.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		messageData.Properties[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(messageData.Sanitize, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope
+}
+
+// Maps the OpenTelemetry severity number ranges to the AppInsights severity levels.
+// Records without a severity are reported as Information.
+func severityNumberToSeverityLevel(severityNumber pdata.SeverityNumber) contracts.SeverityLevel {
+	switch {
+	case severityNumber == pdata.SeverityNumberUNDEFINED:
+		return contracts.Information
+	case severityNumber < pdata.SeverityNumberINFO:
+		return contracts.Verbose
+	case severityNumber < pdata.SeverityNumberWARN:
+		return contracts.Information
+	case severityNumber < pdata.SeverityNumberERROR:
+		return contracts.Warning
+	case severityNumber < pdata.SeverityNumberFATAL:
+		return contracts.Error
+	default:
+		return contracts.Critical
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMessageDataEnvelopeName = "Microsoft.ApplicationInsights.Message"
+	defaultLogName                 = "app.log"
+	defaultLogBody                 = "request handled"
+)
+
+var (
+	defaultLogTime = pdata.TimestampUnixNano(45000000000)
+)
+
+// Tests that a log record is mapped to MessageData correlated with its Span
+func TestLogRecordToEnvelope(t *testing.T) {
+	logRecord := getLogRecord()
+
+	envelope := logRecordToEnvelope(defaultResource, defaultInstrumentationLibrary, logRecord, zap.NewNop())
+
+	assert.Equal(t, defaultMessageDataEnvelopeName, envelope.Name)
+	assert.Equal(t, toTime(defaultLogTime).Format(time.RFC3339Nano), envelope.Time)
+	assert.Equal(t, defaultTraceIDAsHex, envelope.Tags[contracts.OperationId])
+	assert.Equal(t, defaultSpanIDAsHex, envelope.Tags[contracts.OperationParentId])
+	assert.Equal(t, defaultServiceNamespace+"."+defaultServiceName, envelope.Tags[contracts.CloudRole])
+	assert.Equal(t, defaultServiceInstance, envelope.Tags[contracts.CloudRoleInstance])
+
+	data := envelope.Data.(*contracts.Data)
+	assert.Equal(t, "MessageData", data.BaseType)
+	messageData := data.BaseData.(*contracts.MessageData)
+	assert.Equal(t, defaultLogBody, messageData.Message)
+	assert.Equal(t, contracts.Warning, messageData.SeverityLevel)
+	assert.Equal(t, defaultLogName, messageData.Properties[logRecordName])
+	assert.Equal(t, "WARN", messageData.Properties[logRecordSeverityText])
+	assert.Equal(t, "200", messageData.Properties[conventions.AttributeHTTPStatusCode])
+	assert.Equal(t, defaultServiceName, messageData.Properties[conventions.AttributeServiceName])
+	assert.Equal(t, defaultInstrumentationLibraryName, messageData.Properties[instrumentationLibraryName])
+}
+
+// Tests that log records outside of a trace don't get operation tags
+func TestLogRecordWithoutTraceToEnvelope(t *testing.T) {
+	logRecord := getLogRecord()
+	logRecord.SetTraceID(pdata.NewTraceID(nil))
+	logRecord.SetSpanID(pdata.NewSpanID(nil))
+
+	envelope := logRecordToEnvelope(defaultResource, defaultInstrumentationLibrary, logRecord, zap.NewNop())
+
+	assert.NotContains(t, envelope.Tags, contracts.OperationId)
+	assert.NotContains(t, envelope.Tags, contracts.OperationParentId)
+}
+
+// Tests the mapping of the severity number ranges
+func TestSeverityNumberToSeverityLevel(t *testing.T) {
+	tests := []struct {
+		severityNumber pdata.SeverityNumber
+		expected       contracts.SeverityLevel
+	}{
+		{pdata.SeverityNumberUNDEFINED, contracts.Information},
+		{pdata.SeverityNumberTRACE, contracts.Verbose},
+		{pdata.SeverityNumberDEBUG4, contracts.Verbose},
+		{pdata.SeverityNumberINFO, contracts.Information},
+		{pdata.SeverityNumberINFO4, contracts.Information},
+		{pdata.SeverityNumberWARN2, contracts.Warning},
+		{pdata.SeverityNumberERROR, contracts.Error},
+		{pdata.SeverityNumberERROR4, contracts.Error},
+		{pdata.SeverityNumberFATAL, contracts.Critical},
+		{pdata.SeverityNumberFATAL4, contracts.Critical},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, severityNumberToSeverityLevel(tt.severityNumber), tt.severityNumber)
+	}
+}
+
+// Returns a default log record
+func getLogRecord() pdata.LogRecord {
+	logRecord := pdata.NewLogRecord()
+	logRecord.InitEmpty()
+	logRecord.SetName(defaultLogName)
+	logRecord.SetTimestamp(defaultLogTime)
+	logRecord.SetTraceID(pdata.NewTraceID(defaultTraceID))
+	logRecord.SetSpanID(pdata.NewSpanID(defaultSpanID))
+	logRecord.SetSeverityNumber(pdata.SeverityNumberWARN)
+	logRecord.SetSeverityText("WARN")
+	logRecord.Body().SetStringVal(defaultLogBody)
+	logRecord.Attributes().InsertInt(conventions.AttributeHTTPStatusCode, defaultHTTPStatusCode)
+	return logRecord
+}
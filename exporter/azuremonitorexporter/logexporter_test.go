@@ -0,0 +1,69 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// Tests the export onLogData callback with no log records
+func TestExporterLogDataCallbackNoLogs(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getLogExporter(defaultConfig, mockTransportChannel)
+
+	logs := pdata.NewLogs()
+
+	droppedLogs, err := exporter.onLogData(context.Background(), logs)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedLogs)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 0)
+}
+
+// Tests the export onLogData callback with a single log record
+func TestExporterLogDataCallbackSingleLog(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getLogExporter(defaultConfig, mockTransportChannel)
+
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	rl := logs.ResourceLogs().At(0)
+	r := rl.Resource()
+	r.InitEmpty()
+	defaultResource.CopyTo(r)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	ills := rl.InstrumentationLibraryLogs().At(0)
+	defaultInstrumentationLibrary.CopyTo(ills.InstrumentationLibrary())
+	ills.Logs().Append(getLogRecord())
+
+	droppedLogs, err := exporter.onLogData(context.Background(), logs)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedLogs)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func getLogExporter(config *Config, transportChannel transportChannel) *logExporter {
+	return &logExporter{
+		config,
+		transportChannel,
+		zap.NewNop(),
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// Transforms a tuple of pdata.Resource, pdata.InstrumentationLibrary, pdata.Metric into AppInsights contracts.Envelopes,
+// one per data point. Gauges and sums are sent as aggregated metrics with a count of 1,
+// histograms carry their count and sum along with a min and max estimated from the bucket bounds.
+func metricToEnvelopes(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	metric pdata.Metric,
+	logger *zap.Logger) []*contracts.Envelope {
+
+	var envelopes []*contracts.Envelope
+	add := func(labels pdata.StringMap, timestamp pdata.TimestampUnixNano, dataPoint *contracts.DataPoint) {
+		dataPoint.Name = metric.Name()
+		envelopes = append(envelopes, dataPointToEnvelope(resource, instrumentationLibrary, labels, timestamp, dataPoint, logger))
+	}
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if metric.IntGauge().IsNil() {
+			break
+		}
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(), newSingleValueDataPoint(float64(dp.Value())))
+			}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if metric.DoubleGauge().IsNil() {
+			break
+		}
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(), newSingleValueDataPoint(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().IsNil() {
+			break
+		}
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(), newSingleValueDataPoint(float64(dp.Value())))
+			}
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().IsNil() {
+			break
+		}
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(), newSingleValueDataPoint(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if metric.IntHistogram().IsNil() {
+			break
+		}
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(),
+					newHistogramDataPoint(dp.Count(), float64(dp.Sum()), dp.BucketCounts(), dp.ExplicitBounds()))
+			}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if metric.DoubleHistogram().IsNil() {
+			break
+		}
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), dp.Timestamp(),
+					newHistogramDataPoint(dp.Count(), dp.Sum(), dp.BucketCounts(), dp.ExplicitBounds()))
+			}
+		}
+	}
+
+	return envelopes
+}
+
+// Wraps a single DataPoint into an AppInsights MetricData envelope. The labels of the data point
+// are copied into the properties.
+func dataPointToEnvelope(
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary,
+	labels pdata.StringMap,
+	timestamp pdata.TimestampUnixNano,
+	dataPoint *contracts.DataPoint,
+	logger *zap.Logger) *contracts.Envelope {
+
+	metricData := contracts.NewMetricData()
+	metricData.Metrics = []*contracts.DataPoint{dataPoint}
+	metricData.Properties = make(map[string]string)
+
+	envelope := contracts.NewEnvelope()
+	envelope.Tags = make(map[string]string)
+	envelope.Name = metricData.EnvelopeName("")
+	envelope.Time = toTime(timestamp).Format(time.RFC3339Nano)
+
+	data := contracts.NewData()
+	data.BaseData = metricData
+	data.BaseType = metricData.BaseType()
+	envelope.Data = data
+
+	applyResourceAndInstrumentationLibrary(envelope, metricData.Properties, resource, instrumentationLibrary)
+
+	// Labels are the most specific dimensions and take precedence over the resource attributes
+	labels.ForEach(func(k string, v pdata.StringValue) { metricData.Properties[k] = v.Value() })
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(metricData.Sanitize, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope
+}
+
+// Returns an aggregated DataPoint made of a single value
+func newSingleValueDataPoint(value float64) *contracts.DataPoint {
+	dataPoint := contracts.NewDataPoint()
+	dataPoint.Kind = contracts.Aggregation
+	dataPoint.Value = value
+	dataPoint.Count = 1
+	dataPoint.Min = value
+	dataPoint.Max = value
+	return dataPoint
+}
+
+// Returns an aggregated DataPoint for a histogram. OpenTelemetry histograms don't record the
+// extremes, so the min and max are the closest finite bounds of the lowest and highest populated buckets,
+// which keeps min <= max when these buckets are unbounded. Without bounds, the mean is used for both.
+func newHistogramDataPoint(count uint64, sum float64, bucketCounts []uint64, explicitBounds []float64) *contracts.DataPoint {
+	dataPoint := contracts.NewDataPoint()
+	dataPoint.Kind = contracts.Aggregation
+	dataPoint.Value = sum
+	dataPoint.Count = int(count)
+
+	if count == 0 {
+		return dataPoint
+	}
+
+	lowest, highest := -1, -1
+	if len(explicitBounds) > 0 && len(bucketCounts) == len(explicitBounds)+1 {
+		for i, bucketCount := range bucketCounts {
+			if bucketCount == 0 {
+				continue
+			}
+			if lowest < 0 {
+				lowest = i
+			}
+			highest = i
+		}
+	}
+
+	if lowest < 0 {
+		dataPoint.Min = sum / float64(count)
+		dataPoint.Max = dataPoint.Min
+		return dataPoint
+	}
+
+	// Bucket i covers (explicitBounds[i-1], explicitBounds[i]], the first and last buckets are unbounded
+	if lowest > 0 {
+		dataPoint.Min = explicitBounds[lowest-1]
+	} else {
+		dataPoint.Min = explicitBounds[0]
+	}
+	if highest < len(explicitBounds) {
+		dataPoint.Max = explicitBounds[highest]
+	} else {
+		dataPoint.Max = explicitBounds[len(explicitBounds)-1]
+	}
+
+	return dataPoint
+}
@@ -0,0 +1,167 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMetricDataEnvelopeName = "Microsoft.ApplicationInsights.Metric"
+	defaultMetricName             = "requests"
+	defaultMetricLabel            = "http.method"
+)
+
+var (
+	defaultMetricTime = pdata.TimestampUnixNano(60000000000)
+)
+
+// Tests that gauges and sums are mapped to single value aggregations
+func TestGaugeAndSumToMetricData(t *testing.T) {
+	intGauge := getMetric(pdata.MetricDataTypeIntGauge)
+	dp := pdata.NewIntDataPoint()
+	dp.InitEmpty()
+	dp.SetValue(42)
+	dp.SetTimestamp(defaultMetricTime)
+	dp.LabelsMap().Insert(defaultMetricLabel, defaultHTTPMethod)
+	intGauge.IntGauge().DataPoints().Append(dp)
+
+	doubleSum := getMetric(pdata.MetricDataTypeDoubleSum)
+	ddp := pdata.NewDoubleDataPoint()
+	ddp.InitEmpty()
+	ddp.SetValue(1.5)
+	ddp.SetTimestamp(defaultMetricTime)
+	ddp.LabelsMap().Insert(defaultMetricLabel, defaultHTTPMethod)
+	doubleSum.DoubleSum().DataPoints().Append(ddp)
+
+	tests := []struct {
+		metric        pdata.Metric
+		expectedValue float64
+	}{
+		{metric: intGauge, expectedValue: 42},
+		{metric: doubleSum, expectedValue: 1.5},
+	}
+
+	for _, tt := range tests {
+		envelopes := metricToEnvelopes(defaultResource, defaultInstrumentationLibrary, tt.metric, zap.NewNop())
+		assert.Len(t, envelopes, 1)
+
+		dataPoint := commonMetricDataValidations(t, envelopes[0])
+		assert.Equal(t, contracts.Aggregation, dataPoint.Kind)
+		assert.Equal(t, tt.expectedValue, dataPoint.Value)
+		assert.Equal(t, 1, dataPoint.Count)
+		assert.Equal(t, tt.expectedValue, dataPoint.Min)
+		assert.Equal(t, tt.expectedValue, dataPoint.Max)
+	}
+}
+
+// Tests that histograms carry their count and sum and a min and max derived from the buckets
+func TestHistogramToMetricData(t *testing.T) {
+	metric := getMetric(pdata.MetricDataTypeDoubleHistogram)
+	dp := pdata.NewDoubleHistogramDataPoint()
+	dp.InitEmpty()
+	dp.SetCount(6)
+	dp.SetSum(120)
+	dp.SetTimestamp(defaultMetricTime)
+	dp.SetExplicitBounds([]float64{10, 20, 50, 100})
+	dp.SetBucketCounts([]uint64{0, 2, 3, 1, 0})
+	dp.LabelsMap().Insert(defaultMetricLabel, defaultHTTPMethod)
+	metric.DoubleHistogram().DataPoints().Append(dp)
+
+	envelopes := metricToEnvelopes(defaultResource, defaultInstrumentationLibrary, metric, zap.NewNop())
+	assert.Len(t, envelopes, 1)
+
+	dataPoint := commonMetricDataValidations(t, envelopes[0])
+	assert.Equal(t, contracts.Aggregation, dataPoint.Kind)
+	assert.Equal(t, float64(120), dataPoint.Value)
+	assert.Equal(t, 6, dataPoint.Count)
+	assert.Equal(t, float64(10), dataPoint.Min)
+	assert.Equal(t, float64(100), dataPoint.Max)
+}
+
+// Tests the min and max estimation from the histogram buckets
+func TestNewHistogramDataPoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        uint64
+		sum          float64
+		bucketCounts []uint64
+		bounds       []float64
+		expectedMin  float64
+		expectedMax  float64
+	}{
+		{name: "empty", count: 0, sum: 0},
+		{name: "no bounds", count: 4, sum: 10, bucketCounts: []uint64{4}, expectedMin: 2.5, expectedMax: 2.5},
+		{name: "unbounded buckets", count: 2, sum: 30, bucketCounts: []uint64{1, 0, 1}, bounds: []float64{5, 10}, expectedMin: 5, expectedMax: 10},
+		{name: "first bucket", count: 3, sum: 12, bucketCounts: []uint64{2, 1, 0}, bounds: []float64{5, 10}, expectedMin: 5, expectedMax: 10},
+		{name: "only first bucket", count: 3, sum: 6, bucketCounts: []uint64{3, 0, 0}, bounds: []float64{5, 10}, expectedMin: 5, expectedMax: 5},
+		{name: "overflow bucket", count: 3, sum: 40, bucketCounts: []uint64{0, 1, 2}, bounds: []float64{5, 10}, expectedMin: 5, expectedMax: 10},
+		{name: "only overflow bucket", count: 3, sum: 60, bucketCounts: []uint64{0, 0, 3}, bounds: []float64{5, 10}, expectedMin: 10, expectedMax: 10},
+		{name: "single bucket", count: 3, sum: 21, bucketCounts: []uint64{0, 3, 0}, bounds: []float64{5, 10}, expectedMin: 5, expectedMax: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPoint := newHistogramDataPoint(tt.count, tt.sum, tt.bucketCounts, tt.bounds)
+			assert.Equal(t, int(tt.count), dataPoint.Count)
+			assert.Equal(t, tt.sum, dataPoint.Value)
+			assert.Equal(t, tt.expectedMin, dataPoint.Min)
+			assert.Equal(t, tt.expectedMax, dataPoint.Max)
+		})
+	}
+}
+
+// Validates the envelope and returns its single data point
+func commonMetricDataValidations(t *testing.T, envelope *contracts.Envelope) *contracts.DataPoint {
+	assert.Equal(t, defaultMetricDataEnvelopeName, envelope.Name)
+	assert.Equal(t, toTime(defaultMetricTime).Format(time.RFC3339Nano), envelope.Time)
+	assert.Equal(t, defaultServiceNamespace+"."+defaultServiceName, envelope.Tags[contracts.CloudRole])
+	assert.Equal(t, defaultServiceInstance, envelope.Tags[contracts.CloudRoleInstance])
+
+	data := envelope.Data.(*contracts.Data)
+	assert.Equal(t, "MetricData", data.BaseType)
+	metricData := data.BaseData.(*contracts.MetricData)
+	assert.Equal(t, defaultHTTPMethod, metricData.Properties[defaultMetricLabel])
+	assert.Equal(t, defaultServiceName, metricData.Properties[conventions.AttributeServiceName])
+	assert.Equal(t, defaultInstrumentationLibraryName, metricData.Properties[instrumentationLibraryName])
+	assert.Len(t, metricData.Metrics, 1)
+	assert.Equal(t, defaultMetricName, metricData.Metrics[0].Name)
+
+	return metricData.Metrics[0]
+}
+
+// Returns an empty metric of the given type
+func getMetric(dataType pdata.MetricDataType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(defaultMetricName)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeIntGauge:
+		metric.IntGauge().InitEmpty()
+	case pdata.MetricDataTypeDoubleSum:
+		metric.DoubleSum().InitEmpty()
+	case pdata.MetricDataTypeDoubleHistogram:
+		metric.DoubleHistogram().InitEmpty()
+	}
+	return metric
+}
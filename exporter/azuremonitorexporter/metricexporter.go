@@ -0,0 +1,94 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+type metricExporter struct {
+	config           *Config
+	transportChannel transportChannel
+	logger           *zap.Logger
+}
+
+func (exporter *metricExporter) onMetricData(context context.Context, metricData pdata.Metrics) (droppedTimeSeries int, err error) {
+	_, dataPointCount := metricData.MetricAndDataPointCount()
+	if dataPointCount == 0 {
+		return 0, nil
+	}
+
+	processed := 0
+	resourceMetrics := metricData.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		if rm.IsNil() {
+			continue
+		}
+
+		resource := rm.Resource()
+		if resource.IsNil() {
+			// resource is optional, fall back to an empty one
+			resource = pdata.NewResource()
+			resource.InitEmpty()
+		}
+
+		instrumentationLibraryMetricsSlice := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < instrumentationLibraryMetricsSlice.Len(); j++ {
+			instrumentationLibraryMetrics := instrumentationLibraryMetricsSlice.At(j)
+			if instrumentationLibraryMetrics.IsNil() {
+				continue
+			}
+
+			// instrumentation library is optional
+			instrumentationLibrary := instrumentationLibraryMetrics.InstrumentationLibrary()
+			metrics := instrumentationLibraryMetrics.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+
+				for _, envelope := range metricToEnvelopes(resource, instrumentationLibrary, metric, exporter.logger) {
+					// apply the instrumentation key to the envelope
+					envelope.IKey = exporter.config.InstrumentationKey
+
+					// This is a fire and forget operation
+					exporter.transportChannel.Send(envelope)
+					processed++
+				}
+			}
+		}
+	}
+
+	return dataPointCount - processed, nil
+}
+
+// Returns a new instance of the metric exporter
+func newMetricExporter(config *Config, transportChannel transportChannel, logger *zap.Logger) (component.MetricsExporter, error) {
+
+	exporter := &metricExporter{
+		config:           config,
+		transportChannel: transportChannel,
+		logger:           logger,
+	}
+
+	return exporterhelper.NewMetricsExporter(config, exporter.onMetricData)
+}
@@ -0,0 +1,77 @@
+// Copyright OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuremonitorexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// Tests the export onMetricData callback with no data points
+func TestExporterMetricDataCallbackNoDataPoints(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getMetricExporter(defaultConfig, mockTransportChannel)
+
+	metrics := pdata.NewMetrics()
+
+	droppedTimeSeries, err := exporter.onMetricData(context.Background(), metrics)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedTimeSeries)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 0)
+}
+
+// Tests the export onMetricData callback sends an envelope per data point
+func TestExporterMetricDataCallbackDataPoints(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getMetricExporter(defaultConfig, mockTransportChannel)
+
+	metric := getMetric(pdata.MetricDataTypeIntGauge)
+	for i := 0; i < 2; i++ {
+		dp := pdata.NewIntDataPoint()
+		dp.InitEmpty()
+		dp.SetValue(int64(i))
+		metric.IntGauge().DataPoints().Append(dp)
+	}
+
+	metrics := pdata.NewMetrics()
+	metrics.ResourceMetrics().Resize(1)
+	rm := metrics.ResourceMetrics().At(0)
+	r := rm.Resource()
+	r.InitEmpty()
+	defaultResource.CopyTo(r)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilms := rm.InstrumentationLibraryMetrics().At(0)
+	defaultInstrumentationLibrary.CopyTo(ilms.InstrumentationLibrary())
+	ilms.Metrics().Append(metric)
+
+	droppedTimeSeries, err := exporter.onMetricData(context.Background(), metrics)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedTimeSeries)
+
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func getMetricExporter(config *Config, transportChannel transportChannel) *metricExporter {
+	return &metricExporter{
+		config,
+		transportChannel,
+		zap.NewNop(),
+	}
+}
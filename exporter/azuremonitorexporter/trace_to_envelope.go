@@ -109,6 +109,24 @@ func spanToEnvelope(
 	}
 
 	envelope.Data = data
+	applyResourceAndInstrumentationLibrary(envelope, dataProperties, resource, instrumentationLibrary)
+
+	// Sanitize the base data, the envelope and envelope tags
+	sanitize(dataSanitizeFunc, logger)
+	sanitize(func() []string { return envelope.Sanitize() }, logger)
+	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
+
+	return envelope, nil
+}
+
+// Copies the resource attributes and the instrumentation library name and version into the
+// data properties and fills the CloudRole and CloudRoleInstance envelope tags
+func applyResourceAndInstrumentationLibrary(
+	envelope *contracts.Envelope,
+	dataProperties map[string]string,
+	resource pdata.Resource,
+	instrumentationLibrary pdata.InstrumentationLibrary) {
+
 	resourceAttributes := resource.Attributes()
 
 	// Copy all the resource labels into the base data properties. Resource values are always strings
@@ -140,13 +158,6 @@ func spanToEnvelope(
 	if serviceInstance, exists := resourceAttributes.Get(conventions.AttributeServiceInstance); exists {
 		envelope.Tags[contracts.CloudRoleInstance] = serviceInstance.StringVal()
 	}
-
-	// Sanitize the base data, the envelope and envelope tags
-	sanitize(dataSanitizeFunc, logger)
-	sanitize(func() []string { return envelope.Sanitize() }, logger)
-	sanitize(func() []string { return contracts.SanitizeTags(envelope.Tags) }, logger)
-
-	return envelope, nil
 }
 
 // Maps Server/Consumer Span to AppInsights RequestData
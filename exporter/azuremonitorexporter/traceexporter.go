@@ -55,6 +55,12 @@ func (v *traceVisitor) visit(
 	v.exporter.transportChannel.Send(envelope)
 	v.processed++
 
+	// Exception events are sent as separate ExceptionData telemetry correlated with the Span
+	for _, exceptionEnvelope := range spanEventsToExceptionEnvelopes(resource, instrumentationLibrary, span, v.exporter.logger) {
+		exceptionEnvelope.IKey = v.exporter.config.InstrumentationKey
+		v.exporter.transportChannel.Send(exceptionEnvelope)
+	}
+
 	return true
 }
 
@@ -73,6 +73,35 @@ func TestExporterTraceDataCallbackSingleSpan(t *testing.T) {
 	mockTransportChannel.AssertNumberOfCalls(t, "Send", 1)
 }
 
+// Tests the export onTraceData callback with a single Span carrying an exception event
+func TestExporterTraceDataCallbackSingleSpanWithException(t *testing.T) {
+	mockTransportChannel := getMockTransportChannel()
+	exporter := getExporter(defaultConfig, mockTransportChannel)
+
+	span := getDefaultHTTPServerSpan()
+	addSpanEvent(span, conventions.AttributeExceptionEventName, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionType: pdata.NewAttributeValueString(defaultExceptionType),
+	})
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	r := rs.Resource()
+	r.InitEmpty()
+	defaultResource.CopyTo(r)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ilss := rs.InstrumentationLibrarySpans().At(0)
+	defaultInstrumentationLibrary.CopyTo(ilss.InstrumentationLibrary())
+	ilss.Spans().Append(span)
+
+	droppedSpans, err := exporter.onTraceData(context.Background(), traces)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, droppedSpans)
+
+	// One envelope for the Span and one for the exception
+	mockTransportChannel.AssertNumberOfCalls(t, "Send", 2)
+}
+
 // Tests the export onTraceData callback with a single Span that fails to produce an envelope
 func TestExporterTraceDataCallbackSingleSpanNoEnvelope(t *testing.T) {
 	mockTransportChannel := getMockTransportChannel()
@@ -24,6 +24,12 @@ import (
 const (
 	DefaultEndpoint    = "localhost:2003"
 	DefaultSendTimeout = 5 * time.Second
+
+	TransportTCP = "tcp"
+	TransportUDP = "udp"
+
+	NamingTags = "tags"
+	NamingPath = "path"
 )
 
 // Config defines configuration for Carbon exporter.
@@ -38,4 +44,76 @@ type Config struct {
 	// data to the Carbon/Graphite backend.
 	// The default value is defined by the DefaultSendTimeout constant.
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Transport is the protocol used to send metrics, either "tcp" or "udp".
+	// The default value is "tcp".
+	Transport string `mapstructure:"transport"`
+
+	// Naming controls how labels are written. With "tags" they are written as
+	// Graphite 1.1 tags, ie.: "<metric_name>;key=value". With "path" they are
+	// appended to the metric path, ie.: "<metric_name>.key.value". The default
+	// value is "tags".
+	Naming string `mapstructure:"naming"`
+
+	// NameTemplates define the names of the series generated for histograms
+	// and summaries. Templates that are not set use their default value.
+	NameTemplates NameTemplatesConfig `mapstructure:"name_templates"`
+
+	// CumulativeToDelta converts cumulative int and double metrics to the
+	// change since their previous point. The first point of each series is
+	// not sent.
+	CumulativeToDelta bool `mapstructure:"cumulative_to_delta"`
+}
+
+// NameTemplatesConfig defines the names of the series generated for
+// histograms and summaries. Each template must contain the NamePlaceholder,
+// which is replaced by the name of the metric.
+type NameTemplatesConfig struct {
+	// Bucket is the name of the histogram bucket counts. The default value
+	// is "{{name}}.bucket".
+	Bucket string `mapstructure:"bucket"`
+
+	// Quantile is the name of the summary quantiles. The default value is
+	// "{{name}}.quantile".
+	Quantile string `mapstructure:"quantile"`
+
+	// Count is the name of the histogram and summary counts. The default
+	// value is "{{name}}.count".
+	Count string `mapstructure:"count"`
+
+	// Sum is the name of the histogram and summary sums. The default value
+	// is "{{name}}", ie.: the sum is sent with the original metric name.
+	Sum string `mapstructure:"sum"`
+}
+
+// NamePlaceholder is replaced by the metric name in the name templates.
+const NamePlaceholder = "{{name}}"
+
+// DefaultNameTemplates returns the name templates used if none is configured.
+func DefaultNameTemplates() NameTemplatesConfig {
+	return NameTemplatesConfig{
+		Bucket:   NamePlaceholder + ".bucket",
+		Quantile: NamePlaceholder + ".quantile",
+		Count:    NamePlaceholder + ".count",
+		Sum:      NamePlaceholder,
+	}
+}
+
+// nameTemplates returns the configured name templates, using the default
+// value of the ones that are not set.
+func (cfg *Config) nameTemplates() NameTemplatesConfig {
+	templates := DefaultNameTemplates()
+	if cfg.NameTemplates.Bucket != "" {
+		templates.Bucket = cfg.NameTemplates.Bucket
+	}
+	if cfg.NameTemplates.Quantile != "" {
+		templates.Quantile = cfg.NameTemplates.Quantile
+	}
+	if cfg.NameTemplates.Count != "" {
+		templates.Count = cfg.NameTemplates.Count
+	}
+	if cfg.NameTemplates.Sum != "" {
+		templates.Sum = cfg.NameTemplates.Sum
+	}
+	return templates
 }
@@ -53,8 +53,18 @@ func TestLoadConfig(t *testing.T) {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: expectedName,
 		},
-		Endpoint: "localhost:8080",
-		Timeout:  10 * time.Second,
+		Endpoint:  "localhost:8080",
+		Timeout:   10 * time.Second,
+		Transport: TransportUDP,
+		Naming:    NamingPath,
+		NameTemplates: NameTemplatesConfig{
+			Bucket:   "{{name}}_bucket",
+			Quantile: "{{name}}_quantile",
+			Count:    "{{name}}_count",
+			// Templates that are not set keep their default.
+			Sum: "{{name}}",
+		},
+		CumulativeToDelta: true,
 	}
 	assert.Equal(t, &expectedCfg, e1)
 
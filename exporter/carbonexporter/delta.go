@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonexporter
+
+import (
+	"sync"
+	"time"
+)
+
+// staleSeriesTimeout is how long the previous value of a series is kept after
+// it was last seen.
+const staleSeriesTimeout = 15 * time.Minute
+
+// deltaCalculator keeps the previous value of cumulative series to convert
+// them to the change since that value.
+type deltaCalculator struct {
+	mu     sync.Mutex
+	series map[string]*previousValue
+	// When stale series were last removed.
+	lastCleanup time.Time
+	// For easier unit testing
+	now func() time.Time
+}
+
+type previousValue struct {
+	int64Value  int64
+	doubleValue float64
+	lastSeen    time.Time
+}
+
+func newDeltaCalculator() *deltaCalculator {
+	return &deltaCalculator{
+		series:      make(map[string]*previousValue),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// int64Delta returns the change of the series identified by path since its
+// previous value. It returns false if there is no previous value. If the value
+// decreased the series is assumed to have been reset and the value itself is
+// returned.
+func (dc *deltaCalculator) int64Delta(path string, value int64) (int64, bool) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	prev, ok := dc.series[path]
+	if !ok {
+		dc.series[path] = &previousValue{int64Value: value, lastSeen: dc.now()}
+		return 0, false
+	}
+
+	delta := value - prev.int64Value
+	if delta < 0 {
+		delta = value
+	}
+	prev.int64Value = value
+	prev.lastSeen = dc.now()
+	return delta, true
+}
+
+// doubleDelta is the same as int64Delta for double series.
+func (dc *deltaCalculator) doubleDelta(path string, value float64) (float64, bool) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	prev, ok := dc.series[path]
+	if !ok {
+		dc.series[path] = &previousValue{doubleValue: value, lastSeen: dc.now()}
+		return 0, false
+	}
+
+	delta := value - prev.doubleValue
+	if delta < 0 {
+		delta = value
+	}
+	prev.doubleValue = value
+	prev.lastSeen = dc.now()
+	return delta, true
+}
+
+// removeStale removes the series that were not seen in staleSeriesTimeout. To
+// avoid going over all series on every call it does nothing if called again
+// within a minute.
+func (dc *deltaCalculator) removeStale() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	now := dc.now()
+	if now.Sub(dc.lastCleanup) < time.Minute {
+		return
+	}
+	dc.lastCleanup = now
+
+	cutoff := now.Add(-staleSeriesTimeout)
+	for path, prev := range dc.series {
+		if prev.lastSeen.Before(cutoff) {
+			delete(dc.series, path)
+		}
+	}
+}
@@ -15,9 +15,11 @@
 package carbonexporter
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,10 +31,25 @@ import (
 
 // newCarbonExporter returns a new Carbon exporter.
 func newCarbonExporter(cfg *Config) (component.MetricsExporter, error) {
-	// Resolve TCP address just to ensure that it is a valid one. It is better
+	transport := cfg.Transport
+	if transport == "" {
+		transport = TransportTCP
+	}
+
+	// Resolve the address just to ensure that it is a valid one. It is better
 	// to fail here than at when the exporter is started.
-	if _, err := net.ResolveTCPAddr("tcp", cfg.Endpoint); err != nil {
-		return nil, fmt.Errorf("%q exporter has an invalid TCP endpoint: %w", cfg.Name(), err)
+	switch transport {
+	case TransportTCP:
+		if _, err := net.ResolveTCPAddr("tcp", cfg.Endpoint); err != nil {
+			return nil, fmt.Errorf("%q exporter has an invalid TCP endpoint: %w", cfg.Name(), err)
+		}
+	case TransportUDP:
+		if _, err := net.ResolveUDPAddr("udp", cfg.Endpoint); err != nil {
+			return nil, fmt.Errorf("%q exporter has an invalid UDP endpoint: %w", cfg.Name(), err)
+		}
+	default:
+		return nil, fmt.Errorf("%q exporter has an invalid transport %q, it must be %q or %q",
+			cfg.Name(), cfg.Transport, TransportTCP, TransportUDP)
 	}
 
 	// Negative timeouts are not acceptable, since all sends will fail.
@@ -40,8 +57,33 @@ func newCarbonExporter(cfg *Config) (component.MetricsExporter, error) {
 		return nil, fmt.Errorf("%q exporter requires a positive timeout", cfg.Name())
 	}
 
+	switch cfg.Naming {
+	case "", NamingTags, NamingPath:
+	default:
+		return nil, fmt.Errorf("%q exporter has an invalid naming %q, it must be %q or %q",
+			cfg.Name(), cfg.Naming, NamingTags, NamingPath)
+	}
+
+	templates := cfg.nameTemplates()
+	for _, template := range []string{templates.Bucket, templates.Quantile, templates.Count, templates.Sum} {
+		if !strings.Contains(template, NamePlaceholder) {
+			return nil, fmt.Errorf("%q exporter has an invalid name template %q, it must contain %q",
+				cfg.Name(), template, NamePlaceholder)
+		}
+	}
+
+	// The count and sum of histograms and summaries would be the same series.
+	if templates.Count == templates.Sum {
+		return nil, fmt.Errorf("%q exporter requires different count and sum name templates", cfg.Name())
+	}
+
 	sender := carbonSender{
-		connPool: newTCPConnPool(cfg.Endpoint, cfg.Timeout),
+		formatter: newPlaintextFormatter(cfg),
+	}
+	if transport == TransportUDP {
+		sender.writer = newUDPWriter(cfg.Endpoint, cfg.Timeout)
+	} else {
+		sender.writer = newTCPConnPool(cfg.Endpoint, cfg.Timeout)
 	}
 
 	return exporterhelper.NewMetricsExporter(
@@ -50,17 +92,24 @@ func newCarbonExporter(cfg *Config) (component.MetricsExporter, error) {
 		exporterhelper.WithShutdown(sender.Shutdown))
 }
 
-// carbonSender is the struct tying the translation function and the TCP
-// connections into an implementations of exporterhelper.PushMetricsData so
-// the exporter can leverage the helper and get consistent observability.
+// carbonWriter sends Carbon plaintext lines to the backend.
+type carbonWriter interface {
+	Write(bytes []byte) (int, error)
+	Close()
+}
+
+// carbonSender is the struct tying the translation function and the Carbon
+// writer into an implementations of exporterhelper.PushMetricsData so the
+// exporter can leverage the helper and get consistent observability.
 type carbonSender struct {
-	connPool *connPool
+	writer    carbonWriter
+	formatter *plaintextFormatter
 }
 
 func (cs *carbonSender) pushMetricsData(_ context.Context, md pdata.Metrics) (int, error) {
-	lines, converted, dropped := metricDataToPlaintext(internaldata.MetricsToOC(md))
+	lines, converted, dropped := cs.formatter.metricDataToPlaintext(internaldata.MetricsToOC(md))
 
-	if _, err := cs.connPool.Write([]byte(lines)); err != nil {
+	if _, err := cs.writer.Write([]byte(lines)); err != nil {
 		// Use the sum of converted and dropped since the write failed for all.
 		return converted + dropped, err
 	}
@@ -69,7 +118,7 @@ func (cs *carbonSender) pushMetricsData(_ context.Context, md pdata.Metrics) (in
 }
 
 func (cs *carbonSender) Shutdown(context.Context) error {
-	cs.connPool.Close()
+	cs.writer.Close()
 	return nil
 }
 
@@ -175,3 +224,79 @@ func (cp *connPool) createTCPConn() (*net.TCPConn, error) {
 	}
 	return c.(*net.TCPConn), err
 }
+
+// udpMaxPacketSize is the maximum size of the datagrams sent over UDP. It
+// is small enough to avoid fragmentation on most networks.
+const udpMaxPacketSize = 1432
+
+// udpWriter sends Carbon lines over UDP. Lines are never split across
+// datagrams since Carbon parses each datagram independently.
+type udpWriter struct {
+	mtx      sync.Mutex
+	conn     net.Conn
+	endpoint string
+	timeout  time.Duration
+}
+
+func newUDPWriter(endpoint string, timeout time.Duration) *udpWriter {
+	return &udpWriter{
+		endpoint: endpoint,
+		timeout:  timeout,
+	}
+}
+
+func (uw *udpWriter) Write(lines []byte) (int, error) {
+	uw.mtx.Lock()
+	defer uw.mtx.Unlock()
+
+	if uw.conn == nil {
+		conn, err := net.DialTimeout("udp", uw.endpoint, uw.timeout)
+		if err != nil {
+			return 0, err
+		}
+		uw.conn = conn
+	}
+
+	if err := uw.conn.SetWriteDeadline(time.Now().Add(uw.timeout)); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for len(lines) > 0 {
+		packet := nextUDPPacket(lines)
+		n, err := uw.conn.Write(packet)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		lines = lines[len(packet):]
+	}
+	return written, nil
+}
+
+// nextUDPPacket returns the longest prefix of whole lines that fits in a
+// datagram. A single line longer than udpMaxPacketSize is returned on its own.
+func nextUDPPacket(lines []byte) []byte {
+	if len(lines) <= udpMaxPacketSize {
+		return lines
+	}
+
+	if i := bytes.LastIndexByte(lines[:udpMaxPacketSize], '\n'); i >= 0 {
+		return lines[:i+1]
+	}
+
+	if i := bytes.IndexByte(lines, '\n'); i >= 0 {
+		return lines[:i+1]
+	}
+	return lines
+}
+
+func (uw *udpWriter) Close() {
+	uw.mtx.Lock()
+	defer uw.mtx.Unlock()
+
+	if uw.conn != nil {
+		uw.conn.Close()
+		uw.conn = nil
+	}
+}
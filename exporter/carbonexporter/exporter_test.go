@@ -16,11 +16,13 @@ package carbonexporter
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -63,6 +65,52 @@ func TestNew(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "udp",
+			config: &Config{
+				Endpoint:  DefaultEndpoint,
+				Transport: TransportUDP,
+			},
+		},
+		{
+			name: "invalid_transport",
+			config: &Config{
+				Endpoint:  DefaultEndpoint,
+				Transport: "unix",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_naming",
+			config: &Config{
+				Endpoint: DefaultEndpoint,
+				Naming:   "labels",
+			},
+			wantErr: true,
+		},
+		{
+			name: "partial_name_templates",
+			config: &Config{
+				Endpoint:      DefaultEndpoint,
+				NameTemplates: NameTemplatesConfig{Bucket: "{{name}}_bucket"},
+			},
+		},
+		{
+			name: "name_template_without_name",
+			config: &Config{
+				Endpoint:      DefaultEndpoint,
+				NameTemplates: NameTemplatesConfig{Bucket: "bucket"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same_count_and_sum_name_templates",
+			config: &Config{
+				Endpoint:      DefaultEndpoint,
+				NameTemplates: NameTemplatesConfig{Count: "{{name}}"},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -218,7 +266,7 @@ func Test_connPool_Concurrency(t *testing.T) {
 	startCh := make(chan struct{})
 
 	cp := newTCPConnPool(addr, 500*time.Millisecond)
-	sender := carbonSender{connPool: cp}
+	sender := carbonSender{writer: cp, formatter: newPlaintextFormatter(&Config{})}
 	ctx := context.Background()
 	md := generateLargeBatch()
 	concurrentWriters := 3
@@ -280,7 +328,54 @@ func Test_connPool_Concurrency(t *testing.T) {
 	recvWG.Wait()
 }
 
+func TestConsumeMetricsDataUDP(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	require.NoError(t, err)
+	ln, err := net.ListenUDP("udp", laddr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	config := &Config{Endpoint: addr, Timeout: time.Second, Transport: TransportUDP}
+	exp, err := newCarbonExporter(config)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	md := generateBatch(100)
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
+	assert.NoError(t, exp.Shutdown(context.Background()))
+
+	lines := 0
+	buf := make([]byte, 65536)
+	require.NoError(t, ln.SetReadDeadline(time.Now().Add(time.Second)))
+	for lines < 100 {
+		n, err := ln.Read(buf)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, n, udpMaxPacketSize)
+		// Lines are never split across datagrams.
+		assert.Equal(t, byte('\n'), buf[n-1])
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+	}
+	assert.Equal(t, 100, lines)
+}
+
+func Test_nextUDPPacket(t *testing.T) {
+	short := []byte("a 1 1\nb 2 2\n")
+	assert.Equal(t, short, nextUDPPacket(short))
+
+	line := []byte(strings.Repeat("a", udpMaxPacketSize/2) + " 1 1\n")
+	lines := append(append(append([]byte{}, line...), line...), line...)
+	assert.Equal(t, line, nextUDPPacket(lines))
+
+	long := []byte(strings.Repeat("a", udpMaxPacketSize) + " 1 1\n")
+	assert.Equal(t, long, nextUDPPacket(append(append([]byte{}, long...), short...)))
+}
+
 func generateLargeBatch() pdata.Metrics {
+	return generateBatch(65000)
+}
+
+func generateBatch(size int) pdata.Metrics {
 	md := consumerdata.MetricsData{
 		Node: &commonpb.Node{
 			ServiceInfo: &commonpb.ServiceInfo{Name: "test_carbon"},
@@ -289,7 +384,7 @@ func generateLargeBatch() pdata.Metrics {
 	}
 
 	ts := time.Now()
-	for i := 0; i < 65000; i++ {
+	for i := 0; i < size; i++ {
 		md.Metrics = append(md.Metrics,
 			metricstestutil.Gauge(
 				"test_"+strconv.Itoa(i),
@@ -41,8 +41,9 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		Endpoint: DefaultEndpoint,
-		Timeout:  DefaultSendTimeout,
+		Endpoint:      DefaultEndpoint,
+		Timeout:       DefaultSendTimeout,
+		NameTemplates: DefaultNameTemplates(),
 	}
 }
 
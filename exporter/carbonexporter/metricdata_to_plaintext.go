@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -33,25 +34,43 @@ const (
 	tagValueEmptyPlaceholder  = "<empty>"
 	tagValueNotSetPlaceholder = "<null>"
 
-	// Constants used when converting from distribution metrics to Carbon format.
-	distributionBucketSuffix             = ".bucket"
-	distributionUpperBoundTagKey         = "upper_bound"
-	distributionUpperBoundTagBeforeValue = tagPrefix + distributionUpperBoundTagKey + tagKeyValueSeparator
+	// Path node separator used when labels are written to the path.
+	pathNodeSeparator = "."
 
-	// Constants used when converting from summary metrics to Carbon format.
-	summaryQuantileSuffix         = ".quantile"
-	summaryQuantileTagKey         = "quantile"
-	summaryQuantileTagBeforeValue = tagPrefix + summaryQuantileTagKey + tagKeyValueSeparator
+	// Tag key used when converting from distribution metrics to Carbon format.
+	distributionUpperBoundTagKey = "upper_bound"
 
-	// Suffix to be added to original metric name for a Carbon metric representing
-	// a count metric for either distribution or summary metrics.
-	countSuffix = ".count"
+	// Tag key used when converting from summary metrics to Carbon format.
+	summaryQuantileTagKey = "quantile"
 
 	// Textual representation for positive infinity valid in Carbon, ie.:
 	// positive infinity as represented in Python.
 	infinityCarbonValue = "inf"
 )
 
+// plaintextFormatter converts metrics to the Carbon plaintext format according
+// to the exporter configuration.
+type plaintextFormatter struct {
+	naming    string
+	templates NameTemplatesConfig
+	// deltas is nil unless cumulative metrics are converted to deltas.
+	deltas *deltaCalculator
+}
+
+func newPlaintextFormatter(cfg *Config) *plaintextFormatter {
+	f := &plaintextFormatter{
+		naming:    cfg.Naming,
+		templates: cfg.nameTemplates(),
+	}
+	if f.naming == "" {
+		f.naming = NamingTags
+	}
+	if cfg.CumulativeToDelta {
+		f.deltas = newDeltaCalculator()
+	}
+	return f
+}
+
 // metricDataToPlaintext converts internal metrics data to the Carbon plaintext
 // format as defined in https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol)
 // and https://graphite.readthedocs.io/en/latest/tags.html#carbon. See details
@@ -72,6 +91,13 @@ const (
 // <tag> is of the form "key=val", where key can contain any char except ";!^=" and
 // val can contain any char except ";~".
 //
+// If the formatter uses the path naming the labels are instead appended to
+// the metric name as path nodes:
+//
+// 	<metric_name>[.key0.val0...keyN.valN]
+//
+// where keys and values have any '.' and whitespace replaced.
+//
 // The <value> is the textual representation of the metric value.
 //
 // The <timestamp> is the Unix time text of when the measurement was made.
@@ -81,7 +107,7 @@ const (
 // 	  a single Carbon metric.
 //  - number of time series successfully converted to carbon.
 // 	- number of time series that could not be converted to Carbon.
-func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
+func (f *plaintextFormatter) metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 	if len(mds) == 0 {
 		return "", 0, 0
 	}
@@ -100,7 +126,9 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 				continue
 			}
 
-			tagKeys := buildSanitizedTagKeys(metric.MetricDescriptor.LabelKeys)
+			tagKeys := f.buildSanitizedTagKeys(metric.MetricDescriptor.LabelKeys)
+			isCumulative := descriptor.GetType() == metricspb.MetricDescriptor_CUMULATIVE_INT64 ||
+				descriptor.GetType() == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
 
 			for _, ts := range metric.Timeseries {
 				if len(tagKeys) != len(ts.LabelValues) {
@@ -123,17 +151,29 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 					switch pv := point.Value.(type) {
 
 					case *metricspb.Point_Int64Value:
-						path := buildPath(name, tagKeys, ts.LabelValues)
-						valueStr := formatInt64(pv.Int64Value)
-						sb.WriteString(buildLine(path, valueStr, timestampStr))
+						path := f.buildPath(name, tagKeys, ts.LabelValues)
+						value := pv.Int64Value
+						if isCumulative && f.deltas != nil {
+							var ok bool
+							if value, ok = f.deltas.int64Delta(path, value); !ok {
+								continue
+							}
+						}
+						sb.WriteString(buildLine(path, formatInt64(value), timestampStr))
 
 					case *metricspb.Point_DoubleValue:
-						path := buildPath(name, tagKeys, ts.LabelValues)
-						valueStr := formatFloatForValue(pv.DoubleValue)
-						sb.WriteString(buildLine(path, valueStr, timestampStr))
+						path := f.buildPath(name, tagKeys, ts.LabelValues)
+						value := pv.DoubleValue
+						if isCumulative && f.deltas != nil {
+							var ok bool
+							if value, ok = f.deltas.doubleDelta(path, value); !ok {
+								continue
+							}
+						}
+						sb.WriteString(buildLine(path, formatFloatForValue(value), timestampStr))
 
 					case *metricspb.Point_DistributionValue:
-						err := buildDistributionIntoBuilder(
+						err := f.buildDistributionIntoBuilder(
 							&sb, name, tagKeys, ts.LabelValues, timestampStr, pv.DistributionValue)
 						if err != nil {
 							// TODO: log error info
@@ -141,7 +181,7 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 						}
 
 					case *metricspb.Point_SummaryValue:
-						err := buildSummaryIntoBuilder(
+						err := f.buildSummaryIntoBuilder(
 							&sb, name, tagKeys, ts.LabelValues, timestampStr, pv.SummaryValue)
 						if err != nil {
 							// TODO: log error info
@@ -153,6 +193,10 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 		}
 	}
 
+	if f.deltas != nil {
+		f.deltas.removeStale()
+	}
+
 	return sb.String(), totalTimeseries - numTimeseriesDropped, numTimeseriesDropped
 }
 
@@ -170,7 +214,9 @@ func metricDataToPlaintext(mds []consumerdata.MetricsData) (string, int, int) {
 // and will include a dimension "upper_bound" that specifies the maximum value in
 // that bucket. This metric specifies the number of events with a value that is
 // less than or equal to the upper bound.
-func buildDistributionIntoBuilder(
+//
+// The names above are the default ones and can be configured.
+func (f *plaintextFormatter) buildDistributionIntoBuilder(
 	sb *strings.Builder,
 	metricName string,
 	tagKeys []string,
@@ -178,7 +224,7 @@ func buildDistributionIntoBuilder(
 	timestampStr string,
 	distributionValue *metricspb.DistributionValue,
 ) error {
-	buildCountAndSumIntoBuilder(
+	f.buildCountAndSumIntoBuilder(
 		sb,
 		metricName,
 		tagKeys,
@@ -201,10 +247,10 @@ func buildDistributionIntoBuilder(
 	}
 	carbonBounds[len(carbonBounds)-1] = infinityCarbonValue
 
-	bucketPath := buildPath(metricName+distributionBucketSuffix, tagKeys, labelValues)
+	bucketPath := f.buildPath(seriesName(f.templates.Bucket, metricName), tagKeys, labelValues)
 	for i, bucket := range distributionValue.Buckets {
 		sb.WriteString(buildLine(
-			bucketPath+distributionUpperBoundTagBeforeValue+carbonBounds[i],
+			f.appendTag(bucketPath, distributionUpperBoundTagKey, carbonBounds[i]),
 			formatInt64(bucket.Count),
 			timestampStr))
 	}
@@ -224,7 +270,9 @@ func buildDistributionIntoBuilder(
 //
 // 3. Each quantile is represented by a metric named "<metricName>.quantile"
 // and will include a tag key "quantile" that specifies the quantile value.
-func buildSummaryIntoBuilder(
+//
+// The names above are the default ones and can be configured.
+func (f *plaintextFormatter) buildSummaryIntoBuilder(
 	sb *strings.Builder,
 	metricName string,
 	tagKeys []string,
@@ -232,7 +280,7 @@ func buildSummaryIntoBuilder(
 	timestampStr string,
 	summaryValue *metricspb.SummaryValue,
 ) error {
-	buildCountAndSumIntoBuilder(
+	f.buildCountAndSumIntoBuilder(
 		sb,
 		metricName,
 		tagKeys,
@@ -248,10 +296,10 @@ func buildSummaryIntoBuilder(
 			metricName)
 	}
 
-	quantilePath := buildPath(metricName+summaryQuantileSuffix, tagKeys, labelValues)
+	quantilePath := f.buildPath(seriesName(f.templates.Quantile, metricName), tagKeys, labelValues)
 	for _, quantile := range percentiles {
 		sb.WriteString(buildLine(
-			quantilePath+summaryQuantileTagBeforeValue+formatFloatForLabel(quantile.GetPercentile()),
+			f.appendTag(quantilePath, summaryQuantileTagKey, formatFloatForLabel(quantile.GetPercentile())),
 			formatFloatForValue(quantile.GetValue()),
 			timestampStr))
 	}
@@ -267,7 +315,8 @@ func buildSummaryIntoBuilder(
 //
 // 2. The total sum will be represented by a metruc with the original "<metricName>".
 //
-func buildCountAndSumIntoBuilder(
+// The names above are the default ones and can be configured.
+func (f *plaintextFormatter) buildCountAndSumIntoBuilder(
 	sb *strings.Builder,
 	metricName string,
 	tagKeys []string,
@@ -277,19 +326,25 @@ func buildCountAndSumIntoBuilder(
 	timestampStr string,
 ) {
 	// Build count and sum metrics.
-	countPath := buildPath(metricName+countSuffix, tagKeys, labelValues)
+	countPath := f.buildPath(seriesName(f.templates.Count, metricName), tagKeys, labelValues)
 	valueStr := formatInt64(count)
 	sb.WriteString(buildLine(countPath, valueStr, timestampStr))
 
-	sumPath := buildPath(metricName, tagKeys, labelValues)
+	sumPath := f.buildPath(seriesName(f.templates.Sum, metricName), tagKeys, labelValues)
 	valueStr = formatFloatForValue(sum)
 	sb.WriteString(buildLine(sumPath, valueStr, timestampStr))
 }
 
+// seriesName returns the name of a series generated for the given metric name
+// from a name template.
+func seriesName(template, metricName string) string {
+	return strings.ReplaceAll(template, NamePlaceholder, metricName)
+}
+
 // buildPath is used to build the <metric_path> per description above. It
 // assumes that the caller code already checked that len(tagKeys) is equal to
 // len(labelValues) and as such cannot fail to build the path.
-func buildPath(
+func (f *plaintextFormatter) buildPath(
 	name string,
 	tagKeys []string,
 	labelValues []*metricspb.LabelValue,
@@ -314,31 +369,55 @@ func buildPath(
 				value = tagValueNotSetPlaceholder
 			}
 		default:
-			value = sanitizeTagValue(value)
+			value = f.sanitizeValue(value)
 		}
 
-		sb.WriteString(tagPrefix + tagKeys[i] + tagKeyValueSeparator + value)
+		sb.WriteString(f.tag(tagKeys[i], value))
 	}
 
 	return sb.String()
 }
 
+// appendTag appends a tag, whose key is already sanitized, to the path.
+func (f *plaintextFormatter) appendTag(path, key, value string) string {
+	return path + f.tag(key, f.sanitizeValue(value))
+}
+
+func (f *plaintextFormatter) tag(key, value string) string {
+	if f.naming == NamingPath {
+		return pathNodeSeparator + key + pathNodeSeparator + value
+	}
+	return tagPrefix + key + tagKeyValueSeparator + value
+}
+
 // buildSanitizedTagKeys builds an slice with the sanitized label keys to be
 // used as tag keys on the Carbon metric.
-func buildSanitizedTagKeys(labelKeys []*metricspb.LabelKey) []string {
+func (f *plaintextFormatter) buildSanitizedTagKeys(labelKeys []*metricspb.LabelKey) []string {
 	if len(labelKeys) == 0 {
 		return nil
 	}
 
 	tagKeys := make([]string, 0, len(labelKeys))
 	for _, labelKey := range labelKeys {
-		tagKey := sanitizeTagKey(labelKey.Key)
+		tagKey := labelKey.Key
+		if f.naming == NamingPath {
+			tagKey = sanitizePathNode(tagKey)
+		} else {
+			tagKey = sanitizeTagKey(tagKey)
+		}
 		tagKeys = append(tagKeys, tagKey)
 	}
 
 	return tagKeys
 }
 
+func (f *plaintextFormatter) sanitizeValue(value string) string {
+	if f.naming == NamingPath {
+		return sanitizePathNode(value)
+	}
+	return sanitizeTagValue(value)
+}
+
 // buildLine builds a single Carbon metric textual line, ie.: it already adds
 // a new-line character at the end of the string.
 func buildLine(path, value, timestamp string) string {
@@ -375,6 +454,21 @@ func sanitizeTagValue(value string) string {
 	return strings.Map(mapRune, value)
 }
 
+// sanitizePathNode removes any character that would change the structure of
+// the path from a path node, ie.: ".;" and whitespace.
+func sanitizePathNode(node string) string {
+	mapRune := func(r rune) rune {
+		switch {
+		case r == '.', r == ';', unicode.IsSpace(r):
+			return sanitizedRune
+		default:
+			return r
+		}
+	}
+
+	return strings.Map(mapRune, node)
+}
+
 // Formats a float64 per Prometheus label value. This is an attempt to keep other
 // the label values with different formats of metrics.
 func formatFloatForLabel(f float64) string {
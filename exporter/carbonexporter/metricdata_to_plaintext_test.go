@@ -131,7 +131,7 @@ func Test_buildPath(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildPath(tt.args.name, tt.args.tagKeys, tt.args.labelValues)
+			got := newPlaintextFormatter(&Config{}).buildPath(tt.args.name, tt.args.tagKeys, tt.args.labelValues)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -277,7 +277,7 @@ func Test_metricDataToPlaintext(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLines, gotNunConvertedTimeseries, gotNumDroppedTimeseries := metricDataToPlaintext(tt.metricsDataFn())
+			gotLines, gotNunConvertedTimeseries, gotNumDroppedTimeseries := newPlaintextFormatter(&Config{}).metricDataToPlaintext(tt.metricsDataFn())
 			assert.Equal(t, tt.wantNumConvertedTimeseries, gotNunConvertedTimeseries)
 			assert.Equal(t, tt.wantNumDroppedTimeseries, gotNumDroppedTimeseries)
 			got := strings.Split(gotLines, "\n")
@@ -287,6 +287,99 @@ func Test_metricDataToPlaintext(t *testing.T) {
 	}
 }
 
+func Test_metricDataToPlaintext_PathNamingAndNameTemplates(t *testing.T) {
+	tsUnix := time.Unix(1574092046, 0)
+	keys := []string{"k.0", "k 1"}
+	values := []string{"v.0", "v;1"}
+
+	f := newPlaintextFormatter(&Config{
+		Naming: NamingPath,
+		NameTemplates: NameTemplatesConfig{
+			Bucket:   "{{name}}_bucket",
+			Quantile: "{{name}}_quantile",
+			Count:    "hist.{{name}}",
+			Sum:      "{{name}}_sum",
+		},
+	})
+	mds := []consumerdata.MetricsData{
+		{
+			Metrics: []*metricspb.Metric{
+				metricstestutil.Gauge("gauge", keys, metricstestutil.Timeseries(tsUnix, values, metricstestutil.Double(tsUnix, 1.5))),
+				metricstestutil.GaugeDist("distrib", keys, metricstestutil.Timeseries(
+					tsUnix, values, metricstestutil.DistPt(tsUnix, []float64{1.5}, []int64{4, 2}))),
+				metricstestutil.Summary("summary", keys, metricstestutil.Timeseries(
+					tsUnix, values, metricstestutil.SummPt(tsUnix, 11, 111, []float64{99.9}, []float64{1}))),
+			},
+		},
+	}
+
+	gotLines, converted, dropped := f.metricDataToPlaintext(mds)
+	assert.Equal(t, 3, converted)
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, []string{
+		"gauge.k_0.v_0.k_1.v_1 1.5 1574092046",
+		"hist.distrib.k_0.v_0.k_1.v_1 6 1574092046",
+		"distrib_sum.k_0.v_0.k_1.v_1 3 1574092046",
+		"distrib_bucket.k_0.v_0.k_1.v_1.upper_bound.1_5 4 1574092046",
+		"distrib_bucket.k_0.v_0.k_1.v_1.upper_bound.inf 2 1574092046",
+		"hist.summary.k_0.v_0.k_1.v_1 11 1574092046",
+		"summary_sum.k_0.v_0.k_1.v_1 111 1574092046",
+		"summary_quantile.k_0.v_0.k_1.v_1.quantile.99_9 1 1574092046",
+	}, strings.Split(strings.TrimSuffix(gotLines, "\n"), "\n"))
+}
+
+func Test_metricDataToPlaintext_CumulativeToDelta(t *testing.T) {
+	f := newPlaintextFormatter(&Config{CumulativeToDelta: true})
+	keys := []string{"k0"}
+
+	mdsAt := func(secs int64, intVal int64, doubleVal float64) []consumerdata.MetricsData {
+		ts := time.Unix(secs, 0)
+		return []consumerdata.MetricsData{
+			{
+				Metrics: []*metricspb.Metric{
+					metricstestutil.CumulativeInt("requests", keys, metricstestutil.Timeseries(ts, []string{"v0"}, &metricspb.Point{
+						Timestamp: metricstestutil.Timestamp(ts),
+						Value:     &metricspb.Point_Int64Value{Int64Value: intVal},
+					})),
+					metricstestutil.Cumulative("bytes", keys, metricstestutil.Timeseries(ts, []string{"v0"}, metricstestutil.Double(ts, doubleVal))),
+					metricstestutil.Gauge("temperature", keys, metricstestutil.Timeseries(ts, []string{"v0"}, metricstestutil.Double(ts, doubleVal))),
+				},
+			},
+		}
+	}
+
+	// The first point of cumulative series has no previous value to compare.
+	gotLines, converted, dropped := f.metricDataToPlaintext(mdsAt(10, 5, 2.5))
+	assert.Equal(t, 3, converted)
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, "temperature;k0=v0 2.5 10\n", gotLines)
+
+	gotLines, _, _ = f.metricDataToPlaintext(mdsAt(20, 12, 4))
+	assert.Equal(t, "requests;k0=v0 7 20\nbytes;k0=v0 1.5 20\ntemperature;k0=v0 4 20\n", gotLines)
+
+	// A decrease means the cumulative series was reset.
+	gotLines, _, _ = f.metricDataToPlaintext(mdsAt(30, 3, 1))
+	assert.Equal(t, "requests;k0=v0 3 30\nbytes;k0=v0 1 30\ntemperature;k0=v0 1 30\n", gotLines)
+}
+
+func Test_deltaCalculator_removeStale(t *testing.T) {
+	dc := newDeltaCalculator()
+	now := time.Unix(1000, 0)
+	dc.now = func() time.Time { return now }
+	dc.lastCleanup = now
+
+	_, ok := dc.int64Delta("a", 1)
+	assert.False(t, ok)
+	now = now.Add(10 * time.Minute)
+	_, ok = dc.int64Delta("b", 1)
+	assert.False(t, ok)
+
+	now = now.Add(10 * time.Minute)
+	dc.removeStale()
+	assert.Len(t, dc.series, 1)
+	assert.Contains(t, dc.series, "b")
+}
+
 func expectedDistributionLines(
 	metricName, tags, timestampStr string,
 	sum float64,
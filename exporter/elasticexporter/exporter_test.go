@@ -25,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm/model"
 	"go.elastic.co/apm/transport/transporttest"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -56,6 +57,35 @@ func TestTraceExporter(t *testing.T) {
 	assert.Equal(t, "foobar", payloads.Transactions[0].Name)
 }
 
+func TestMetricsExporter(t *testing.T) {
+	factory := NewFactory()
+	recorder, cfg := newRecorder(t)
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	me, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, me, "failed to create metrics exporter")
+
+	metrics := pdata.NewMetrics()
+	resourceMetrics := metrics.ResourceMetrics()
+	resourceMetrics.Resize(1)
+	resourceMetrics.At(0).InitEmpty()
+	resourceMetrics.At(0).InstrumentationLibraryMetrics().Resize(1)
+	resourceMetrics.At(0).InstrumentationLibraryMetrics().At(0).Metrics().Resize(1)
+	metric := resourceMetrics.At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	metric.SetName("foobar")
+	metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	metric.DoubleGauge().InitEmpty()
+	metric.DoubleGauge().DataPoints().Resize(1)
+	metric.DoubleGauge().DataPoints().At(0).SetValue(123)
+
+	err = me.ConsumeMetrics(context.Background(), metrics)
+	assert.NoError(t, err)
+
+	payloads := recorder.Payloads()
+	require.Len(t, payloads.Metrics, 1)
+	assert.Equal(t, map[string]model.Metric{"foobar": {Value: 123}}, payloads.Metrics[0].Samples)
+}
+
 // newRecorder returns a go.elastic.co/apm/transport/transporrtest.RecorderTransport,
 // and an exporter config that sends to an HTTP server that will record events in the
 // Elastic APM format.
@@ -32,7 +32,8 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(createTraceExporter))
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithMetrics(createMetricsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -51,3 +52,11 @@ func createTraceExporter(
 ) (component.TraceExporter, error) {
 	return newElasticTraceExporter(params, cfg)
 }
+
+func createMetricsExporter(
+	ctx context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	return newElasticMetricsExporter(params, cfg)
+}
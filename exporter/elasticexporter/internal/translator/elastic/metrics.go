@@ -0,0 +1,282 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package elastic contains an opentelemetry-collector exporter
+// for Elastic APM.
+package elastic
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/model"
+	"go.elastic.co/fastjson"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// EncodeMetrics encodes an OpenTelemetry metrics slice as metricset lines,
+// writing to w. Data points sharing a timestamp and a label set are written
+// to the same metricset, with one sample per metric name: the last data point
+// of a name wins. NaN and infinite values can't be encoded in JSON, the data
+// points and histogram buckets holding them are skipped.
+//
+// The number of data points found in otlpMetrics is returned.
+func EncodeMetrics(otlpMetrics pdata.MetricSlice, w *fastjson.Writer) (int, error) {
+	var metricsets metricsets
+	for i := 0; i < otlpMetrics.Len(); i++ {
+		metric := otlpMetrics.At(i)
+		if metric.IsNil() {
+			continue
+		}
+		metricsets.add(metric)
+	}
+	for _, ms := range metricsets.metricsets {
+		if err := ms.encode(w); err != nil {
+			return metricsets.samples, err
+		}
+	}
+	return metricsets.samples, nil
+}
+
+type metricsets struct {
+	metricsets []*metricset
+	index      map[metricsetKey]*metricset
+	samples    int
+}
+
+type metricsetKey struct {
+	timestamp pdata.TimestampUnixNano
+	labels    string
+}
+
+type metricset struct {
+	timestamp time.Time
+	labels    model.StringMap
+	samples   []sample
+	// names indexes samples by name.
+	names map[string]int
+}
+
+type sample struct {
+	name string
+
+	// value is set for gauges and sums.
+	value float64
+
+	// values and counts are set for histograms.
+	histogram bool
+	values    []float64
+	counts    []uint64
+}
+
+func (ms *metricsets) add(metric pdata.Metric) {
+	name := metric.Name()
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if metric.IntGauge().IsNil() {
+			break
+		}
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), sample{name: name, value: float64(dp.Value())})
+			}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if metric.DoubleGauge().IsNil() {
+			break
+		}
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), sample{name: name, value: dp.Value()})
+			}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().IsNil() {
+			break
+		}
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), sample{name: name, value: float64(dp.Value())})
+			}
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().IsNil() {
+			break
+		}
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), sample{name: name, value: dp.Value()})
+			}
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if metric.IntHistogram().IsNil() {
+			break
+		}
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), histogramSample(
+					name, dp.Count(), float64(dp.Sum()), dp.BucketCounts(), dp.ExplicitBounds(),
+				))
+			}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if metric.DoubleHistogram().IsNil() {
+			break
+		}
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				ms.upsert(dp.Timestamp(), dp.LabelsMap(), histogramSample(
+					name, dp.Count(), dp.Sum(), dp.BucketCounts(), dp.ExplicitBounds(),
+				))
+			}
+		}
+	}
+}
+
+func (ms *metricsets) upsert(timestamp pdata.TimestampUnixNano, labelsMap pdata.StringMap, sample sample) {
+	ms.samples++
+	if !sample.histogram && !isFinite(sample.value) {
+		return
+	}
+
+	labels := make(model.StringMap, 0, labelsMap.Len())
+	labelsMap.ForEach(func(k string, v pdata.StringValue) {
+		labels = append(labels, model.StringMapItem{Key: cleanLabelKey(k), Value: truncate(v.Value())})
+	})
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+
+	var keyBuilder strings.Builder
+	for _, label := range labels {
+		keyBuilder.WriteString(label.Key)
+		keyBuilder.WriteByte(0)
+		keyBuilder.WriteString(label.Value)
+		keyBuilder.WriteByte(0)
+	}
+	key := metricsetKey{timestamp: timestamp, labels: keyBuilder.String()}
+
+	if ms.index == nil {
+		ms.index = make(map[metricsetKey]*metricset)
+	}
+	m, ok := ms.index[key]
+	if !ok {
+		m = &metricset{
+			timestamp: time.Unix(0, int64(timestamp)).UTC(),
+			labels:    labels,
+			names:     make(map[string]int),
+		}
+		ms.index[key] = m
+		ms.metricsets = append(ms.metricsets, m)
+	}
+	if i, ok := m.names[sample.name]; ok {
+		m.samples[i] = sample
+		return
+	}
+	m.names[sample.name] = len(m.samples)
+	m.samples = append(m.samples, sample)
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// histogramSample returns a sample in the APM Server histogram representation,
+// where each populated bucket is represented by its midpoint and count. The
+// first and last buckets are unbounded, they are represented by their only
+// bound. Histograms without bounds are represented by their mean.
+func histogramSample(name string, count uint64, sum float64, bucketCounts []uint64, explicitBounds []float64) sample {
+	s := sample{name: name, histogram: true}
+	if len(explicitBounds) == 0 || len(bucketCounts) != len(explicitBounds)+1 {
+		if mean := sum / float64(count); count > 0 && isFinite(mean) {
+			s.values = []float64{mean}
+			s.counts = []uint64{count}
+		}
+		return s
+	}
+	for i, bucketCount := range bucketCounts {
+		if bucketCount == 0 {
+			continue
+		}
+		var value float64
+		switch i {
+		case 0:
+			value = explicitBounds[0]
+		case len(explicitBounds):
+			value = explicitBounds[i-1]
+		default:
+			value = explicitBounds[i-1] + (explicitBounds[i]-explicitBounds[i-1])/2
+		}
+		if !isFinite(value) {
+			continue
+		}
+		s.values = append(s.values, value)
+		s.counts = append(s.counts, bucketCount)
+	}
+	return s
+}
+
+func (m *metricset) encode(w *fastjson.Writer) error {
+	w.RawString(`{"metricset":{"samples":{`)
+	for i, sample := range m.samples {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		w.String(sample.name)
+		w.RawByte(':')
+		sample.encode(w)
+	}
+	w.RawString(`},"timestamp":`)
+	if err := model.Time(m.timestamp).MarshalFastJSON(w); err != nil {
+		return err
+	}
+	if len(m.labels) > 0 {
+		w.RawString(`,"tags":`)
+		if err := m.labels.MarshalFastJSON(w); err != nil {
+			return err
+		}
+	}
+	w.RawString("}}\n")
+	return nil
+}
+
+func (s *sample) encode(w *fastjson.Writer) {
+	if !s.histogram {
+		w.RawString(`{"value":`)
+		w.Float64(s.value)
+		w.RawByte('}')
+		return
+	}
+	w.RawString(`{"type":"histogram","values":[`)
+	for i, value := range s.values {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		w.Float64(value)
+	}
+	w.RawString(`],"counts":[`)
+	for i, count := range s.counts {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		w.Uint64(count)
+	}
+	w.RawString("]}")
+}
@@ -0,0 +1,215 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elastic_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm/model"
+	"go.elastic.co/apm/transport/transporttest"
+	"go.elastic.co/fastjson"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticexporter/internal/translator/elastic"
+)
+
+func TestEncodeMetrics(t *testing.T) {
+	var w fastjson.Writer
+	var recorder transporttest.RecorderTransport
+	elastic.EncodeResourceMetadata(pdata.NewResource(), &w)
+
+	timestamp0 := time.Unix(123, 0).UTC()
+	timestamp1 := time.Unix(456, 0).UTC()
+
+	metrics := pdata.NewMetricSlice()
+	appendMetric := func(name string, dataType pdata.MetricDataType) pdata.Metric {
+		metric := pdata.NewMetric()
+		metric.InitEmpty()
+		metric.SetName(name)
+		metric.SetDataType(dataType)
+		metrics.Append(metric)
+		return metric
+	}
+
+	intGauge := appendMetric("int_gauge_metric", pdata.MetricDataTypeIntGauge)
+	intGauge.IntGauge().InitEmpty()
+	intGauge.IntGauge().DataPoints().Resize(3)
+	intGauge.IntGauge().DataPoints().At(0).SetTimestamp(pdata.TimestampUnixNano(timestamp0.UnixNano()))
+	intGauge.IntGauge().DataPoints().At(0).SetValue(1)
+	intGauge.IntGauge().DataPoints().At(1).SetTimestamp(pdata.TimestampUnixNano(timestamp1.UnixNano()))
+	intGauge.IntGauge().DataPoints().At(1).SetValue(2)
+	intGauge.IntGauge().DataPoints().At(1).LabelsMap().InitFromMap(map[string]string{"k": "v"})
+	intGauge.IntGauge().DataPoints().At(2).SetTimestamp(pdata.TimestampUnixNano(timestamp1.UnixNano()))
+	intGauge.IntGauge().DataPoints().At(2).SetValue(3)
+	intGauge.IntGauge().DataPoints().At(2).LabelsMap().InitFromMap(map[string]string{"k": "v2"})
+
+	doubleSum := appendMetric("double_sum_metric", pdata.MetricDataTypeDoubleSum)
+	doubleSum.DoubleSum().InitEmpty()
+	doubleSum.DoubleSum().DataPoints().Resize(1)
+	doubleSum.DoubleSum().DataPoints().At(0).SetTimestamp(pdata.TimestampUnixNano(timestamp1.UnixNano()))
+	doubleSum.DoubleSum().DataPoints().At(0).SetValue(4.5)
+	doubleSum.DoubleSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"k": "v"})
+
+	count, err := elastic.EncodeMetrics(metrics, &w)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	sendStream(t, &w, &recorder)
+
+	payloads := recorder.Payloads()
+	assert.Equal(t, []model.Metrics{{
+		Timestamp: model.Time(timestamp0),
+		Samples: map[string]model.Metric{
+			"int_gauge_metric": {Value: 1},
+		},
+	}, {
+		Timestamp: model.Time(timestamp1),
+		Labels:    model.StringMap{{Key: "k", Value: "v"}},
+		Samples: map[string]model.Metric{
+			"int_gauge_metric":  {Value: 2},
+			"double_sum_metric": {Value: 4.5},
+		},
+	}, {
+		Timestamp: model.Time(timestamp1),
+		Labels:    model.StringMap{{Key: "k", Value: "v2"}},
+		Samples: map[string]model.Metric{
+			"int_gauge_metric": {Value: 3},
+		},
+	}}, payloads.Metrics)
+}
+
+func TestEncodeMetricsHistogram(t *testing.T) {
+	metrics := pdata.NewMetricSlice()
+	metrics.Resize(2)
+
+	doubleHistogram := metrics.At(0)
+	doubleHistogram.SetName("double_histogram_metric")
+	doubleHistogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	doubleHistogram.DoubleHistogram().InitEmpty()
+	doubleHistogram.DoubleHistogram().DataPoints().Resize(1)
+	dp := doubleHistogram.DoubleHistogram().DataPoints().At(0)
+	dp.SetCount(6)
+	dp.SetSum(120)
+	dp.SetExplicitBounds([]float64{10, 20, 50})
+	dp.SetBucketCounts([]uint64{1, 2, 0, 3})
+
+	intHistogram := metrics.At(1)
+	intHistogram.SetName("int_histogram_metric")
+	intHistogram.SetDataType(pdata.MetricDataTypeIntHistogram)
+	intHistogram.IntHistogram().InitEmpty()
+	intHistogram.IntHistogram().DataPoints().Resize(1)
+	intDP := intHistogram.IntHistogram().DataPoints().At(0)
+	intDP.SetCount(4)
+	intDP.SetSum(10)
+
+	// The first bucket is represented by its bound, even when it isn't positive.
+	negativeHistogram := pdata.NewMetric()
+	negativeHistogram.InitEmpty()
+	negativeHistogram.SetName("negative_histogram_metric")
+	negativeHistogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	negativeHistogram.DoubleHistogram().InitEmpty()
+	negativeHistogram.DoubleHistogram().DataPoints().Resize(1)
+	negativeDP := negativeHistogram.DoubleHistogram().DataPoints().At(0)
+	negativeDP.SetCount(3)
+	negativeDP.SetSum(-25)
+	negativeDP.SetExplicitBounds([]float64{-10, 0, 10})
+	negativeDP.SetBucketCounts([]uint64{2, 1, 0, 0})
+	metrics.Append(negativeHistogram)
+
+	var w fastjson.Writer
+	count, err := elastic.EncodeMetrics(metrics, &w)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	lines := strings.Split(strings.TrimSpace(string(w.Bytes())), "\n")
+	require.Len(t, lines, 1)
+
+	var decoded struct {
+		Metricset struct {
+			Samples map[string]struct {
+				Type   string    `json:"type"`
+				Values []float64 `json:"values"`
+				Counts []uint64  `json:"counts"`
+			} `json:"samples"`
+		} `json:"metricset"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+
+	samples := decoded.Metricset.Samples
+	require.Len(t, samples, 3)
+	assert.Equal(t, "histogram", samples["double_histogram_metric"].Type)
+	assert.Equal(t, []float64{10, 15, 50}, samples["double_histogram_metric"].Values)
+	assert.Equal(t, []uint64{1, 2, 3}, samples["double_histogram_metric"].Counts)
+	assert.Equal(t, "histogram", samples["int_histogram_metric"].Type)
+	assert.Equal(t, []float64{2.5}, samples["int_histogram_metric"].Values)
+	assert.Equal(t, []uint64{4}, samples["int_histogram_metric"].Counts)
+	assert.Equal(t, []float64{-10, -5}, samples["negative_histogram_metric"].Values)
+	assert.Equal(t, []uint64{2, 1}, samples["negative_histogram_metric"].Counts)
+}
+
+func TestEncodeMetricsDuplicateAndNonFinite(t *testing.T) {
+	metrics := pdata.NewMetricSlice()
+	appendGauge := func(name string, value float64) {
+		metric := pdata.NewMetric()
+		metric.InitEmpty()
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		metric.DoubleGauge().InitEmpty()
+		metric.DoubleGauge().DataPoints().Resize(1)
+		metric.DoubleGauge().DataPoints().At(0).SetValue(value)
+		metrics.Append(metric)
+	}
+	appendGauge("gauge", 1)
+	appendGauge("gauge", 2)
+	appendGauge("nan", math.NaN())
+	appendGauge("inf", math.Inf(-1))
+
+	histogram := pdata.NewMetric()
+	histogram.InitEmpty()
+	histogram.SetName("histogram")
+	histogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	histogram.DoubleHistogram().InitEmpty()
+	histogram.DoubleHistogram().DataPoints().Resize(1)
+	dp := histogram.DoubleHistogram().DataPoints().At(0)
+	dp.SetCount(3)
+	dp.SetSum(math.Inf(1))
+	dp.SetExplicitBounds([]float64{10, math.Inf(1)})
+	dp.SetBucketCounts([]uint64{1, 0, 2})
+	metrics.Append(histogram)
+
+	var w fastjson.Writer
+	count, err := elastic.EncodeMetrics(metrics, &w)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	lines := strings.Split(strings.TrimSpace(string(w.Bytes())), "\n")
+	require.Len(t, lines, 1)
+
+	var decoded struct {
+		Metricset struct {
+			Samples map[string]json.RawMessage `json:"samples"`
+		} `json:"metricset"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, map[string]json.RawMessage{
+		"gauge":     json.RawMessage(`{"value":2}`),
+		"histogram": json.RawMessage(`{"type":"histogram","values":[10],"counts":[1]}`),
+	}, decoded.Metricset.Samples)
+}
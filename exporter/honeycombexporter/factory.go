@@ -32,7 +32,8 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(createTraceExporter))
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithMetrics(createMetricsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -57,3 +58,12 @@ func createTraceExporter(
 	eCfg := cfg.(*Config)
 	return newHoneycombTraceExporter(eCfg, params.Logger)
 }
+
+func createMetricsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	eCfg := cfg.(*Config)
+	return newHoneycombMetricsExporter(eCfg, params.Logger)
+}
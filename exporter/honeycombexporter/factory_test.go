@@ -63,6 +63,6 @@ func TestCreateMetricsExporter(t *testing.T) {
 
 	params := component.ExporterCreateParams{Logger: zap.NewNop()}
 	exporter, err := factory.CreateMetricsExporter(context.Background(), params, cfg.Exporters["honeycomb/customname"])
-	assert.NotNil(t, err)
-	assert.Nil(t, exporter)
+	assert.Nil(t, err)
+	assert.NotNil(t, exporter)
 }
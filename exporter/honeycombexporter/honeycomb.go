@@ -16,18 +16,14 @@ package honeycombexporter
 
 import (
 	"context"
-	"strings"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/honeycombio/libhoney-go"
 	"github.com/honeycombio/libhoney-go/transmission"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 )
 
@@ -36,8 +32,13 @@ const (
 	oTelCollectorUserAgentStr = "Honeycomb-OpenTelemetry-Collector"
 )
 
+// sourceFormat is sent as the "source_format" field of spans, the value set by
+// the collector when it converted the spans to OpenCensus before sending them.
+const sourceFormat = "otlp_trace"
+
 // honeycombExporter is the object that sends events to honeycomb.
 type honeycombExporter struct {
+	client  *libhoney.Client
 	builder *libhoney.Builder
 	onError func(error)
 	logger  *zap.Logger
@@ -54,7 +55,7 @@ type event struct {
 	DurationMilli   float64 `json:"duration_ms"`
 }
 
-// spanEvent represents an event attached to a specific span.¬
+// spanEvent represents an event attached to a specific span.
 type spanEvent struct {
 	Name           string `json:"name"`
 	TraceID        string `json:"trace.trace_id"`
@@ -67,23 +68,18 @@ type spanEvent struct {
 // TraceID and ParentID are used to identify the span with which the trace is associated
 // We are modeling Links for now as child spans rather than properties of the event.
 type link struct {
-	TraceID        string      `json:"trace.trace_id"`
-	ParentID       string      `json:"trace.parent_id,omitempty"`
-	LinkTraceID    string      `json:"trace.link.trace_id"`
-	LinkSpanID     string      `json:"trace.link.span_id"`
-	AnnotationType string      `json:"meta.annotation_type"`
-	RefType        spanRefType `json:"ref_type,omitempty"`
+	TraceID        string `json:"trace.trace_id"`
+	ParentID       string `json:"trace.parent_id,omitempty"`
+	LinkTraceID    string `json:"trace.link.trace_id"`
+	LinkSpanID     string `json:"trace.link.span_id"`
+	AnnotationType string `json:"meta.annotation_type"`
 }
 
-// spanRefType defines the relationship a Link has to a trace or a span. It can
-// either be a child of, or a follows from relationship.
-type spanRefType int64
-
-// newHoneycombTraceExporter creates and returns a new honeycombExporter. It
-// wraps the exporter in the component.TraceExporterOld helper method.
-func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.TraceExporter, error) {
-	libhoneyConfig := libhoney.Config{
-		WriteKey:   cfg.APIKey,
+// newHoneycombExporter creates a honeycombExporter with its own libhoney
+// client. Each trace or metrics exporter created by the factory builds one.
+func newHoneycombExporter(cfg *Config, logger *zap.Logger) (*honeycombExporter, error) {
+	libhoneyConfig := libhoney.ClientConfig{
+		APIKey:     cfg.APIKey,
 		Dataset:    cfg.Dataset,
 		APIHost:    cfg.APIURL,
 		SampleRate: cfg.SampleRate,
@@ -95,16 +91,26 @@ func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.Trace
 		libhoneyConfig.Logger = &libhoney.DefaultLogger{}
 	}
 
-	if err := libhoney.Init(libhoneyConfig); err != nil {
+	client, err := libhoney.NewClient(libhoneyConfig)
+	if err != nil {
 		return nil, err
 	}
-	builder := libhoney.NewBuilder()
-	exporter := &honeycombExporter{
-		builder: builder,
+	return &honeycombExporter{
+		client:  client,
+		builder: client.NewBuilder(),
 		logger:  logger,
 		onError: func(err error) {
 			logger.Warn(err.Error())
 		},
+	}, nil
+}
+
+// newHoneycombTraceExporter creates and returns a new honeycombExporter. It
+// wraps the exporter in the component.TraceExporter helper method.
+func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.TraceExporter, error) {
+	exporter, err := newHoneycombExporter(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	return exporterhelper.NewTraceExporter(
@@ -113,6 +119,21 @@ func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.Trace
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
 
+// newHoneycombMetricsExporter creates and returns a new honeycombExporter
+// that writes every metric data point as an event. It wraps the exporter in
+// the component.MetricsExporter helper method.
+func newHoneycombMetricsExporter(cfg *Config, logger *zap.Logger) (component.MetricsExporter, error) {
+	exporter, err := newHoneycombExporter(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewMetricsExporter(
+		cfg,
+		exporter.pushMetricsData,
+		exporterhelper.WithShutdown(exporter.Shutdown))
+}
+
 // pushTraceData is the method called when trace data is available. It will be
 // responsible for sending a batch of events.
 func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
@@ -122,71 +143,49 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 	// Run the error logger. This just listens for messages in the error
 	// response queue and writes them out using the logger.
 	ctx, cancel := context.WithCancel(ctx)
-	go e.RunErrorLogger(ctx, libhoney.TxResponses())
+	go e.RunErrorLogger(ctx, e.client.TxResponses())
 	defer cancel()
 
-	octds := internaldata.TraceDataToOC(td)
-	for _, octd := range octds {
+	// Children are only counted within the batch, the count of a span whose
+	// children are exported in other batches is lower than its actual count.
+	childSpanCounts := getChildSpanCounts(td)
 
-		// Extract Node and Resource attributes, labels and other information.
-		// Because these exist on the TraceData, they will be added to every span.
-		traceLevelFields := getTraceLevelFields(octd.Node, octd.Resource, octd.SourceFormat)
-		addTraceLevelFields := func(ev *libhoney.Event, tlf map[string]interface{}) {
-			for k, v := range tlf {
-				ev.AddField(k, v)
-			}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
 		}
 
-		for _, span := range octd.Spans {
-			ev := e.builder.NewEvent()
+		// Extract resource attributes. Because these exist on the resource,
+		// they will be added to every span.
+		resourceFields := getResourceFields(rs.Resource())
 
-			tlf := traceLevelFields
-			// If Resource present need to recalculate traceLevelFields
-			if span.Resource != nil {
-				tlf = getTraceLevelFields(octd.Node, span.Resource, octd.SourceFormat)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
 			}
-			addTraceLevelFields(ev, tlf)
 
-			if len(span.GetParentSpanId()) == 0 || hasRemoteParent(span) {
-				if octd.Node != nil {
-					for k, v := range octd.Node.Attributes {
-						ev.AddField(k, v)
-					}
-				}
+			traceFields := getInstrumentationLibraryFields(ils.InstrumentationLibrary())
+			for k, v := range resourceFields {
+				traceFields[k] = v
 			}
+			traceFields["source_format"] = sourceFormat
 
-			if attrs := spanAttributesToMap(span.GetAttributes()); attrs != nil {
-				for k, v := range attrs {
-					ev.AddField(k, v)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
 				}
-			}
 
-			ev.Timestamp = timestampToTime(span.GetStartTime())
-			startTime := timestampToTime(span.GetStartTime())
-			endTime := timestampToTime(span.GetEndTime())
-
-			ev.Add(event{
-				ID:              getHoneycombSpanID(span.GetSpanId()),
-				TraceID:         getHoneycombTraceID(span.GetTraceId()),
-				ParentID:        getHoneycombSpanID(span.GetParentSpanId()),
-				Name:            truncatableStringAsString(span.GetName()),
-				DurationMilli:   float64(endTime.Sub(startTime)) / float64(time.Millisecond),
-				HasRemoteParent: hasRemoteParent(span),
-			})
-
-			e.sendMessageEvents(octd, span, tlf)
-			e.sendSpanLinks(span)
-
-			ev.AddField("span_kind", strings.ToLower(span.Kind.String()))
-			ev.AddField("status.code", getStatusCode(span.Status))
-			ev.AddField("status.message", getStatusMessage(span.Status))
-			ev.AddField("has_remote_parent", !span.GetSameProcessAsParentSpan().GetValue())
-			ev.AddField("child_span_count", span.GetChildSpanCount())
-
-			if err := ev.SendPresampled(); err != nil {
-				errs = append(errs, err)
-			} else {
-				goodSpans++
+				if err := e.sendSpan(span, traceFields, childSpanCounts); err != nil {
+					errs = append(errs, err)
+				} else {
+					goodSpans++
+				}
 			}
 		}
 	}
@@ -194,27 +193,62 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 	return td.SpanCount() - goodSpans, componenterror.CombineErrors(errs)
 }
 
+// sendSpan sends a single span, along with its events and links, to Honeycomb.
+func (e *honeycombExporter) sendSpan(span pdata.Span, traceFields map[string]interface{}, childSpanCounts map[string]int) error {
+	ev := e.builder.NewEvent()
+
+	for k, v := range traceFields {
+		ev.AddField(k, v)
+	}
+	for k, v := range attributesToMap(span.Attributes()) {
+		ev.AddField(k, v)
+	}
+
+	startTime := timestampToTime(span.StartTime())
+	endTime := timestampToTime(span.EndTime())
+	ev.Timestamp = startTime
+
+	ev.Add(event{
+		ID:              getHoneycombSpanID(span.SpanID().Bytes()),
+		TraceID:         getHoneycombTraceID(span.TraceID().Bytes()),
+		ParentID:        getHoneycombSpanID(span.ParentSpanID().Bytes()),
+		Name:            span.Name(),
+		DurationMilli:   float64(endTime.Sub(startTime)) / float64(time.Millisecond),
+		HasRemoteParent: hasRemoteParent(span),
+	})
+
+	e.sendSpanEvents(span, traceFields)
+	e.sendSpanLinks(span)
+
+	ev.AddField("span_kind", getSpanKind(span.Kind()))
+	ev.AddField("status.code", getStatusCode(span.Status()))
+	ev.AddField("status.message", getStatusMessage(span.Status()))
+	ev.AddField("has_remote_parent", hasRemoteParent(span))
+	ev.AddField("child_span_count", childSpanCounts[getHoneycombSpanID(span.SpanID().Bytes())])
+
+	return ev.SendPresampled()
+}
+
 // sendSpanLinks gets the list of links associated with this span and sends them as
 // separate events to Honeycomb, with a span type "link".
-func (e *honeycombExporter) sendSpanLinks(span *tracepb.Span) {
-	links := span.GetLinks()
+func (e *honeycombExporter) sendSpanLinks(span pdata.Span) {
+	links := span.Links()
 
-	if links == nil {
-		return
-	}
+	for i := 0; i < links.Len(); i++ {
+		l := links.At(i)
+		if l.IsNil() {
+			continue
+		}
 
-	for _, l := range links.GetLink() {
 		ev := e.builder.NewEvent()
 		ev.Add(link{
-			TraceID:        getHoneycombTraceID(span.GetTraceId()),
-			ParentID:       getHoneycombSpanID(span.GetSpanId()),
-			LinkTraceID:    getHoneycombTraceID(l.GetTraceId()),
-			LinkSpanID:     getHoneycombSpanID(l.GetSpanId()),
+			TraceID:        getHoneycombTraceID(span.TraceID().Bytes()),
+			ParentID:       getHoneycombSpanID(span.SpanID().Bytes()),
+			LinkTraceID:    getHoneycombTraceID(l.TraceID().Bytes()),
+			LinkSpanID:     getHoneycombSpanID(l.SpanID().Bytes()),
 			AnnotationType: "link",
-			RefType:        spanRefType(l.GetType()),
 		})
-		attrs := spanAttributesToMap(l.GetAttributes())
-		for k, v := range attrs {
+		for k, v := range attributesToMap(l.Attributes()) {
 			ev.AddField(k, v)
 		}
 		if err := ev.SendPresampled(); err != nil {
@@ -223,45 +257,31 @@ func (e *honeycombExporter) sendSpanLinks(span *tracepb.Span) {
 	}
 }
 
-// sendMessageEvents gets the list of timeevents from the span and sends them as
+// sendSpanEvents gets the list of events from the span and sends them as
 // separate events to Honeycomb, with a span type "span_event".
-func (e *honeycombExporter) sendMessageEvents(td consumerdata.TraceData, span *tracepb.Span, traceFields map[string]interface{}) {
-	timeEvents := span.GetTimeEvents()
-	if timeEvents == nil {
-		return
-	}
+func (e *honeycombExporter) sendSpanEvents(span pdata.Span, traceFields map[string]interface{}) {
+	events := span.Events()
 
-	for _, event := range timeEvents.TimeEvent {
-		annotation := event.GetAnnotation()
-		if annotation == nil {
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() {
 			continue
 		}
 
-		ts := timestampToTime(event.GetTime())
-		name := annotation.GetDescription().GetValue()
-		attrs := spanAttributesToMap(annotation.GetAttributes())
-
-		// treat trace level fields as underlays with same keyed span attributes taking precedence.
+		// treat trace level fields as underlays with same keyed event attributes taking precedence.
 		ev := e.builder.NewEvent()
 		for k, v := range traceFields {
 			ev.AddField(k, v)
 		}
-		if len(span.GetParentSpanId()) == 0 || hasRemoteParent(span) {
-			if td.Node != nil {
-				for k, v := range td.Node.Attributes {
-					ev.AddField(k, v)
-				}
-			}
-		}
-		for k, v := range attrs {
+		for k, v := range attributesToMap(event.Attributes()) {
 			ev.AddField(k, v)
 		}
-		ev.Timestamp = ts
+		ev.Timestamp = timestampToTime(event.Timestamp())
 		ev.Add(spanEvent{
-			Name:           name,
-			TraceID:        getHoneycombTraceID(span.GetTraceId()),
-			ParentID:       getHoneycombSpanID(span.GetSpanId()),
-			ParentName:     truncatableStringAsString(span.GetName()),
+			Name:           event.Name(),
+			TraceID:        getHoneycombTraceID(span.TraceID().Bytes()),
+			ParentID:       getHoneycombSpanID(span.SpanID().Bytes()),
+			ParentName:     span.Name(),
 			AnnotationType: "span_event",
 		})
 		if err := ev.SendPresampled(); err != nil {
@@ -274,7 +294,7 @@ func (e *honeycombExporter) sendMessageEvents(td consumerdata.TraceData, span *t
 // this case, we close the honeycomb sdk which flushes any events still in the
 // queue and closes any open channels between queues.
 func (e *honeycombExporter) Shutdown(context.Context) error {
-	libhoney.Close()
+	e.client.Close()
 	return nil
 }
 
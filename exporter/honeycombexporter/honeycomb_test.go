@@ -26,6 +26,7 @@ import (
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -92,105 +93,99 @@ func testTraceExporter(td pdata.Traces, t *testing.T) []honeycombData {
 	return got
 }
 
-func TestExporter(t *testing.T) {
-	td := consumerdata.TraceData{
-		Node: &commonpb.Node{
-			ServiceInfo: &commonpb.ServiceInfo{Name: "test_service"},
-			Attributes: map[string]string{
-				"A": "B",
-			},
-		},
-		Resource: &resourcepb.Resource{
-			Type: "foobar",
-			Labels: map[string]string{
-				"B": "C",
-			},
-		},
-		Spans: []*tracepb.Span{
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "root"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"span_attr_name": {
-							Value: &tracepb.AttributeValue_StringValue{
-								StringValue: &tracepb.TruncatableString{Value: "Span Attribute"},
-							},
-						},
-					},
-				},
-				Resource: &resourcepb.Resource{
-					Type: "override",
-					Labels: map[string]string{
-						"B": "D",
-					},
-				},
-				TimeEvents: &tracepb.Span_TimeEvents{
-					TimeEvent: []*tracepb.Span_TimeEvent{
-						{
-							Time: &timestamppb.Timestamp{
-								Seconds: 0,
-								Nanos:   0,
-							},
-							Value: &tracepb.Span_TimeEvent_Annotation_{
-								Annotation: &tracepb.Span_TimeEvent_Annotation{
-									Description: &tracepb.TruncatableString{Value: "Some Description"},
-									Attributes: &tracepb.Span_Attributes{
-										AttributeMap: map[string]*tracepb.AttributeValue{
-											"attribute_name": {
-												Value: &tracepb.AttributeValue_StringValue{
-													StringValue: &tracepb.TruncatableString{Value: "Hello MessageEvent"},
-												},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x03},
-				ParentSpanId:            []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "client"},
-				Kind:                    tracepb.Span_CLIENT,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-				Links: &tracepb.Span_Links{
-					Link: []*tracepb.Span_Link{
-						{
-							TraceId: []byte{0x04},
-							SpanId:  []byte{0x05},
-							Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
-							Attributes: &tracepb.Span_Attributes{
-								AttributeMap: map[string]*tracepb.AttributeValue{
-									"span_link_attr": {
-										Value: &tracepb.AttributeValue_IntValue{
-											IntValue: 12345,
-										},
-									},
-								},
-							},
-						},
-					},
-					DroppedLinksCount: 0,
-				},
-			},
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x04},
-				ParentSpanId:            []byte{0x03},
-				Name:                    &tracepb.TruncatableString{Value: "server"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: false},
-			},
-		},
+func testMetricsExporter(md pdata.Metrics, t *testing.T) []honeycombData {
+	var got []honeycombData
+	server := testingServer(func(data []honeycombData) {
+		got = append(got, data...)
+	})
+	defer server.Close()
+	cfg := Config{
+		APIKey:     "test",
+		Dataset:    "test",
+		APIURL:     server.URL,
+		Debug:      false,
+		SampleRate: 1,
 	}
-	got := testTraceExporter(internaldata.OCToTraceData(td), t)
+
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exporter, err := createMetricsExporter(context.Background(), params, &cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = exporter.ConsumeMetrics(ctx, md)
+	require.NoError(t, err)
+	exporter.Shutdown(context.Background())
+
+	return got
+}
+
+// sortData orders events by their JSON representation so that comparisons
+// do not depend on the order in which the SDK batched them.
+var sortData = cmpopts.SortSlices(func(a, b honeycombData) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) < string(bj)
+})
+
+func TestExporter(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("test_service"),
+		"A":            pdata.NewAttributeValueString("B"),
+		"B":            pdata.NewAttributeValueString("C"),
+	})
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().InitEmpty()
+	ils.InstrumentationLibrary().SetName("test_library")
+	ils.InstrumentationLibrary().SetVersion("1.0.0")
+	ils.Spans().Resize(3)
+
+	root := ils.Spans().At(0)
+	root.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	root.SetSpanID(pdata.NewSpanID([]byte{0x02}))
+	root.SetName("root")
+	root.SetKind(pdata.SpanKindSERVER)
+	root.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"span_attr_name": pdata.NewAttributeValueString("Span Attribute"),
+		"span_attr_int":  pdata.NewAttributeValueInt(123),
+	})
+	root.Events().Resize(1)
+	root.Events().At(0).SetName("Some Description")
+	root.Events().At(0).Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"attribute_name": pdata.NewAttributeValueString("Hello MessageEvent"),
+	})
+
+	client := ils.Spans().At(1)
+	client.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	client.SetSpanID(pdata.NewSpanID([]byte{0x03}))
+	client.SetParentSpanID(pdata.NewSpanID([]byte{0x02}))
+	client.SetName("client")
+	client.SetKind(pdata.SpanKindCLIENT)
+	client.Status().InitEmpty()
+	client.Status().SetCode(pdata.StatusCodeUnavailable)
+	client.Links().Resize(1)
+	client.Links().At(0).SetTraceID(pdata.NewTraceID([]byte{0x04}))
+	client.Links().At(0).SetSpanID(pdata.NewSpanID([]byte{0x05}))
+	client.Links().At(0).Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"span_link_attr": pdata.NewAttributeValueInt(12345),
+	})
+
+	server := ils.Spans().At(2)
+	server.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	server.SetSpanID(pdata.NewSpanID([]byte{0x04}))
+	server.SetParentSpanID(pdata.NewSpanID([]byte{0x03}))
+	server.SetName("server")
+	server.SetKind(pdata.SpanKindSERVER)
+	server.Attributes().InsertBool("opencensus.same_process_as_parent_span", false)
+	server.Status().InitEmpty()
+	server.Status().SetCode(pdata.StatusCodeNotFound)
+	server.Status().SetMessage("no such thing")
+
+	got := testTraceExporter(td, t)
 	want := []honeycombData{
 		{
 			Data: map[string]interface{}{
@@ -204,112 +199,121 @@ func TestExporter(t *testing.T) {
 		},
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "client",
-				"service_name":                           "test_service",
-				"span_kind":                              "client",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.parent_id":                        "02",
-				"trace.span_id":                          "03",
-				"trace.trace_id":                         "01",
-				"opencensus.resourcetype":                "foobar",
-				"opencensus.same_process_as_parent_span": true,
-				"A":                                      "B",
-				"B":                                      "C",
+				"source_format":     "otlp_trace",
+				"child_span_count":  float64(1),
+				"duration_ms":       float64(0),
+				"has_remote_parent": false,
+				"name":              "client",
+				"service_name":      "test_service",
+				"service.name":      "test_service",
+				"library.name":      "test_library",
+				"library.version":   "1.0.0",
+				"span_kind":         "client",
+				"status.code":       float64(14),
+				"status.message":    "Unavailable",
+				"trace.parent_id":   "02",
+				"trace.span_id":     "03",
+				"trace.trace_id":    "01",
+				"A":                 "B",
+				"B":                 "C",
 			},
 		},
 		{
 			Data: map[string]interface{}{
+				"source_format":                          "otlp_trace",
+				"child_span_count":                       float64(0),
 				"duration_ms":                            float64(0),
 				"has_remote_parent":                      true,
 				"name":                                   "server",
+				"opencensus.same_process_as_parent_span": false,
 				"service_name":                           "test_service",
+				"service.name":                           "test_service",
+				"library.name":                           "test_library",
+				"library.version":                        "1.0.0",
 				"span_kind":                              "server",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
+				"status.code":                            float64(5),
+				"status.message":                         "no such thing",
 				"trace.parent_id":                        "03",
 				"trace.span_id":                          "04",
 				"trace.trace_id":                         "01",
 				"A":                                      "B",
 				"B":                                      "C",
-				"opencensus.resourcetype":                "foobar",
-				"opencensus.same_process_as_parent_span": false,
 			},
 		},
 		{
 			Data: map[string]interface{}{
-				"A":                       "B",
-				"B":                       "D",
-				"attribute_name":          "Hello MessageEvent",
-				"meta.annotation_type":    "span_event",
-				"name":                    "Some Description",
-				"opencensus.resourcetype": "override",
-				"service_name":            "test_service",
-				"source_format":           "otlp_trace",
-				"trace.parent_id":         "02",
-				"trace.parent_name":       "root",
-				"trace.trace_id":          "01",
+				"source_format":        "otlp_trace",
+				"A":                    "B",
+				"B":                    "C",
+				"attribute_name":       "Hello MessageEvent",
+				"meta.annotation_type": "span_event",
+				"name":                 "Some Description",
+				"service_name":         "test_service",
+				"service.name":         "test_service",
+				"library.name":         "test_library",
+				"library.version":      "1.0.0",
+				"trace.parent_id":      "02",
+				"trace.parent_name":    "root",
+				"trace.trace_id":       "01",
 			},
 		},
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"service_name":                           "test_service",
-				"source_format":                          "otlp_trace",
-				"span_attr_name":                         "Span Attribute",
-				"span_kind":                              "server",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"A":                                      "B",
-				"B":                                      "D",
-				"opencensus.resourcetype":                "override",
-				"opencensus.same_process_as_parent_span": true,
+				"source_format":     "otlp_trace",
+				"child_span_count":  float64(1),
+				"duration_ms":       float64(0),
+				"has_remote_parent": false,
+				"name":              "root",
+				"service_name":      "test_service",
+				"service.name":      "test_service",
+				"library.name":      "test_library",
+				"library.version":   "1.0.0",
+				"span_attr_name":    "Span Attribute",
+				"span_attr_int":     float64(123),
+				"span_kind":         "server",
+				"status.code":       float64(0),
+				"status.message":    "OK",
+				"trace.span_id":     "02",
+				"trace.trace_id":    "01",
+				"A":                 "B",
+				"B":                 "C",
 			},
 		},
 	}
 
-	if diff := cmp.Diff(want, got); diff != "" {
+	if diff := cmp.Diff(want, got, sortData); diff != "" {
 		t.Errorf("otel span: (-want +got):\n%s", diff)
 	}
 }
 
-func TestEmptyNode(t *testing.T) {
-	td := consumerdata.TraceData{
-		Node: nil,
-		Spans: []*tracepb.Span{
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "root"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-			},
-		},
-	}
+func TestEmptyResource(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	span.SetSpanID(pdata.NewSpanID([]byte{0x02}))
+	span.SetName("root")
+	span.SetKind(pdata.SpanKindSERVER)
 
-	got := testTraceExporter(internaldata.OCToTraceData(td), t)
+	got := testTraceExporter(td, t)
 
 	want := []honeycombData{
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"source_format":                          "otlp_trace",
-				"span_kind":                              "server",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"opencensus.same_process_as_parent_span": true,
+				"source_format":     "otlp_trace",
+				"child_span_count":  float64(0),
+				"duration_ms":       float64(0),
+				"has_remote_parent": false,
+				"name":              "root",
+				"span_kind":         "server",
+				"status.code":       float64(0),
+				"status.message":    "OK",
+				"trace.span_id":     "02",
+				"trace.trace_id":    "01",
 			},
 		},
 	}
@@ -339,11 +343,12 @@ func TestNode(t *testing.T) {
 				},
 			},
 			expected: map[string]interface{}{
+				"source_format":                          "otlp_trace",
 				"B":                                      "C",
+				"child_span_count":                       float64(0),
 				"duration_ms":                            float64(0),
 				"has_remote_parent":                      false,
 				"name":                                   "root",
-				"source_format":                          "otlp_trace",
 				"span_kind":                              "server",
 				"status.code":                            float64(0),
 				"status.message":                         "OK",
@@ -352,8 +357,12 @@ func TestNode(t *testing.T) {
 				"opencensus.resourcetype":                "container",
 				"opencensus.same_process_as_parent_span": true,
 				"opencensus.start_timestamp":             "2020-09-08T20:15:12Z",
-				"process.hostname":                       "my-host",
+				"opencensus.starttime":                   "2020-09-08T20:15:12Z",
 				"process.pid":                            float64(123),
+				"opencensus.pid":                         float64(123),
+				"process.hostname":                       "my-host",
+				"host.hostname":                          "my-host",
+				"service.name":                           "test_service",
 				"service_name":                           "test_service",
 			},
 		},
@@ -363,11 +372,12 @@ func TestNode(t *testing.T) {
 				HostName: "my-host",
 			},
 			expected: map[string]interface{}{
+				"source_format":                          "otlp_trace",
 				"B":                                      "C",
+				"child_span_count":                       float64(0),
 				"duration_ms":                            float64(0),
 				"has_remote_parent":                      false,
 				"name":                                   "root",
-				"source_format":                          "otlp_trace",
 				"span_kind":                              "server",
 				"status.code":                            float64(0),
 				"status.message":                         "OK",
@@ -376,6 +386,8 @@ func TestNode(t *testing.T) {
 				"opencensus.resourcetype":                "container",
 				"opencensus.same_process_as_parent_span": true,
 				"process.hostname":                       "my-host",
+				"host.hostname":                          "my-host",
+				"service.name":                           "test_service",
 				"service_name":                           "test_service",
 			},
 		},
@@ -383,11 +395,12 @@ func TestNode(t *testing.T) {
 			name:       "nil_identifier",
 			identifier: nil,
 			expected: map[string]interface{}{
+				"source_format":                          "otlp_trace",
 				"B":                                      "C",
+				"child_span_count":                       float64(0),
 				"duration_ms":                            float64(0),
 				"has_remote_parent":                      false,
 				"name":                                   "root",
-				"source_format":                          "otlp_trace",
 				"span_kind":                              "server",
 				"status.code":                            float64(0),
 				"status.message":                         "OK",
@@ -395,6 +408,7 @@ func TestNode(t *testing.T) {
 				"trace.trace_id":                         "01",
 				"opencensus.resourcetype":                "container",
 				"opencensus.same_process_as_parent_span": true,
+				"service.name":                           "test_service",
 				"service_name":                           "test_service",
 			},
 		},
@@ -439,3 +453,87 @@ func TestNode(t *testing.T) {
 	}
 
 }
+
+func TestMetricsExporter(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("test_service"),
+		"host.name":    pdata.NewAttributeValueString("my-host"),
+	})
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(3)
+
+	gauge := metrics.At(0)
+	gauge.SetName("memory.used")
+	gauge.SetDataType(pdata.MetricDataTypeIntGauge)
+	gauge.IntGauge().InitEmpty()
+	gauge.IntGauge().DataPoints().Resize(1)
+	gauge.IntGauge().DataPoints().At(0).SetValue(1024)
+	gauge.IntGauge().DataPoints().At(0).SetTimestamp(pdata.TimestampUnixNano(1599596112000000000))
+	gauge.IntGauge().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"state": "used"})
+
+	sum := metrics.At(1)
+	sum.SetName("requests")
+	sum.SetDataType(pdata.MetricDataTypeDoubleSum)
+	sum.DoubleSum().InitEmpty()
+	sum.DoubleSum().DataPoints().Resize(2)
+	sum.DoubleSum().DataPoints().At(0).SetValue(1.5)
+	sum.DoubleSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"host.name": "override"})
+	sum.DoubleSum().DataPoints().At(1).SetValue(2.5)
+
+	histogram := metrics.At(2)
+	histogram.SetName("latency")
+	histogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	histogram.DoubleHistogram().InitEmpty()
+	histogram.DoubleHistogram().DataPoints().Resize(1)
+	histogram.DoubleHistogram().DataPoints().At(0).SetCount(3)
+	histogram.DoubleHistogram().DataPoints().At(0).SetSum(12.5)
+	histogram.DoubleHistogram().DataPoints().At(0).SetBucketCounts([]uint64{1, 2})
+	histogram.DoubleHistogram().DataPoints().At(0).SetExplicitBounds([]float64{5})
+
+	got := testMetricsExporter(md, t)
+	want := []honeycombData{
+		{
+			Data: map[string]interface{}{
+				"service.name": "test_service",
+				"service_name": "test_service",
+				"host.name":    "my-host",
+				"state":        "used",
+				"memory.used":  float64(1024),
+			},
+		},
+		{
+			Data: map[string]interface{}{
+				"service.name": "test_service",
+				"service_name": "test_service",
+				"host.name":    "override",
+				"requests":     1.5,
+			},
+		},
+		{
+			Data: map[string]interface{}{
+				"service.name": "test_service",
+				"service_name": "test_service",
+				"host.name":    "my-host",
+				"requests":     2.5,
+			},
+		},
+		{
+			Data: map[string]interface{}{
+				"service.name":  "test_service",
+				"service_name":  "test_service",
+				"host.name":     "my-host",
+				"latency.count": float64(3),
+				"latency.sum":   12.5,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got, sortData); diff != "" {
+		t.Errorf("otel metrics: (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package honeycombexporter
+
+import (
+	"context"
+
+	"github.com/honeycombio/libhoney-go"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// pushMetricsData is the method called when metric data is available. Every
+// data point is sent as its own event, with the resource attributes and the
+// data point labels as fields, and the value stored in a field named after the
+// metric. Histograms are written as "<name>.count" and "<name>.sum" fields.
+func (e *honeycombExporter) pushMetricsData(ctx context.Context, md pdata.Metrics) (int, error) {
+	var errs []error
+	_, numPoints := md.MetricAndDataPointCount()
+	goodPoints := 0
+
+	// Run the error logger. This just listens for messages in the error
+	// response queue and writes them out using the logger.
+	ctx, cancel := context.WithCancel(ctx)
+	go e.RunErrorLogger(ctx, e.client.TxResponses())
+	defer cancel()
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+
+		resourceFields := getResourceFields(rm.Resource())
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+
+			metricFields := getInstrumentationLibraryFields(ilm.InstrumentationLibrary())
+			for k, v := range resourceFields {
+				metricFields[k] = v
+			}
+
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+
+				sent, err := e.sendMetric(metric, metricFields)
+				goodPoints += sent
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	return numPoints - goodPoints, componenterror.CombineErrors(errs)
+}
+
+// dataPoint is implemented by the data points of every metric type.
+type dataPoint interface {
+	IsNil() bool
+	LabelsMap() pdata.StringMap
+	Timestamp() pdata.TimestampUnixNano
+}
+
+// sendMetric sends one event per data point of the given metric. It returns
+// the number of data points that were sent successfully.
+func (e *honeycombExporter) sendMetric(metric pdata.Metric, metricFields map[string]interface{}) (int, []error) {
+	var errs []error
+	sent := 0
+
+	n, at := getDataPoints(metric)
+	for i := 0; i < n; i++ {
+		dp := at(i)
+		if dp.IsNil() {
+			continue
+		}
+
+		ev := e.newMetricEvent(metricFields, dp.LabelsMap(), dp.Timestamp())
+		for k, v := range getDataPointFields(metric.Name(), dp) {
+			ev.AddField(k, v)
+		}
+		if err := ev.SendPresampled(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, errs
+}
+
+// getDataPoints returns the number of data points of the metric and a function
+// to access them.
+func getDataPoints(metric pdata.Metric) (int, func(int) dataPoint) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if m := metric.IntGauge(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if m := metric.DoubleGauge(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	case pdata.MetricDataTypeIntSum:
+		if m := metric.IntSum(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if m := metric.DoubleSum(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if m := metric.IntHistogram(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if m := metric.DoubleHistogram(); !m.IsNil() {
+			return m.DataPoints().Len(), func(i int) dataPoint { return m.DataPoints().At(i) }
+		}
+	}
+	return 0, nil
+}
+
+// getDataPointFields returns the fields holding the value of a data point.
+func getDataPointFields(name string, dp dataPoint) map[string]interface{} {
+	switch dp := dp.(type) {
+	case pdata.IntDataPoint:
+		return map[string]interface{}{name: dp.Value()}
+	case pdata.DoubleDataPoint:
+		return map[string]interface{}{name: dp.Value()}
+	case pdata.IntHistogramDataPoint:
+		return map[string]interface{}{name + ".count": dp.Count(), name + ".sum": dp.Sum()}
+	case pdata.DoubleHistogramDataPoint:
+		return map[string]interface{}{name + ".count": dp.Count(), name + ".sum": dp.Sum()}
+	}
+	return nil
+}
+
+// newMetricEvent creates an event carrying the resource fields and the
+// labels of a data point. Labels take precedence over resource attributes
+// with the same key.
+func (e *honeycombExporter) newMetricEvent(metricFields map[string]interface{}, labels pdata.StringMap, ts pdata.TimestampUnixNano) *libhoney.Event {
+	ev := e.builder.NewEvent()
+	for k, v := range metricFields {
+		ev.AddField(k, v)
+	}
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		ev.AddField(k, v.Value())
+	})
+	ev.Timestamp = timestampToTime(ts)
+	return ev
+}
@@ -17,146 +17,169 @@ package honeycombexporter
 import (
 	"time"
 
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// spanAttributesToMap converts an opencensus proto Span_Attributes object into a map
-// of strings to generic types usable for sending events to honeycomb.
-func spanAttributesToMap(spanAttrs *tracepb.Span_Attributes) map[string]interface{} {
-	var attrs map[string]interface{}
+// attributesToMap converts a pdata AttributeMap into a map of strings to
+// generic types usable for sending events to honeycomb. Scalar values keep
+// their native type, while maps and arrays are encoded as JSON strings since
+// honeycomb columns cannot hold nested values.
+func attributesToMap(attrMap pdata.AttributeMap) map[string]interface{} {
+	attrs := make(map[string]interface{}, attrMap.Len())
 
-	if spanAttrs == nil {
-		return attrs
-	}
-
-	attrMap := spanAttrs.GetAttributeMap()
-	if attrMap == nil {
-		return attrs
-	}
-
-	attrs = make(map[string]interface{}, len(attrMap))
-
-	for key, attributeValue := range attrMap {
-		switch val := attributeValue.Value.(type) {
-		case *tracepb.AttributeValue_StringValue:
-			attrs[key] = attributeValueAsString(attributeValue)
-		case *tracepb.AttributeValue_BoolValue:
-			attrs[key] = val.BoolValue
-		case *tracepb.AttributeValue_IntValue:
-			attrs[key] = val.IntValue
-		case *tracepb.AttributeValue_DoubleValue:
-			attrs[key] = val.DoubleValue
+	attrMap.ForEach(func(key string, value pdata.AttributeValue) {
+		if v, ok := attributeValueToInterface(value); ok {
+			attrs[key] = v
 		}
-	}
+	})
 	return attrs
 }
 
-// hasRemoteParent returns true if the this span is a child of a span in a different process.
-func hasRemoteParent(span *tracepb.Span) bool {
-	if sameProcess := span.GetSameProcessAsParentSpan(); sameProcess != nil {
-		return !sameProcess.Value
+// attributeValueToInterface returns the native go value of the given
+// attribute value. The second return value is false for null values.
+func attributeValueToInterface(value pdata.AttributeValue) (interface{}, bool) {
+	switch value.Type() {
+	case pdata.AttributeValueSTRING:
+		return value.StringVal(), true
+	case pdata.AttributeValueBOOL:
+		return value.BoolVal(), true
+	case pdata.AttributeValueINT:
+		return value.IntVal(), true
+	case pdata.AttributeValueDOUBLE:
+		return value.DoubleVal(), true
+	case pdata.AttributeValueMAP, pdata.AttributeValueARRAY:
+		return tracetranslator.AttributeValueToString(value, true), true
 	}
-	return false
+	return nil, false
 }
 
-// timestampToTime converts a protobuf timestamp into a time.Time.
-func timestampToTime(ts *timestamppb.Timestamp) (t time.Time) {
-	if ts == nil {
-		return
+// getResourceFields extracts the resource attributes that should be added as
+// underlays on every event generated from the resource. The service name and
+// the process of OpenCensus nodes are additionally exposed under the field
+// names they were sent as before, to keep existing queries working.
+func getResourceFields(resource pdata.Resource) map[string]interface{} {
+	if resource.IsNil() {
+		return map[string]interface{}{}
 	}
-	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
-}
 
-// getStatusCode returns the status code
-func getStatusCode(status *tracepb.Status) int32 {
-	if status != nil {
-		return int32(codes.Code(status.Code))
+	fields := attributesToMap(resource.Attributes())
+	legacyFields := map[string]string{
+		conventions.AttributeServiceName:        "service_name",
+		conventions.AttributeHostHostname:       "process.hostname",
+		conventions.OCAttributeProcessID:        "process.pid",
+		conventions.OCAttributeProcessStartTime: "opencensus.start_timestamp",
 	}
-
-	return int32(codes.OK)
-}
-
-// getStatusMessage returns the status message as a string
-func getStatusMessage(status *tracepb.Status) string {
-	if status != nil {
-		if len(status.GetMessage()) > 0 {
-			return status.GetMessage()
+	for key, legacyKey := range legacyFields {
+		if value, ok := fields[key]; ok {
+			fields[legacyKey] = value
 		}
 	}
+	return fields
+}
 
-	return codes.OK.String()
+// getInstrumentationLibraryFields returns the name and version of the
+// library that produced the telemetry, if known.
+func getInstrumentationLibraryFields(il pdata.InstrumentationLibrary) map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+	if il.IsNil() {
+		return fields
+	}
+	if il.Name() != "" {
+		fields["library.name"] = il.Name()
+	}
+	if il.Version() != "" {
+		fields["library.version"] = il.Version()
+	}
+	return fields
 }
 
-// getTraceLevelfFields extracts the node and resource fields from TraceData that
-// should be added as underlays on every span in a trace.
-func getTraceLevelFields(node *commonpb.Node, resource *resourcepb.Resource, sourceFormat string) map[string]interface{} {
-	fields := make(map[string]interface{})
+// hasRemoteParent returns true if the this span is a child of a span in a different process.
+// Only spans received as OpenCensus carry this information, as an attribute.
+func hasRemoteParent(span pdata.Span) bool {
+	if sameProcess, ok := span.Attributes().Get(conventions.OCAttributeSameProcessAsParentSpan); ok &&
+		sameProcess.Type() == pdata.AttributeValueBOOL {
+		return !sameProcess.BoolVal()
+	}
+	return false
+}
 
-	// we want to get the service_name and some opencensus fields that are set when
-	// run as an agent from the node.
-	nodeFields := func(node *commonpb.Node, fields map[string]interface{}) {
-		if node == nil {
-			return
-		}
-		if node.ServiceInfo != nil {
-			fields["service_name"] = node.ServiceInfo.Name
+// getChildSpanCounts returns the number of children of each span of the batch,
+// keyed by the Honeycomb span ID of the parent. Children sent in other batches
+// are not counted.
+func getChildSpanCounts(td pdata.Traces) map[string]int {
+	counts := map[string]int{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
 		}
-		if process := node.GetIdentifier(); process != nil {
-			if len(process.HostName) != 0 {
-				fields["process.hostname"] = process.HostName
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
 			}
-			if process.Pid != 0 {
-				fields["process.pid"] = process.Pid
-			}
-
-			startTime := timestampToTime(process.StartTimestamp)
-			if startTime != (time.Time{}) {
-				fields["opencensus.start_timestamp"] = startTime
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() || len(span.ParentSpanID().Bytes()) == 0 {
+					continue
+				}
+				counts[getHoneycombSpanID(span.ParentSpanID().Bytes())]++
 			}
 		}
 	}
+	return counts
+}
 
-	// all resource labels should be applied as trace level fields
-	resourceFields := func(resource *resourcepb.Resource, fields map[string]interface{}) {
-		if resource == nil {
-			return
-		}
-		resourceType := resource.GetType()
-		if resourceType != "" {
-			fields["opencensus.resourcetype"] = resourceType
-		}
-		for k, v := range resource.GetLabels() {
-			fields[k] = v
-		}
+// timestampToTime converts a pdata timestamp into a time.Time.
+func timestampToTime(ts pdata.TimestampUnixNano) (t time.Time) {
+	if ts == 0 {
+		return
 	}
+	return pdata.UnixNanoToTime(ts).UTC()
+}
 
-	nodeFields(node, fields)
-	resourceFields(resource, fields)
-
-	if sourceFormat := sourceFormat; len(sourceFormat) > 0 {
-		fields["source_format"] = sourceFormat
+// getSpanKind returns the lowercase name of the span kind, e.g. "server".
+func getSpanKind(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindINTERNAL:
+		return "internal"
+	case pdata.SpanKindSERVER:
+		return "server"
+	case pdata.SpanKindCLIENT:
+		return "client"
+	case pdata.SpanKindPRODUCER:
+		return "producer"
+	case pdata.SpanKindCONSUMER:
+		return "consumer"
+	default:
+		return "unspecified"
 	}
-
-	return fields
 }
 
-// attributeValueAsString converts a opencensus proto AttributeValue object into a string
-func attributeValueAsString(val *tracepb.AttributeValue) string {
-	if wrapper := val.GetStringValue(); wrapper != nil {
-		return wrapper.GetValue()
+// getStatusCode returns the status code
+func getStatusCode(status pdata.SpanStatus) int32 {
+	if !status.IsNil() {
+		return int32(status.Code())
 	}
 
-	return ""
+	return int32(codes.OK)
 }
 
-// truncatableStringAsString just returns the value part as a string. "" if s is nil
-func truncatableStringAsString(s *tracepb.TruncatableString) string {
-	if s != nil {
-		return s.GetValue()
+// getStatusMessage returns the status message as a string, falling back to
+// the name of the status code when no message was recorded.
+func getStatusMessage(status pdata.SpanStatus) string {
+	if !status.IsNil() {
+		if len(status.Message()) > 0 {
+			return status.Message()
+		}
+		return codes.Code(status.Code()).String()
 	}
-	return ""
+
+	return codes.OK.String()
 }
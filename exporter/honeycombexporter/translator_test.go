@@ -18,85 +18,72 @@ import (
 	"testing"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
-func TestSpanAttributesToMap(t *testing.T) {
-
-	newSpanAttr := func(key string, value *tracepb.AttributeValue, count int32) *tracepb.Span_Attributes {
-		return &tracepb.Span_Attributes{
-			AttributeMap: map[string]*tracepb.AttributeValue{
-				key: value,
-			},
-			DroppedAttributesCount: count,
-		}
-	}
-
-	spanAttrs := []*tracepb.Span_Attributes{
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_StringValue{
-				StringValue: &tracepb.TruncatableString{Value: "bar"},
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_IntValue{
-				IntValue: 1234,
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_BoolValue{
-				BoolValue: true,
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_DoubleValue{
-				DoubleValue: 0.3145,
-			},
-		}, 0),
-		nil,
-		{
-			AttributeMap:           nil,
-			DroppedAttributesCount: 0,
-		},
-	}
+func TestAttributesToMap(t *testing.T) {
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"string": pdata.NewAttributeValueString("bar"),
+		"int":    pdata.NewAttributeValueInt(1234),
+		"bool":   pdata.NewAttributeValueBool(true),
+		"double": pdata.NewAttributeValueDouble(0.3145),
+		"null":   pdata.NewAttributeValueNull(),
+	})
+	arr := pdata.NewAttributeValueArray()
+	arr.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	arr.ArrayVal().Append(pdata.NewAttributeValueInt(2))
+	attrs.Insert("array", arr)
+	m := pdata.NewAttributeValueMap()
+	m.MapVal().InsertString("key", "value")
+	attrs.Insert("map", m)
 
-	wantResults := []map[string]interface{}{
-		{"foo": "bar"},
-		{"foo": int64(1234)},
-		{"foo": true},
-		{"foo": 0.3145},
-		{},
-		{},
-	}
+	assert.Equal(t, map[string]interface{}{
+		"string": "bar",
+		"int":    int64(1234),
+		"bool":   true,
+		"double": 0.3145,
+		"array":  `["a",2]`,
+		"map":    `{"key":"value"}`,
+	}, attributesToMap(attrs))
 
-	for i, attrs := range spanAttrs {
-		got := spanAttributesToMap(attrs)
-		want := wantResults[i]
-		for k := range want {
-			if interface{}(got[k]) != want[k] {
-				t.Errorf("Got: %+v, Want: %+v", got[k], want[k])
-			}
-		}
-		i++
-	}
+	assert.Equal(t, map[string]interface{}{}, attributesToMap(pdata.NewAttributeMap()))
 }
 
 func TestTimestampToTime(t *testing.T) {
 	var t1 time.Time
-	emptyTime := timestampToTime(nil)
+	emptyTime := timestampToTime(0)
 	if t1 != emptyTime {
 		t.Errorf("Expected %+v, Got: %+v\n", t1, emptyTime)
 	}
 
 	t2 := time.Now()
-	seconds := t2.UnixNano() / 1000000000
-	nowTime := timestampToTime(&timestamppb.Timestamp{
-		Seconds: seconds,
-		Nanos:   int32(t2.UnixNano() - (seconds * 1000000000)),
-	})
+	nowTime := timestampToTime(pdata.TimestampUnixNano(t2.UnixNano()))
 
 	if !t2.Equal(nowTime) {
 		t.Errorf("Expected %+v, Got %+v\n", t2, nowTime)
 	}
 }
+
+func TestGetSpanKind(t *testing.T) {
+	assert.Equal(t, "unspecified", getSpanKind(pdata.SpanKindUNSPECIFIED))
+	assert.Equal(t, "internal", getSpanKind(pdata.SpanKindINTERNAL))
+	assert.Equal(t, "server", getSpanKind(pdata.SpanKindSERVER))
+	assert.Equal(t, "client", getSpanKind(pdata.SpanKindCLIENT))
+	assert.Equal(t, "producer", getSpanKind(pdata.SpanKindPRODUCER))
+	assert.Equal(t, "consumer", getSpanKind(pdata.SpanKindCONSUMER))
+}
+
+func TestHasRemoteParent(t *testing.T) {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetKind(pdata.SpanKindSERVER)
+	span.SetParentSpanID(pdata.NewSpanID([]byte{0x01}))
+	assert.False(t, hasRemoteParent(span), "the span kind doesn't tell where the parent is")
+
+	span.Attributes().InsertBool("opencensus.same_process_as_parent_span", false)
+	assert.True(t, hasRemoteParent(span))
+
+	span.Attributes().UpdateBool("opencensus.same_process_as_parent_span", true)
+	assert.False(t, hasRemoteParent(span))
+}
@@ -18,21 +18,47 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// ProtocolHTTP sends Thrift batches to the Jaeger collector HTTP endpoint.
+	ProtocolHTTP = "http"
+	// ProtocolUDPCompact sends emitBatch messages, using the Thrift compact
+	// protocol, to the Jaeger agent.
+	ProtocolUDPCompact = "udp_compact"
+	// ProtocolUDPBinary sends emitBatch messages, using the Thrift binary
+	// protocol, to the Jaeger agent.
+	ProtocolUDPBinary = "udp_binary"
+	// ProtocolGRPC sends batches to the Jaeger collector gRPC endpoint.
+	ProtocolGRPC = "grpc"
 )
 
 // Config defines configuration for Jaeger Thrift over HTTP exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
+	// Protocol used to send the trace data, one of "http", "udp_compact",
+	// "udp_binary" or "grpc". The default value is "http".
+	Protocol string `mapstructure:"protocol"`
+
 	// URL is the URL to send the Jaeger trace data to (e.g.:
-	// http://some.url:14268/api/traces).
+	// http://some.url:14268/api/traces). Only used by the "http" protocol.
 	URL string `mapstructure:"url"`
 
+	// Endpoint is the host:port of the Jaeger agent (e.g.: localhost:6831)
+	// for the "udp_compact" and "udp_binary" protocols, or of the Jaeger
+	// collector (e.g.: some.host:14250) for the "grpc" protocol.
+	Endpoint string `mapstructure:"endpoint"`
+
 	// Timeout is the maximum timeout for HTTP request sending trace data. The
 	// default value is 5 seconds.
 	Timeout time.Duration `mapstructure:"timeout"`
 
 	// Headers are a set of headers to be added to the HTTP request sending
-	// trace data.
+	// trace data. They are sent as metadata by the "grpc" protocol.
 	Headers map[string]string `mapstructure:"headers"`
+
+	// TLSSetting is the TLS configuration of the "grpc" protocol.
+	TLSSetting configtls.TLSClientSetting `mapstructure:",squash"`
 }
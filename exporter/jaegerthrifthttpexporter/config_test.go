@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -54,7 +55,8 @@ func TestLoadConfig(t *testing.T) {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: expectedName,
 		},
-		URL: "http://some.other.location/api/traces",
+		Protocol: ProtocolHTTP,
+		URL:      "http://some.other.location/api/traces",
 		Headers: map[string]string{
 			"added-entry": "added value",
 			"dot.test":    "test",
@@ -66,4 +68,32 @@ func TestLoadConfig(t *testing.T) {
 	te, err := factory.CreateTraceExporter(context.Background(), component.ExporterCreateParams{}, e1)
 	require.NoError(t, err)
 	require.NotNil(t, te)
+
+	e2 := cfg.Exporters["jaeger_thrift/agent"]
+	assert.Equal(t, &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "jaeger_thrift/agent",
+		},
+		Protocol: ProtocolUDPCompact,
+		Endpoint: "localhost:6831",
+		Timeout:  defaultHTTPTimeout,
+	}, e2)
+
+	e3 := cfg.Exporters["jaeger_thrift/grpc"]
+	assert.Equal(t, &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "jaeger_thrift/grpc",
+		},
+		Protocol: ProtocolGRPC,
+		Endpoint: "some.collector:14250",
+		Timeout:  3 * time.Second,
+		Headers: map[string]string{
+			"added-entry": "added value",
+		},
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}, e3)
 }
@@ -17,9 +17,11 @@ package jaegerthrifthttpexporter
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -43,7 +45,8 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		Timeout: defaultHTTPTimeout,
+		Protocol: ProtocolHTTP,
+		Timeout:  defaultHTTPTimeout,
 	}
 }
 
@@ -54,6 +57,37 @@ func createTraceExporter(
 ) (component.TraceExporter, error) {
 
 	expCfg := config.(*Config)
+	switch expCfg.Protocol {
+	case "", ProtocolHTTP:
+		return createHTTPTraceExporter(expCfg)
+	case ProtocolUDPCompact, ProtocolUDPBinary:
+		if err := validateEndpoint(expCfg); err != nil {
+			return nil, err
+		}
+		return newUDPTraceExporter(config, expCfg.Endpoint, expCfg.Protocol, expCfg.Timeout)
+	case ProtocolGRPC:
+		if err := validateEndpoint(expCfg); err != nil {
+			return nil, err
+		}
+		grpcSettings := configgrpc.GRPCClientSettings{
+			Endpoint:   expCfg.Endpoint,
+			Headers:    expCfg.Headers,
+			TLSSetting: expCfg.TLSSetting,
+		}
+		return newGRPCTraceExporter(config, grpcSettings, expCfg.Timeout)
+	default:
+		return nil, fmt.Errorf(
+			"%q config has an unsupported \"protocol\" %q, must be one of %q, %q, %q or %q",
+			expCfg.Name(),
+			expCfg.Protocol,
+			ProtocolHTTP,
+			ProtocolUDPCompact,
+			ProtocolUDPBinary,
+			ProtocolGRPC)
+	}
+}
+
+func createHTTPTraceExporter(expCfg *Config) (component.TraceExporter, error) {
 	_, err := url.ParseRequestURI(expCfg.URL)
 	if err != nil {
 		// TODO: Improve error message, see #215
@@ -64,12 +98,29 @@ func createTraceExporter(
 		return nil, err
 	}
 
+	if err = validateTimeout(expCfg); err != nil {
+		return nil, err
+	}
+
+	return newTraceExporter(expCfg, expCfg.URL, expCfg.Headers, expCfg.Timeout)
+}
+
+func validateEndpoint(expCfg *Config) error {
+	if _, _, err := net.SplitHostPort(expCfg.Endpoint); err != nil {
+		return fmt.Errorf(
+			"%q config requires a valid \"endpoint\" for protocol %q: %v",
+			expCfg.Name(),
+			expCfg.Protocol,
+			err)
+	}
+	return validateTimeout(expCfg)
+}
+
+func validateTimeout(expCfg *Config) error {
 	if expCfg.Timeout <= 0 {
-		err := fmt.Errorf(
+		return fmt.Errorf(
 			"%q config requires a positive value for \"timeout\"",
 			expCfg.Name())
-		return nil, err
 	}
-
-	return newTraceExporter(config, expCfg.URL, expCfg.Headers, expCfg.Timeout)
+	return nil
 }
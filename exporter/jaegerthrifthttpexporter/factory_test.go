@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -73,6 +74,23 @@ func TestFactory_CreateTraceExporter(t *testing.T) {
 	assert.NotNil(t, te)
 }
 
+func TestFactory_CreateTraceExporterProtocols(t *testing.T) {
+	for _, protocol := range []string{ProtocolUDPCompact, ProtocolUDPBinary, ProtocolGRPC} {
+		t.Run(protocol, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Protocol = protocol
+			cfg.Endpoint = "localhost:6831"
+			cfg.TLSSetting.Insecure = true
+
+			params := component.ExporterCreateParams{Logger: zap.NewNop()}
+			te, err := createTraceExporter(context.Background(), params, cfg)
+			assert.NoError(t, err)
+			require.NotNil(t, te)
+			assert.NoError(t, te.Shutdown(context.Background()))
+		})
+	}
+}
+
 func TestFactory_CreateTraceExporterFails(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -112,6 +130,43 @@ func TestFactory_CreateTraceExporterFails(t *testing.T) {
 			},
 			errorMessage: "\"jaeger_thrift\" config requires a positive value for \"timeout\"",
 		},
+		{
+			name: "unsupported_protocol",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Protocol: "tcp",
+				Timeout:  time.Second,
+			},
+			errorMessage: "\"jaeger_thrift\" config has an unsupported \"protocol\" \"tcp\", must be one of \"http\", \"udp_compact\", \"udp_binary\" or \"grpc\"",
+		},
+		{
+			name: "udp_missing_endpoint",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Protocol: ProtocolUDPCompact,
+				Timeout:  time.Second,
+			},
+			errorMessage: "\"jaeger_thrift\" config requires a valid \"endpoint\" for protocol \"udp_compact\": missing port in address",
+		},
+		{
+			name: "grpc_negative_duration",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Protocol: ProtocolGRPC,
+				Endpoint: "localhost:14250",
+				Timeout:  -2 * time.Second,
+			},
+			errorMessage: "\"jaeger_thrift\" config requires a positive value for \"timeout\"",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
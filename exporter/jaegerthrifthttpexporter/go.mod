@@ -10,5 +10,6 @@ require (
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
 	go.uber.org/zap v1.16.0
+	google.golang.org/grpc v1.32.0
 	google.golang.org/protobuf v1.25.0
 )
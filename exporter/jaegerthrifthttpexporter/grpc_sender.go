@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	jaegerproto "github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newGRPCTraceExporter(
+	config configmodels.Exporter,
+	grpcSettings configgrpc.GRPCClientSettings,
+	timeout time.Duration,
+) (component.TraceExporter, error) {
+
+	opts, err := grpcSettings.ToDialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(grpcSettings.Endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	clientTimeout := defaultHTTPTimeout
+	if timeout != 0 {
+		clientTimeout = timeout
+	}
+	s := &jaegerGRPCSender{
+		conn:     conn,
+		client:   jaegerproto.NewCollectorServiceClient(conn),
+		metadata: metadata.New(grpcSettings.Headers),
+		timeout:  clientTimeout,
+	}
+
+	return exporterhelper.NewTraceExporter(
+		config,
+		s.pushTraceData,
+		exporterhelper.WithShutdown(s.shutdown))
+}
+
+// jaegerGRPCSender sends batches to the gRPC endpoint of a Jaeger collector.
+type jaegerGRPCSender struct {
+	conn     *grpc.ClientConn
+	client   jaegerproto.CollectorServiceClient
+	metadata metadata.MD
+	timeout  time.Duration
+}
+
+func (s *jaegerGRPCSender) pushTraceData(
+	ctx context.Context,
+	td pdata.Traces,
+) (droppedSpans int, err error) {
+	batches, err := jaegertranslator.InternalTracesToJaegerProto(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
+
+	if s.metadata.Len() > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, s.metadata)
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	sentSpans := 0
+	for _, batch := range batches {
+		_, err = s.client.PostSpans(ctx, &jaegerproto.PostSpansRequest{Batch: *batch})
+		if err != nil {
+			return td.SpanCount() - sentSpans, fmt.Errorf("failed to post spans: %w", err)
+		}
+		sentSpans += len(batch.Spans)
+	}
+
+	return 0, nil
+}
+
+func (s *jaegerGRPCSender) shutdown(context.Context) error {
+	return s.conn.Close()
+}
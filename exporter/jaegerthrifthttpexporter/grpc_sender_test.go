@@ -0,0 +1,97 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	jaegerproto "github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockCollector struct {
+	mu       sync.Mutex
+	requests []*jaegerproto.PostSpansRequest
+	metadata []metadata.MD
+}
+
+func (c *mockCollector) PostSpans(ctx context.Context, r *jaegerproto.PostSpansRequest) (*jaegerproto.PostSpansResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.requests = append(c.requests, r)
+	c.metadata = append(c.metadata, md)
+	return &jaegerproto.PostSpansResponse{}, nil
+}
+
+func TestGRPCSender(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	collector := &mockCollector{}
+	jaegerproto.RegisterCollectorServiceServer(server, collector)
+	go server.Serve(ln)
+	defer server.Stop()
+
+	settings := configgrpc.GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		Headers:  map[string]string{"x-test": "value"},
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	exp, err := newGRPCTraceExporter(&configmodels.ExporterSettings{}, settings, time.Second)
+	require.NoError(t, err)
+	defer exp.Shutdown(context.Background())
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces(3, 10)))
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	require.Len(t, collector.requests, 1)
+	assert.Equal(t, "test-service", collector.requests[0].Batch.Process.ServiceName)
+	assert.Len(t, collector.requests[0].Batch.Spans, 3)
+	assert.Equal(t, []string{"value"}, collector.metadata[0].Get("x-test"))
+}
+
+func TestGRPCSenderFails(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	// Nothing is listening at the endpoint after the listener is closed.
+	endpoint := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	settings := configgrpc.GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	exp, err := newGRPCTraceExporter(&configmodels.ExporterSettings{}, settings, 100*time.Millisecond)
+	require.NoError(t, err)
+	defer exp.Shutdown(context.Background())
+
+	assert.Error(t, exp.ConsumeTraces(context.Background(), newTestTraces(3, 10)))
+}
@@ -0,0 +1,158 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+// maxUDPPacketSize is the default max packet size of the Jaeger agent.
+const maxUDPPacketSize = 65000
+
+func newUDPTraceExporter(
+	config configmodels.Exporter,
+	agentAddress string,
+	protocol string,
+	timeout time.Duration,
+) (component.TraceExporter, error) {
+
+	var protocolFactory thrift.TProtocolFactory
+	switch protocol {
+	case ProtocolUDPCompact:
+		protocolFactory = thrift.NewTCompactProtocolFactory()
+	case ProtocolUDPBinary:
+		protocolFactory = thrift.NewTBinaryProtocolFactoryDefault()
+	default:
+		return nil, fmt.Errorf("unsupported UDP protocol %q", protocol)
+	}
+
+	// Dialing UDP doesn't send anything, it only sets the destination
+	// address of the writes.
+	conn, err := net.Dial("udp", agentAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	writeTimeout := defaultHTTPTimeout
+	if timeout != 0 {
+		writeTimeout = timeout
+	}
+	s := &jaegerThriftUDPSender{
+		conn:            conn,
+		protocolFactory: protocolFactory,
+		timeout:         writeTimeout,
+	}
+
+	return exporterhelper.NewTraceExporter(
+		config,
+		s.pushTraceData,
+		exporterhelper.WithShutdown(s.shutdown))
+}
+
+// jaegerThriftUDPSender sends emitBatch messages to a Jaeger agent. Each
+// message must fit in a single UDP packet so batches are split as needed.
+type jaegerThriftUDPSender struct {
+	conn            net.Conn
+	protocolFactory thrift.TProtocolFactory
+	timeout         time.Duration
+}
+
+func (s *jaegerThriftUDPSender) pushTraceData(
+	ctx context.Context,
+	td pdata.Traces,
+) (droppedSpans int, err error) {
+	var errs []error
+	octds := internaldata.TraceDataToOC(td)
+	for _, octd := range octds {
+		tBatch, err := oCProtoToJaegerThrift(octd)
+		if err != nil {
+			return td.SpanCount(), consumererror.Permanent(err)
+		}
+
+		dropped, err := s.emitBatch(ctx, tBatch)
+		if err != nil {
+			droppedSpans += dropped
+			errs = append(errs, err)
+		}
+	}
+
+	return droppedSpans, componenterror.CombineErrors(errs)
+}
+
+// emitBatch sends the batch to the agent, halving it until each part fits in
+// a UDP packet. It returns the number of spans that could not be sent.
+func (s *jaegerThriftUDPSender) emitBatch(ctx context.Context, batch *jaeger.Batch) (int, error) {
+	packet, err := s.serializeEmitBatch(ctx, batch)
+	if err != nil {
+		return len(batch.Spans), consumererror.Permanent(err)
+	}
+
+	if len(packet) <= maxUDPPacketSize {
+		if err = s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+			return len(batch.Spans), err
+		}
+		if _, err = s.conn.Write(packet); err != nil {
+			return len(batch.Spans), err
+		}
+		return 0, nil
+	}
+
+	if len(batch.Spans) <= 1 {
+		return len(batch.Spans), consumererror.Permanent(fmt.Errorf(
+			"emitBatch message of %d bytes with a single span exceeds the max UDP packet size of %d bytes",
+			len(packet), maxUDPPacketSize))
+	}
+
+	half := len(batch.Spans) / 2
+	var errs []error
+	dropped := 0
+	for _, spans := range [][]*jaeger.Span{batch.Spans[:half], batch.Spans[half:]} {
+		d, err := s.emitBatch(ctx, &jaeger.Batch{Process: batch.Process, Spans: spans})
+		if err != nil {
+			dropped += d
+			errs = append(errs, err)
+		}
+	}
+	return dropped, componenterror.CombineErrors(errs)
+}
+
+// serializeEmitBatch returns the Thrift emitBatch message expected by the
+// Jaeger agent for the batch.
+func (s *jaegerThriftUDPSender) serializeEmitBatch(ctx context.Context, batch *jaeger.Batch) ([]byte, error) {
+	buffer := thrift.NewTMemoryBuffer()
+	client := agent.NewAgentClientFactory(buffer, s.protocolFactory)
+	if err := client.EmitBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (s *jaegerThriftUDPSender) shutdown(context.Context) error {
+	return s.conn.Close()
+}
@@ -0,0 +1,153 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type testAgentHandler struct {
+	batches []*jaeger.Batch
+}
+
+func (h *testAgentHandler) EmitZipkinBatch(context.Context, []*zipkincore.Span) error {
+	return nil
+}
+
+func (h *testAgentHandler) EmitBatch(_ context.Context, batch *jaeger.Batch) error {
+	h.batches = append(h.batches, batch)
+	return nil
+}
+
+func newTestTraces(numSpans int, nameLen int) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "test-service")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(numSpans)
+	for i := 0; i < numSpans; i++ {
+		span := spans.At(i)
+		span.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		span.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+		span.SetName(strings.Repeat("s", nameLen))
+		span.SetStartTime(pdata.TimestampUnixNano(time.Now().UnixNano()))
+		span.SetEndTime(pdata.TimestampUnixNano(time.Now().UnixNano()))
+	}
+	return td
+}
+
+func receiveBatches(t *testing.T, conn net.PacketConn, protocolFactory thrift.TProtocolFactory, numPackets int) []*jaeger.Batch {
+	handler := &testAgentHandler{}
+	processor := agent.NewAgentProcessor(handler)
+	buf := make([]byte, maxUDPPacketSize)
+	for i := 0; i < numPackets; i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, n, maxUDPPacketSize)
+
+		transport := thrift.NewTMemoryBuffer()
+		_, err = transport.Write(buf[:n])
+		require.NoError(t, err)
+		protocol := protocolFactory.GetProtocol(transport)
+		_, procErr := processor.Process(context.Background(), protocol, protocol)
+		require.Nil(t, procErr)
+	}
+	return handler.batches
+}
+
+func TestUDPSender(t *testing.T) {
+	tests := []struct {
+		protocol        string
+		protocolFactory thrift.TProtocolFactory
+	}{
+		{
+			protocol:        ProtocolUDPCompact,
+			protocolFactory: thrift.NewTCompactProtocolFactory(),
+		},
+		{
+			protocol:        ProtocolUDPBinary,
+			protocolFactory: thrift.NewTBinaryProtocolFactoryDefault(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer conn.Close()
+
+			exp, err := newUDPTraceExporter(&configmodels.ExporterSettings{}, conn.LocalAddr().String(), tt.protocol, time.Second)
+			require.NoError(t, err)
+			defer exp.Shutdown(context.Background())
+
+			require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces(3, 10)))
+
+			batches := receiveBatches(t, conn, tt.protocolFactory, 1)
+			require.Len(t, batches, 1)
+			assert.Equal(t, "test-service", batches[0].Process.ServiceName)
+			assert.Len(t, batches[0].Spans, 3)
+		})
+	}
+}
+
+func TestUDPSenderSplitsBatches(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	exp, err := newUDPTraceExporter(&configmodels.ExporterSettings{}, conn.LocalAddr().String(), ProtocolUDPCompact, time.Second)
+	require.NoError(t, err)
+	defer exp.Shutdown(context.Background())
+
+	// Each span is ~10KB so the 20 spans need to be split in at least 4 packets.
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces(20, 10000)))
+
+	batches := receiveBatches(t, conn, thrift.NewTCompactProtocolFactory(), 4)
+	spanCount := 0
+	for _, batch := range batches {
+		assert.Equal(t, "test-service", batch.Process.ServiceName)
+		spanCount += len(batch.Spans)
+	}
+	assert.Equal(t, 20, spanCount)
+}
+
+func TestUDPSenderDropsOversizedSpan(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	exp, err := newUDPTraceExporter(&configmodels.ExporterSettings{}, conn.LocalAddr().String(), ProtocolUDPCompact, time.Second)
+	require.NoError(t, err)
+	defer exp.Shutdown(context.Background())
+
+	err = exp.ConsumeTraces(context.Background(), newTestTraces(1, 2*maxUDPPacketSize))
+	assert.Error(t, err)
+}
@@ -15,19 +15,48 @@
 package kinesisexporter
 
 import (
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	// Supported values of Encoding.
+	encodingOTLPProto   = "otlp_proto"
+	encodingOTLPJSON    = "otlp_json"
+	encodingJaegerProto = "jaeger_proto"
+
+	// Supported values of PartitionKey.
+	partitionKeyTraceID     = "trace_id"
+	partitionKeyServiceName = "service.name"
+	partitionKeyRandom      = "random"
+
+	// maxRecordSize is the largest data blob Kinesis accepts in a single record.
+	maxRecordSize = 1024 * 1024
 )
 
 // AWSConfig contains AWS specific configuration such as kinesis stream, region, etc.
 type AWSConfig struct {
-	StreamName      string `mapstructure:"stream_name"`
+	StreamName string `mapstructure:"stream_name"`
+	Region     string `mapstructure:"region"`
+	Role       string `mapstructure:"role"`
+
+	// Endpoint overrides the Kinesis endpoint resolved from the region,
+	// e.g. to send records to a local Kinesis stand-in.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Deprecated: use Endpoint instead.
 	KinesisEndpoint string `mapstructure:"kinesis_endpoint"`
-	Region          string `mapstructure:"region"`
-	Role            string `mapstructure:"role"`
 }
 
-// KPLConfig contains kinesis producer library related config to controls things
-// like aggregation, batching, connections, retries, etc.
+// KPLConfig contains the settings of the Kinesis producer library the exporter
+// used to write records with.
+//
+// Deprecated: records are written with PutRecords, only MaxBackoffSeconds is
+// still used, as the max interval of retry_on_failure.
 type KPLConfig struct {
 	AggregateBatchCount  int `mapstructure:"aggregate_batch_count"`
 	AggregateBatchSize   int `mapstructure:"aggregate_batch_size"`
@@ -42,14 +71,97 @@ type KPLConfig struct {
 
 // Config contains the main configuration options for the kinesis exporter
 type Config struct {
-	configmodels.ExporterSettings `mapstructure:",squash"`
+	configmodels.ExporterSettings  `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
 
 	AWS AWSConfig `mapstructure:"aws"`
-	KPL KPLConfig `mapstructure:"kpl"`
 
-	QueueSize            int `mapstructure:"queue_size"`
-	NumWorkers           int `mapstructure:"num_workers"`
-	MaxBytesPerBatch     int `mapstructure:"max_bytes_per_batch"`
-	MaxBytesPerSpan      int `mapstructure:"max_bytes_per_span"`
+	// Encoding is the format of the records written to the stream, one of
+	// jaeger_proto, the default writing a span per record, otlp_proto or
+	// otlp_json. jaeger_proto only supports traces.
+	Encoding string `mapstructure:"encoding"`
+
+	// PartitionKey selects how the partition key of each record is chosen, one of
+	// trace_id, service.name or random. Telemetry without a trace id uses a random key.
+	PartitionKey string `mapstructure:"partition_key"`
+
+	// MaxRecordSize is the maximum size in bytes of the data of a single record.
+	// Telemetry is split across records so that none exceeds it.
+	MaxRecordSize int `mapstructure:"max_record_size"`
+
+	// Deprecated: see KPLConfig.
+	KPL KPLConfig `mapstructure:"kpl"`
+	// Deprecated: use sending_queue.queue_size instead.
+	QueueSize int `mapstructure:"queue_size"`
+	// Deprecated: use sending_queue.num_consumers instead.
+	NumWorkers int `mapstructure:"num_workers"`
+	// Deprecated: use max_record_size instead.
+	MaxBytesPerBatch int `mapstructure:"max_bytes_per_batch"`
+	// Deprecated: spans larger than max_record_size are dropped.
+	MaxBytesPerSpan int `mapstructure:"max_bytes_per_span"`
+	// Deprecated: records are written as soon as telemetry is received.
 	FlushIntervalSeconds int `mapstructure:"flush_interval_seconds"`
 }
+
+// applyDeprecated maps the deprecated settings that are set onto the settings
+// replacing them, and warns about those that are no longer used. The queue
+// the exporter used to have is enabled when its settings are set.
+func (c *Config) applyDeprecated(logger *zap.Logger) {
+	warn := func(key, replacement string) {
+		logger.Warn("deprecated kinesis exporter setting", zap.String("setting", key), zap.String("replacement", replacement))
+	}
+	if c.AWS.KinesisEndpoint != "" {
+		warn("aws.kinesis_endpoint", "aws.endpoint")
+		if c.AWS.Endpoint == "" {
+			c.AWS.Endpoint = c.AWS.KinesisEndpoint
+		}
+	}
+	if c.KPL != (KPLConfig{}) {
+		warn("kpl", "retry_on_failure")
+		if c.KPL.MaxBackoffSeconds > 0 {
+			c.RetrySettings.MaxInterval = time.Duration(c.KPL.MaxBackoffSeconds) * time.Second
+		}
+	}
+	if c.QueueSize > 0 {
+		warn("queue_size", "sending_queue.queue_size")
+		c.QueueSettings.Enabled = true
+		c.QueueSettings.QueueSize = c.QueueSize
+	}
+	if c.NumWorkers > 0 {
+		warn("num_workers", "sending_queue.num_consumers")
+		c.QueueSettings.Enabled = true
+		c.QueueSettings.NumConsumers = c.NumWorkers
+	}
+	if c.MaxBytesPerBatch > 0 {
+		warn("max_bytes_per_batch", "max_record_size")
+		c.MaxRecordSize = c.MaxBytesPerBatch
+	}
+	if c.MaxBytesPerSpan > 0 {
+		warn("max_bytes_per_span", "max_record_size")
+	}
+	if c.FlushIntervalSeconds > 0 {
+		warn("flush_interval_seconds", "none")
+	}
+}
+
+func (c *Config) validate() error {
+	if c.AWS.StreamName == "" {
+		return fmt.Errorf("'aws.stream_name' must be set")
+	}
+	switch c.Encoding {
+	case encodingOTLPProto, encodingOTLPJSON, encodingJaegerProto:
+	default:
+		return fmt.Errorf("unsupported encoding %q", c.Encoding)
+	}
+	switch c.PartitionKey {
+	case partitionKeyTraceID, partitionKeyServiceName, partitionKeyRandom:
+	default:
+		return fmt.Errorf("unsupported partition key %q", c.PartitionKey)
+	}
+	if c.MaxRecordSize <= 0 || c.MaxRecordSize > maxRecordSize {
+		return fmt.Errorf("'max_record_size' must be between 1 and %d", maxRecordSize)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ package kinesisexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -46,22 +49,15 @@ func TestDefaultConfig(t *testing.T) {
 				TypeVal: "kinesis",
 				NameVal: "kinesis",
 			},
+			TimeoutSettings: exporterhelper.CreateDefaultTimeoutSettings(),
+			QueueSettings:   exporterhelper.CreateDefaultQueueSettings(),
+			RetrySettings:   exporterhelper.CreateDefaultRetrySettings(),
 			AWS: AWSConfig{
 				Region: "us-west-2",
 			},
-			KPL: KPLConfig{
-				BatchSize:            5242880,
-				BatchCount:           1000,
-				BacklogCount:         2000,
-				FlushIntervalSeconds: 5,
-				MaxConnections:       24,
-			},
-
-			QueueSize:            100000,
-			NumWorkers:           8,
-			FlushIntervalSeconds: 5,
-			MaxBytesPerBatch:     100000,
-			MaxBytesPerSpan:      900000,
+			Encoding:      "jaeger_proto",
+			PartitionKey:  "random",
+			MaxRecordSize: 1048576,
 		},
 	)
 }
@@ -87,33 +83,90 @@ func TestConfig(t *testing.T) {
 				TypeVal: "kinesis",
 				NameVal: "kinesis",
 			},
-			AWS: AWSConfig{
-				StreamName:      "test-stream",
-				KinesisEndpoint: "kinesis.mars-1.aws.galactic",
-				Region:          "mars-1",
-				Role:            "arn:test-role",
+			TimeoutSettings: exporterhelper.TimeoutSettings{
+				Timeout: 10 * time.Second,
 			},
-			KPL: KPLConfig{
-				AggregateBatchCount:  10,
-				AggregateBatchSize:   11,
-				BatchSize:            12,
-				BatchCount:           13,
-				BacklogCount:         14,
-				FlushIntervalSeconds: 15,
-				MaxConnections:       16,
-				MaxRetries:           17,
-				MaxBackoffSeconds:    18,
+			QueueSettings: exporterhelper.QueueSettings{
+				Enabled:      true,
+				NumConsumers: 2,
+				QueueSize:    10,
 			},
-
-			QueueSize:            1,
-			NumWorkers:           2,
-			FlushIntervalSeconds: 3,
-			MaxBytesPerBatch:     4,
-			MaxBytesPerSpan:      5,
+			RetrySettings: exporterhelper.RetrySettings{
+				Enabled:         true,
+				InitialInterval: 10 * time.Second,
+				MaxInterval:     1 * time.Minute,
+				MaxElapsedTime:  10 * time.Minute,
+			},
+			AWS: AWSConfig{
+				StreamName: "test-stream",
+				Region:     "mars-1",
+				Role:       "arn:test-role",
+				Endpoint:   "http://localhost:4567",
+			},
+			Encoding:      "otlp_json",
+			PartitionKey:  "trace_id",
+			MaxRecordSize: 500000,
 		},
 	)
 }
 
+func TestDeprecatedConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[factory.Type()] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "deprecated.yaml"), factories,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	loaded := cfg.Exporters["kinesis"].(*Config)
+	c, err := resolveConfig(loaded, zap.NewNop())
+	require.NoError(t, err)
+	resolved, err := resolveConfig(loaded, zap.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, c, resolved)
+	assert.Equal(t, "", loaded.AWS.Endpoint, "the loaded config must not be modified")
+
+	assert.Equal(t, "kinesis.mars-1.aws.galactic", c.AWS.Endpoint)
+	assert.Equal(t, exporterhelper.QueueSettings{
+		Enabled:      true,
+		NumConsumers: 2,
+		QueueSize:    1,
+	}, c.QueueSettings)
+	assert.Equal(t, 18*time.Second, c.RetrySettings.MaxInterval)
+	assert.Equal(t, 400000, c.MaxRecordSize)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		errMsg string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "no stream", modify: func(c *Config) { c.AWS.StreamName = "" }, errMsg: "'aws.stream_name' must be set"},
+		{name: "bad encoding", modify: func(c *Config) { c.Encoding = "zipkin" }, errMsg: `unsupported encoding "zipkin"`},
+		{name: "bad partition key", modify: func(c *Config) { c.PartitionKey = "span_id" }, errMsg: `unsupported partition key "span_id"`},
+		{name: "record too large", modify: func(c *Config) { c.MaxRecordSize = 2 * 1024 * 1024 }, errMsg: "'max_record_size' must be between 1 and 1048576"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := createDefaultConfig().(*Config)
+			c.AWS.StreamName = "test-stream"
+			tt.modify(c)
+			err := c.validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
 func TestConfigCheck(t *testing.T) {
 	cfg := (NewFactory()).CreateDefaultConfig()
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
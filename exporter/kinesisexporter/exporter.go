@@ -16,57 +16,242 @@ package kinesisexporter
 
 import (
 	"context"
+	"fmt"
+	"time"
 
-	kinesis "github.com/signalfx/opencensus-go-exporter-kinesis"
-	"go.opentelemetry.io/collector/component"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
-	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
 	"go.uber.org/zap"
 )
 
-// Exporter implements an OpenTelemetry trace exporter that exports all spans to AWS Kinesis
-type Exporter struct {
-	kinesis *kinesis.Exporter
-	logger  *zap.Logger
-}
+const (
+	// Limits of a single PutRecords request.
+	maxRecordsPerRequest = 500
+	maxBytesPerRequest   = 5 * 1024 * 1024
+
+	// maxPutAttempts is the number of times the records rejected by a
+	// PutRecords request are sent.
+	maxPutAttempts = 3
+)
 
-var _ component.TraceExporter = (*Exporter)(nil)
+// putRetryInterval is the time to wait before sending rejected records again,
+// doubled after every attempt.
+var putRetryInterval = 100 * time.Millisecond
 
-// Start tells the exporter to start. The exporter may prepare for exporting
-// by connecting to the endpoint. Host parameter can be used for communicating
-// with the host after Start() has already returned. If error is returned by
-// Start() then the collector startup will be aborted.
-func (e Exporter) Start(_ context.Context, _ component.Host) error {
-	return nil
+// exporter writes telemetry to an AWS Kinesis stream, encoded with the
+// configured marshaler and split into records no larger than the maximum
+// record size.
+type exporter struct {
+	client    kinesisiface.KinesisAPI
+	marshaler marshaler
+	config    *Config
+	logger    *zap.Logger
 }
 
-// Shutdown is invoked during exporter shutdown.
-func (e Exporter) Shutdown(context.Context) error {
-	e.kinesis.Flush()
-	return nil
+// newExporter creates an exporter for a config with the deprecated settings
+// already resolved, see resolveConfig.
+func newExporter(c *Config, logger *zap.Logger) (*exporter, error) {
+	m, err := newMarshaler(c.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newKinesisClient(c)
+	if err != nil {
+		return nil, err
+	}
+	return &exporter{
+		client:    client,
+		marshaler: m,
+		config:    c,
+		logger:    logger,
+	}, nil
 }
 
-// ConsumeTraceData receives a span batch and exports it to AWS Kinesis
-func (e Exporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	pBatches, err := jaegertranslator.InternalTracesToJaegerProto(td)
+func newKinesisClient(c *Config) (kinesisiface.KinesisAPI, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(c.AWS.Region)})
 	if err != nil {
-		e.logger.Error("error translating span batch", zap.Error(err))
-		return consumererror.Permanent(err)
+		return nil, err
 	}
-	// TODO: Use a multi error type
-	var exportErr error
-	for _, pBatch := range pBatches {
-		for _, span := range pBatch.GetSpans() {
-			if span.Process == nil {
-				span.Process = pBatch.Process
+	cfg := &aws.Config{}
+	if c.AWS.Role != "" {
+		cfg.Credentials = stscreds.NewCredentials(sess, c.AWS.Role)
+	}
+	if c.AWS.Endpoint != "" {
+		cfg.Endpoint = aws.String(c.AWS.Endpoint)
+	}
+	return kinesis.New(sess, cfg), nil
+}
+
+func (e *exporter) pushTraces(ctx context.Context, td pdata.Traces) (int, error) {
+	rb := e.newRecordBuilder()
+	keys, groups := groupTraces(td, func(resource pdata.Resource, span pdata.Span) string {
+		switch e.config.PartitionKey {
+		case partitionKeyTraceID:
+			return traceIDPartitionKey(span.TraceID())
+		case partitionKeyServiceName:
+			return serviceNamePartitionKey(resource)
+		}
+		return ""
+	})
+	for _, key := range keys {
+		if e.config.Encoding != encodingJaegerProto {
+			rb.add(tracesBatch{groups[key]}, key)
+			continue
+		}
+		// Jaeger records hold a single span.
+		spanKeys, spans := groupTraces(groups[key], spanKey())
+		for _, k := range spanKeys {
+			rb.add(tracesBatch{spans[k]}, key)
+		}
+	}
+	return e.send(ctx, rb)
+}
+
+func (e *exporter) pushMetrics(ctx context.Context, md pdata.Metrics) (int, error) {
+	rb := e.newRecordBuilder()
+	// Metrics are not part of a trace, so they fall back to a random key
+	// when partitioning by trace id.
+	keys, groups := groupMetrics(md, func(resource pdata.Resource, _ pdata.Metric) string {
+		if e.config.PartitionKey == partitionKeyServiceName {
+			return serviceNamePartitionKey(resource)
+		}
+		return ""
+	})
+	for _, key := range keys {
+		rb.add(metricsBatch{groups[key]}, key)
+	}
+	return e.send(ctx, rb)
+}
+
+func (e *exporter) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	rb := e.newRecordBuilder()
+	keys, groups := groupLogs(ld, func(resource pdata.Resource, logRecord pdata.LogRecord) string {
+		switch e.config.PartitionKey {
+		case partitionKeyTraceID:
+			return traceIDPartitionKey(logRecord.TraceID())
+		case partitionKeyServiceName:
+			return serviceNamePartitionKey(resource)
+		}
+		return ""
+	})
+	for _, key := range keys {
+		rb.add(logsBatch{groups[key]}, key)
+	}
+	return e.send(ctx, rb)
+}
+
+func (e *exporter) newRecordBuilder() *recordBuilder {
+	return &recordBuilder{
+		marshaler:     e.marshaler,
+		maxRecordSize: e.config.MaxRecordSize,
+	}
+}
+
+// send writes the records built by rb to the stream, it returns the number of
+// items that were dropped while building the records or failed to be written.
+// Once some records were written, errors are permanent: retrying the whole
+// batch would write these records again.
+func (e *exporter) send(ctx context.Context, rb *recordBuilder) (int, error) {
+	dropped := rb.dropped
+	errs := rb.errs
+	written := false
+
+	var entries []*kinesis.PutRecordsRequestEntry
+	var pending []record
+	var size int
+	flush := func() {
+		if len(entries) == 0 {
+			return
+		}
+		failed, err := e.putRecords(ctx, entries, pending)
+		dropped += failed
+		if err != nil {
+			errs = append(errs, err)
+		}
+		written = written || failed < countItems(pending)
+		entries, pending, size = nil, nil, 0
+	}
+
+	for _, r := range rb.records {
+		recordSize := len(r.data) + len(r.partitionKey)
+		if len(entries) == maxRecordsPerRequest || size+recordSize > maxBytesPerRequest {
+			flush()
+		}
+		entries = append(entries, &kinesis.PutRecordsRequestEntry{
+			Data:         r.data,
+			PartitionKey: aws.String(r.partitionKey),
+		})
+		pending = append(pending, r)
+		size += recordSize
+	}
+	flush()
+
+	err := componenterror.CombineErrors(errs)
+	if err != nil && written && !consumererror.IsPermanent(err) {
+		err = consumererror.Permanent(err)
+	}
+	return dropped, err
+}
+
+// putRecords writes the entries with PutRecords and returns the number of
+// items held by the records that could not be written. The entries rejected
+// by Kinesis, e.g. because a shard is throttled, are retried on their own up
+// to maxPutAttempts times, the entries that were written are never sent again.
+func (e *exporter) putRecords(ctx context.Context, entries []*kinesis.PutRecordsRequestEntry, records []record) (int, error) {
+	interval := putRetryInterval
+	for attempt := 1; ; attempt++ {
+		out, err := e.client.PutRecordsWithContext(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(e.config.AWS.StreamName),
+			Records:    entries,
+		})
+		if err != nil {
+			return countItems(records), err
+		}
+		if aws.Int64Value(out.FailedRecordCount) == 0 {
+			return 0, nil
+		}
+
+		var failedEntries []*kinesis.PutRecordsRequestEntry
+		var failedRecords []record
+		var firstErr error
+		for i, result := range out.Records {
+			if result.ErrorCode == nil || i >= len(records) {
+				continue
 			}
-			err := e.kinesis.ExportSpan(span)
-			if err != nil {
-				e.logger.Error("error exporting span to kinesis", zap.Error(err))
-				exportErr = err
+			failedEntries = append(failedEntries, entries[i])
+			failedRecords = append(failedRecords, records[i])
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %s", aws.StringValue(result.ErrorCode), aws.StringValue(result.ErrorMessage))
 			}
 		}
+		e.logger.Debug("failed to put records to kinesis",
+			zap.Int("attempt", attempt),
+			zap.Int("failed_records", len(failedEntries)), zap.Error(firstErr))
+
+		if attempt == maxPutAttempts || ctx.Err() != nil {
+			return countItems(failedRecords), fmt.Errorf("failed to put %d of %d records: %v",
+				len(failedEntries), len(entries), firstErr)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+		}
+		interval *= 2
+		entries, records = failedEntries, failedRecords
+	}
+}
+
+func countItems(records []record) int {
+	count := 0
+	for _, r := range records {
+		count += r.count
 	}
-	return exportErr
+	return count
 }
@@ -0,0 +1,313 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinesisexporter
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/google/uuid"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+type mockKinesisClient struct {
+	kinesisiface.KinesisAPI
+	inputs []*kinesis.PutRecordsInput
+	// failures maps partition keys to the number of times their records are
+	// rejected before being accepted.
+	failures map[string]int
+	err      error
+}
+
+func (m *mockKinesisClient) PutRecordsWithContext(_ aws.Context, input *kinesis.PutRecordsInput, _ ...request.Option) (*kinesis.PutRecordsOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := &kinesis.PutRecordsOutput{FailedRecordCount: aws.Int64(0)}
+	for i := range input.Records {
+		result := &kinesis.PutRecordsResultEntry{}
+		if key := aws.StringValue(input.Records[i].PartitionKey); m.failures[key] > 0 {
+			m.failures[key]--
+			result.ErrorCode = aws.String("ProvisionedThroughputExceededException")
+			result.ErrorMessage = aws.String("rate exceeded")
+			*out.FailedRecordCount++
+		}
+		out.Records = append(out.Records, result)
+	}
+	return out, nil
+}
+
+func (m *mockKinesisClient) records() []*kinesis.PutRecordsRequestEntry {
+	var records []*kinesis.PutRecordsRequestEntry
+	for _, input := range m.inputs {
+		records = append(records, input.Records...)
+	}
+	return records
+}
+
+func init() {
+	putRetryInterval = time.Millisecond
+}
+
+func newTestExporter(t *testing.T, client *mockKinesisClient, modify func(c *Config)) *exporter {
+	c := createDefaultConfig().(*Config)
+	c.AWS.StreamName = "test-stream"
+	c.Encoding = encodingOTLPProto
+	if modify != nil {
+		modify(c)
+	}
+	require.NoError(t, c.validate())
+	m, err := newMarshaler(c.Encoding)
+	require.NoError(t, err)
+	return &exporter{client: client, marshaler: m, config: c, logger: zap.NewNop()}
+}
+
+func TestPushTracesPartitionByTraceID(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) { c.PartitionKey = partitionKeyTraceID })
+
+	traceIDs := [][]byte{
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+	}
+	td := newTraces("svc", traceIDs[0], traceIDs[1], traceIDs[0])
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, "test-stream", aws.StringValue(client.inputs[0].StreamName))
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, hex.EncodeToString(traceIDs[0]), aws.StringValue(records[0].PartitionKey))
+	assert.Equal(t, hex.EncodeToString(traceIDs[1]), aws.StringValue(records[1].PartitionKey))
+}
+
+func TestPushTracesJaegerProtoRecordPerSpan(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) {
+		c.Encoding = encodingJaegerProto
+		c.PartitionKey = partitionKeyTraceID
+	})
+
+	traceIDs := [][]byte{
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+	}
+	td := newTraces("svc", traceIDs[0], traceIDs[1], traceIDs[0])
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	records := client.records()
+	require.Len(t, records, 3)
+	assert.Equal(t, hex.EncodeToString(traceIDs[0]), aws.StringValue(records[0].PartitionKey))
+	assert.Equal(t, hex.EncodeToString(traceIDs[0]), aws.StringValue(records[1].PartitionKey))
+	assert.Equal(t, hex.EncodeToString(traceIDs[1]), aws.StringValue(records[2].PartitionKey))
+	for _, r := range records {
+		var span model.Span
+		require.NoError(t, span.Unmarshal(r.Data))
+		require.NotNil(t, span.Process)
+		assert.Equal(t, "svc", span.Process.ServiceName)
+	}
+}
+
+func TestPushTracesSplitsRecords(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) {
+		c.PartitionKey = partitionKeyServiceName
+		c.MaxRecordSize = 200
+	})
+
+	traceID := []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	td := newTraces("svc", traceID, traceID, traceID, traceID, traceID)
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	records := client.records()
+	assert.True(t, len(records) > 1, "expected the spans to be split across records")
+	for _, r := range records {
+		assert.Equal(t, "svc", aws.StringValue(r.PartitionKey))
+		assert.True(t, len(r.Data)+len(aws.StringValue(r.PartitionKey)) <= 200)
+	}
+}
+
+func TestPushTracesDropsOversizedSpan(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) { c.MaxRecordSize = 10 })
+
+	td := newTraces("svc", []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, dropped)
+	assert.Empty(t, client.inputs)
+}
+
+func TestPushTracesRetriesFailedRecords(t *testing.T) {
+	traceIDs := [][]byte{
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+	}
+	failedKey := hex.EncodeToString(traceIDs[1])
+	client := &mockKinesisClient{failures: map[string]int{failedKey: 1}}
+	e := newTestExporter(t, client, func(c *Config) { c.PartitionKey = partitionKeyTraceID })
+
+	td := newTraces("svc", traceIDs[0], traceIDs[1], traceIDs[1])
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	// Only the rejected record is sent again.
+	require.Len(t, client.inputs, 2)
+	assert.Len(t, client.inputs[0].Records, 2)
+	require.Len(t, client.inputs[1].Records, 1)
+	assert.Equal(t, failedKey, aws.StringValue(client.inputs[1].Records[0].PartitionKey))
+}
+
+func TestPushTracesFailedRecords(t *testing.T) {
+	traceIDs := [][]byte{
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+	}
+	client := &mockKinesisClient{failures: map[string]int{hex.EncodeToString(traceIDs[1]): maxPutAttempts}}
+	e := newTestExporter(t, client, func(c *Config) { c.PartitionKey = partitionKeyTraceID })
+
+	td := newTraces("svc", traceIDs[0], traceIDs[1], traceIDs[1])
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to put 1 of 1 records")
+	// The other record was written, retrying the batch would duplicate it.
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, dropped)
+	assert.Len(t, client.inputs, maxPutAttempts)
+}
+
+func TestPushTracesRequestError(t *testing.T) {
+	client := &mockKinesisClient{err: errors.New("no connection")}
+	e := newTestExporter(t, client, nil)
+
+	td := newTraces("svc", []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.EqualError(t, err, "no connection")
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, dropped)
+}
+
+func TestPushMetricsPartitionByServiceName(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) { c.PartitionKey = partitionKeyServiceName })
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(2)
+	for i, serviceName := range []string{"svc1", "svc2"} {
+		rm := md.ResourceMetrics().At(i)
+		rm.Resource().InitEmpty()
+		rm.Resource().Attributes().InsertString(conventions.AttributeServiceName, serviceName)
+		rm.InstrumentationLibraryMetrics().Resize(1)
+		metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		metrics.Resize(1)
+		metrics.At(0).SetName("gauge")
+		metrics.At(0).SetDataType(pdata.MetricDataTypeIntGauge)
+		metrics.At(0).IntGauge().InitEmpty()
+		metrics.At(0).IntGauge().DataPoints().Resize(2)
+	}
+
+	dropped, err := e.pushMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	records := client.records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "svc1", aws.StringValue(records[0].PartitionKey))
+	assert.Equal(t, "svc2", aws.StringValue(records[1].PartitionKey))
+}
+
+func TestPushLogsRandomPartitionKey(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, func(c *Config) { c.Encoding = encodingOTLPJSON })
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(3)
+
+	dropped, err := e.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	records := client.records()
+	require.Len(t, records, 1)
+	_, err = uuid.Parse(aws.StringValue(records[0].PartitionKey))
+	assert.NoError(t, err)
+}
+
+func TestSendBatchesRequests(t *testing.T) {
+	client := &mockKinesisClient{}
+	e := newTestExporter(t, client, nil)
+
+	rb := e.newRecordBuilder()
+	for i := 0; i < maxRecordsPerRequest+1; i++ {
+		rb.records = append(rb.records, record{data: []byte{1}, partitionKey: "key", count: 1})
+	}
+
+	dropped, err := e.send(context.Background(), rb)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	require.Len(t, client.inputs, 2)
+	assert.Len(t, client.inputs[0].Records, maxRecordsPerRequest)
+	assert.Len(t, client.inputs[1].Records, 1)
+}
+
+// newTraces returns traces holding a single resource with the given service
+// name and one span per trace id.
+func newTraces(serviceName string, traceIDs ...[]byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, serviceName)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(traceIDs))
+	for i, traceID := range traceIDs {
+		spans.At(i).SetTraceID(pdata.NewTraceID(traceID))
+		spans.At(i).SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, byte(i + 1)}))
+		spans.At(i).SetName("operation")
+	}
+	return td
+}
@@ -16,17 +16,17 @@ package kinesisexporter
 
 import (
 	"context"
+	"sync"
 
-	kinesis "github.com/signalfx/opencensus-go-exporter-kinesis"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
 )
 
 const (
 	// The value of "type" key in configuration.
-	typeStr      = "kinesis"
-	exportFormat = "jaeger-proto"
+	typeStr = "kinesis"
 )
 
 // NewFactory creates a factory for Kinesis exporter.
@@ -34,7 +34,9 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(createTraceExporter))
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -43,22 +45,15 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		TimeoutSettings: exporterhelper.CreateDefaultTimeoutSettings(),
+		QueueSettings:   exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings:   exporterhelper.CreateDefaultRetrySettings(),
 		AWS: AWSConfig{
 			Region: "us-west-2",
 		},
-		KPL: KPLConfig{
-			BatchSize:            5242880,
-			BatchCount:           1000,
-			BacklogCount:         2000,
-			FlushIntervalSeconds: 5,
-			MaxConnections:       24,
-		},
-
-		QueueSize:            100000,
-		NumWorkers:           8,
-		FlushIntervalSeconds: 5,
-		MaxBytesPerBatch:     100000,
-		MaxBytesPerSpan:      900000,
+		Encoding:      encodingJaegerProto,
+		PartitionKey:  partitionKeyRandom,
+		MaxRecordSize: maxRecordSize,
 	}
 }
 
@@ -67,33 +62,87 @@ func createTraceExporter(
 	params component.ExporterCreateParams,
 	config configmodels.Exporter,
 ) (component.TraceExporter, error) {
-	c := config.(*Config)
-	k, err := kinesis.NewExporter(&kinesis.Options{
-		Name:               c.Name(),
-		StreamName:         c.AWS.StreamName,
-		AWSRegion:          c.AWS.Region,
-		AWSRole:            c.AWS.Role,
-		AWSKinesisEndpoint: c.AWS.KinesisEndpoint,
+	c, err := resolveConfig(config.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	e, err := newExporter(c, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewTraceExporter(
+		c,
+		e.pushTraces,
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithRetry(c.RetrySettings))
+}
 
-		KPLAggregateBatchSize:   c.KPL.AggregateBatchSize,
-		KPLAggregateBatchCount:  c.KPL.AggregateBatchCount,
-		KPLBatchSize:            c.KPL.BatchSize,
-		KPLBatchCount:           c.KPL.BatchCount,
-		KPLBacklogCount:         c.KPL.BacklogCount,
-		KPLFlushIntervalSeconds: c.KPL.FlushIntervalSeconds,
-		KPLMaxConnections:       c.KPL.MaxConnections,
-		KPLMaxRetries:           c.KPL.MaxRetries,
-		KPLMaxBackoffSeconds:    c.KPL.MaxBackoffSeconds,
+func createMetricsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	config configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	c, err := resolveConfig(config.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if c.Encoding == encodingJaegerProto {
+		return nil, errUnsupportedEncoding
+	}
+	e, err := newExporter(c, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewMetricsExporter(
+		c,
+		e.pushMetrics,
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithRetry(c.RetrySettings))
+}
 
-		QueueSize:             c.QueueSize,
-		NumWorkers:            c.NumWorkers,
-		MaxAllowedSizePerSpan: c.MaxBytesPerSpan,
-		MaxListSize:           c.MaxBytesPerBatch,
-		ListFlushInterval:     c.FlushIntervalSeconds,
-		Encoding:              exportFormat,
-	}, params.Logger)
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	config configmodels.Exporter,
+) (component.LogsExporter, error) {
+	c, err := resolveConfig(config.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if c.Encoding == encodingJaegerProto {
+		return nil, errUnsupportedEncoding
+	}
+	e, err := newExporter(c, params.Logger)
 	if err != nil {
 		return nil, err
 	}
-	return Exporter{k, params.Logger}, nil
+	return exporterhelper.NewLogsExporter(
+		c,
+		e.pushLogs,
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithRetry(c.RetrySettings))
+}
+
+var resolvedLock sync.Mutex
+var resolvedConfigs = map[*Config]*Config{}
+
+// resolveConfig returns a copy of cfg with the deprecated settings mapped onto
+// the settings replacing them. The copy is shared by the exporters of all
+// signals created from cfg, so that the deprecation warnings are logged once.
+func resolveConfig(cfg *Config, logger *zap.Logger) (*Config, error) {
+	resolvedLock.Lock()
+	defer resolvedLock.Unlock()
+	if c, ok := resolvedConfigs[cfg]; ok {
+		return c, nil
+	}
+	c := *cfg
+	c.applyDeprecated(logger)
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	resolvedConfigs[cfg] = &c
+	return &c, nil
 }
@@ -3,7 +3,10 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kinesi
 go 1.14
 
 require (
-	github.com/signalfx/opencensus-go-exporter-kinesis v0.6.3
+	github.com/aws/aws-sdk-go v1.34.9
+	github.com/gogo/protobuf v1.3.1
+	github.com/google/uuid v1.1.2
+	github.com/jaegertracing/jaeger v1.19.2
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
 	go.uber.org/zap v1.16.0
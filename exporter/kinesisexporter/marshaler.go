@@ -0,0 +1,143 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinesisexporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+)
+
+var errUnsupportedEncoding = errors.New("encoding does not support this telemetry type")
+
+// marshaler encodes telemetry into the data of a Kinesis record.
+type marshaler interface {
+	MarshalTraces(td pdata.Traces) ([]byte, error)
+	MarshalMetrics(md pdata.Metrics) ([]byte, error)
+	MarshalLogs(ld pdata.Logs) ([]byte, error)
+}
+
+func newMarshaler(encoding string) (marshaler, error) {
+	switch encoding {
+	case encodingOTLPProto:
+		return otlpProtoMarshaler{}, nil
+	case encodingOTLPJSON:
+		return otlpJSONMarshaler{}, nil
+	case encodingJaegerProto:
+		return jaegerProtoMarshaler{}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// otlpProtoMarshaler encodes records as OTLP Export*ServiceRequest protobuf messages.
+type otlpProtoMarshaler struct{}
+
+func (otlpProtoMarshaler) MarshalTraces(td pdata.Traces) ([]byte, error) {
+	return td.ToOtlpProtoBytes()
+}
+
+func (otlpProtoMarshaler) MarshalMetrics(md pdata.Metrics) ([]byte, error) {
+	return md.ToOtlpProtoBytes()
+}
+
+func (otlpProtoMarshaler) MarshalLogs(ld pdata.Logs) ([]byte, error) {
+	return ld.ToOtlpProtoBytes()
+}
+
+// otlpJSONMarshaler encodes records as the JSON mapping of the OTLP
+// Export*ServiceRequest messages.
+type otlpJSONMarshaler struct{}
+
+func (m otlpJSONMarshaler) MarshalTraces(td pdata.Traces) ([]byte, error) {
+	resourceSpans := pdata.TracesToOtlp(td)
+	messages := make([]proto.Message, len(resourceSpans))
+	for i, rs := range resourceSpans {
+		messages[i] = rs
+	}
+	return m.marshal("resourceSpans", messages)
+}
+
+func (m otlpJSONMarshaler) MarshalMetrics(md pdata.Metrics) ([]byte, error) {
+	resourceMetrics := pdata.MetricsToOtlp(md)
+	messages := make([]proto.Message, len(resourceMetrics))
+	for i, rm := range resourceMetrics {
+		messages[i] = rm
+	}
+	return m.marshal("resourceMetrics", messages)
+}
+
+func (m otlpJSONMarshaler) MarshalLogs(ld pdata.Logs) ([]byte, error) {
+	resourceLogs := *ld.InternalRep().Orig
+	messages := make([]proto.Message, len(resourceLogs))
+	for i, rl := range resourceLogs {
+		messages[i] = rl
+	}
+	return m.marshal("resourceLogs", messages)
+}
+
+// marshal writes the messages as the JSON array field of the request, the
+// request message types themselves are internal to the collector.
+func (otlpJSONMarshaler) marshal(field string, messages []proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	var jsonMarshaler jsonpb.Marshaler
+	fmt.Fprintf(&buf, `{%q:[`, field)
+	for i, message := range messages {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonMarshaler.Marshal(&buf, message); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("]}")
+	return buf.Bytes(), nil
+}
+
+// jaegerProtoMarshaler encodes a single span as a Jaeger protobuf model.Span
+// carrying its process, the records written by the previous version of the
+// exporter.
+type jaegerProtoMarshaler struct{}
+
+func (jaegerProtoMarshaler) MarshalTraces(td pdata.Traces) ([]byte, error) {
+	if n := td.SpanCount(); n != 1 {
+		return nil, fmt.Errorf("a jaeger_proto record holds a single span, got %d", n)
+	}
+	batches, err := jaegertranslator.InternalTracesToJaegerProto(td)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		for _, span := range b.Spans {
+			if span.Process == nil {
+				span.Process = b.Process
+			}
+			return span.Marshal()
+		}
+	}
+	return nil, errors.New("no span to marshal")
+}
+
+func (jaegerProtoMarshaler) MarshalMetrics(pdata.Metrics) ([]byte, error) {
+	return nil, errUnsupportedEncoding
+}
+
+func (jaegerProtoMarshaler) MarshalLogs(pdata.Logs) ([]byte, error) {
+	return nil, errUnsupportedEncoding
+}
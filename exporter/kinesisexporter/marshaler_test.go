@@ -0,0 +1,82 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinesisexporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestOTLPJSONMarshaler(t *testing.T) {
+	m, err := newMarshaler(encodingOTLPJSON)
+	require.NoError(t, err)
+
+	data, err := m.MarshalTraces(newTraces("svc", []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	require.NoError(t, err)
+
+	var decoded struct {
+		ResourceSpans []struct {
+			InstrumentationLibrarySpans []struct {
+				Spans []struct {
+					Name string `json:"name"`
+				} `json:"spans"`
+			} `json:"instrumentationLibrarySpans"`
+		} `json:"resourceSpans"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.ResourceSpans, 1)
+	assert.Equal(t, "operation", decoded.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0].Name)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	data, err = m.MarshalLogs(ld)
+	require.NoError(t, err)
+	var decodedLogs map[string][]interface{}
+	require.NoError(t, json.Unmarshal(data, &decodedLogs))
+	assert.Len(t, decodedLogs["resourceLogs"], 2)
+}
+
+func TestJaegerProtoMarshaler(t *testing.T) {
+	m, err := newMarshaler(encodingJaegerProto)
+	require.NoError(t, err)
+
+	data, err := m.MarshalTraces(newTraces("svc", []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	require.NoError(t, err)
+
+	var span model.Span
+	require.NoError(t, span.Unmarshal(data))
+	assert.Equal(t, "operation", span.OperationName)
+	require.NotNil(t, span.Process)
+	assert.Equal(t, "svc", span.Process.ServiceName)
+
+	_, err = m.MarshalTraces(newTraces("svc",
+		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		[]byte{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+	))
+	assert.EqualError(t, err, "a jaeger_proto record holds a single span, got 2")
+
+	_, err = m.MarshalMetrics(pdata.NewMetrics())
+	assert.Equal(t, errUnsupportedEncoding, err)
+}
+
+func TestUnsupportedEncoding(t *testing.T) {
+	_, err := newMarshaler("zipkin")
+	assert.EqualError(t, err, `unsupported encoding "zipkin"`)
+}
@@ -0,0 +1,395 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinesisexporter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// maxPartitionKeyLength is the maximum length of a Kinesis partition key.
+const maxPartitionKeyLength = 256
+
+// record is the data and partition key of a Kinesis record, along with the
+// number of spans, data points or log records it holds.
+type record struct {
+	data         []byte
+	partitionKey string
+	count        int
+}
+
+// batch is telemetry of a single type sharing a partition key, which can be
+// split in two when it doesn't fit into a single record.
+type batch interface {
+	// count returns the number of items reported as dropped if the batch
+	// cannot be exported.
+	count() int
+	marshal(m marshaler) ([]byte, error)
+	// halve splits the batch in two, it returns false when the batch holds a
+	// single item and cannot be split any further.
+	halve() (batch, batch, bool)
+}
+
+// recordBuilder encodes batches into records no larger than maxRecordSize.
+type recordBuilder struct {
+	marshaler     marshaler
+	maxRecordSize int
+
+	records []record
+	dropped int
+	errs    []error
+}
+
+// add encodes b into one or more records using the given partition key, or
+// a random one per record if it is empty. Batches that don't fit are split
+// in half until they do, single items that are still too large are dropped.
+func (rb *recordBuilder) add(b batch, partitionKey string) {
+	data, err := b.marshal(rb.marshaler)
+	if err != nil {
+		rb.dropped += b.count()
+		rb.errs = append(rb.errs, consumererror.Permanent(err))
+		return
+	}
+
+	key := partitionKey
+	if key == "" {
+		key = randomPartitionKey()
+	}
+	if len(data)+len(key) <= rb.maxRecordSize {
+		rb.records = append(rb.records, record{data: data, partitionKey: key, count: b.count()})
+		return
+	}
+
+	first, second, ok := b.halve()
+	if !ok {
+		rb.dropped += b.count()
+		rb.errs = append(rb.errs, consumererror.Permanent(
+			fmt.Errorf("item of %d bytes exceeds the maximum record size of %d bytes", len(data), rb.maxRecordSize)))
+		return
+	}
+	rb.add(first, partitionKey)
+	rb.add(second, partitionKey)
+}
+
+// randomPartitionKey returns a random UUID, spreading the records across
+// all shards.
+func randomPartitionKey() string {
+	return uuid.New().String()
+}
+
+func truncatePartitionKey(key string) string {
+	if len(key) > maxPartitionKeyLength {
+		return key[:maxPartitionKeyLength]
+	}
+	return key
+}
+
+// serviceNamePartitionKey returns the service name of the resource, or an
+// empty string if it is not set.
+func serviceNamePartitionKey(resource pdata.Resource) string {
+	if resource.IsNil() {
+		return ""
+	}
+	if serviceName, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		return truncatePartitionKey(serviceName.StringVal())
+	}
+	return ""
+}
+
+// traceIDPartitionKey returns the hex encoded trace id, or an empty string
+// for telemetry that isn't part of a trace.
+func traceIDPartitionKey(traceID pdata.TraceID) string {
+	return hex.EncodeToString(traceID.Bytes())
+}
+
+// halfKey returns a key function that assigns the first half of n items
+// to "0" and the remaining ones to "1".
+func halfKey(n int) func() string {
+	i := 0
+	return func() string {
+		i++
+		if i <= n/2 {
+			return "0"
+		}
+		return "1"
+	}
+}
+
+// spanKey returns a key function that assigns every span a key of its own.
+func spanKey() func(pdata.Resource, pdata.Span) string {
+	i := 0
+	return func(pdata.Resource, pdata.Span) string {
+		i++
+		return strconv.Itoa(i)
+	}
+}
+
+type tracesBatch struct {
+	traces pdata.Traces
+}
+
+func (b tracesBatch) count() int {
+	return b.traces.SpanCount()
+}
+
+func (b tracesBatch) marshal(m marshaler) ([]byte, error) {
+	return m.MarshalTraces(b.traces)
+}
+
+func (b tracesBatch) halve() (batch, batch, bool) {
+	n := b.count()
+	if n <= 1 {
+		return nil, nil, false
+	}
+	next := halfKey(n)
+	_, groups := groupTraces(b.traces, func(pdata.Resource, pdata.Span) string { return next() })
+	return tracesBatch{groups["0"]}, tracesBatch{groups["1"]}, true
+}
+
+// groupTraces splits td by the key of each span, keeping the resource and
+// instrumentation library of the spans. The keys are returned in the order
+// they were first seen. Spans are shared with td, not copied.
+func groupTraces(td pdata.Traces, keyOf func(pdata.Resource, pdata.Span) string) ([]string, map[string]pdata.Traces) {
+	type cursor struct {
+		rs  pdata.ResourceSpans
+		ils pdata.InstrumentationLibrarySpans
+		i   int
+		j   int
+	}
+	var keys []string
+	groups := make(map[string]pdata.Traces)
+	cursors := make(map[string]*cursor)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				key := keyOf(rs.Resource(), span)
+				c, ok := cursors[key]
+				if !ok {
+					keys = append(keys, key)
+					groups[key] = pdata.NewTraces()
+					c = &cursor{i: -1, j: -1}
+					cursors[key] = c
+				}
+				if c.i != i {
+					dest := groups[key].ResourceSpans()
+					dest.Resize(dest.Len() + 1)
+					c.rs = dest.At(dest.Len() - 1)
+					rs.Resource().CopyTo(c.rs.Resource())
+					c.i, c.j = i, -1
+				}
+				if c.j != j {
+					dest := c.rs.InstrumentationLibrarySpans()
+					dest.Resize(dest.Len() + 1)
+					c.ils = dest.At(dest.Len() - 1)
+					ils.InstrumentationLibrary().CopyTo(c.ils.InstrumentationLibrary())
+					c.j = j
+				}
+				c.ils.Spans().Append(span)
+			}
+		}
+	}
+	return keys, groups
+}
+
+type metricsBatch struct {
+	metrics pdata.Metrics
+}
+
+func (b metricsBatch) count() int {
+	_, dataPoints := b.metrics.MetricAndDataPointCount()
+	return dataPoints
+}
+
+func (b metricsBatch) marshal(m marshaler) ([]byte, error) {
+	return m.MarshalMetrics(b.metrics)
+}
+
+// halve splits the batch by metrics, the data points of a metric are never
+// split across records.
+func (b metricsBatch) halve() (batch, batch, bool) {
+	n := b.metrics.MetricCount()
+	if n <= 1 {
+		return nil, nil, false
+	}
+	next := halfKey(n)
+	_, groups := groupMetrics(b.metrics, func(pdata.Resource, pdata.Metric) string { return next() })
+	return metricsBatch{groups["0"]}, metricsBatch{groups["1"]}, true
+}
+
+// groupMetrics splits md by the key of each metric, keeping the resource and
+// instrumentation library of the metrics. The keys are returned in the order
+// they were first seen. Metrics are shared with md, not copied.
+func groupMetrics(md pdata.Metrics, keyOf func(pdata.Resource, pdata.Metric) string) ([]string, map[string]pdata.Metrics) {
+	type cursor struct {
+		rm  pdata.ResourceMetrics
+		ilm pdata.InstrumentationLibraryMetrics
+		i   int
+		j   int
+	}
+	var keys []string
+	groups := make(map[string]pdata.Metrics)
+	cursors := make(map[string]*cursor)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+				key := keyOf(rm.Resource(), metric)
+				c, ok := cursors[key]
+				if !ok {
+					keys = append(keys, key)
+					groups[key] = pdata.NewMetrics()
+					c = &cursor{i: -1, j: -1}
+					cursors[key] = c
+				}
+				if c.i != i {
+					dest := groups[key].ResourceMetrics()
+					dest.Resize(dest.Len() + 1)
+					c.rm = dest.At(dest.Len() - 1)
+					rm.Resource().CopyTo(c.rm.Resource())
+					c.i, c.j = i, -1
+				}
+				if c.j != j {
+					dest := c.rm.InstrumentationLibraryMetrics()
+					dest.Resize(dest.Len() + 1)
+					c.ilm = dest.At(dest.Len() - 1)
+					ilm.InstrumentationLibrary().CopyTo(c.ilm.InstrumentationLibrary())
+					c.j = j
+				}
+				c.ilm.Metrics().Append(metric)
+			}
+		}
+	}
+	return keys, groups
+}
+
+type logsBatch struct {
+	logs pdata.Logs
+}
+
+func (b logsBatch) count() int {
+	return b.logs.LogRecordCount()
+}
+
+func (b logsBatch) marshal(m marshaler) ([]byte, error) {
+	return m.MarshalLogs(b.logs)
+}
+
+func (b logsBatch) halve() (batch, batch, bool) {
+	n := b.count()
+	if n <= 1 {
+		return nil, nil, false
+	}
+	next := halfKey(n)
+	_, groups := groupLogs(b.logs, func(pdata.Resource, pdata.LogRecord) string { return next() })
+	return logsBatch{groups["0"]}, logsBatch{groups["1"]}, true
+}
+
+// groupLogs splits ld by the key of each log record, keeping the resource and
+// instrumentation library of the records. The keys are returned in the order
+// they were first seen. Log records are shared with ld, not copied.
+func groupLogs(ld pdata.Logs, keyOf func(pdata.Resource, pdata.LogRecord) string) ([]string, map[string]pdata.Logs) {
+	type cursor struct {
+		rl  pdata.ResourceLogs
+		ill pdata.InstrumentationLibraryLogs
+		i   int
+		j   int
+	}
+	var keys []string
+	groups := make(map[string]pdata.Logs)
+	cursors := make(map[string]*cursor)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				logRecord := logs.At(k)
+				if logRecord.IsNil() {
+					continue
+				}
+				key := keyOf(rl.Resource(), logRecord)
+				c, ok := cursors[key]
+				if !ok {
+					keys = append(keys, key)
+					groups[key] = pdata.NewLogs()
+					c = &cursor{i: -1, j: -1}
+					cursors[key] = c
+				}
+				if c.i != i {
+					dest := groups[key].ResourceLogs()
+					dest.Resize(dest.Len() + 1)
+					c.rl = dest.At(dest.Len() - 1)
+					rl.Resource().CopyTo(c.rl.Resource())
+					c.i, c.j = i, -1
+				}
+				if c.j != j {
+					dest := c.rl.InstrumentationLibraryLogs()
+					dest.Resize(dest.Len() + 1)
+					c.ill = dest.At(dest.Len() - 1)
+					ill.InstrumentationLibrary().CopyTo(c.ill.InstrumentationLibrary())
+					c.j = j
+				}
+				c.ill.Logs().Append(logRecord)
+			}
+		}
+	}
+	return keys, groups
+}
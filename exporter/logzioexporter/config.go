@@ -16,6 +16,7 @@ package logzioexporter
 
 import (
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // Config contains Logz.io specific configuration such as Account Token, Region, etc.
@@ -24,4 +25,10 @@ type Config struct {
 	Token                         string `mapstructure:"account_token"`   // Your Logz.io Account Token, can be found at https://app.logz.io/#/dashboard/settings/general
 	Region                        string `mapstructure:"region"`          // Your Logz.io 2-letter region code, can be found at https://docs.logz.io/user-guide/accounts/account-region.html#available-regions
 	CustomEndpoint                string `mapstructure:"custom_endpoint"` // Custom endpoint to ship traces to. Use only for dev and tests. The will override the Region parameter
+	LogType                       string `mapstructure:"log_type"`        // Logz.io log type of the exported logs, used to select the parsing applied to them
+
+	// The timeout, queue and retry settings only apply to the logs exporter.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
 }
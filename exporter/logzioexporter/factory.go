@@ -29,13 +29,22 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(createTraceExporter))
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
 	return &Config{
-		Region: "",
-		Token:  "",
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: typeStr,
+		},
+		Region:          "",
+		Token:           "",
+		LogType:         defaultLogType,
+		TimeoutSettings: exporterhelper.CreateDefaultTimeoutSettings(),
+		QueueSettings:   exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings:   exporterhelper.CreateDefaultRetrySettings(),
 	}
 }
 
@@ -43,3 +52,8 @@ func createTraceExporter(_ context.Context, params component.ExporterCreateParam
 	config := cfg.(*Config)
 	return newLogzioTraceExporter(config, params)
 }
+
+func createLogsExporter(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.LogsExporter, error) {
+	config := cfg.(*Config)
+	return newLogzioLogsExporter(config, params)
+}
@@ -2,4 +2,8 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/logzio
 
 go 1.14
 
-require go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
+require (
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
+	go.uber.org/zap v1.16.0
+)
@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLogType = "otel"
+
+	listenerPort = 8071
+
+	// Field names of the JSON documents sent to the listener.
+	timestampField   = "@timestamp"
+	messageField     = "message"
+	logLevelField    = "log_level"
+	logTypeField     = "type"
+	nameField        = "name"
+	traceIDField     = "trace_id"
+	spanIDField      = "span_id"
+	traceFlagsField  = "trace_flags"
+	severityNumField = "severity_number"
+)
+
+type logzioLogsExporter struct {
+	client   *http.Client
+	endpoint string
+	logType  string
+	logger   *zap.Logger
+}
+
+func newLogzioLogsExporter(config *Config, params component.ExporterCreateParams) (component.LogsExporter, error) {
+	if config.Token == "" {
+		return nil, errors.New("\"account_token\" is required to export logs to logz.io")
+	}
+
+	endpoint, err := listenerURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := &logzioLogsExporter{
+		// The request timeout is enforced by the exporterhelper through the
+		// context of the request.
+		client:   &http.Client{},
+		endpoint: endpoint,
+		logType:  config.LogType,
+		logger:   params.Logger,
+	}
+
+	return exporterhelper.NewLogsExporter(
+		config,
+		exporter.pushLogsData,
+		exporterhelper.WithTimeout(config.TimeoutSettings),
+		exporterhelper.WithQueue(config.QueueSettings),
+		exporterhelper.WithRetry(config.RetrySettings))
+}
+
+// listenerURL returns the URL of the logz.io bulk HTTP listener of the
+// configured region, or the custom endpoint when set.
+func listenerURL(config *Config) (string, error) {
+	endpoint := config.CustomEndpoint
+	if endpoint == "" {
+		host := "listener.logz.io"
+		if config.Region != "" && config.Region != "us" {
+			host = fmt.Sprintf("listener-%s.logz.io", config.Region)
+		}
+		endpoint = fmt.Sprintf("https://%s:%d", host, listenerPort)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid logz.io listener endpoint %q: %w", endpoint, err)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	query := u.Query()
+	query.Set("token", config.Token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// redactURLError removes the query of the listener URL, which holds the
+// account token, from the errors of the HTTP client.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	redacted := "logz.io listener"
+	if u, perr := url.Parse(urlErr.URL); perr == nil {
+		u.RawQuery = ""
+		redacted = u.String()
+	}
+	return &url.Error{Op: urlErr.Op, URL: redacted, Err: urlErr.Err}
+}
+
+func (e *logzioLogsExporter) pushLogsData(ctx context.Context, ld pdata.Logs) (int, error) {
+	body, err := e.logsToBulkPayload(ld)
+	if err != nil {
+		return ld.LogRecordCount(), consumererror.Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, body)
+	if err != nil {
+		return ld.LogRecordCount(), consumererror.Permanent(redactURLError(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ld.LogRecordCount(), redactURLError(err)
+	}
+	// Drain the body so that the connection can be reused.
+	_, copyErr := io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// The logs were accepted, retrying them would duplicate them.
+		if copyErr != nil {
+			e.logger.Debug("Failed to read the logz.io listener response", zap.Error(copyErr))
+		}
+		return 0, nil
+	}
+
+	err = fmt.Errorf("logz.io listener responded with HTTP status %q", resp.Status)
+	// Requests rejected by the listener, other than throttled ones, would be
+	// rejected again so don't retry them.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		err = consumererror.Permanent(err)
+	}
+	return ld.LogRecordCount(), err
+}
+
+// logsToBulkPayload returns the gzipped newline-delimited JSON documents of
+// the log records.
+func (e *logzioLogsExporter) logsToBulkPayload(ld pdata.Logs) (*bytes.Buffer, error) {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	encoder := json.NewEncoder(gzipWriter)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		var resourceFields map[string]interface{}
+		if resource := rl.Resource(); !resource.IsNil() {
+			resourceFields = tracetranslator.AttributeMapToMap(resource.Attributes())
+		}
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				if log.IsNil() {
+					continue
+				}
+
+				if err := encoder.Encode(e.logRecordToDocument(resourceFields, log)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// logRecordToDocument converts a log record to the JSON document indexed by
+// logz.io. Attributes of the record override the ones of its resource.
+func (e *logzioLogsExporter) logRecordToDocument(resourceFields map[string]interface{}, log pdata.LogRecord) map[string]interface{} {
+	doc := make(map[string]interface{}, len(resourceFields)+log.Attributes().Len()+8)
+	for k, v := range resourceFields {
+		doc[k] = v
+	}
+	for k, v := range tracetranslator.AttributeMapToMap(log.Attributes()) {
+		doc[k] = v
+	}
+
+	if e.logType != "" {
+		doc[logTypeField] = e.logType
+	}
+	if log.Timestamp() != 0 {
+		doc[timestampField] = time.Unix(0, int64(log.Timestamp())).UTC().Format(time.RFC3339Nano)
+	}
+	doc[messageField] = tracetranslator.AttributeValueToString(log.Body(), false)
+	if log.Name() != "" {
+		doc[nameField] = log.Name()
+	}
+
+	if level := logLevel(log); level != "" {
+		doc[logLevelField] = level
+	}
+	if log.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		doc[severityNumField] = int32(log.SeverityNumber())
+	}
+
+	if traceID := log.TraceID(); len(traceID.Bytes()) != 0 {
+		doc[traceIDField] = traceID.HexString()
+	}
+	if spanID := log.SpanID(); len(spanID.Bytes()) != 0 {
+		doc[spanIDField] = spanID.HexString()
+	}
+	if log.Flags() != 0 {
+		doc[traceFlagsField] = log.Flags()
+	}
+
+	return doc
+}
+
+// logLevel returns the severity text of the log record, or the name of the
+// range of its severity number when there is no severity text.
+func logLevel(log pdata.LogRecord) string {
+	if log.SeverityText() != "" {
+		return log.SeverityText()
+	}
+
+	switch sev := log.SeverityNumber(); {
+	case sev == pdata.SeverityNumberUNDEFINED:
+		return ""
+	case sev < pdata.SeverityNumberDEBUG:
+		return "TRACE"
+	case sev < pdata.SeverityNumberINFO:
+		return "DEBUG"
+	case sev < pdata.SeverityNumberWARN:
+		return "INFO"
+	case sev < pdata.SeverityNumberERROR:
+		return "WARN"
+	case sev < pdata.SeverityNumberFATAL:
+		return "ERROR"
+	default:
+		return "FATAL"
+	}
+}
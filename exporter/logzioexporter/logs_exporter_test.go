@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logzioexporter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func newTestLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("host.name", "host-1")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	log := logs.At(0)
+	log.SetTimestamp(pdata.TimestampUnixNano(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC).UnixNano()))
+	log.Body().SetStringVal("hello")
+	log.SetSeverityText("Warning")
+	log.SetSeverityNumber(pdata.SeverityNumberWARN)
+	log.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	log.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	log.Attributes().InsertString("host.name", "host-2")
+	log.Attributes().InsertInt("attempt", 3)
+
+	log = logs.At(1)
+	log.Body().SetStringVal("failed")
+	log.SetSeverityNumber(pdata.SeverityNumberERROR2)
+	return ld
+}
+
+func TestLogsExporter(t *testing.T) {
+	var docs []map[string]interface{}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			doc := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &doc))
+			docs = append(docs, doc)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Token = "token"
+	cfg.CustomEndpoint = server.URL
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+
+	exp, err := createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.ConsumeLogs(context.Background(), newTestLogs()))
+
+	assert.Equal(t, "token=token", query)
+	require.Len(t, docs, 2)
+	assert.Equal(t, map[string]interface{}{
+		"@timestamp":      "2020-10-01T12:00:00Z",
+		"message":         "hello",
+		"type":            "otel",
+		"log_level":       "Warning",
+		"severity_number": float64(pdata.SeverityNumberWARN),
+		"trace_id":        "0102030405060708090a0b0c0d0e0f10",
+		"span_id":         "0102030405060708",
+		"host.name":       "host-2",
+		"attempt":         float64(3),
+	}, docs[0])
+	assert.Equal(t, "ERROR", docs[1]["log_level"])
+	assert.Equal(t, "host-1", docs[1]["host.name"])
+}
+
+func TestLogsExporterPermanentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Token = "token"
+	cfg.CustomEndpoint = server.URL
+	cfg.QueueSettings.Enabled = false
+
+	exp, err := createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	// Retries are enabled but the error is permanent so it returns right away.
+	assert.Error(t, exp.ConsumeLogs(context.Background(), newTestLogs()))
+}
+
+func TestLogsExporterErrorHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	// Nothing listens on the endpoint anymore, so the request fails.
+	server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Token = "secret-token"
+	cfg.CustomEndpoint = endpoint
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+
+	exp, err := createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	err = exp.ConsumeLogs(context.Background(), newTestLogs())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), endpoint)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestListenerURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{
+			name:     "default_region",
+			config:   Config{Token: "abc"},
+			expected: "https://listener.logz.io:8071/?token=abc",
+		},
+		{
+			name:     "eu_region",
+			config:   Config{Token: "abc", Region: "eu"},
+			expected: "https://listener-eu.logz.io:8071/?token=abc",
+		},
+		{
+			name:     "custom_endpoint",
+			config:   Config{Token: "abc", Region: "eu", CustomEndpoint: "http://localhost:9000/bulk"},
+			expected: "http://localhost:9000/bulk?token=abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listenerURL(&tt.config)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestLogsExporterRequiresToken(t *testing.T) {
+	_, err := createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, createDefaultConfig())
+	assert.EqualError(t, err, "\"account_token\" is required to export logs to logz.io")
+}
@@ -15,6 +15,8 @@
 package newrelicexporter
 
 import (
+	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
@@ -29,8 +31,8 @@ type Config struct {
 	APIKey string `mapstructure:"apikey"`
 
 	// Timeout is the total amount of time spent attempting a request,
-	// including retries, before abandoning and dropping data. Default is 15
-	// seconds.
+	// including retries, before abandoning and dropping data. It must be
+	// positive, the default is 15 seconds.
 	Timeout time.Duration `mapstructure:"timeout"`
 
 	// CommonAttributes are the attributes to be applied to all telemetry
@@ -42,10 +44,16 @@ type Config struct {
 
 	// SpansURLOverride overrides the spans endpoint.
 	SpansURLOverride string `mapstructure:"spans_url_override"`
+
+	// LogsURLOverride overrides the logs endpoint.
+	LogsURLOverride string `mapstructure:"logs_url_override"`
 }
 
 // HarvestOption sets all relevant Config values when instantiating a New
 // Relic Harvester.
+//
+// Deprecated: the exporter no longer uses a Harvester, it sends the data with
+// the request factories of the New Relic SDK.
 func (c Config) HarvestOption(cfg *telemetry.Config) {
 	cfg.APIKey = c.APIKey
 	cfg.HarvestPeriod = 0 // use collector harvest period.
@@ -55,4 +63,27 @@ func (c Config) HarvestOption(cfg *telemetry.Config) {
 	cfg.ProductVersion = version
 	cfg.MetricsURLOverride = c.MetricsURLOverride
 	cfg.SpansURLOverride = c.SpansURLOverride
+	cfg.LogsURLOverride = c.LogsURLOverride
+}
+
+// clientOptions returns the options of the New Relic request factories. The
+// host of urlOverride, when set, overrides their default endpoint.
+func (c Config) clientOptions(urlOverride string) ([]telemetry.ClientOption, error) {
+	opts := []telemetry.ClientOption{
+		telemetry.WithInsertKey(c.APIKey),
+		telemetry.WithUserAgent(product + "/" + version),
+	}
+
+	if urlOverride != "" {
+		u, err := url.Parse(urlOverride)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL override %q: %w", urlOverride, err)
+		}
+		opts = append(opts, telemetry.WithEndpoint(u.Host))
+		if u.Scheme == "http" {
+			opts = append(opts, telemetry.WithInsecure())
+		}
+	}
+
+	return opts, nil
 }
@@ -15,7 +15,9 @@
 package newrelicexporter
 
 import (
+	"context"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -60,6 +62,7 @@ func TestLoadConfig(t *testing.T) {
 		},
 		MetricsURLOverride: "http://alt.metrics.newrelic.com",
 		SpansURLOverride:   "http://alt.spans.newrelic.com",
+		LogsURLOverride:    "http://alt.logs.newrelic.com",
 	})
 
 	nrConfig := new(telemetry.Config)
@@ -75,7 +78,23 @@ func TestLoadConfig(t *testing.T) {
 		},
 		MetricsURLOverride: "http://alt.metrics.newrelic.com",
 		SpansURLOverride:   "http://alt.spans.newrelic.com",
+		LogsURLOverride:    "http://alt.logs.newrelic.com",
 		Product:            product,
 		ProductVersion:     version,
 	})
+
+	opts, err := r1.clientOptions(r1.SpansURLOverride)
+	require.NoError(t, err)
+	requestFactory, err := telemetry.NewSpanRequestFactory(opts...)
+	require.NoError(t, err)
+	req, err := requestFactory.BuildRequest(context.Background(), []telemetry.Batch{{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "http", req.URL.Scheme)
+	assert.Equal(t, "alt.spans.newrelic.com", req.URL.Host)
+	assert.Equal(t, "a1b2c3d4", req.Header.Get("Api-Key"))
+	assert.True(t, strings.HasSuffix(req.Header.Get("User-Agent"), " "+product+"/"+version))
+
+	_, err = r1.clientOptions("://bad")
+	assert.Error(t, err)
 }
@@ -31,7 +31,8 @@ func NewFactory() component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTraceExporter),
-		exporterhelper.WithMetrics(createMetricsExporter))
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -50,12 +51,12 @@ func createTraceExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.TraceExporter, error) {
-	exp, err := newExporter(params.Logger, cfg)
+	exp, err := newExporter(params.Logger, cfg, configmodels.TracesDataType)
 	if err != nil {
 		return nil, err
 	}
 
-	return exporterhelper.NewTraceExporter(cfg, exp.pushTraceData, exporterhelper.WithShutdown(exp.Shutdown))
+	return exporterhelper.NewTraceExporter(cfg, exp.pushTraceData, withTimeout(cfg))
 }
 
 // CreateMetricsExporter creates a New Relic metrics exporter for this configuration.
@@ -64,10 +65,30 @@ func createMetricsExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.MetricsExporter, error) {
-	exp, err := newExporter(params.Logger, cfg)
+	exp, err := newExporter(params.Logger, cfg, configmodels.MetricsDataType)
 	if err != nil {
 		return nil, err
 	}
 
-	return exporterhelper.NewMetricsExporter(cfg, exp.pushMetricData, exporterhelper.WithShutdown(exp.Shutdown))
+	return exporterhelper.NewMetricsExporter(cfg, exp.pushMetricData, withTimeout(cfg))
+}
+
+// CreateLogsExporter creates a New Relic logs exporter for this configuration.
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.LogsExporter, error) {
+	exp, err := newExporter(params.Logger, cfg, configmodels.LogsDataType)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(cfg, exp.pushLogData, withTimeout(cfg))
+}
+
+// withTimeout makes the data be sent, including retries, within the
+// configured timeout.
+func withTimeout(cfg configmodels.Exporter) exporterhelper.ExporterOption {
+	return exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: cfg.(*Config).Timeout})
 }
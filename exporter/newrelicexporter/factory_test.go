@@ -50,3 +50,15 @@ func TestCreateExporter(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, me, "failed to create metrics exporter")
 }
+
+func TestCreateExporterRejectsNonPositiveTimeout(t *testing.T) {
+	cfg := createDefaultConfig()
+	nrConfig := cfg.(*Config)
+	nrConfig.APIKey = "a1b2c3d4"
+	nrConfig.Timeout = 0
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	te, err := createTraceExporter(context.Background(), params, nrConfig)
+	assert.EqualError(t, err, "timeout must be positive")
+	assert.Nil(t, te)
+}
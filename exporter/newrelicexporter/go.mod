@@ -4,7 +4,7 @@ go 1.14
 
 require (
 	github.com/census-instrumentation/opencensus-proto v0.3.0
-	github.com/newrelic/newrelic-telemetry-sdk-go v0.4.0
+	github.com/newrelic/newrelic-telemetry-sdk-go v0.8.1
 	github.com/stretchr/testify v1.6.1
 	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
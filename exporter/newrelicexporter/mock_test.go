@@ -26,12 +26,13 @@ type Data struct {
 	Common          Common   `json:"common"`
 	Spans           []Span   `json:"spans"`
 	Metrics         []Metric `json:"metrics"`
+	Logs            []Log    `json:"logs"`
 	XXXUnrecognized []byte   `json:"-"`
 }
 
 type Common struct {
-	Attributes      map[string]string `json:"attributes"`
-	XXXUnrecognized []byte            `json:"-"`
+	Attributes      map[string]interface{} `json:"attributes"`
+	XXXUnrecognized []byte                 `json:"-"`
 }
 
 type Span struct {
@@ -51,6 +52,13 @@ type Metric struct {
 	XXXUnrecognized []byte                 `json:"-"`
 }
 
+type Log struct {
+	Message         string                 `json:"message"`
+	Timestamp       int64                  `json:"timestamp"`
+	Attributes      map[string]interface{} `json:"attributes"`
+	XXXUnrecognized []byte                 `json:"-"`
+}
+
 // Mock caches decompressed request bodies
 type Mock struct {
 	Data []Data
@@ -72,6 +80,14 @@ func (c *Mock) Metrics() []Metric {
 	return metrics
 }
 
+func (c *Mock) Logs() []Log {
+	var logs []Log
+	for _, data := range c.Data {
+		logs = append(logs, data.Logs...)
+	}
+	return logs
+}
+
 func (c *Mock) Server() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// telemetry sdk gzip compresses json payloads
@@ -16,70 +16,111 @@ package newrelicexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
+	"net/http"
+	"time"
 
 	"github.com/newrelic/newrelic-telemetry-sdk-go/cumulative"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
 const (
 	name    = "opentelemetry-collector"
 	version = "0.0.0"
 	product = "NewRelic-Collector-OpenTelemetry"
+
+	// maxRequestSize is the maximum compressed size of the requests accepted
+	// by New Relic, larger requests are split.
+	maxRequestSize = 1e6
 )
 
-var _ io.Writer = logWriter{}
+// backoffSequence is the time waited before retrying a request, by attempt.
+// Same as the one of the New Relic harvester.
+var backoffSequence = []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}
 
-// logWriter wraps a zap.Logger into an io.Writer.
-type logWriter struct {
-	logf func(string, ...zapcore.Field)
+// batch holds the data of a resource. The data of a batch is sent in the same
+// request, along with its common attributes, unless the request would be too
+// large.
+type batch struct {
+	// count is the number of items of the batch, as counted by the pipeline.
+	count int
+	// entries returns the request entries of the items [from, to).
+	entries func(from, to int) telemetry.Batch
 }
 
-// Write implements io.Writer
-func (w logWriter) Write(p []byte) (n int, err error) {
-	w.logf(string(p))
-	return len(p), nil
+// chunk is a range of the items of a batch.
+type chunk struct {
+	*batch
+	from, to int
 }
 
 // exporter exporters OpenTelemetry Collector data to New Relic.
 type exporter struct {
-	deltaCalculator *cumulative.DeltaCalculator
-	harvester       *telemetry.Harvester
+	deltaCalculator  *cumulative.DeltaCalculator
+	requestFactory   telemetry.RequestFactory
+	client           *http.Client
+	commonAttributes map[string]interface{}
+	logger           *zap.Logger
 }
 
-func newExporter(l *zap.Logger, c configmodels.Exporter) (*exporter, error) {
+func newExporter(l *zap.Logger, c configmodels.Exporter, dataType configmodels.DataType) (*exporter, error) {
 	nrConfig, ok := c.(*Config)
 	if !ok {
 		return nil, fmt.Errorf("invalid config: %#v", c)
 	}
+	if nrConfig.APIKey == "" {
+		return nil, errors.New("apikey is required")
+	}
+	// Requests are retried until the timeout expires.
+	if nrConfig.Timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
 
-	opts := []func(*telemetry.Config){
-		nrConfig.HarvestOption,
-		telemetry.ConfigBasicErrorLogger(logWriter{l.Error}),
-		telemetry.ConfigBasicDebugLogger(logWriter{l.Info}),
-		telemetry.ConfigBasicAuditLogger(logWriter{l.Debug}),
+	var (
+		newRequestFactory func(...telemetry.ClientOption) (telemetry.RequestFactory, error)
+		urlOverride       string
+	)
+	switch dataType {
+	case configmodels.TracesDataType:
+		newRequestFactory, urlOverride = telemetry.NewSpanRequestFactory, nrConfig.SpansURLOverride
+	case configmodels.MetricsDataType:
+		newRequestFactory, urlOverride = telemetry.NewMetricRequestFactory, nrConfig.MetricsURLOverride
+	case configmodels.LogsDataType:
+		newRequestFactory, urlOverride = telemetry.NewLogRequestFactory, nrConfig.LogsURLOverride
+	default:
+		return nil, fmt.Errorf("unsupported data type %q", dataType)
 	}
 
-	h, err := telemetry.NewHarvester(opts...)
-	if nil != err {
+	opts, err := nrConfig.clientOptions(urlOverride)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newRequestFactory(opts...)
+	if err != nil {
 		return nil, err
 	}
 
 	return &exporter{
-		deltaCalculator: cumulative.NewDeltaCalculator(),
-		harvester:       h,
+		deltaCalculator:  cumulative.NewDeltaCalculator(),
+		requestFactory:   f,
+		client:           &http.Client{},
+		commonAttributes: nrConfig.CommonAttributes,
+		logger:           l,
 	}, nil
 }
 
-func (e exporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
-	var errs []error
+func (e *exporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
+	var (
+		errs    []error
+		batches []batch
+	)
 	goodSpans := 0
 
 	octds := internaldata.TraceDataToOC(td)
@@ -95,28 +136,49 @@ func (e exporter) pushTraceData(ctx context.Context, td pdata.Traces) (int, erro
 			Resource:    octd.Resource,
 		}
 
+		var spans []telemetry.Span
 		for _, span := range octd.Spans {
 			nrSpan, err := transform.Span(span)
 			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
-			err = e.harvester.RecordSpan(nrSpan)
-			if err != nil {
-				errs = append(errs, err)
-				continue
+			if nrSpan.Timestamp.IsZero() {
+				nrSpan.Timestamp = time.Now()
 			}
-			goodSpans++
+			spans = append(spans, nrSpan)
+		}
+		if len(spans) == 0 {
+			continue
 		}
+
+		common, err := telemetry.NewSpanCommonBlock(telemetry.WithSpanAttributes(e.commonAttributes))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		batches = append(batches, batch{
+			count: len(spans),
+			entries: func(from, to int) telemetry.Batch {
+				return telemetry.Batch{common, telemetry.NewSpanGroup(spans[from:to])}
+			},
+		})
+		goodSpans += len(spans)
 	}
 
-	e.harvester.HarvestNow(ctx)
+	dropped, err := e.send(ctx, batches)
+	if err != nil {
+		errs = append(errs, err)
+	}
 
-	return td.SpanCount() - goodSpans, componenterror.CombineErrors(errs)
+	return td.SpanCount() - goodSpans + dropped, componenterror.CombineErrors(errs)
 }
 
-func (e exporter) pushMetricData(ctx context.Context, md pdata.Metrics) (int, error) {
-	var errs []error
+func (e *exporter) pushMetricData(ctx context.Context, md pdata.Metrics) (int, error) {
+	var (
+		errs    []error
+		batches []batch
+	)
 	goodMetrics := 0
 
 	ocmds := internaldata.MetricsToOC(md)
@@ -132,26 +194,253 @@ func (e exporter) pushMetricData(ctx context.Context, md pdata.Metrics) (int, er
 			Resource:        ocmd.Resource,
 		}
 
+		// The metrics converted from each metric, so that they are sent in
+		// the same request.
+		var metrics [][]telemetry.Metric
 		for _, metric := range ocmd.Metrics {
 			nrMetrics, err := transform.Metric(metric)
 			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
-			// TODO: optimize this, RecordMetric locks each call.
-			for _, m := range nrMetrics {
-				e.harvester.RecordMetric(m)
+			metrics = append(metrics, nrMetrics)
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+
+		// Metrics use the time they are sent at when they don't have one.
+		common, err := telemetry.NewMetricCommonBlock(
+			telemetry.WithMetricAttributes(e.commonAttributes),
+			telemetry.WithMetricTimestamp(time.Now()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		batches = append(batches, batch{
+			count: len(metrics),
+			entries: func(from, to int) telemetry.Batch {
+				var group []telemetry.Metric
+				for _, nrMetrics := range metrics[from:to] {
+					group = append(group, nrMetrics...)
+				}
+				return telemetry.Batch{common, telemetry.NewMetricGroup(group)}
+			},
+		})
+		goodMetrics += len(metrics)
+	}
+
+	dropped, err := e.send(ctx, batches)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return md.MetricCount() - goodMetrics + dropped, componenterror.CombineErrors(errs)
+}
+
+func (e *exporter) pushLogData(ctx context.Context, ld pdata.Logs) (int, error) {
+	var (
+		errs    []error
+		batches []batch
+	)
+	goodLogs := 0
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		transform := &transformer{}
+
+		var logs []telemetry.Log
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+
+			lrs := ill.Logs()
+			for k := 0; k < lrs.Len(); k++ {
+				log := lrs.At(k)
+				if log.IsNil() {
+					continue
+				}
+
+				nrLog, err := transform.Log(log)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if nrLog.Timestamp.IsZero() {
+					nrLog.Timestamp = time.Now()
+				}
+				logs = append(logs, nrLog)
+			}
+		}
+		if len(logs) == 0 {
+			continue
+		}
+
+		// The resource attributes are sent once for all the logs of the
+		// resource, as their common attributes.
+		common, err := telemetry.NewLogCommonBlock(telemetry.WithLogAttributes(e.logCommonAttributes(rl.Resource())))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		batches = append(batches, batch{
+			count: len(logs),
+			entries: func(from, to int) telemetry.Batch {
+				return telemetry.Batch{common, telemetry.NewLogGroup(logs[from:to])}
+			},
+		})
+		goodLogs += len(logs)
+	}
+
+	dropped, err := e.send(ctx, batches)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return ld.LogRecordCount() - goodLogs + dropped, componenterror.CombineErrors(errs)
+}
+
+// logCommonAttributes returns the common attributes of the logs of a
+// resource. The resource attributes override the configured ones.
+func (e *exporter) logCommonAttributes(resource pdata.Resource) map[string]interface{} {
+	length := len(e.commonAttributes)
+	if !resource.IsNil() {
+		length += resource.Attributes().Len()
+	}
+
+	attrs := make(map[string]interface{}, length)
+	for k, v := range e.commonAttributes {
+		attrs[k] = v
+	}
+	if !resource.IsNil() {
+		addAttributes(attrs, resource.Attributes())
+	}
+	return attrs
+}
+
+// send sends the batches to New Relic. It returns the number of items that
+// couldn't be sent.
+func (e *exporter) send(ctx context.Context, batches []batch) (int, error) {
+	if len(batches) == 0 {
+		return 0, nil
+	}
+
+	chunks := make([]chunk, len(batches))
+	for i := range batches {
+		chunks[i] = chunk{batch: &batches[i], from: 0, to: batches[i].count}
+	}
+	return e.sendChunks(ctx, chunks)
+}
+
+// sendChunks sends the chunks in a single request, or splits them in halves
+// sent separately when the request would be too large.
+func (e *exporter) sendChunks(ctx context.Context, chunks []chunk) (int, error) {
+	count := 0
+	payload := make([]telemetry.Batch, len(chunks))
+	for i, c := range chunks {
+		count += c.to - c.from
+		payload[i] = c.entries(c.from, c.to)
+	}
+
+	req, err := e.requestFactory.BuildRequest(ctx, payload)
+	if err != nil {
+		return count, consumererror.Permanent(err)
+	}
+
+	if req.ContentLength >= maxRequestSize {
+		var halves [2][]chunk
+		switch {
+		case len(chunks) > 1:
+			middle := len(chunks) / 2
+			halves = [2][]chunk{chunks[:middle], chunks[middle:]}
+		case count > 1:
+			c := chunks[0]
+			middle := c.from + count/2
+			halves = [2][]chunk{{{batch: c.batch, from: c.from, to: middle}}, {{batch: c.batch, from: middle, to: c.to}}}
+		}
+
+		if halves[0] != nil {
+			var errs []error
+			dropped := 0
+			for _, half := range halves {
+				n, err := e.sendChunks(ctx, half)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				dropped += n
 			}
-			goodMetrics++
+			return dropped, componenterror.CombineErrors(errs)
 		}
 	}
 
-	e.harvester.HarvestNow(ctx)
+	if err := e.post(ctx, req); err != nil {
+		return count, err
+	}
+	return 0, nil
+}
+
+// post sends the request to New Relic, retrying it like the New Relic
+// harvester until the context is done.
+func (e *exporter) post(ctx context.Context, req *http.Request) error {
+	for attempt := 0; ; attempt++ {
+		backoff := backoffSequence[len(backoffSequence)-1]
+		if attempt < len(backoffSequence) {
+			backoff = backoffSequence[attempt]
+		}
+
+		resp, err := e.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			switch {
+			case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted:
+				return nil
+			case isPermanentHTTPStatus(resp.StatusCode):
+				return consumererror.Permanent(fmt.Errorf("New Relic rejected the data with HTTP status %q", resp.Status))
+			}
 
-	return md.MetricCount() - goodMetrics, componenterror.CombineErrors(errs)
+			err = fmt.Errorf("New Relic responded with HTTP status %q", resp.Status)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				// Honor the Retry-After header, in seconds.
+				if retryAfter, perr := time.ParseDuration(resp.Header.Get("Retry-After") + "s"); perr == nil && retryAfter > backoff {
+					backoff = retryAfter
+				}
+			}
+		}
+
+		e.logger.Debug("Failed to send data to New Relic, retrying", zap.Error(err), zap.Duration("backoff", backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if req.Body, err = req.GetBody(); err != nil {
+			return consumererror.Permanent(err)
+		}
+	}
 }
 
-func (e exporter) Shutdown(ctx context.Context) error {
-	e.harvester.HarvestNow(ctx)
-	return nil
+// isPermanentHTTPStatus returns whether the data sent is not retried when New
+// Relic responds with the status code.
+func isPermanentHTTPStatus(code int) bool {
+	switch code {
+	case http.StatusBadRequest,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusMethodNotAllowed,
+		http.StatusLengthRequired,
+		http.StatusRequestEntityTooLarge:
+		return true
+	}
+	return false
 }
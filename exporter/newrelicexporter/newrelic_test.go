@@ -16,6 +16,11 @@ package newrelicexporter
 
 import (
 	"context"
+	"encoding/hex"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -26,30 +31,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func TestLogWriter(t *testing.T) {
-	var messages []string
-	l := logWriter{func(s string, _ ...zapcore.Field) {
-		messages = append(messages, s)
-	}}
-
-	n, err := l.Write([]byte("one"))
-	require.NoError(t, err)
-	assert.Equal(t, 3, n)
-	assert.Len(t, messages, 1)
-
-	n, err = l.Write([]byte("two"))
-	require.NoError(t, err)
-	assert.Equal(t, 3, n)
-	assert.Len(t, messages, 2)
-}
-
 func testTraceData(t *testing.T, expected []Span, td consumerdata.TraceData) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -418,3 +408,122 @@ func TestExportMetricDataFull(t *testing.T) {
 
 	testExportMetricData(t, expected, md)
 }
+
+func TestExportLogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &Mock{make([]Data, 0, 1)}
+	srv := m.Server()
+	defer srv.Close()
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "test-service")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+	logs.At(0).SetTimestamp(pdata.TimestampUnixNano(time.Unix(100, 0).UnixNano()))
+	logs.At(0).Body().SetStringVal("hello")
+	logs.At(0).SetTraceID(pdata.NewTraceID([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	logs.At(0).SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 1}))
+	// The second log doesn't have a message so it is dropped.
+
+	f := NewFactory()
+	c := f.CreateDefaultConfig().(*Config)
+	c.APIKey, c.LogsURLOverride = "1", srv.URL
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exp, err := f.CreateLogsExporter(context.Background(), params, c)
+	require.NoError(t, err)
+	assert.EqualError(t, exp.ConsumeLogs(ctx, ld), "empty log message")
+	require.NoError(t, exp.Shutdown(ctx))
+
+	assert.Equal(t, []Log{
+		{
+			Message:   "hello",
+			Timestamp: int64(100 * time.Second / time.Millisecond),
+			Attributes: map[string]interface{}{
+				"collector.name":    name,
+				"collector.version": version,
+				"trace.id":          "01010101010101010101010101010101",
+				"span.id":           "0000000000000001",
+			},
+		},
+	}, m.Logs())
+	require.Len(t, m.Data, 1)
+	assert.Equal(t, map[string]interface{}{"service.name": "test-service"}, m.Data[0].Common.Attributes)
+}
+
+func TestExportReportsRejectedData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	f := NewFactory()
+	c := f.CreateDefaultConfig().(*Config)
+	c.APIKey, c.SpansURLOverride = "1", srv.URL
+	exp, err := newExporter(zap.NewNop(), c, configmodels.TracesDataType)
+	require.NoError(t, err)
+
+	td := consumerdata.TraceData{
+		Spans: []*tracepb.Span{
+			{
+				TraceId: []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+				SpanId:  []byte{0, 0, 0, 0, 0, 0, 0, 1},
+				Name:    &tracepb.TruncatableString{Value: "root"},
+			},
+		},
+	}
+	dropped, err := exp.pushTraceData(ctx, internaldata.OCToTraceData(td))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, dropped)
+}
+
+func TestExportReportsPartiallyRejectedData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The data of the two resources doesn't fit in a request, only the
+	// first of the requests sent is rejected.
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	f := NewFactory()
+	c := f.CreateDefaultConfig().(*Config)
+	c.APIKey, c.LogsURLOverride = "1", srv.URL
+	exp, err := newExporter(zap.NewNop(), c, configmodels.LogsDataType)
+	require.NoError(t, err)
+
+	rnd := rand.New(rand.NewSource(1))
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	for i := 0; i < 2; i++ {
+		rl := ld.ResourceLogs().At(i)
+		rl.InstrumentationLibraryLogs().Resize(1)
+		logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+		logs.Resize(2)
+		for j := 0; j < 2; j++ {
+			b := make([]byte, maxRequestSize/4)
+			rnd.Read(b)
+			logs.At(j).Body().SetStringVal(hex.EncodeToString(b))
+		}
+	}
+
+	dropped, err := exp.pushLogData(ctx, ld)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
@@ -17,6 +17,8 @@ package newrelicexporter
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
@@ -26,6 +28,8 @@ import (
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -35,6 +39,15 @@ const (
 	collectorNameKey    = "collector.name"
 	collectorVersionKey = "collector.version"
 	serviceNameKey      = "service.name"
+	traceIDKey          = "trace.id"
+	spanIDKey           = "span.id"
+	logLevelKey         = "log.level"
+	logNameKey          = "name"
+	quantileKey         = "quantile"
+	bucketBoundKey      = "le"
+
+	quantilesSuffix = ".quantiles"
+	bucketsSuffix   = ".buckets"
 )
 
 type transformer struct {
@@ -177,9 +190,13 @@ func (t *transformer) Metric(metric *metricspb.Metric) ([]telemetry.Metric, erro
 				metricspb.MetricDescriptor_GAUGE_DOUBLE:
 				metrics = append(metrics, t.Gauge(md.Name, attr, point))
 			case
-				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
 				metricspb.MetricDescriptor_SUMMARY:
 				metrics = append(metrics, t.DeltaSummary(md.Name, attr, startTime, point))
+				metrics = append(metrics, t.Quantiles(md.Name, attr, point)...)
+			case
+				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION:
+				metrics = append(metrics, t.DeltaSummary(md.Name, attr, startTime, point))
+				metrics = append(metrics, t.DeltaBuckets(md.Name, attr, startTime, point)...)
 			case
 				metricspb.MetricDescriptor_CUMULATIVE_INT64,
 				metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
@@ -187,14 +204,43 @@ func (t *transformer) Metric(metric *metricspb.Metric) ([]telemetry.Metric, erro
 			case
 				metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION:
 				metrics = append(metrics, t.CumulativeSummary(md.Name, attr, startTime, point))
+				metrics = append(metrics, t.CumulativeBuckets(md.Name, attr, startTime, point)...)
 			default:
 				errs = append(errs, fmt.Errorf("unsupported metric type: %s", md.Type.String()))
 			}
 		}
 	}
+
+	for _, m := range metrics {
+		if !isFiniteMetric(m) {
+			errs = append(errs, fmt.Errorf("metric %q has a non-finite value", md.Name))
+			break
+		}
+	}
 	return metrics, componenterror.CombineErrors(errs)
 }
 
+// isFiniteMetric returns whether the values of the metric are finite, New
+// Relic rejects requests containing other values.
+func isFiniteMetric(m telemetry.Metric) bool {
+	var values []float64
+	switch m := m.(type) {
+	case telemetry.Gauge:
+		values = []float64{m.Value}
+	case telemetry.Count:
+		values = []float64{m.Value}
+	case telemetry.Summary:
+		values = []float64{m.Count, m.Sum, m.Min, m.Max}
+	}
+
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 func (t *transformer) MetricAttributes(metric *metricspb.Metric) map[string]interface{} {
 	length := 3
 
@@ -294,8 +340,8 @@ func (t *transformer) DeltaSummary(name string, attrs map[string]interface{}, st
 		m.Count = float64(t.DistributionValue.Count)
 		m.Sum = t.DistributionValue.Sum
 	case *metricspb.Point_SummaryValue:
-		m.Count = float64(t.SummaryValue.Count.Value)
-		m.Sum = t.SummaryValue.Sum.Value
+		m.Count = float64(t.SummaryValue.GetCount().GetValue())
+		m.Sum = t.SummaryValue.GetSum().GetValue()
 	}
 
 	return m
@@ -315,6 +361,12 @@ func (t *transformer) CumulativeCount(name string, attrs map[string]interface{},
 		now = t.Timestamp(point.Timestamp)
 	}
 
+	return t.deltaCount(name, attrs, value, start, now)
+}
+
+// deltaCount returns the count since the previous value of the cumulative
+// count, or the value itself for the first measurement or after a reset.
+func (t *transformer) deltaCount(name string, attrs map[string]interface{}, value float64, start, now time.Time) telemetry.Count {
 	count, valid := t.DeltaCalculator.CountMetric(name, attrs, value, now)
 
 	// This is the first measurement or a reset happened.
@@ -366,3 +418,157 @@ func (t *transformer) CumulativeSummary(name string, attrs map[string]interface{
 
 	return summary
 }
+
+// Quantiles returns a gauge for each quantile of a summary point, named after
+// the summary with a "quantile" attribute.
+func (t *transformer) Quantiles(name string, attrs map[string]interface{}, point *metricspb.Point) []telemetry.Metric {
+	summary := point.GetSummaryValue()
+	if summary == nil || summary.Snapshot == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if point.Timestamp != nil {
+		now = t.Timestamp(point.Timestamp)
+	}
+
+	metrics := make([]telemetry.Metric, 0, len(summary.Snapshot.PercentileValues))
+	for _, pv := range summary.Snapshot.PercentileValues {
+		metrics = append(metrics, telemetry.Gauge{
+			Name:       name + quantilesSuffix,
+			Attributes: withAttribute(attrs, quantileKey, pv.Percentile/100),
+			Value:      pv.Value,
+			Timestamp:  now,
+		})
+	}
+	return metrics
+}
+
+// DeltaBuckets returns a count for each bucket of a distribution point
+// covering a single interval, named after the distribution with a "le"
+// attribute. The count of a bucket includes the ones of the previous buckets.
+func (t *transformer) DeltaBuckets(name string, attrs map[string]interface{}, start time.Time, point *metricspb.Point) []telemetry.Metric {
+	now := time.Now()
+	if point.Timestamp != nil {
+		now = t.Timestamp(point.Timestamp)
+	}
+
+	var metrics []telemetry.Metric
+	forEachBucket(point, func(le string, count float64) {
+		metrics = append(metrics, telemetry.Count{
+			Name:       name + bucketsSuffix,
+			Attributes: withAttribute(attrs, bucketBoundKey, le),
+			Value:      count,
+			Timestamp:  start,
+			Interval:   now.Sub(start),
+		})
+	})
+	return metrics
+}
+
+// CumulativeBuckets is the equivalent of DeltaBuckets for cumulative
+// distribution points, it returns the counts since the previous point.
+func (t *transformer) CumulativeBuckets(name string, attrs map[string]interface{}, start time.Time, point *metricspb.Point) []telemetry.Metric {
+	now := time.Now()
+	if point.Timestamp != nil {
+		now = t.Timestamp(point.Timestamp)
+	}
+
+	var metrics []telemetry.Metric
+	forEachBucket(point, func(le string, count float64) {
+		metrics = append(metrics, t.deltaCount(name+bucketsSuffix, withAttribute(attrs, bucketBoundKey, le), count, start, now))
+	})
+	return metrics
+}
+
+// forEachBucket calls fn with the upper bound and the cumulative count of each
+// bucket of a distribution point with explicit bucket bounds.
+func forEachBucket(point *metricspb.Point, fn func(le string, count float64)) {
+	dist := point.GetDistributionValue()
+	bounds := dist.GetBucketOptions().GetExplicit().GetBounds()
+	if dist == nil || len(dist.Buckets) != len(bounds)+1 {
+		return
+	}
+
+	var count int64
+	for i, bucket := range dist.Buckets {
+		count += bucket.Count
+		le := "+Inf"
+		if i < len(bounds) {
+			le = strconv.FormatFloat(bounds[i], 'f', -1, 64)
+		}
+		fn(le, float64(count))
+	}
+}
+
+// withAttribute returns a copy of attrs with the additional attribute.
+func withAttribute(attrs map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+func (t *transformer) Log(log pdata.LogRecord) (telemetry.Log, error) {
+	message := tracetranslator.AttributeValueToString(log.Body(), false)
+	if message == "" {
+		return telemetry.Log{}, errors.New("empty log message")
+	}
+
+	nrLog := telemetry.Log{
+		Message:    message,
+		Attributes: t.LogAttributes(log),
+	}
+	if log.Timestamp() != 0 {
+		nrLog.Timestamp = time.Unix(0, int64(log.Timestamp()))
+	}
+	return nrLog, nil
+}
+
+// LogAttributes returns the attributes of a log record. The attributes of its
+// resource are sent as the common attributes of the logs of the resource.
+func (t *transformer) LogAttributes(log pdata.LogRecord) map[string]interface{} {
+	attrs := make(map[string]interface{}, log.Attributes().Len()+6)
+	addAttributes(attrs, log.Attributes())
+
+	if log.Name() != "" {
+		attrs[logNameKey] = log.Name()
+	}
+	if log.SeverityText() != "" {
+		attrs[logLevelKey] = log.SeverityText()
+	}
+
+	// Trace context of the log so it shows up in context of its trace.
+	if traceID := log.TraceID(); len(traceID.Bytes()) != 0 {
+		attrs[traceIDKey] = traceID.HexString()
+	}
+	if spanID := log.SpanID(); len(spanID.Bytes()) != 0 {
+		attrs[spanIDKey] = spanID.HexString()
+	}
+
+	attrs[collectorNameKey] = name
+	attrs[collectorVersionKey] = version
+
+	return attrs
+}
+
+// addAttributes adds the attributes to attrs, New Relic only supports boolean,
+// numeric and string attribute values so other values are converted to
+// strings.
+func addAttributes(attrs map[string]interface{}, attrMap pdata.AttributeMap) {
+	attrMap.ForEach(func(k string, v pdata.AttributeValue) {
+		switch v.Type() {
+		case pdata.AttributeValueBOOL:
+			attrs[k] = v.BoolVal()
+		case pdata.AttributeValueINT:
+			attrs[k] = v.IntVal()
+		case pdata.AttributeValueDOUBLE:
+			attrs[k] = v.DoubleVal()
+		case pdata.AttributeValueNULL:
+		default:
+			attrs[k] = tracetranslator.AttributeValueToString(v, false)
+		}
+	})
+}
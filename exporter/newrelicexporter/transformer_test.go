@@ -16,6 +16,7 @@ package newrelicexporter
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -303,6 +305,29 @@ func TestTransformGuage(t *testing.T) {
 	t.Run("Int64", func(t *testing.T) { testTransformMetric(t, gi, expected) })
 }
 
+func TestTransformNonFiniteGauge(t *testing.T) {
+	transform := &transformer{DeltaCalculator: cumulative.NewDeltaCalculator()}
+	_, err := transform.Metric(&metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: "gauge",
+			Type: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &timestamppb.Timestamp{Seconds: 1},
+						Value: &metricspb.Point_DoubleValue{
+							DoubleValue: math.NaN(),
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.EqualError(t, err, `metric "gauge" has a non-finite value`)
+}
+
 func TestTransformDeltaSummary(t *testing.T) {
 	start := &timestamppb.Timestamp{Seconds: 1}
 	ts := &timestamppb.Timestamp{Seconds: 2}
@@ -527,3 +552,239 @@ func TestTransformCumulativeSummary(t *testing.T) {
 	}
 	t.Run("Distribution", func(t *testing.T) { testTransformMetric(t, cd, expected) })
 }
+
+func TestTransformSummaryQuantiles(t *testing.T) {
+	start := &timestamppb.Timestamp{Seconds: 1}
+	ts := &timestamppb.Timestamp{Seconds: 2}
+	attrs := func(quantile float64) map[string]interface{} {
+		a := map[string]interface{}{
+			"collector.name":    name,
+			"collector.version": version,
+			"resource":          "R1",
+			"service.name":      "test-service",
+		}
+		if quantile >= 0 {
+			a["quantile"] = quantile
+		}
+		return a
+	}
+	expected := []telemetry.Metric{
+		telemetry.Summary{
+			Name:       "summary",
+			Timestamp:  time.Unix(1, 0),
+			Interval:   time.Second,
+			Attributes: attrs(-1),
+		},
+		telemetry.Gauge{
+			Name:       "summary.quantiles",
+			Value:      3,
+			Timestamp:  time.Unix(2, 0),
+			Attributes: attrs(0.5),
+		},
+		telemetry.Gauge{
+			Name:       "summary.quantiles",
+			Value:      9,
+			Timestamp:  time.Unix(2, 0),
+			Attributes: attrs(0.99),
+		},
+	}
+
+	s := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: "summary",
+			Type: metricspb.MetricDescriptor_SUMMARY,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				StartTimestamp: start,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: ts,
+						Value: &metricspb.Point_SummaryValue{
+							SummaryValue: &metricspb.SummaryValue{
+								Snapshot: &metricspb.SummaryValue_Snapshot{
+									PercentileValues: []*metricspb.SummaryValue_Snapshot_ValueAtPercentile{
+										{Percentile: 50, Value: 3},
+										{Percentile: 99, Value: 9},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	testTransformMetric(t, s, expected)
+}
+
+func distributionPoint(ts *timestamppb.Timestamp, counts ...int64) *metricspb.Point {
+	dist := &metricspb.DistributionValue{
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: []float64{1, 2.5},
+				},
+			},
+		},
+	}
+	for _, c := range counts {
+		dist.Count += c
+		dist.Buckets = append(dist.Buckets, &metricspb.DistributionValue_Bucket{Count: c})
+	}
+	return &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_DistributionValue{DistributionValue: dist},
+	}
+}
+
+func bucketAttrs(le string) map[string]interface{} {
+	return map[string]interface{}{
+		"collector.name":    name,
+		"collector.version": version,
+		"resource":          "R1",
+		"service.name":      "test-service",
+		"le":                le,
+	}
+}
+
+func TestTransformDeltaBuckets(t *testing.T) {
+	start := &timestamppb.Timestamp{Seconds: 1}
+	ts := &timestamppb.Timestamp{Seconds: 2}
+	gd := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: "histogram",
+			Type: metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				StartTimestamp: start,
+				Points:         []*metricspb.Point{distributionPoint(ts, 1, 2, 3)},
+			},
+		},
+	}
+
+	expected := []telemetry.Metric{
+		telemetry.Summary{
+			Name:      "histogram",
+			Count:     6,
+			Timestamp: time.Unix(1, 0),
+			Interval:  time.Second,
+			Attributes: map[string]interface{}{
+				"collector.name":    name,
+				"collector.version": version,
+				"resource":          "R1",
+				"service.name":      "test-service",
+			},
+		},
+		telemetry.Count{
+			Name:       "histogram.buckets",
+			Value:      1,
+			Timestamp:  time.Unix(1, 0),
+			Interval:   time.Second,
+			Attributes: bucketAttrs("1"),
+		},
+		telemetry.Count{
+			Name:       "histogram.buckets",
+			Value:      3,
+			Timestamp:  time.Unix(1, 0),
+			Interval:   time.Second,
+			Attributes: bucketAttrs("2.5"),
+		},
+		telemetry.Count{
+			Name:       "histogram.buckets",
+			Value:      6,
+			Timestamp:  time.Unix(1, 0),
+			Interval:   time.Second,
+			Attributes: bucketAttrs("+Inf"),
+		},
+	}
+	testTransformMetric(t, gd, expected)
+}
+
+func TestTransformCumulativeBuckets(t *testing.T) {
+	transform := &transformer{
+		DeltaCalculator: cumulative.NewDeltaCalculator(),
+		ServiceName:     "test-service",
+		Resource: &resourcepb.Resource{
+			Labels: map[string]string{
+				"resource": "R1",
+			},
+		},
+	}
+	metric := func(point *metricspb.Point) *metricspb.Metric {
+		return &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name: "histogram",
+				Type: metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					StartTimestamp: &timestamppb.Timestamp{Seconds: 1},
+					Points:         []*metricspb.Point{point},
+				},
+			},
+		}
+	}
+
+	bucketValues := func(metrics []telemetry.Metric) map[string]float64 {
+		values := map[string]float64{}
+		for _, m := range metrics {
+			c, ok := m.(telemetry.Count)
+			if !ok || c.Name != "histogram.buckets" {
+				continue
+			}
+			// Deltas only have their attributes encoded in JSON.
+			attrs := c.Attributes
+			if attrs == nil {
+				require.NoError(t, json.Unmarshal(c.AttributesJSON, &attrs))
+			}
+			values[attrs["le"].(string)] = c.Value
+		}
+		return values
+	}
+
+	// The first point is sent as is.
+	got, err := transform.Metric(metric(distributionPoint(&timestamppb.Timestamp{Seconds: 2}, 1, 2, 3)))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"1": 1, "2.5": 3, "+Inf": 6}, bucketValues(got))
+
+	// The next ones are sent as deltas.
+	got, err = transform.Metric(metric(distributionPoint(&timestamppb.Timestamp{Seconds: 3}, 2, 2, 5)))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"1": 1, "2.5": 1, "+Inf": 3}, bucketValues(got))
+}
+
+func TestTransformLog(t *testing.T) {
+	log := pdata.NewLogRecord()
+	log.InitEmpty()
+	log.SetTimestamp(pdata.TimestampUnixNano(time.Unix(2, 0).UnixNano()))
+	log.Body().SetStringVal("hello")
+	log.SetSeverityText("WARN")
+	log.SetTraceID(pdata.NewTraceID([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	log.SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 1}))
+	log.Attributes().InsertInt("attempt", 2)
+	log.Attributes().InsertBool("retry", true)
+
+	transform := &transformer{}
+	got, err := transform.Log(log)
+	require.NoError(t, err)
+	assert.Equal(t, telemetry.Log{
+		Message:   "hello",
+		Timestamp: time.Unix(2, 0),
+		Attributes: map[string]interface{}{
+			"collector.name":    name,
+			"collector.version": version,
+			"attempt":           int64(2),
+			"retry":             true,
+			"log.level":         "WARN",
+			"trace.id":          "01010101010101010101010101010101",
+			"span.id":           "0000000000000001",
+		},
+	}, got)
+
+	empty := pdata.NewLogRecord()
+	empty.InitEmpty()
+	_, err = transform.Log(empty)
+	assert.Error(t, err)
+}
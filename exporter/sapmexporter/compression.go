@@ -0,0 +1,89 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sapmexporter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// Same value used by the SAPM client for its default HTTP client.
+	defaultMaxIdleConns = 100
+)
+
+// zstdTransport compresses the body of outgoing requests with zstd.
+type zstdTransport struct {
+	next    http.RoundTripper
+	encoder *zstd.Encoder
+}
+
+func newZstdHTTPClient(maxConns uint, timeout time.Duration) *http.Client {
+	maxIdleConns := defaultMaxIdleConns
+	if maxConns > 0 {
+		maxIdleConns = int(maxConns)
+	}
+
+	// An encoder without a writer is only used through EncodeAll which is
+	// safe for concurrent use, so it can be shared by all workers. NewWriter
+	// only fails on invalid options.
+	encoder, _ := zstd.NewWriter(nil)
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &zstdTransport{
+			next: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   30 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				MaxIdleConns:        maxIdleConns,
+				MaxIdleConnsPerHost: maxIdleConns,
+				IdleConnTimeout:     30 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			encoder: encoder,
+		},
+	}
+}
+
+func (t *zstdTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	compressed := t.encoder.EncodeAll(body, make([]byte, 0, len(body)/2))
+
+	// RoundTrippers must not modify the original request.
+	zreq := req.Clone(req.Context())
+	zreq.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	zreq.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	zreq.ContentLength = int64(len(compressed))
+	zreq.Header.Set("Content-Encoding", compressionZstd)
+	return t.next.RoundTrip(zreq)
+}
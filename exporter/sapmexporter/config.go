@@ -16,6 +16,7 @@ package sapmexporter
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 
 	sapmclient "github.com/signalfx/sapm-proto/client"
@@ -28,6 +29,10 @@ import (
 const (
 	defaultEndpointScheme = "https"
 	defaultNumWorkers     = 8
+
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+	compressionNone = "none"
 )
 
 // Config defines configuration for SAPM exporter.
@@ -49,8 +54,18 @@ type Config struct {
 	MaxConnections uint `mapstructure:"max_connections"`
 
 	// Disable GZip compression.
+	// Deprecated: use Compression set to "none" instead.
 	DisableCompression bool `mapstructure:"disable_compression"`
 
+	// Compression is the compression applied to outgoing requests, one of
+	// "gzip", "zstd" or "none". Defaults to "gzip".
+	Compression string `mapstructure:"compression"`
+
+	// MaxSpansPerRequest is the maximum number of spans sent in a single
+	// request. Spans for each access token are split across as many requests
+	// as needed. No limit is applied if 0.
+	MaxSpansPerRequest int `mapstructure:"max_spans_per_request"`
+
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
 
 	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
@@ -72,9 +87,31 @@ func (c *Config) validate() error {
 		e.Scheme = defaultEndpointScheme
 	}
 	c.Endpoint = e.String()
+
+	switch c.Compression {
+	case "", compressionGzip, compressionZstd, compressionNone:
+	default:
+		return fmt.Errorf("`compression` must be one of %q, %q or %q", compressionGzip, compressionZstd, compressionNone)
+	}
+
+	if c.MaxSpansPerRequest < 0 {
+		return errors.New("`max_spans_per_request` cannot be negative")
+	}
 	return nil
 }
 
+// compression returns the effective compression taking the deprecated
+// DisableCompression setting into account.
+func (c *Config) compression() string {
+	if c.DisableCompression {
+		return compressionNone
+	}
+	if c.Compression == "" {
+		return compressionGzip
+	}
+	return c.Compression
+}
+
 func (c *Config) clientOptions() []sapmclient.Option {
 	opts := []sapmclient.Option{
 		sapmclient.WithEndpoint(c.Endpoint),
@@ -91,8 +128,15 @@ func (c *Config) clientOptions() []sapmclient.Option {
 		opts = append(opts, sapmclient.WithAccessToken(c.AccessToken))
 	}
 
-	if c.DisableCompression {
+	switch c.compression() {
+	case compressionNone:
 		opts = append(opts, sapmclient.WithDisabledCompression())
+	case compressionZstd:
+		// The SAPM client only knows how to gzip requests so they are sent
+		// uncompressed and zstd is applied by the HTTP transport instead.
+		opts = append(opts,
+			sapmclient.WithDisabledCompression(),
+			sapmclient.WithHTTPClient(newZstdHTTPClient(c.MaxConnections, c.Timeout)))
 	}
 
 	return opts
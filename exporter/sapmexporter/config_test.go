@@ -50,11 +50,13 @@ func TestLoadConfig(t *testing.T) {
 	r1 := cfg.Exporters["sapm/customname"].(*Config)
 	assert.Equal(t, r1,
 		&Config{
-			ExporterSettings: configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "sapm/customname"},
-			Endpoint:         "test-endpoint",
-			AccessToken:      "abcd1234",
-			NumWorkers:       3,
-			MaxConnections:   45,
+			ExporterSettings:   configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "sapm/customname"},
+			Endpoint:           "test-endpoint",
+			AccessToken:        "abcd1234",
+			NumWorkers:         3,
+			MaxConnections:     45,
+			Compression:        "zstd",
+			MaxSpansPerRequest: 1000,
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: false,
 			},
@@ -92,4 +94,26 @@ func TestInvalidConfig(t *testing.T) {
 	}
 	invalidURLErr := invalid.validate()
 	require.Error(t, invalidURLErr)
+
+	invalid = Config{
+		Endpoint:    "test-endpoint",
+		Compression: "lz4",
+	}
+	require.EqualError(t, invalid.validate(), "`compression` must be one of \"gzip\", \"zstd\" or \"none\"")
+
+	invalid = Config{
+		Endpoint:           "test-endpoint",
+		MaxSpansPerRequest: -1,
+	}
+	require.EqualError(t, invalid.validate(), "`max_spans_per_request` cannot be negative")
+}
+
+func TestCompression(t *testing.T) {
+	assert.Equal(t, "gzip", (&Config{}).compression())
+	assert.Equal(t, "zstd", (&Config{Compression: "zstd"}).compression())
+	assert.Equal(t, "none", (&Config{Compression: "none"}).compression())
+	assert.Equal(t, "none", (&Config{Compression: "zstd", DisableCompression: true}).compression())
+
+	// The zstd client uses the configured timeout.
+	assert.Equal(t, 3*time.Second, newZstdHTTPClient(0, 3*time.Second).Timeout)
 }
@@ -18,6 +18,7 @@ package sapmexporter
 import (
 	"context"
 
+	jaegerpb "github.com/jaegertracing/jaeger/model"
 	sapmclient "github.com/signalfx/sapm-proto/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
@@ -120,20 +121,73 @@ func (se *sapmExporter) pushTraceData(ctx context.Context, td pdata.Traces) (dro
 	for accessToken, trace := range traces {
 		batches, translateErr := jaeger.InternalTracesToJaegerProto(trace)
 		if translateErr != nil {
-			droppedSpansCount += trace.SpanCount()
+			spanCount := trace.SpanCount()
+			recordSpans(ctx, accessToken, 0, spanCount)
+			droppedSpansCount += spanCount
 			err = consumererror.Permanent(translateErr)
 			continue
 		}
 
-		exportErr := se.client.ExportWithAccessToken(ctx, batches, accessToken)
-		if exportErr != nil {
-			if sendErr, ok := exportErr.(*sapmclient.ErrSend); ok {
-				if sendErr.Permanent {
-					err = consumererror.Permanent(sendErr)
+		for _, request := range splitBatches(batches, se.config.MaxSpansPerRequest) {
+			spanCount := batchesSpanCount(request)
+			exportErr := se.client.ExportWithAccessToken(ctx, request, accessToken)
+			if exportErr != nil {
+				if sendErr, ok := exportErr.(*sapmclient.ErrSend); ok {
+					if sendErr.Permanent {
+						err = consumererror.Permanent(sendErr)
+					}
 				}
+				recordSpans(ctx, accessToken, 0, spanCount)
+				droppedSpansCount += spanCount
+				continue
 			}
-			droppedSpansCount += trace.SpanCount()
+			recordSpans(ctx, accessToken, spanCount, 0)
 		}
 	}
 	return
 }
+
+// splitBatches groups batches into requests of at most maxSpans spans each.
+// Batches that don't fit in a request are split into several batches sharing
+// the same process. Batches are returned as a single request if maxSpans is
+// not positive.
+func splitBatches(batches []*jaegerpb.Batch, maxSpans int) [][]*jaegerpb.Batch {
+	if maxSpans <= 0 {
+		return [][]*jaegerpb.Batch{batches}
+	}
+
+	var requests [][]*jaegerpb.Batch
+	var request []*jaegerpb.Batch
+	requestSpans := 0
+	for _, batch := range batches {
+		spans := batch.Spans
+		for len(spans) > 0 {
+			n := maxSpans - requestSpans
+			if n > len(spans) {
+				n = len(spans)
+			}
+
+			request = append(request, &jaegerpb.Batch{Process: batch.Process, Spans: spans[:n]})
+			spans = spans[n:]
+			requestSpans += n
+
+			if requestSpans == maxSpans {
+				requests = append(requests, request)
+				request = nil
+				requestSpans = 0
+			}
+		}
+	}
+	if len(request) > 0 {
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func batchesSpanCount(batches []*jaegerpb.Batch) int {
+	count := 0
+	for _, batch := range batches {
+		count += len(batch.Spans)
+	}
+	return count
+}
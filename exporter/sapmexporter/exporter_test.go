@@ -15,14 +15,21 @@
 package sapmexporter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
+	jaegerpb "github.com/jaegertracing/jaeger/model"
+	"github.com/klauspost/compress/zstd"
+	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -280,3 +287,128 @@ func TestSAPMClientTokenUsageAndErrorMarshalling(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitBatches(t *testing.T) {
+	process1 := &jaegerpb.Process{ServiceName: "service1"}
+	process2 := &jaegerpb.Process{ServiceName: "service2"}
+	spans := make([]*jaegerpb.Span, 5)
+	for i := range spans {
+		spans[i] = &jaegerpb.Span{OperationName: fmt.Sprintf("span%d", i)}
+	}
+	batches := []*jaegerpb.Batch{
+		{Process: process1, Spans: spans[:3]},
+		{Process: process2, Spans: spans[3:]},
+	}
+
+	assert.Equal(t, [][]*jaegerpb.Batch{batches}, splitBatches(batches, 0))
+	assert.Equal(t, [][]*jaegerpb.Batch{
+		{{Process: process1, Spans: spans[:2]}},
+		{{Process: process1, Spans: spans[2:3]}, {Process: process2, Spans: spans[3:4]}},
+		{{Process: process2, Spans: spans[4:]}},
+	}, splitBatches(batches, 2))
+	assert.Equal(t, [][]*jaegerpb.Batch{
+		{{Process: process1, Spans: spans[:3]}, {Process: process2, Spans: spans[3:]}},
+	}, splitBatches(batches, 10))
+}
+
+func TestMaxSpansPerRequestAndCompression(t *testing.T) {
+	tests := []struct {
+		compression     string
+		contentEncoding string
+		decode          func([]byte) ([]byte, error)
+	}{
+		{
+			compression:     "gzip",
+			contentEncoding: "gzip",
+			decode: func(b []byte) ([]byte, error) {
+				r, err := gzip.NewReader(bytes.NewReader(b))
+				if err != nil {
+					return nil, err
+				}
+				return ioutil.ReadAll(r)
+			},
+		},
+		{
+			compression:     "zstd",
+			contentEncoding: "zstd",
+			decode: func(b []byte) ([]byte, error) {
+				d, err := zstd.NewReader(nil)
+				if err != nil {
+					return nil, err
+				}
+				defer d.Close()
+				return d.DecodeAll(b, nil)
+			},
+		},
+		{
+			compression: "none",
+			decode: func(b []byte) ([]byte, error) {
+				return b, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compression, func(t *testing.T) {
+			var mu sync.Mutex
+			spansByToken := map[string][]int{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.contentEncoding, r.Header.Get("Content-Encoding"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				decoded, err := tt.decode(body)
+				require.NoError(t, err)
+
+				var req splunksapm.PostSpansRequest
+				require.NoError(t, req.Unmarshal(decoded))
+				spans := 0
+				for _, batch := range req.Batches {
+					spans += len(batch.Spans)
+				}
+
+				mu.Lock()
+				token := r.Header.Get("x-sf-token")
+				spansByToken[token] = append(spansByToken[token], spans)
+				mu.Unlock()
+			}))
+			defer server.Close()
+
+			config := &Config{
+				Endpoint:           server.URL,
+				AccessToken:        "ClientAccessToken",
+				Compression:        tt.compression,
+				MaxSpansPerRequest: 10,
+				AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
+					AccessTokenPassthrough: true,
+				},
+			}
+			se, err := newSAPMExporter(config, component.ExporterCreateParams{Logger: zap.NewNop()})
+			require.NoError(t, err)
+			defer se.Shutdown(context.Background())
+
+			traces, _ := buildTestTraces(true, true)
+			for i := 0; i < traces.ResourceSpans().Len(); i++ {
+				span := traces.ResourceSpans().At(i).InstrumentationLibrarySpans().At(0).Spans().At(0)
+				span.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)}))
+				span.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+			}
+			dropped, err := se.pushTraceData(context.Background(), traces)
+			require.NoError(t, err)
+			assert.Equal(t, 0, dropped)
+
+			// 25 spans without a token, 12 and 13 spans for each of the 2 tokens.
+			assert.Equal(t, map[string][]int{
+				"ClientAccessToken": {10, 10, 5},
+				"MyToken0":          {10, 2},
+				"MyToken1":          {10, 3},
+			}, spansByToken)
+		})
+	}
+}
+
+func TestAccessTokenID(t *testing.T) {
+	assert.Equal(t, "default", accessTokenID(""))
+	assert.Len(t, accessTokenID("MyToken0"), 16)
+	assert.NotEqual(t, accessTokenID("MyToken0"), accessTokenID("MyToken1"))
+	assert.NotContains(t, accessTokenID("MyToken0"), "MyToken0")
+}
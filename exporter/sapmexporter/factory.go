@@ -16,7 +16,9 @@ package sapmexporter
 
 import (
 	"context"
+	"sync"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -29,8 +31,15 @@ const (
 	typeStr = "sapm"
 )
 
+var once sync.Once
+
 // NewFactory creates a factory for SAPM exporter.
 func NewFactory() component.ExporterFactory {
+	// register views for self-observability
+	once.Do(func() {
+		view.Register(viewSpansSent, viewSpansDropped)
+	})
+
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -46,7 +55,8 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		NumWorkers: defaultNumWorkers,
+		NumWorkers:  defaultNumWorkers,
+		Compression: compressionGzip,
 		AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 			AccessTokenPassthrough: true,
 		},
@@ -3,9 +3,12 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sapmex
 go 1.14
 
 require (
+	github.com/jaegertracing/jaeger v1.19.2
+	github.com/klauspost/compress v1.10.10
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
 	github.com/signalfx/sapm-proto v0.6.0
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
 	go.uber.org/zap v1.16.0
 )
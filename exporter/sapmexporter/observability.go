@@ -0,0 +1,78 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sapmexporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	mSpansSent    = stats.Int64("sapmexporter/spans_sent", "Number of spans sent per access token", stats.UnitDimensionless)
+	mSpansDropped = stats.Int64("sapmexporter/spans_dropped", "Number of spans dropped per access token", stats.UnitDimensionless)
+
+	// Access tokens are secrets so they are identified by a fingerprint
+	// instead of being used as tag values.
+	tokenIDKey = tag.MustNewKey("access_token_id")
+)
+
+// defaultTokenID identifies spans sent with the access token from the
+// exporter configuration.
+const defaultTokenID = "default"
+
+var viewSpansSent = &view.View{
+	Name:        mSpansSent.Name(),
+	Description: mSpansSent.Description(),
+	Measure:     mSpansSent,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{tokenIDKey},
+}
+
+var viewSpansDropped = &view.View{
+	Name:        mSpansDropped.Name(),
+	Description: mSpansDropped.Description(),
+	Measure:     mSpansDropped,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{tokenIDKey},
+}
+
+// accessTokenID returns the first 8 bytes of the SHA-256 hash of the token,
+// hex encoded.
+func accessTokenID(accessToken string) string {
+	if accessToken == "" {
+		return defaultTokenID
+	}
+	sum := sha256.Sum256([]byte(accessToken))
+	return hex.EncodeToString(sum[:8])
+}
+
+func recordSpans(ctx context.Context, accessToken string, sent, dropped int) {
+	ctx, err := tag.New(ctx, tag.Upsert(tokenIDKey, accessTokenID(accessToken)))
+	if err != nil {
+		return
+	}
+
+	if sent > 0 {
+		stats.Record(ctx, mSpansSent.M(int64(sent)))
+	}
+	if dropped > 0 {
+		stats.Record(ctx, mSpansDropped.M(int64(dropped)))
+	}
+}
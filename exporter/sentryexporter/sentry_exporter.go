@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 const (
@@ -72,6 +73,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 	}
 
 	maybeOrphanSpans := make([]*sentry.Span, 0, td.SpanCount())
+	var exceptionEvents []*sentry.Event
 
 	// Maps all child span ids to their root span.
 	idMap := make(map[string]string)
@@ -85,6 +87,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 		}
 
 		resourceTags := generateTagsFromResource(rs.Resource())
+		language := resourceTags[conventions.AttributeTelemetrySDKLanguage]
 
 		ilss := rs.InstrumentationLibrarySpans()
 		for j := 0; j < ilss.Len(); j++ {
@@ -103,6 +106,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 				}
 
 				sentrySpan := convertToSentrySpan(otelSpan, library, resourceTags)
+				exceptionEvents = append(exceptionEvents, convertEventsToSentryExceptions(otelSpan.Events(), sentrySpan, language)...)
 
 				// If a span is a root span, we consider it the start of a Sentry transaction.
 				// We should then create a new transaction for that root span, and keep track of it.
@@ -124,17 +128,21 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 		}
 	}
 
-	if len(transactionMap) == 0 {
-		return 0, nil
-	}
-
-	// After the first pass through, we can't necessarily make the assumption we have not associated all
-	// the spans with a transaction. As such, we must classify the remaining spans as orphans or not.
-	orphanSpans := classifyAsOrphanSpans(maybeOrphanSpans, len(maybeOrphanSpans)+1, idMap, transactionMap)
+	// Exception events are sent even if the batch has no root span to start
+	// a transaction with.
+	var transactions []*sentry.Event
+	if len(transactionMap) > 0 {
+		// After the first pass through, we can't necessarily make the assumption we have not associated all
+		// the spans with a transaction. As such, we must classify the remaining spans as orphans or not.
+		orphanSpans := classifyAsOrphanSpans(maybeOrphanSpans, len(maybeOrphanSpans)+1, idMap, transactionMap)
 
-	transactions := generateTransactions(transactionMap, orphanSpans)
+		transactions = generateTransactions(transactionMap, orphanSpans)
+	}
+	linkExceptionsToTransactions(exceptionEvents, idMap, transactionMap)
 
-	s.transport.SendTransactions(transactions)
+	if events := append(transactions, exceptionEvents...); len(events) > 0 {
+		s.transport.SendEvents(events)
+	}
 
 	return 0, nil
 }
@@ -228,6 +236,101 @@ func convertToSentrySpan(span pdata.Span, library pdata.InstrumentationLibrary,
 	return sentrySpan
 }
 
+// convertEventsToSentryExceptions creates a Sentry error event for every exception event recorded
+// on a span. The error events carry the trace context of the span, so Sentry links them to the
+// transaction the span belongs to.
+//
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/trace/semantic_conventions/exceptions.md
+// for more details about the exception semantic conventions.
+func convertEventsToSentryExceptions(events pdata.SpanEventSlice, sentrySpan *sentry.Span, language string) []*sentry.Event {
+	var exceptions []*sentry.Event
+
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() || event.Name() != conventions.AttributeExceptionEventName {
+			continue
+		}
+
+		var exceptionType, message, stacktrace string
+		extra := make(map[string]interface{})
+		event.Attributes().ForEach(func(key string, attr pdata.AttributeValue) {
+			switch key {
+			case conventions.AttributeExceptionType:
+				exceptionType = attr.StringVal()
+			case conventions.AttributeExceptionMessage:
+				message = attr.StringVal()
+			case conventions.AttributeExceptionStacktrace:
+				stacktrace = attr.StringVal()
+			default:
+				extra[key] = tracetranslator.AttributeValueToString(attr, false)
+			}
+		})
+
+		// The semantic conventions require either the type or the message to be present.
+		if exceptionType == "" && message == "" {
+			continue
+		}
+
+		exception := sentry.NewEvent()
+		exception.Level = sentry.LevelError
+		exception.Platform = sentryPlatform(language, stacktrace)
+		exception.Timestamp = unixNanoToTime(event.Timestamp())
+		exception.Extra = extra
+
+		exception.Sdk.Name = otelSentryExporterName
+		exception.Sdk.Version = otelSentryExporterVersion
+
+		for k, v := range sentrySpan.Tags {
+			exception.Tags[k] = v
+		}
+
+		exception.Contexts["trace"] = sentry.TraceContext{
+			TraceID:     sentrySpan.TraceID,
+			SpanID:      sentrySpan.SpanID,
+			Op:          sentrySpan.Op,
+			Description: sentrySpan.Description,
+			Status:      sentrySpan.Status,
+		}
+
+		exception.Exception = []sentry.Exception{{
+			Type:       exceptionType,
+			Value:      message,
+			Stacktrace: parseStacktrace(language, stacktrace),
+		}}
+
+		exceptions = append(exceptions, exception)
+	}
+
+	return exceptions
+}
+
+// linkExceptionsToTransactions sets the transaction name of every exception to the name of the
+// transaction that contains the span the exception was recorded on. Exceptions of orphan spans
+// keep the span description, as orphan spans become transactions of their own.
+func linkExceptionsToTransactions(exceptions []*sentry.Event, idMap map[string]string, transactionMap map[string]*sentry.Event) {
+	for _, exception := range exceptions {
+		traceContext := exception.Contexts["trace"].(sentry.TraceContext)
+
+		if rootSpanID, ok := idMap[traceContext.SpanID]; ok {
+			exception.Transaction = transactionMap[rootSpanID].Transaction
+		} else {
+			exception.Transaction = traceContext.Description
+		}
+	}
+}
+
+// sentryPlatform maps the language of the SDK that recorded an exception to a Sentry platform.
+func sentryPlatform(language string, stacktrace string) string {
+	if language == "" {
+		language = detectLanguage(stacktrace)
+	}
+
+	if language == "" {
+		return "other"
+	}
+	return language
+}
+
 // generateSpanDescriptors generates generate span descriptors (op and description)
 // from the name, attributes and SpanKind of an otel span based onSemantic Conventions
 // described by the open telemetry specification.
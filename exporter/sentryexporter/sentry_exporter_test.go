@@ -493,6 +493,75 @@ func TestClassifyOrphanSpans(t *testing.T) {
 	}
 }
 
+func TestConvertEventsToSentryExceptions(t *testing.T) {
+	events := pdata.NewSpanEventSlice()
+	events.Resize(3)
+
+	exceptionEvent := events.At(0)
+	exceptionEvent.SetName(conventions.AttributeExceptionEventName)
+	exceptionEvent.SetTimestamp(pdata.TimestampUnixNano(7))
+	exceptionEvent.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionType:       pdata.NewAttributeValueString("ValueError"),
+		conventions.AttributeExceptionMessage:    pdata.NewAttributeValueString("boom"),
+		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString(pythonStacktrace),
+		"exception.escaped":                      pdata.NewAttributeValueBool(true),
+	})
+
+	// Events that are not exceptions are ignored.
+	events.At(1).SetName("cache miss")
+
+	// Exceptions without type and message are ignored.
+	events.At(2).SetName(conventions.AttributeExceptionEventName)
+
+	exceptions := convertEventsToSentryExceptions(events, rootSpan1, languagePython)
+	assert.Len(t, exceptions, 1)
+
+	expected := sentry.NewEvent()
+	expected.Level = sentry.LevelError
+	expected.Platform = languagePython
+	expected.Timestamp = unixNanoToTime(7)
+	expected.Extra = map[string]interface{}{"exception.escaped": "true"}
+	expected.Sdk.Name = otelSentryExporterName
+	expected.Sdk.Version = otelSentryExporterVersion
+	expected.Tags = rootSpan1.Tags
+	expected.Contexts["trace"] = sentry.TraceContext{
+		TraceID:     rootSpan1.TraceID,
+		SpanID:      rootSpan1.SpanID,
+		Op:          rootSpan1.Op,
+		Description: rootSpan1.Description,
+		Status:      rootSpan1.Status,
+	}
+	expected.Exception = []sentry.Exception{{
+		Type:       "ValueError",
+		Value:      "boom",
+		Stacktrace: parseStacktrace(languagePython, pythonStacktrace),
+	}}
+
+	if diff := cmp.Diff(expected, exceptions[0]); diff != "" {
+		t.Errorf("exception event mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLinkExceptionsToTransactions(t *testing.T) {
+	transactionMap := map[string]*sentry.Event{
+		rootSpan1.SpanID: transactionFromSpan(rootSpan1),
+	}
+	idMap := map[string]string{
+		rootSpan1.SpanID:  rootSpan1.SpanID,
+		childSpan1.SpanID: rootSpan1.SpanID,
+	}
+
+	childException := sentry.NewEvent()
+	childException.Contexts["trace"] = sentry.TraceContext{SpanID: childSpan1.SpanID, Description: childSpan1.Description}
+	orphanException := sentry.NewEvent()
+	orphanException.Contexts["trace"] = sentry.TraceContext{SpanID: orphanSpan1.SpanID, Description: orphanSpan1.Description}
+
+	linkExceptionsToTransactions([]*sentry.Event{childException, orphanException}, idMap, transactionMap)
+
+	assert.Equal(t, rootSpan1.Description, childException.Transaction)
+	assert.Equal(t, orphanSpan1.Description, orphanException.Transaction)
+}
+
 func TestGenerateTransactions(t *testing.T) {
 	transactionMap := generateEmptyTransactionMap(rootSpan1, rootSpan2)
 	orphanSpans := generateOrphanSpansFromSpans(orphanSpan1, childSpan1)
@@ -503,12 +572,12 @@ func TestGenerateTransactions(t *testing.T) {
 }
 
 type mockTransport struct {
-	called       bool
-	transactions []*sentry.Event
+	called bool
+	events []*sentry.Event
 }
 
-func (t *mockTransport) SendTransactions(transactions []*sentry.Event) {
-	t.transactions = transactions
+func (t *mockTransport) SendEvents(events []*sentry.Event) {
+	t.events = events
 	t.called = true
 }
 
@@ -523,6 +592,7 @@ type PushTraceDataTestCase struct {
 	td pdata.Traces
 	// output
 	called bool
+	events int
 }
 
 func TestPushTraceData(t *testing.T) {
@@ -553,6 +623,44 @@ func TestPushTraceData(t *testing.T) {
 			}(),
 			called: false,
 		},
+		{
+			testName: "with exception event",
+			td: func() pdata.Traces {
+				traces := pdata.NewTraces()
+				resourceSpans := traces.ResourceSpans()
+				resourceSpans.Resize(1)
+				resourceSpans.At(0).InitEmpty()
+				resourceSpans.At(0).InstrumentationLibrarySpans().Resize(1)
+				spans := resourceSpans.At(0).InstrumentationLibrarySpans().At(0).Spans()
+				spans.Resize(1)
+				spans.At(0).Events().Resize(1)
+				spans.At(0).Events().At(0).SetName(conventions.AttributeExceptionEventName)
+				spans.At(0).Events().At(0).Attributes().InsertString(conventions.AttributeExceptionType, "ValueError")
+				return traces
+			}(),
+			called: true,
+			events: 2,
+		},
+		{
+			testName: "with exception event and no root span",
+			td: func() pdata.Traces {
+				traces := pdata.NewTraces()
+				resourceSpans := traces.ResourceSpans()
+				resourceSpans.Resize(1)
+				resourceSpans.At(0).InitEmpty()
+				resourceSpans.At(0).InstrumentationLibrarySpans().Resize(1)
+				spans := resourceSpans.At(0).InstrumentationLibrarySpans().At(0).Spans()
+				spans.Resize(1)
+				spans.At(0).SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+				spans.At(0).SetParentSpanID(pdata.NewSpanID([]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+				spans.At(0).Events().Resize(1)
+				spans.At(0).Events().At(0).SetName(conventions.AttributeExceptionEventName)
+				spans.At(0).Events().At(0).Attributes().InsertString(conventions.AttributeExceptionType, "ValueError")
+				return traces
+			}(),
+			called: true,
+			events: 1,
+		},
 		{
 			testName: "with full trace",
 			td: func() pdata.Traces {
@@ -565,6 +673,7 @@ func TestPushTraceData(t *testing.T) {
 				return traces
 			}(),
 			called: true,
+			events: 1,
 		},
 	}
 
@@ -579,6 +688,7 @@ func TestPushTraceData(t *testing.T) {
 
 			s.pushTraceData(context.Background(), test.td)
 			assert.Equal(t, test.called, transport.called)
+			assert.Len(t, transport.events, test.events)
 		})
 	}
 }
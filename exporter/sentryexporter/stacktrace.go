@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	languageJava   = "java"
+	languagePython = "python"
+	languageGo     = "go"
+)
+
+var (
+	// Ex. "	at com.example.Foo.bar(Foo.java:42)" or
+	// "	at java.base/java.lang.Thread.run(Thread.java:834)".
+	javaFrameRegexp = regexp.MustCompile(`^\s*at\s+(?:[^\s/(]+/)?([^\s(]+)\.([^\s.(]+)\(([^)]*)\)`)
+	// Ex. `  File "/app/main.py", line 10, in handler`.
+	pythonFrameRegexp = regexp.MustCompile(`^\s*File "([^"]+)", line (\d+), in (.+)$`)
+	// Ex. "	/app/server.go:42 +0x1d".
+	goLocationRegexp = regexp.MustCompile(`^\s+(\S+):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// detectLanguage guesses the language a stacktrace was recorded in from its
+// format. It is used when the resource doesn't carry the telemetry.sdk.language
+// attribute.
+func detectLanguage(stacktrace string) string {
+	switch {
+	case strings.Contains(stacktrace, "Traceback (most recent call last):"):
+		return languagePython
+	case strings.HasPrefix(stacktrace, "goroutine ") || strings.HasPrefix(stacktrace, "panic: "):
+		return languageGo
+	case strings.Contains(stacktrace, "\tat "):
+		return languageJava
+	}
+	return ""
+}
+
+// parseStacktrace converts the stacktrace recorded in the exception.stacktrace
+// attribute into Sentry frames. Sentry expects frames ordered from the
+// outermost call to the one that raised the exception. It returns nil for
+// unknown languages or when no frame could be parsed.
+func parseStacktrace(language string, stacktrace string) *sentry.Stacktrace {
+	if stacktrace == "" {
+		return nil
+	}
+	if language == "" {
+		language = detectLanguage(stacktrace)
+	}
+
+	var frames []sentry.Frame
+	switch language {
+	case languageJava:
+		frames = parseJavaStacktrace(stacktrace)
+	case languagePython:
+		frames = parsePythonStacktrace(stacktrace)
+	case languageGo:
+		frames = parseGoStacktrace(stacktrace)
+	}
+
+	if len(frames) == 0 {
+		return nil
+	}
+	return &sentry.Stacktrace{Frames: frames}
+}
+
+// parseJavaStacktrace parses the output of Throwable.printStackTrace. Only the
+// frames of the outermost exception are kept, "Caused by" sections are skipped.
+func parseJavaStacktrace(stacktrace string) []sentry.Frame {
+	var frames []sentry.Frame
+
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "Caused by:") {
+			break
+		}
+
+		match := javaFrameRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		frame := sentry.Frame{
+			Module:   match[1],
+			Function: match[2],
+		}
+		location := match[3]
+		if i := strings.LastIndex(location, ":"); i >= 0 {
+			if lineno, err := strconv.Atoi(location[i+1:]); err == nil {
+				frame.Lineno = lineno
+				location = location[:i]
+			}
+		}
+		if location != "Native Method" && location != "Unknown Source" {
+			frame.Filename = location
+		}
+		frames = append(frames, frame)
+	}
+
+	// Java prints the innermost frame first.
+	reverseFrames(frames)
+	return frames
+}
+
+// parsePythonStacktrace parses the output of traceback.format_exc, which
+// already lists the most recent call last.
+func parsePythonStacktrace(stacktrace string) []sentry.Frame {
+	var frames []sentry.Frame
+
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if match := pythonFrameRegexp.FindStringSubmatch(line); match != nil {
+			lineno, _ := strconv.Atoi(match[2])
+			frames = append(frames, sentry.Frame{
+				AbsPath:  match[1],
+				Filename: match[1],
+				Lineno:   lineno,
+				Function: match[3],
+			})
+			continue
+		}
+
+		// The source line of a frame is printed indented below it.
+		if len(frames) > 0 && strings.HasPrefix(line, "    ") && frames[len(frames)-1].ContextLine == "" {
+			frames[len(frames)-1].ContextLine = strings.TrimSpace(line)
+		}
+	}
+
+	return frames
+}
+
+// parseGoStacktrace parses goroutine dumps as produced by panics and
+// runtime/debug.Stack, where every function line is followed by its location.
+func parseGoStacktrace(stacktrace string) []sentry.Frame {
+	var frames []sentry.Frame
+	var function string
+
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if match := goLocationRegexp.FindStringSubmatch(line); match != nil {
+			if function == "" {
+				continue
+			}
+			lineno, _ := strconv.Atoi(match[2])
+			module, fn := splitGoFunctionName(function)
+			frames = append(frames, sentry.Frame{
+				Module:   module,
+				Function: fn,
+				AbsPath:  match[1],
+				Filename: match[1],
+				Lineno:   lineno,
+			})
+			function = ""
+			continue
+		}
+
+		function = goFunctionName(line)
+	}
+
+	// Go prints the innermost frame first.
+	reverseFrames(frames)
+	return frames
+}
+
+// goFunctionName extracts the function name from a function line such as
+// "main.(*Server).handle(0xc000010000, 0x1)" or "created by net/http.(*Server).Serve".
+// It returns an empty string for lines that don't name a function.
+func goFunctionName(line string) string {
+	if line == "" || line[0] == ' ' || line[0] == '\t' ||
+		strings.HasPrefix(line, "goroutine ") || strings.HasPrefix(line, "panic: ") {
+		return ""
+	}
+
+	line = strings.TrimPrefix(line, "created by ")
+	if i := strings.Index(line, " in goroutine "); i >= 0 {
+		line = line[:i]
+	}
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			line = line[:i]
+		}
+	}
+	if strings.ContainsAny(line, " \t") {
+		return ""
+	}
+	return line
+}
+
+// splitGoFunctionName splits a fully qualified function name such as
+// "github.com/org/repo/pkg.(*Type).Method" into its package and function.
+func splitGoFunctionName(name string) (module string, function string) {
+	start := strings.LastIndex(name, "/") + 1
+	dot := strings.Index(name[start:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:start+dot], name[start+dot+1:]
+}
+
+func reverseFrames(frames []sentry.Frame) {
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+}
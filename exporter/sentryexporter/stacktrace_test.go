@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	javaStacktrace = `java.lang.IllegalStateException: user not found
+	at com.example.UserService.getUser(UserService.java:42)
+	at com.example.UserController.handle(UserController.java:17)
+	at java.base/java.lang.Thread.run(Native Method)
+Caused by: java.sql.SQLException: connection closed
+	at com.example.Database.query(Database.java:88)
+	... 3 more`
+
+	pythonStacktrace = `Traceback (most recent call last):
+  File "/app/main.py", line 10, in <module>
+    main()
+  File "/app/main.py", line 6, in main
+    raise ValueError("boom")
+ValueError: boom`
+
+	goStacktrace = `goroutine 1 [running]:
+github.com/example/app/server.(*Server).handle(0xc000010000, 0x1)
+	/app/server/server.go:42 +0x1d
+main.main()
+	/app/main.go:12 +0x39
+created by main.start
+	/app/main.go:20 +0x20`
+)
+
+func TestParseStacktrace(t *testing.T) {
+	testCases := []struct {
+		testName   string
+		language   string
+		stacktrace string
+		expected   *sentry.Stacktrace
+	}{
+		{
+			testName:   "java",
+			language:   languageJava,
+			stacktrace: javaStacktrace,
+			expected: &sentry.Stacktrace{
+				Frames: []sentry.Frame{
+					{Module: "java.lang.Thread", Function: "run"},
+					{Module: "com.example.UserController", Function: "handle", Filename: "UserController.java", Lineno: 17},
+					{Module: "com.example.UserService", Function: "getUser", Filename: "UserService.java", Lineno: 42},
+				},
+			},
+		},
+		{
+			testName:   "python",
+			language:   languagePython,
+			stacktrace: pythonStacktrace,
+			expected: &sentry.Stacktrace{
+				Frames: []sentry.Frame{
+					{Function: "<module>", Filename: "/app/main.py", AbsPath: "/app/main.py", Lineno: 10, ContextLine: "main()"},
+					{Function: "main", Filename: "/app/main.py", AbsPath: "/app/main.py", Lineno: 6, ContextLine: `raise ValueError("boom")`},
+				},
+			},
+		},
+		{
+			testName:   "go",
+			language:   languageGo,
+			stacktrace: goStacktrace,
+			expected: &sentry.Stacktrace{
+				Frames: []sentry.Frame{
+					{Module: "main", Function: "start", Filename: "/app/main.go", AbsPath: "/app/main.go", Lineno: 20},
+					{Module: "main", Function: "main", Filename: "/app/main.go", AbsPath: "/app/main.go", Lineno: 12},
+					{Module: "github.com/example/app/server", Function: "(*Server).handle", Filename: "/app/server/server.go", AbsPath: "/app/server/server.go", Lineno: 42},
+				},
+			},
+		},
+		{
+			testName:   "detected language",
+			language:   "",
+			stacktrace: pythonStacktrace,
+			expected: &sentry.Stacktrace{
+				Frames: []sentry.Frame{
+					{Function: "<module>", Filename: "/app/main.py", AbsPath: "/app/main.py", Lineno: 10, ContextLine: "main()"},
+					{Function: "main", Filename: "/app/main.py", AbsPath: "/app/main.py", Lineno: 6, ContextLine: `raise ValueError("boom")`},
+				},
+			},
+		},
+		{
+			testName:   "unknown language",
+			language:   "erlang",
+			stacktrace: javaStacktrace,
+			expected:   nil,
+		},
+		{
+			testName:   "empty stacktrace",
+			language:   languageJava,
+			stacktrace: "",
+			expected:   nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseStacktrace(test.language, test.stacktrace))
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	assert.Equal(t, languageJava, detectLanguage(javaStacktrace))
+	assert.Equal(t, languagePython, detectLanguage(pythonStacktrace))
+	assert.Equal(t, languageGo, detectLanguage(goStacktrace))
+	assert.Equal(t, "", detectLanguage("something went wrong"))
+}
@@ -88,6 +88,33 @@ type Config struct {
 	// backend. If translations enabled with SendCompatibleMetrics or TranslationRules
 	// options, the exclusion will be applied on translated metrics.
 	ExcludeMetrics []string `mapstructure:"exclude_metrics"`
+
+	// DimensionClient configures how dimension property and tag updates, eg.:
+	// from k8s metadata or SyncHostMetadata, are sent to SignalFx.
+	DimensionClient DimensionClientConfig `mapstructure:"dimension_client"`
+}
+
+// DimensionClientConfig defines configuration for sending dimension updates.
+type DimensionClientConfig struct {
+	// MaxBuffered is the maximum number of distinct dimensions whose updates
+	// can be queued. Updates for other dimensions are dropped once it is
+	// reached. Default is 10000.
+	MaxBuffered int `mapstructure:"max_buffered"`
+
+	// SendDelay is how long updates are held before being sent. Updates to
+	// the same dimension within that time are merged into the queued one.
+	// Default is 10s.
+	SendDelay time.Duration `mapstructure:"send_delay"`
+
+	// MaxBatchSize is the maximum number of queued updates taken off the
+	// queue at once. Each update is still sent in its own request since the
+	// dimension API has no bulk endpoint. Default is 100.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+
+	// RequestsPerSecond limits the rate of requests made to the SignalFx
+	// dimension API. Requests are also backed off exponentially whenever the
+	// API responds with 429. Default is 20.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
 }
 
 func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
@@ -109,6 +136,19 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 		cfg.Timeout = 5 * time.Second
 	}
 
+	if cfg.DimensionClient.MaxBuffered == 0 {
+		cfg.DimensionClient.MaxBuffered = defaultDimMaxBuffered
+	}
+	if cfg.DimensionClient.SendDelay == 0 {
+		cfg.DimensionClient.SendDelay = defaultDimSendDelay
+	}
+	if cfg.DimensionClient.MaxBatchSize == 0 {
+		cfg.DimensionClient.MaxBatchSize = defaultDimMaxBatchSize
+	}
+	if cfg.DimensionClient.RequestsPerSecond == 0 {
+		cfg.DimensionClient.RequestsPerSecond = defaultDimRequestsPerSecond
+	}
+
 	var metricTranslator *translation.MetricTranslator
 	if cfg.SendCompatibleMetrics {
 		metricTranslator, err = translation.NewMetricTranslator(cfg.TranslationRules, cfg.DeltaTranslationTTL)
@@ -141,6 +181,11 @@ func (cfg *Config) validateConfig() error {
 		return errors.New("cannot have a negative \"timeout\"")
 	}
 
+	if cfg.DimensionClient.MaxBuffered < 0 || cfg.DimensionClient.SendDelay < 0 ||
+		cfg.DimensionClient.MaxBatchSize < 0 || cfg.DimensionClient.RequestsPerSecond < 0 {
+		return errors.New("cannot have negative \"dimension_client\" settings")
+	}
+
 	return nil
 }
 
@@ -79,6 +79,12 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 		DeltaTranslationTTL: 3600,
+		DimensionClient: DimensionClientConfig{
+			MaxBuffered:       1000,
+			SendDelay:         5 * time.Second,
+			MaxBatchSize:      50,
+			RequestsPerSecond: 10,
+		},
 	}
 	assert.Equal(t, &expectedCfg, e1)
 
@@ -30,14 +30,15 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/translation"
 )
 
 // DimensionClient sends updates to dimensions to the SignalFx API
 // This is a port of https://github.com/signalfx/signalfx-agent/blob/master/pkg/core/writer/dimensions/client.go
-// with the only major difference being deduplication of dimension
-// updates are currently not done by this port.
+// with the difference that queued updates are taken off the queue in batches,
+// throttled by a token bucket, and backed off when the API responds with 429.
 type DimensionClient struct {
 	sync.RWMutex
 	ctx           context.Context
@@ -53,14 +54,27 @@ type DimensionClient struct {
 	sendDelay time.Duration
 	// Set of dims that have been queued up for sending.  Use map to quickly
 	// look up in case we need to replace due to flappy prop generation.
-	delayedSet map[DimensionKey]*DimensionUpdate
+	delayedSet map[DimensionKey]*queuedDimension
 	// Queue of dimensions to update.  The ordering should never change once
 	// put in the queue so no need for heap/priority queue.
-	delayedQueue chan *queuedDimension
+	delayedQueue []DimensionKey
+	// Maximum number of distinct dimensions that can be queued.
+	maxBuffered int
+	// Maximum number of updates taken off the queue at once.
+	maxBatchSize int
+	// Limits the rate of requests made to the API.
+	limiter *rate.Limiter
+	// Signals processQueue that a new dimension has been queued.
+	queued chan struct{}
+	// No requests are sent before throttledUntil.  It is pushed forward,
+	// doubling throttleBackoff each time, when the API responds with 429.
+	throttledUntil     time.Time
+	throttleBackoff    time.Duration
+	minThrottleBackoff time.Duration
+	maxThrottleBackoff time.Duration
 	// For easier unit testing
 	now func() time.Time
 
-	// TODO: Look into collecting these metrics and other traces via obsreport
 	DimensionsCurrentlyDelayed int64
 	TotalDimensionsDropped     int64
 	// The number of dimension updates that happened to the same dimension
@@ -68,11 +82,13 @@ type DimensionClient struct {
 	TotalFlappyUpdates           int64
 	TotalClientError4xxResponses int64
 	TotalRetriedUpdates          int64
+	TotalThrottledResponses      int64
 	TotalInvalidDimensions       int64
 	TotalSuccessfulUpdates       int64
 	logUpdates                   bool
 	logger                       *zap.Logger
 	metricTranslator             *translation.MetricTranslator
+	exporterName                 string
 }
 
 type queuedDimension struct {
@@ -85,11 +101,24 @@ type DimensionClientOptions struct {
 	APIURL                *url.URL
 	LogUpdates            bool
 	Logger                *zap.Logger
-	SendDelay             int
+	SendDelay             time.Duration
 	PropertiesMaxBuffered int
-	MetricTranslator      *translation.MetricTranslator
+	// MaxBatchSize is the maximum number of updates that are taken off the
+	// queue at once. Defaults to PropertiesMaxBuffered if not set.
+	MaxBatchSize int
+	// RequestsPerSecond limits the rate of requests made to the API. No limit
+	// is applied if not set.
+	RequestsPerSecond float64
+	MetricTranslator  *translation.MetricTranslator
+	// ExporterName tags the self-observability metrics of the client.
+	ExporterName string
 }
 
+const (
+	defaultMinThrottleBackoff = time.Second
+	defaultMaxThrottleBackoff = time.Minute
+)
+
 // NewDimensionClient returns a new client
 func NewDimensionClient(ctx context.Context, options DimensionClientOptions) *DimensionClient {
 
@@ -110,19 +139,38 @@ func NewDimensionClient(ctx context.Context, options DimensionClientOptions) *Di
 	}
 	sender := NewReqSender(ctx, client, 20, map[string]string{"client": "dimension"})
 
+	maxBatchSize := options.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = options.PropertiesMaxBuffered
+	}
+
+	limit, burst := rate.Inf, 1
+	if options.RequestsPerSecond > 0 {
+		limit = rate.Limit(options.RequestsPerSecond)
+		if int(options.RequestsPerSecond) > burst {
+			burst = int(options.RequestsPerSecond)
+		}
+	}
+
 	return &DimensionClient{
-		ctx:              ctx,
-		Token:            options.Token,
-		APIURL:           options.APIURL,
-		sendDelay:        time.Duration(options.SendDelay) * time.Second,
-		delayedSet:       make(map[DimensionKey]*DimensionUpdate),
-		delayedQueue:     make(chan *queuedDimension, options.PropertiesMaxBuffered),
-		requestSender:    sender,
-		client:           client,
-		now:              time.Now,
-		logger:           options.Logger,
-		logUpdates:       options.LogUpdates,
-		metricTranslator: options.MetricTranslator,
+		ctx:                ctx,
+		Token:              options.Token,
+		APIURL:             options.APIURL,
+		sendDelay:          options.SendDelay,
+		delayedSet:         make(map[DimensionKey]*queuedDimension),
+		maxBuffered:        options.PropertiesMaxBuffered,
+		maxBatchSize:       maxBatchSize,
+		limiter:            rate.NewLimiter(limit, burst),
+		queued:             make(chan struct{}, 1),
+		minThrottleBackoff: defaultMinThrottleBackoff,
+		maxThrottleBackoff: defaultMaxThrottleBackoff,
+		requestSender:      sender,
+		client:             client,
+		now:                time.Now,
+		logger:             options.Logger,
+		logUpdates:         options.LogUpdates,
+		metricTranslator:   options.MetricTranslator,
+		exporterName:       options.ExporterName,
 	}
 }
 
@@ -134,37 +182,71 @@ func (dc *DimensionClient) Start() {
 // acceptDimension to be sent to the API.  This will return fairly quickly and
 // won't block. If the buffer is full, the dim update will be dropped.
 func (dc *DimensionClient) acceptDimension(dimUpdate *DimensionUpdate) error {
+	return dc.enqueue(dimUpdate, false)
+}
+
+// retryDimension queues a dimension update that failed to be sent. It is
+// merged under a newer update to the same dimension if one is already queued.
+func (dc *DimensionClient) retryDimension(dimUpdate *DimensionUpdate) error {
+	return dc.enqueue(dimUpdate, true)
+}
+
+func (dc *DimensionClient) enqueue(dimUpdate *DimensionUpdate, isRetry bool) error {
 	dc.Lock()
 	defer dc.Unlock()
 
 	if delayedDimUpdate := dc.delayedSet[dimUpdate.Key()]; delayedDimUpdate != nil {
-		if !reflect.DeepEqual(delayedDimUpdate, dimUpdate) {
-			dc.TotalFlappyUpdates++
-
-			// Merge the latest updates into existing one.
-			delayedDimUpdate.Properties = mergeProperties(delayedDimUpdate.Properties, dimUpdate.Properties)
-			delayedDimUpdate.Tags = mergeTags(delayedDimUpdate.Tags, dimUpdate.Tags)
+		// The updates are deltas, so they are merged into the queued one. A
+		// queued update is always at least as recent as a retried one, its
+		// values take precedence over the retried ones.
+		older, newer := delayedDimUpdate.DimensionUpdate, dimUpdate
+		if isRetry {
+			older, newer = dimUpdate, delayedDimUpdate.DimensionUpdate
+		} else {
+			if !reflect.DeepEqual(delayedDimUpdate.DimensionUpdate, dimUpdate) {
+				dc.TotalFlappyUpdates++
+			}
+			dc.recordDimensionUpdates(mDimensionUpdatesQueued, 1)
 		}
-	} else {
-		atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(1))
+		delayedDimUpdate.DimensionUpdate = mergeDimensionUpdates(older, newer)
+		return nil
+	}
 
-		dc.delayedSet[dimUpdate.Key()] = dimUpdate
-		select {
-		case dc.delayedQueue <- &queuedDimension{
-			DimensionUpdate: dimUpdate,
-			TimeToSend:      dc.now().Add(dc.sendDelay),
-		}:
-			break
-		default:
-			dc.TotalDimensionsDropped++
-			atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(-1))
-			return errors.New("dropped dimension update, propertiesMaxBuffered exceeded")
-		}
+	if len(dc.delayedQueue) >= dc.maxBuffered {
+		dc.TotalDimensionsDropped++
+		dc.recordDimensionUpdates(mDimensionUpdatesDropped, 1)
+		return errors.New("dropped dimension update, propertiesMaxBuffered exceeded")
+	}
+
+	atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(1))
+	dc.delayedSet[dimUpdate.Key()] = &queuedDimension{
+		DimensionUpdate: dimUpdate,
+		TimeToSend:      dc.now().Add(dc.sendDelay),
+	}
+	dc.delayedQueue = append(dc.delayedQueue, dimUpdate.Key())
+	if !isRetry {
+		dc.recordDimensionUpdates(mDimensionUpdatesQueued, 1)
+	}
+
+	select {
+	case dc.queued <- struct{}{}:
+	default:
 	}
 
 	return nil
 }
 
+// mergeDimensionUpdates merges two updates to the same dimension, the values
+// of the newer one take precedence.
+func mergeDimensionUpdates(older, newer *DimensionUpdate) *DimensionUpdate {
+	return &DimensionUpdate{
+		Name:       newer.Name,
+		Value:      newer.Value,
+		Properties: mergeProperties(older.Properties, newer.Properties),
+		Tags:       mergeTags(older.Tags, newer.Tags),
+	}
+}
+
 // mergeProperties merges 2 or more maps of properties. This method gives
 // precedence to values of properties in later maps. i.e., if more than one
 // map has the same key, the last value seen will be the effective value in
@@ -194,22 +276,20 @@ func mergeTags(tagSets ...map[string]bool) map[string]bool {
 
 func (dc *DimensionClient) processQueue() {
 	for {
-		select {
-		case <-dc.ctx.Done():
-			return
-		case delayedDimUpdate := <-dc.delayedQueue:
-			now := dc.now()
-			if now.Before(delayedDimUpdate.TimeToSend) {
-				// dims are always in the channel in order of TimeToSend
-				time.Sleep(delayedDimUpdate.TimeToSend.Sub(now))
+		batch, wait := dc.nextBatch()
+		for i, delayedDimUpdate := range batch {
+			if err := dc.limiter.Wait(dc.ctx); err != nil {
+				return
 			}
 
-			atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(-1))
-
-			dc.Lock()
-			delete(dc.delayedSet, delayedDimUpdate.Key())
-			dc.Unlock()
+			// A 429 to one of the previous requests stops the rest of the
+			// batch from being sent until the backoff has passed.
+			if dc.isThrottled() {
+				dc.requeue(batch[i:])
+				break
+			}
 
+			// The dimension API only updates a single dimension per request.
 			if err := dc.handleDimensionUpdate(delayedDimUpdate.DimensionUpdate); err != nil {
 				dc.logger.Error(
 					"Could not send dimension update",
@@ -218,9 +298,117 @@ func (dc *DimensionClient) processQueue() {
 				)
 			}
 		}
+		if len(batch) > 0 {
+			continue
+		}
+
+		var timeout <-chan time.Time
+		if wait > 0 {
+			timeout = time.After(wait)
+		}
+
+		select {
+		case <-dc.ctx.Done():
+			return
+		case <-dc.queued:
+		case <-timeout:
+		}
 	}
 }
 
+// nextBatch takes up to maxBatchSize updates that are due off the queue. If
+// none are due it returns how long to wait until the next one is, or 0 if the
+// queue is empty.
+func (dc *DimensionClient) nextBatch() ([]*queuedDimension, time.Duration) {
+	dc.Lock()
+	defer dc.Unlock()
+
+	now := dc.now()
+	if now.Before(dc.throttledUntil) {
+		return nil, dc.throttledUntil.Sub(now)
+	}
+
+	var batch []*queuedDimension
+	for len(dc.delayedQueue) > 0 && len(batch) < dc.maxBatchSize {
+		// dims are always in the queue in order of TimeToSend
+		delayedDimUpdate := dc.delayedSet[dc.delayedQueue[0]]
+		if now.Before(delayedDimUpdate.TimeToSend) {
+			if len(batch) == 0 {
+				return nil, delayedDimUpdate.TimeToSend.Sub(now)
+			}
+			break
+		}
+
+		dc.delayedQueue[0] = DimensionKey{}
+		dc.delayedQueue = dc.delayedQueue[1:]
+		delete(dc.delayedSet, delayedDimUpdate.Key())
+		atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(-1))
+
+		batch = append(batch, delayedDimUpdate)
+	}
+
+	return batch, 0
+}
+
+// requeue puts updates taken off the queue but not sent back at its front,
+// keeping their order. Updates to dimensions that were queued again in the
+// meantime are superseded and discarded.
+func (dc *DimensionClient) requeue(updates []*queuedDimension) {
+	dc.Lock()
+	defer dc.Unlock()
+
+	keys := make([]DimensionKey, 0, len(updates)+len(dc.delayedQueue))
+	for _, delayedDimUpdate := range updates {
+		// An update queued since is more recent than the unsent one.
+		if queued, ok := dc.delayedSet[delayedDimUpdate.Key()]; ok {
+			queued.DimensionUpdate = mergeDimensionUpdates(delayedDimUpdate.DimensionUpdate, queued.DimensionUpdate)
+			continue
+		}
+		dc.delayedSet[delayedDimUpdate.Key()] = delayedDimUpdate
+		keys = append(keys, delayedDimUpdate.Key())
+	}
+	atomic.AddInt64(&dc.DimensionsCurrentlyDelayed, int64(len(keys)))
+	dc.delayedQueue = append(keys, dc.delayedQueue...)
+}
+
+func (dc *DimensionClient) isThrottled() bool {
+	dc.RLock()
+	defer dc.RUnlock()
+
+	return dc.now().Before(dc.throttledUntil)
+}
+
+// throttle stops requests from being sent for an exponentially increasing
+// amount of time.
+func (dc *DimensionClient) throttle() {
+	dc.Lock()
+	defer dc.Unlock()
+
+	now := dc.now()
+	if now.Before(dc.throttledUntil) {
+		// Requests in flight when the first 429 was received shouldn't
+		// extend the backoff further.
+		return
+	}
+
+	if dc.throttleBackoff == 0 {
+		dc.throttleBackoff = dc.minThrottleBackoff
+	} else {
+		dc.throttleBackoff *= 2
+		if dc.throttleBackoff > dc.maxThrottleBackoff {
+			dc.throttleBackoff = dc.maxThrottleBackoff
+		}
+	}
+	dc.throttledUntil = now.Add(dc.throttleBackoff)
+}
+
+func (dc *DimensionClient) resetThrottle() {
+	dc.Lock()
+	defer dc.Unlock()
+
+	dc.throttleBackoff = 0
+}
+
 // handleDimensionUpdate will set custom properties on a specific dimension value.
 func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) error {
 	var (
@@ -236,8 +424,21 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 
 	req = req.WithContext(
 		context.WithValue(req.Context(), RequestFailedCallbackKey, RequestFailedCallback(func(statusCode int, err error) {
+			if statusCode == http.StatusTooManyRequests {
+				atomic.AddInt64(&dc.TotalThrottledResponses, int64(1))
+				dc.logger.Warn(
+					"Dimension updates are being rate limited, backing off",
+					zap.String("URL", req.URL.String()),
+					zap.String("dimensionUpdate", dimUpdate.String()),
+				)
+				dc.throttle()
+				dc.retry(req, dimUpdate)
+				return
+			}
+
 			if statusCode >= 400 && statusCode < 500 && statusCode != 404 {
 				atomic.AddInt64(&dc.TotalClientError4xxResponses, int64(1))
+				dc.recordDimensionUpdates(mDimensionUpdatesDropped, 1)
 				dc.logger.Error(
 					"Unable to update dimension, not retrying",
 					zap.Error(err),
@@ -259,23 +460,14 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 				zap.String("URL", req.URL.String()),
 				zap.String("dimensionUpdate", dimUpdate.String()),
 			)
-			atomic.AddInt64(&dc.TotalRetriedUpdates, int64(1))
-			// The retry is meant to provide some measure of robustness against
-			// temporary API failures.  If the API is down for significant
-			// periods of time, dimension updates will probably eventually back
-			// up beyond PropertiesMaxBuffered and start dropping.
-			if err := dc.acceptDimension(dimUpdate); err != nil {
-				dc.logger.Error(
-					"Failed to retry dimension update",
-					zap.Error(err),
-					zap.String("URL", req.URL.String()),
-					zap.String("dimensionUpdate", dimUpdate.String()),
-				)
-			}
+			dc.retry(req, dimUpdate)
 		})))
 
 	req = req.WithContext(
 		context.WithValue(req.Context(), RequestSuccessCallbackKey, RequestSuccessCallback(func([]byte) {
+			atomic.AddInt64(&dc.TotalSuccessfulUpdates, int64(1))
+			dc.recordDimensionUpdates(mDimensionUpdatesSent, 1)
+			dc.resetThrottle()
 			if dc.logUpdates {
 				dc.logger.Info(
 					"Updated dimension",
@@ -289,6 +481,22 @@ func (dc *DimensionClient) handleDimensionUpdate(dimUpdate *DimensionUpdate) err
 	return nil
 }
 
+func (dc *DimensionClient) retry(req *http.Request, dimUpdate *DimensionUpdate) {
+	atomic.AddInt64(&dc.TotalRetriedUpdates, int64(1))
+	// The retry is meant to provide some measure of robustness against
+	// temporary API failures.  If the API is down for significant
+	// periods of time, dimension updates will probably eventually back
+	// up beyond PropertiesMaxBuffered and start dropping.
+	if err := dc.retryDimension(dimUpdate); err != nil {
+		dc.logger.Error(
+			"Failed to retry dimension update",
+			zap.Error(err),
+			zap.String("URL", req.URL.String()),
+			zap.String("dimensionUpdate", dimUpdate.String()),
+		)
+	}
+}
+
 func (dc *DimensionClient) makeDimURL(key, value string) (*url.URL, error) {
 	url, err := dc.APIURL.Parse(fmt.Sprintf("/v2/dimension/%s/%s", url.PathEscape(key), url.PathEscape(value)))
 	if err != nil {
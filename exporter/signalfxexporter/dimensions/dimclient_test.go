@@ -117,7 +117,7 @@ func setup(t *testing.T) (*DimensionClient, chan dim, *atomic.Value, context.Can
 		APIURL:                serverURL,
 		LogUpdates:            true,
 		Logger:                zap.NewNop(),
-		SendDelay:             1,
+		SendDelay:             time.Second,
 		PropertiesMaxBuffered: 10,
 	})
 	client.Start()
@@ -397,6 +397,187 @@ func TestInvalidUpdatesNotSent(t *testing.T) {
 	require.Equal(t, int64(0), atomic.LoadInt64(&client.TotalInvalidDimensions))
 }
 
+func TestRateLimitedUpdatesBackOff(t *testing.T) {
+	client, dimCh, forcedResp, cancel := setup(t)
+	defer cancel()
+
+	forcedResp.Store(429)
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{
+		Name:       "host",
+		Value:      "throttled",
+		Properties: map[string]*string{"a": newString("b")},
+	}))
+
+	dims := waitForDims(dimCh, 1, 2)
+	require.Len(t, dims, 0)
+	require.Equal(t, int64(1), atomic.LoadInt64(&client.TotalThrottledResponses))
+	require.Equal(t, int64(0), atomic.LoadInt64(&client.TotalClientError4xxResponses))
+
+	forcedResp.Store(200)
+	dims = waitForDims(dimCh, 1, 3)
+	require.Equal(t, []dim{
+		{
+			Key:        "host",
+			Value:      "throttled",
+			Properties: map[string]*string{"a": newString("b")},
+		},
+	}, dims)
+}
+
+func TestThrottleBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewDimensionClient(ctx, DimensionClientOptions{
+		Logger:                zap.NewNop(),
+		PropertiesMaxBuffered: 10,
+	})
+	now := time.Unix(1000, 0)
+	client.now = func() time.Time { return now }
+
+	client.throttle()
+	require.Equal(t, now.Add(time.Second), client.throttledUntil)
+
+	// Responses to requests already in flight don't extend the backoff.
+	client.throttle()
+	require.Equal(t, now.Add(time.Second), client.throttledUntil)
+
+	now = client.throttledUntil
+	client.throttle()
+	require.Equal(t, now.Add(2*time.Second), client.throttledUntil)
+
+	for i := 0; i < 10; i++ {
+		now = client.throttledUntil
+		client.throttle()
+	}
+	require.Equal(t, now.Add(time.Minute), client.throttledUntil)
+
+	client.resetThrottle()
+	now = client.throttledUntil
+	client.throttle()
+	require.Equal(t, now.Add(time.Second), client.throttledUntil)
+}
+
+func TestNextBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewDimensionClient(ctx, DimensionClientOptions{
+		Logger:                zap.NewNop(),
+		SendDelay:             time.Second,
+		PropertiesMaxBuffered: 3,
+		MaxBatchSize:          2,
+	})
+	now := time.Unix(1000, 0)
+	client.now = func() time.Time { return now }
+
+	batch, wait := client.nextBatch()
+	require.Len(t, batch, 0)
+	require.Equal(t, time.Duration(0), wait)
+
+	for _, v := range []string{"a", "b", "c"} {
+		require.NoError(t, client.acceptDimension(&DimensionUpdate{Name: "pod_uid", Value: v}))
+	}
+	require.EqualError(t, client.acceptDimension(&DimensionUpdate{Name: "pod_uid", Value: "d"}),
+		"dropped dimension update, propertiesMaxBuffered exceeded")
+	// Updates to dimensions that are already queued are never dropped.
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{
+		Name:       "pod_uid",
+		Value:      "a",
+		Properties: map[string]*string{"index": newString("1")},
+	}))
+	require.Equal(t, int64(1), client.TotalDimensionsDropped)
+	require.Equal(t, int64(3), atomic.LoadInt64(&client.DimensionsCurrentlyDelayed))
+
+	batch, wait = client.nextBatch()
+	require.Len(t, batch, 0)
+	require.Equal(t, time.Second, wait)
+
+	now = now.Add(time.Second)
+	batch, _ = client.nextBatch()
+	require.Equal(t, []*DimensionUpdate{
+		{Name: "pod_uid", Value: "a", Properties: map[string]*string{"index": newString("1")}, Tags: map[string]bool{}},
+		{Name: "pod_uid", Value: "b"},
+	}, dimensionUpdates(batch))
+
+	// Updates that couldn't be sent because of a 429 are queued again in
+	// front of the others, or merged into a newer update queued since.
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{
+		Name:       "pod_uid",
+		Value:      "b",
+		Properties: map[string]*string{"index": newString("2")},
+	}))
+	client.requeue(batch)
+	require.Equal(t, []DimensionKey{
+		{Name: "pod_uid", Value: "a"},
+		{Name: "pod_uid", Value: "c"},
+		{Name: "pod_uid", Value: "b"},
+	}, client.delayedQueue)
+	require.Equal(t, int64(3), atomic.LoadInt64(&client.DimensionsCurrentlyDelayed))
+	batch, _ = client.nextBatch()
+	require.Equal(t, []*DimensionUpdate{
+		{Name: "pod_uid", Value: "a", Properties: map[string]*string{"index": newString("1")}, Tags: map[string]bool{}},
+		{Name: "pod_uid", Value: "c"},
+	}, dimensionUpdates(batch))
+
+	client.throttle()
+	batch, wait = client.nextBatch()
+	require.Len(t, batch, 0)
+	require.Equal(t, time.Second, wait)
+
+	now = now.Add(time.Second)
+	batch, _ = client.nextBatch()
+	require.Equal(t, []*DimensionUpdate{
+		{Name: "pod_uid", Value: "b", Properties: map[string]*string{"index": newString("2")}, Tags: map[string]bool{}},
+	}, dimensionUpdates(batch))
+	require.Equal(t, int64(0), atomic.LoadInt64(&client.DimensionsCurrentlyDelayed))
+}
+
+func TestRetryDoesNotOverrideNewerUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewDimensionClient(ctx, DimensionClientOptions{
+		Logger:                zap.NewNop(),
+		PropertiesMaxBuffered: 10,
+	})
+
+	require.NoError(t, client.acceptDimension(&DimensionUpdate{
+		Name:       "pod_uid",
+		Value:      "abcd",
+		Properties: map[string]*string{"phase": newString("running")},
+		Tags:       map[string]bool{"ready": true},
+	}))
+	require.NoError(t, client.retryDimension(&DimensionUpdate{
+		Name:  "pod_uid",
+		Value: "abcd",
+		Properties: map[string]*string{
+			"phase": newString("pending"),
+			"node":  newString("n1"),
+		},
+		Tags: map[string]bool{"ready": false},
+	}))
+
+	// The values of the retried update that weren't updated since are kept.
+	require.Equal(t, &DimensionUpdate{
+		Name:  "pod_uid",
+		Value: "abcd",
+		Properties: map[string]*string{
+			"phase": newString("running"),
+			"node":  newString("n1"),
+		},
+		Tags: map[string]bool{"ready": true},
+	}, client.delayedSet[DimensionKey{Name: "pod_uid", Value: "abcd"}].DimensionUpdate)
+}
+
+func dimensionUpdates(batch []*queuedDimension) []*DimensionUpdate {
+	out := make([]*DimensionUpdate, len(batch))
+	for i, delayedDimUpdate := range batch {
+		out[i] = delayedDimUpdate.DimensionUpdate
+	}
+	return out
+}
+
 func newString(s string) *string {
 	out := s
 	return &out
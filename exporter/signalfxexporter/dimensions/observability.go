@@ -0,0 +1,73 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dimensions
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	mDimensionUpdatesQueued  = stats.Int64("signalfx/dimension_updates_queued", "Number of dimension updates queued to be sent", "1")
+	mDimensionUpdatesSent    = stats.Int64("signalfx/dimension_updates_sent", "Number of dimension updates successfully sent", "1")
+	mDimensionUpdatesDropped = stats.Int64("signalfx/dimension_updates_dropped", "Number of dimension updates dropped", "1")
+	exporterKey              = tag.MustNewKey("exporter")
+)
+
+var viewDimensionUpdatesQueued = &view.View{
+	Name:        mDimensionUpdatesQueued.Name(),
+	Description: mDimensionUpdatesQueued.Description(),
+	Measure:     mDimensionUpdatesQueued,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{exporterKey},
+}
+
+var viewDimensionUpdatesSent = &view.View{
+	Name:        mDimensionUpdatesSent.Name(),
+	Description: mDimensionUpdatesSent.Description(),
+	Measure:     mDimensionUpdatesSent,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{exporterKey},
+}
+
+var viewDimensionUpdatesDropped = &view.View{
+	Name:        mDimensionUpdatesDropped.Name(),
+	Description: mDimensionUpdatesDropped.Description(),
+	Measure:     mDimensionUpdatesDropped,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{exporterKey},
+}
+
+// MetricViews returns the views for the dimension client's self-observability
+// metrics.
+func MetricViews() []*view.View {
+	return []*view.View{
+		viewDimensionUpdatesQueued,
+		viewDimensionUpdatesSent,
+		viewDimensionUpdatesDropped,
+	}
+}
+
+func (dc *DimensionClient) recordDimensionUpdates(m *stats.Int64Measure, n int64) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(exporterKey, dc.exporterName))
+	if err != nil {
+		return
+	}
+
+	stats.Record(ctx, m.M(n))
+}
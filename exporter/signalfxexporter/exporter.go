@@ -93,14 +93,13 @@ func newSignalFxExporter(
 			APIURL:     options.apiURL,
 			LogUpdates: options.logDimUpdate,
 			Logger:     logger,
-			// Duration to wait between property updates. This might be worth
-			// being made configurable.
-			SendDelay: 10,
-			// In case of having issues sending dimension updates to SignalFx,
-			// buffer a fixed number of updates. Might also be a good candidate
-			// to make configurable.
-			PropertiesMaxBuffered: 10000,
+			// Duration to wait between property updates.
+			SendDelay:             config.DimensionClient.SendDelay,
+			PropertiesMaxBuffered: config.DimensionClient.MaxBuffered,
+			MaxBatchSize:          config.DimensionClient.MaxBatchSize,
+			RequestsPerSecond:     config.DimensionClient.RequestsPerSecond,
 			MetricTranslator:      options.metricTranslator,
+			ExporterName:          config.Name(),
 		})
 	dimClient.Start()
 
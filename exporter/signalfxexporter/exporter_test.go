@@ -935,7 +935,7 @@ func TestConsumeMetadata(t *testing.T) {
 					APIURL:                serverURL,
 					LogUpdates:            true,
 					Logger:                logger,
-					SendDelay:             1,
+					SendDelay:             time.Second,
 					PropertiesMaxBuffered: 10,
 				})
 			dimClient.Start()
@@ -18,13 +18,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	otelconfig "go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/dimensions"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/translation"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
 )
@@ -34,10 +37,22 @@ const (
 	typeStr = "signalfx"
 
 	defaultHTTPTimeout = time.Second * 5
+
+	defaultDimMaxBuffered       = 10000
+	defaultDimSendDelay         = time.Second * 10
+	defaultDimMaxBatchSize      = 100
+	defaultDimRequestsPerSecond = 20
 )
 
+var once sync.Once
+
 // NewFactory creates a factory for SignalFx exporter.
 func NewFactory() component.ExporterFactory {
+	// register views for self-observability
+	once.Do(func() {
+		view.Register(dimensions.MetricViews()...)
+	})
+
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -58,6 +73,12 @@ func createDefaultConfig() configmodels.Exporter {
 		SendCompatibleMetrics: false,
 		TranslationRules:      nil,
 		DeltaTranslationTTL:   3600,
+		DimensionClient: DimensionClientConfig{
+			MaxBuffered:       defaultDimMaxBuffered,
+			SendDelay:         defaultDimSendDelay,
+			MaxBatchSize:      defaultDimMaxBatchSize,
+			RequestsPerSecond: defaultDimRequestsPerSecond,
+		},
 	}
 }
 
@@ -26,13 +26,19 @@ type deltaTranslator struct {
 }
 
 func newDeltaTranslator(ttl int64) *deltaTranslator {
+	return &deltaTranslator{prevPts: newPrevPtsMap(ttl)}
+}
+
+// newPrevPtsMap returns a started TTLMap to keep the previous datapoints of each metric
+// and dimensions for ttl seconds.
+func newPrevPtsMap(ttl int64) *ttlmap.TTLMap {
 	sweepIntervalSeconds := ttl / 2
 	if sweepIntervalSeconds == 0 {
 		sweepIntervalSeconds = 1
 	}
 	m := ttlmap.New(sweepIntervalSeconds, ttl)
 	m.Start()
-	return &deltaTranslator{prevPts: m}
+	return m
 }
 
 func (t *deltaTranslator) translate(pts []*sfxpb.DataPoint, tr Rule) []*sfxpb.DataPoint {
@@ -53,8 +59,7 @@ func (t *deltaTranslator) translate(pts []*sfxpb.DataPoint, tr Rule) []*sfxpb.Da
 
 func (t *deltaTranslator) deltaPt(deltaMetricName string, currPt *sfxpb.DataPoint) *sfxpb.DataPoint {
 	// check if we have a previous point for this metric + dimensions
-	dimKey := stringifyDimensions(currPt.Dimensions, nil)
-	fullKey := currPt.Metric + ":" + dimKey
+	fullKey := prevPtKey(currPt)
 	v := t.prevPts.Get(fullKey)
 	// without proto.Clone here, points' DoubleValue are converted into IntValues, presumably by other translators
 	t.prevPts.Put(fullKey, proto.Clone(currPt))
@@ -74,6 +79,11 @@ func (t *deltaTranslator) deltaPt(deltaMetricName string, currPt *sfxpb.DataPoin
 	return deltaPt
 }
 
+// prevPtKey returns the key of the previous datapoint of the same metric and dimensions.
+func prevPtKey(pt *sfxpb.DataPoint) string {
+	return pt.Metric + ":" + stringifyDimensions(pt.Dimensions, nil)
+}
+
 func doubleDeltaPt(currPt *sfxpb.DataPoint, prevPt *sfxpb.DataPoint, deltaMetricName string) *sfxpb.DataPoint {
 	delta := *currPt.Value.DoubleValue - *prevPt.Value.DoubleValue
 	if delta < 0 {
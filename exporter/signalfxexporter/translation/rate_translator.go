@@ -0,0 +1,77 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"github.com/gogo/protobuf/proto"
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/ttlmap"
+)
+
+type rateTranslator struct {
+	prevPts *ttlmap.TTLMap
+}
+
+func newRateTranslator(ttl int64) *rateTranslator {
+	return &rateTranslator{prevPts: newPrevPtsMap(ttl)}
+}
+
+func (t *rateTranslator) translate(pts []*sfxpb.DataPoint, tr Rule) []*sfxpb.DataPoint {
+	for _, currPt := range pts {
+		rateMetricName, ok := tr.Mapping[currPt.Metric]
+		if !ok {
+			// only metrics defined in Rule.Mapping get translated
+			continue
+		}
+		ratePt := t.ratePt(rateMetricName, currPt)
+		if ratePt == nil {
+			continue
+		}
+		pts = append(pts, ratePt)
+	}
+	return pts
+}
+
+func (t *rateTranslator) ratePt(rateMetricName string, currPt *sfxpb.DataPoint) *sfxpb.DataPoint {
+	fullKey := prevPtKey(currPt)
+	v := t.prevPts.Get(fullKey)
+	t.prevPts.Put(fullKey, proto.Clone(currPt))
+	if v == nil {
+		// no previous point, so we can't calculate a rate
+		return nil
+	}
+	prevPt := v.(*sfxpb.DataPoint)
+
+	// datapoint timestamps are in milliseconds
+	elapsedSeconds := float64(currPt.Timestamp-prevPt.Timestamp) / 1e3
+	if elapsedSeconds <= 0 {
+		return nil
+	}
+
+	currVal := ptToFloatVal(currPt)
+	prevVal := ptToFloatVal(prevPt)
+	if currVal == nil || prevVal == nil || *currVal < *prevVal {
+		// the counter was reset, wait for the next point
+		return nil
+	}
+
+	rate := (*currVal - *prevVal) / elapsedSeconds
+	ratePt := proto.Clone(currPt).(*sfxpb.DataPoint)
+	ratePt.Metric = rateMetricName
+	ratePt.MetricType = &metricTypeGauge
+	ratePt.Value = sfxpb.Datum{DoubleValue: &rate}
+	return ratePt
+}
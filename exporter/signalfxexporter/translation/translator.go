@@ -104,10 +104,16 @@ const (
 	//  operand1_metric: memory.used
 	//  operand2_metric: memory.total
 	//  operator: /
-	// the integer value of the 'memory.used' metric will be divided by the integer value of 'memory.total'. The
+	// the value of the 'memory.used' metric will be divided by the value of 'memory.total'. The
 	// result will be a new float metric with the name 'memory.utilization' and the value of the quotient. The
 	// new metric will also get any attributes of the 'memory.used' metric except for its value and metric name.
-	// Currently only integer inputs are handled and only division is supported.
+	// The operator can be one of /, +, - and *. A constant can be used as second operand by setting
+	// operand2_value instead of operand2_metric, for example to convert a ratio to a percentage:
+	// - action: calculate_new_metric
+	//  metric_name: memory.utilization_percent
+	//  operand1_metric: memory.utilization
+	//  operand2_value: 100
+	//  operator: "*"
 	ActionCalculateNewMetric Action = "calculate_new_metric"
 
 	// ActionDropMetrics drops datapoints with metric name defined in "metric_names".
@@ -117,12 +123,28 @@ const (
 	// metric. It takes mappings of names of the existing metrics to the names of the new, delta metrics to be
 	// created. All dimensions will be preserved.
 	ActionDeltaMetric Action = "delta_metric"
+
+	// ActionCalculateRate creates a new per-second rate metric from an existing cumulative int or double
+	// metric. It takes mappings of names of the existing metrics to the names of the new, rate metrics to be
+	// created. The rate is computed between consecutive datapoints having the same metric name and
+	// dimensions, no rate is emitted for the first datapoint or when the counter was reset.
+	// All dimensions will be preserved.
+	// For example, for the following translation rule:
+	// - action: calculate_rate
+	//   mapping:
+	//     system.network.io: system.network.io.rate
+	// the datapoints system.network.io{interface="eth0"} 1000 at t=0s and 3000 at t=10s
+	// will produce system.network.io.rate{interface="eth0"} 200 at t=10s.
+	ActionCalculateRate Action = "calculate_rate"
 )
 
 type MetricOperator string
 
 const (
-	MetricOperatorDivision MetricOperator = "/"
+	MetricOperatorDivision       MetricOperator = "/"
+	MetricOperatorAddition       MetricOperator = "+"
+	MetricOperatorSubtraction    MetricOperator = "-"
+	MetricOperatorMultiplication MetricOperator = "*"
 )
 
 // MetricValueType is the enum to capture valid metric value types that can be converted
@@ -197,8 +219,11 @@ type Rule struct {
 	// MetricNames is used by "rename_dimension_keys" and "drop_metrics" translation rules.
 	MetricNames map[string]bool `mapstructure:"metric_names"`
 
+	// Operand1Metric, Operand2Metric and Operator are used by "calculate_new_metric" translation rule.
+	// Operand2Value can be set instead of Operand2Metric to use a constant as second operand.
 	Operand1Metric string         `mapstructure:"operand1_metric"`
 	Operand2Metric string         `mapstructure:"operand2_metric"`
+	Operand2Value  *float64       `mapstructure:"operand2_value"`
 	Operator       MetricOperator `mapstructure:"operator"`
 }
 
@@ -209,6 +234,7 @@ type MetricTranslator struct {
 	dimensionsMap map[string]string
 
 	deltaTranslator *deltaTranslator
+	rateTranslator  *rateTranslator
 }
 
 func NewMetricTranslator(rules []Rule, ttl int64) (*MetricTranslator, error) {
@@ -221,6 +247,7 @@ func NewMetricTranslator(rules []Rule, ttl int64) (*MetricTranslator, error) {
 		rules:           rules,
 		dimensionsMap:   createDimensionsMap(rules),
 		deltaTranslator: newDeltaTranslator(ttl),
+		rateTranslator:  newRateTranslator(ttl),
 	}, nil
 }
 
@@ -302,18 +329,29 @@ func validateTranslationRules(rules []Rule) error {
 					tr.AggregationMethod, tr.Action)
 			}
 		case ActionCalculateNewMetric:
-			if tr.MetricName == "" || tr.Operand1Metric == "" || tr.Operand2Metric == "" || tr.Operator == "" {
-				return fmt.Errorf(`fields "metric_name", "operand1_metric", "operand2_metric", and "operator" are `+
-					"required for %q translation rule", tr.Action)
+			if tr.MetricName == "" || tr.Operand1Metric == "" || (tr.Operand2Metric == "" && tr.Operand2Value == nil) ||
+				tr.Operator == "" {
+				return fmt.Errorf(`fields "metric_name", "operand1_metric", "operand2_metric" or "operand2_value", `+
+					`and "operator" are required for %q translation rule`, tr.Action)
 			}
-			if tr.Operator != MetricOperatorDivision {
+			if tr.Operand2Metric != "" && tr.Operand2Value != nil {
+				return fmt.Errorf(`only one of "operand2_metric" and "operand2_value" can be set for %q translation rule`,
+					tr.Action)
+			}
+			switch tr.Operator {
+			case MetricOperatorDivision, MetricOperatorAddition, MetricOperatorSubtraction, MetricOperatorMultiplication:
+			default:
 				return fmt.Errorf("invalid operator %q for %q translation rule", tr.Operator, tr.Action)
 			}
+			if tr.Operator == MetricOperatorDivision && tr.Operand2Value != nil && *tr.Operand2Value == 0 {
+				return fmt.Errorf(`"operand2_value" cannot be 0 with operator %q for %q translation rule`,
+					tr.Operator, tr.Action)
+			}
 		case ActionDropMetrics:
 			if len(tr.MetricNames) == 0 {
 				return fmt.Errorf(`field "metric_names" is required for %q translation rule`, tr.Action)
 			}
-		case ActionDeltaMetric:
+		case ActionDeltaMetric, ActionCalculateRate:
 			if len(tr.Mapping) == 0 {
 				return fmt.Errorf(`field "mapping" is required for %q translation rule`, tr.Action)
 			}
@@ -427,9 +465,29 @@ func (mp *MetricTranslator) TranslateDataPoints(logger *zap.Logger, sfxDataPoint
 				}
 			}
 		case ActionCalculateNewMetric:
+			if tr.Operand2Value != nil {
+				for _, dp := range processedDataPoints {
+					if dp.Metric != tr.Operand1Metric {
+						continue
+					}
+					if newPt := calculateNewMetric(logger, dp, tr.Operand2Value, tr); newPt != nil {
+						processedDataPoints = append(processedDataPoints, newPt)
+					}
+				}
+				break
+			}
 			pairs := calcNewMetricInputPairs(processedDataPoints, tr)
 			for _, pair := range pairs {
-				newPt := calculateNewMetric(logger, pair[0], pair[1], tr)
+				v2 := ptToFloatVal(pair[1])
+				if v2 == nil {
+					logger.Warn(
+						"calculate_new_metric: operand2 has no numeric value",
+						zap.String("tr.Operand2Metric", tr.Operand2Metric),
+						zap.String("tr.MetricName", tr.MetricName),
+					)
+					continue
+				}
+				newPt := calculateNewMetric(logger, pair[0], v2, tr)
 				if newPt == nil {
 					continue
 				}
@@ -473,6 +531,9 @@ func (mp *MetricTranslator) TranslateDataPoints(logger *zap.Logger, sfxDataPoint
 
 		case ActionDeltaMetric:
 			processedDataPoints = mp.deltaTranslator.translate(processedDataPoints, tr)
+
+		case ActionCalculateRate:
+			processedDataPoints = mp.rateTranslator.translate(processedDataPoints, tr)
 		}
 	}
 
@@ -523,10 +584,12 @@ func dimensionsEqual(d1 []*sfxpb.Dimension, d2 []*sfxpb.Dimension) bool {
 	return true
 }
 
+// calculateNewMetric applies the operator of the rule to the value of operand1 and v2, the value of
+// the second operand.
 func calculateNewMetric(
 	logger *zap.Logger,
 	operand1 *sfxpb.DataPoint,
-	operand2 *sfxpb.DataPoint,
+	v2 *float64,
 	tr Rule,
 ) *sfxpb.DataPoint {
 	v1 := ptToFloatVal(operand1)
@@ -539,16 +602,6 @@ func calculateNewMetric(
 		return nil
 	}
 
-	v2 := ptToFloatVal(operand2)
-	if v2 == nil {
-		logger.Warn(
-			"calculate_new_metric: operand2 has no numeric value",
-			zap.String("tr.Operand2Metric", tr.Operand1Metric),
-			zap.String("tr.MetricName", tr.MetricName),
-		)
-		return nil
-	}
-
 	if tr.Operator == MetricOperatorDivision && *v2 == 0 {
 		logger.Warn(
 			"calculate_new_metric: attempt to divide by zero, skipping",
@@ -562,9 +615,14 @@ func calculateNewMetric(
 	newPt.Metric = tr.MetricName
 	var newPtVal float64
 	switch tr.Operator {
-	// only supporting divide operator for now
 	case MetricOperatorDivision:
 		newPtVal = *v1 / *v2
+	case MetricOperatorAddition:
+		newPtVal = *v1 + *v2
+	case MetricOperatorSubtraction:
+		newPtVal = *v1 - *v2
+	case MetricOperatorMultiplication:
+		newPtVal = *v1 * *v2
 	default:
 		logger.Warn("calculate_new_metric: unsupported operator", zap.String("operator", string(tr.Operator)))
 		return nil
@@ -453,8 +453,8 @@ func TestNewMetricTranslator(t *testing.T) {
 				},
 			},
 			wantDimensionsMap: nil,
-			wantError: `fields "metric_name", "operand1_metric", "operand2_metric", and "operator" ` +
-				`are required for "calculate_new_metric" translation rule`,
+			wantError: `fields "metric_name", "operand1_metric", "operand2_metric" or "operand2_value", ` +
+				`and "operator" are required for "calculate_new_metric" translation rule`,
 		},
 		{
 			name: "divide_metrics_invalid_missing_op_1",
@@ -467,8 +467,8 @@ func TestNewMetricTranslator(t *testing.T) {
 				},
 			},
 			wantDimensionsMap: nil,
-			wantError: `fields "metric_name", "operand1_metric", "operand2_metric", and "operator" ` +
-				`are required for "calculate_new_metric" translation rule`,
+			wantError: `fields "metric_name", "operand1_metric", "operand2_metric" or "operand2_value", ` +
+				`and "operator" are required for "calculate_new_metric" translation rule`,
 		},
 		{
 			name: "divide_metrics_invalid_missing_op_2",
@@ -481,8 +481,8 @@ func TestNewMetricTranslator(t *testing.T) {
 				},
 			},
 			wantDimensionsMap: nil,
-			wantError: `fields "metric_name", "operand1_metric", "operand2_metric", and "operator" ` +
-				`are required for "calculate_new_metric" translation rule`,
+			wantError: `fields "metric_name", "operand1_metric", "operand2_metric" or "operand2_value", ` +
+				`and "operator" are required for "calculate_new_metric" translation rule`,
 		},
 		{
 			name: "calculate_new_metric_missing_operator",
@@ -495,8 +495,8 @@ func TestNewMetricTranslator(t *testing.T) {
 				},
 			},
 			wantDimensionsMap: nil,
-			wantError: `fields "metric_name", "operand1_metric", "operand2_metric", and "operator" ` +
-				`are required for "calculate_new_metric" translation rule`,
+			wantError: `fields "metric_name", "operand1_metric", "operand2_metric" or "operand2_value", ` +
+				`and "operator" are required for "calculate_new_metric" translation rule`,
 		},
 		{
 			name: "drop_metrics_valid",
@@ -528,6 +528,65 @@ func TestNewMetricTranslator(t *testing.T) {
 			},
 			wantError: `field "mapping" is required for "delta_metric" translation rule`,
 		},
+		{
+			name: "calculate_new_metric_constant_valid",
+			trs: []Rule{
+				{
+					Action:         ActionCalculateNewMetric,
+					MetricName:     "metric",
+					Operand1Metric: "op1_metric",
+					Operand2Value:  generateFloatPtr(100),
+					Operator:       MetricOperatorMultiplication,
+				},
+			},
+			wantError: "",
+		},
+		{
+			name: "calculate_new_metric_both_operand2",
+			trs: []Rule{
+				{
+					Action:         ActionCalculateNewMetric,
+					MetricName:     "metric",
+					Operand1Metric: "op1_metric",
+					Operand2Metric: "op2_metric",
+					Operand2Value:  generateFloatPtr(100),
+					Operator:       MetricOperatorMultiplication,
+				},
+			},
+			wantError: `only one of "operand2_metric" and "operand2_value" can be set for "calculate_new_metric" translation rule`,
+		},
+		{
+			name: "calculate_new_metric_divide_by_zero_constant",
+			trs: []Rule{
+				{
+					Action:         ActionCalculateNewMetric,
+					MetricName:     "metric",
+					Operand1Metric: "op1_metric",
+					Operand2Value:  generateFloatPtr(0),
+					Operator:       MetricOperatorDivision,
+				},
+			},
+			wantError: `"operand2_value" cannot be 0 with operator "/" for "calculate_new_metric" translation rule`,
+		},
+		{
+			name: "calculate_rate_valid",
+			trs: []Rule{
+				{
+					Action:  ActionCalculateRate,
+					Mapping: map[string]string{"metric": "metric.rate"},
+				},
+			},
+			wantError: "",
+		},
+		{
+			name: "calculate_rate_invalid",
+			trs: []Rule{
+				{
+					Action: ActionCalculateRate,
+				},
+			},
+			wantError: `field "mapping" is required for "calculate_rate" translation rule`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2038,12 +2097,12 @@ func TestNewMetricTranslator_InvalidOperator(t *testing.T) {
 		MetricName:     "metric3",
 		Operand1Metric: "metric1",
 		Operand2Metric: "metric2",
-		Operator:       "*",
+		Operator:       "%",
 	}}, 1)
-	require.Errorf(
+	require.EqualError(
 		t,
 		err,
-		`invalid operator "*" for "calculate_new_metric" translation rule`,
+		`invalid operator "%" for "calculate_new_metric" translation rule`,
 	)
 }
 
@@ -2332,7 +2391,7 @@ func TestUnsupportedOperator(t *testing.T) {
 		MetricName:     "metric3",
 		Operand1Metric: "metric1",
 		Operand2Metric: "metric2",
-		Operator:       "*",
+		Operator:       "%",
 	}}, 1)
 	require.Error(t, err)
 }
@@ -2398,6 +2457,93 @@ func generateFloatPtr(i float64) *float64 {
 	return &iPtr
 }
 
+func TestCalculateNewMetric_Operators(t *testing.T) {
+	tests := []struct {
+		operator MetricOperator
+		want     float64
+	}{
+		{MetricOperatorDivision, 0.5},
+		{MetricOperatorAddition, 3},
+		{MetricOperatorSubtraction, -1},
+		{MetricOperatorMultiplication, 2},
+	}
+	for _, test := range tests {
+		t.Run(string(test.operator), func(t *testing.T) {
+			mt, err := NewMetricTranslator([]Rule{{
+				Action:         ActionCalculateNewMetric,
+				MetricName:     "metric3",
+				Operand1Metric: "metric1",
+				Operand2Metric: "metric2",
+				Operator:       test.operator,
+			}}, 1)
+			require.NoError(t, err)
+			dps := []*sfxpb.DataPoint{
+				{
+					Metric:     "metric1",
+					Timestamp:  msec,
+					MetricType: &gaugeType,
+					Value:      sfxpb.Datum{IntValue: generateIntPtr(1)},
+				},
+				{
+					Metric:     "metric2",
+					Timestamp:  msec,
+					MetricType: &gaugeType,
+					Value:      sfxpb.Datum{DoubleValue: generateFloatPtr(2)},
+				},
+			}
+			translated := mt.TranslateDataPoints(zap.NewNop(), dps)
+			require.Equal(t, 3, len(translated))
+			require.Equal(t, "metric3", translated[2].Metric)
+			require.Equal(t, test.want, *translated[2].Value.DoubleValue)
+		})
+	}
+}
+
+func TestCalculateNewMetric_Constant(t *testing.T) {
+	mt, err := NewMetricTranslator([]Rule{{
+		Action:         ActionCalculateNewMetric,
+		MetricName:     "metric1.percent",
+		Operand1Metric: "metric1",
+		Operand2Value:  generateFloatPtr(100),
+		Operator:       MetricOperatorMultiplication,
+	}}, 1)
+	require.NoError(t, err)
+	m1 := &sfxpb.DataPoint{
+		Metric:     "metric1",
+		Timestamp:  msec,
+		MetricType: &gaugeType,
+		Value:      sfxpb.Datum{DoubleValue: generateFloatPtr(0.25)},
+		Dimensions: []*sfxpb.Dimension{{Key: "dim1", Value: "val1"}},
+	}
+	m2 := &sfxpb.DataPoint{
+		Metric:     "metric1",
+		Timestamp:  msec,
+		MetricType: &gaugeType,
+		Value:      sfxpb.Datum{DoubleValue: generateFloatPtr(0.5)},
+		Dimensions: []*sfxpb.Dimension{{Key: "dim1", Value: "val2"}},
+	}
+	translated := mt.TranslateDataPoints(zap.NewNop(), []*sfxpb.DataPoint{m1, m2})
+	want := []*sfxpb.DataPoint{
+		m1,
+		m2,
+		{
+			Metric:     "metric1.percent",
+			Timestamp:  msec,
+			MetricType: &gaugeType,
+			Value:      sfxpb.Datum{DoubleValue: generateFloatPtr(25)},
+			Dimensions: []*sfxpb.Dimension{{Key: "dim1", Value: "val1"}},
+		},
+		{
+			Metric:     "metric1.percent",
+			Timestamp:  msec,
+			MetricType: &gaugeType,
+			Value:      sfxpb.Datum{DoubleValue: generateFloatPtr(50)},
+			Dimensions: []*sfxpb.Dimension{{Key: "dim1", Value: "val2"}},
+		},
+	}
+	assertEqualPoints(t, translated, want, ActionCalculateNewMetric)
+}
+
 func TestDimensionsEqual(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -2542,6 +2688,54 @@ func TestDeltaTranslatorMismatchedValueTypes(t *testing.T) {
 	require.Equal(t, 1, len(idx))
 }
 
+func TestCalculateRate(t *testing.T) {
+	mt, err := NewMetricTranslator([]Rule{{
+		Action:  ActionCalculateRate,
+		Mapping: map[string]string{"system.cpu.time": "system.cpu.rate"},
+	}}, 1)
+	require.NoError(t, err)
+	c := NewMetricsConverter(zap.NewNop(), mt)
+
+	pts, _ := c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMD(10, 0)}, nil)
+	require.Equal(t, 1, len(indexPts(pts)), "no rate expected for the first datapoints")
+
+	// 30 more in 20 seconds
+	pts, _ = c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMD(30, 30)}, nil)
+	ratePts, ok := indexPts(pts)["system.cpu.rate"]
+	require.True(t, ok)
+	require.Equal(t, 6, len(ratePts))
+	gaugeType := sfxpb.MetricType_GAUGE
+	for _, pt := range ratePts {
+		require.Equal(t, &gaugeType, pt.MetricType)
+		require.EqualValues(t, 1.5, *pt.Value.DoubleValue)
+		require.Equal(t, 2, len(pt.Dimensions))
+	}
+
+	// no rate after a reset
+	pts, _ = c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMDAfterReset(40, 5)}, nil)
+	require.Equal(t, 1, len(indexPts(pts)))
+
+	pts, _ = c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMDAfterReset(50, 25)}, nil)
+	ratePts = indexPts(pts)["system.cpu.rate"]
+	require.Equal(t, 6, len(ratePts))
+	for _, pt := range ratePts {
+		require.EqualValues(t, 2, *pt.Value.DoubleValue)
+	}
+}
+
+func TestCalculateRateSameTimestamp(t *testing.T) {
+	mt, err := NewMetricTranslator([]Rule{{
+		Action:  ActionCalculateRate,
+		Mapping: map[string]string{"system.cpu.time": "system.cpu.rate"},
+	}}, 1)
+	require.NoError(t, err)
+	c := NewMetricsConverter(zap.NewNop(), mt)
+
+	_, _ = c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMD(10, 0)}, nil)
+	pts, _ := c.MetricDataToSignalFxV2([]consumerdata.MetricsData{intMD(10, 10)}, nil)
+	require.Equal(t, 1, len(indexPts(pts)))
+}
+
 func requireDeltaMetricOk(t *testing.T, md1, md2, md3 consumerdata.MetricsData) (
 	[]*sfxpb.DataPoint, []*sfxpb.DataPoint,
 ) {
@@ -34,6 +34,7 @@ type Config struct {
 	// Timeout for all API calls. If not set, defaults to 12 seconds.
 	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 	ResourceMappings               []ResourceMapping        `mapstructure:"resource_mappings"`
+	LogConfig                      LogConfig                `mapstructure:"log"`
 	// GetClientOptions returns additional options to be passed
 	// to the underlying Google Cloud API client.
 	// Must be set programmatically (no support via declarative config).
@@ -41,6 +42,14 @@ type Config struct {
 	GetClientOptions func() []option.ClientOption
 }
 
+// LogConfig defines configuration for the export of logs to Cloud Logging.
+type LogConfig struct {
+	// DefaultLogName is the name of the log written to for log records without a name.
+	DefaultLogName string `mapstructure:"default_log_name"`
+	// MaxEntriesPerRequest is the maximum number of entries written in a single request.
+	MaxEntriesPerRequest int `mapstructure:"max_entries_per_request"`
+}
+
 // ResourceMapping defines mapping of resources from source (OpenCensus) to target (Stackdriver).
 type ResourceMapping struct {
 	SourceType string `mapstructure:"source_type"`
@@ -81,5 +81,9 @@ func TestLoadConfig(t *testing.T) {
 					TargetType: "target-resource2",
 				},
 			},
+			LogConfig: LogConfig{
+				DefaultLogName:       "my-app",
+				MaxEntriesPerRequest: 500,
+			},
 		})
 }
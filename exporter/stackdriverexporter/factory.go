@@ -45,6 +45,7 @@ func NewFactory() component.ExporterFactory {
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTraceExporter),
 		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter),
 	)
 }
 
@@ -57,6 +58,10 @@ func createDefaultConfig() configmodels.Exporter {
 		},
 		TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: defaultTimeout},
 		UserAgent:       "opentelemetry-collector-contrib {{version}}",
+		LogConfig: LogConfig{
+			DefaultLogName:       "opentelemetry-collector",
+			MaxEntriesPerRequest: 1000,
+		},
 	}
 }
 
@@ -75,5 +80,14 @@ func createMetricsExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter) (component.MetricsExporter, error) {
 	eCfg := cfg.(*Config)
-	return newStackdriverMetricsExporter(eCfg, params.ApplicationStartInfo.Version)
+	return newStackdriverMetricsExporter(eCfg, params.ApplicationStartInfo.Version, params.Logger)
+}
+
+// createLogsExporter creates a logs exporter based on this config.
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter) (component.LogsExporter, error) {
+	eCfg := cfg.(*Config)
+	return newStackdriverLogsExporter(eCfg, params.ApplicationStartInfo.Version)
 }
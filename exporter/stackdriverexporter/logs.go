@@ -0,0 +1,281 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	logtypepb "google.golang.org/genproto/googleapis/logging/type"
+	loggingpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLoggingEndpoint = "logging.googleapis.com:443"
+	loggingWriteScope      = "https://www.googleapis.com/auth/logging.write"
+
+	// Cloud Logging rejects WriteLogEntries requests larger than 10MB, keep some room for
+	// the fields of the request wrapping the entries.
+	maxWriteLogEntriesBytes = 9 * 1024 * 1024
+)
+
+// logsExporter writes pdata logs to Cloud Logging
+type logsExporter struct {
+	client    loggingpb.LoggingServiceV2Client
+	conn      *grpc.ClientConn
+	projectID string
+	cfg       LogConfig
+	mapper    resourceMapper
+}
+
+func (*logsExporter) Name() string {
+	return name
+}
+
+func (le *logsExporter) Shutdown(context.Context) error {
+	return le.conn.Close()
+}
+
+func newStackdriverLogsExporter(cfg *Config, version string) (component.LogsExporter, error) {
+	conn, projectID, err := dialGoogleAPI(cfg, version, defaultLoggingEndpoint, loggingWriteScope)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure Cloud Logging client: %w", err)
+	}
+
+	lExp := &logsExporter{
+		client:    loggingpb.NewLoggingServiceV2Client(conn),
+		conn:      conn,
+		projectID: projectID,
+		cfg:       cfg.LogConfig,
+		mapper:    resourceMapper{mappings: cfg.ResourceMappings},
+	}
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		lExp.pushLogs,
+		exporterhelper.WithShutdown(lExp.Shutdown),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings))
+}
+
+// pushLogs converts the given logs to LogEntries and writes them in batches. When a batch is rejected
+// because some of its entries are invalid, its entries are written one by one so that only the invalid
+// ones are dropped.
+func (le *logsExporter) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	var errs []error
+	dropped := 0
+
+	batches := le.batchEntries(le.logsToEntries(ld))
+	for _, batch := range batches {
+		err := le.writeEntries(ctx, batch)
+		if err == nil {
+			continue
+		}
+		if !isPartialFailure(err) {
+			dropped += len(batch)
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range batch {
+			if err := le.writeEntries(ctx, []*loggingpb.LogEntry{entry}); err != nil {
+				dropped++
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return dropped, componenterror.CombineErrors(errs)
+}
+
+func (le *logsExporter) writeEntries(ctx context.Context, entries []*loggingpb.LogEntry) error {
+	// Partial success is disabled so that nothing is written when the batch is rejected,
+	// the valid entries are then written again one by one without being duplicated.
+	_, err := le.client.WriteLogEntries(ctx, &loggingpb.WriteLogEntriesRequest{Entries: entries})
+	if err != nil && isPartialFailure(err) && len(entries) == 1 {
+		// The entry itself is invalid, writing it again won't help
+		return consumererror.Permanent(err)
+	}
+	return err
+}
+
+// isPartialFailure returns whether the error reports individual entries which failed to be written
+func isPartialFailure(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if _, ok := detail.(*loggingpb.WriteLogEntriesPartialErrors); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// batchEntries splits entries in batches holding at most MaxEntriesPerRequest entries
+// and maxWriteLogEntriesBytes bytes.
+func (le *logsExporter) batchEntries(entries []*loggingpb.LogEntry) [][]*loggingpb.LogEntry {
+	var batches [][]*loggingpb.LogEntry
+	var batch []*loggingpb.LogEntry
+	batchSize := 0
+	for _, entry := range entries {
+		size := proto.Size(entry)
+		if len(batch) > 0 && (len(batch) == le.cfg.MaxEntriesPerRequest || batchSize+size > maxWriteLogEntriesBytes) {
+			batches = append(batches, batch)
+			batch, batchSize = nil, 0
+		}
+		batch = append(batch, entry)
+		batchSize += size
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func (le *logsExporter) logsToEntries(ld pdata.Logs) []*loggingpb.LogEntry {
+	var entries []*loggingpb.LogEntry
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		monitoredResource := le.mapper.mapPdataResource(rl.Resource(), le.projectID)
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				if lr := logs.At(k); !lr.IsNil() {
+					entries = append(entries, logRecordToEntry(le.projectID, le.cfg.DefaultLogName, monitoredResource, lr))
+				}
+			}
+		}
+	}
+	return entries
+}
+
+// logRecordToEntry converts a LogRecord to a LogEntry. Map bodies are sent as JSON payloads,
+// all other bodies as text payloads.
+func logRecordToEntry(projectID, defaultLogName string, monitoredResource *monitoredrespb.MonitoredResource, lr pdata.LogRecord) *loggingpb.LogEntry {
+	logName := lr.Name()
+	if logName == "" {
+		logName = defaultLogName
+	}
+
+	entry := &loggingpb.LogEntry{
+		LogName:  fmt.Sprintf("projects/%s/logs/%s", projectID, url.PathEscape(logName)),
+		Resource: monitoredResource,
+		Severity: severityNumberToLogSeverity(lr.SeverityNumber()),
+	}
+
+	if lr.Timestamp() != 0 {
+		entry.Timestamp = timestamppb.New(time.Unix(0, int64(lr.Timestamp())))
+	}
+
+	if traceID := lr.TraceID().HexString(); traceID != "" {
+		entry.Trace = fmt.Sprintf("projects/%s/traces/%s", projectID, traceID)
+		entry.SpanId = lr.SpanID().HexString()
+		entry.TraceSampled = lr.Flags()&0x1 == 0x1
+	}
+
+	if attrs := lr.Attributes(); attrs.Len() > 0 {
+		entry.Labels = make(map[string]string, attrs.Len())
+		attrs.ForEach(func(k string, v pdata.AttributeValue) {
+			entry.Labels[k] = tracetranslator.AttributeValueToString(v, false)
+		})
+	}
+
+	body := lr.Body()
+	switch {
+	case body.IsNil():
+	case body.Type() == pdata.AttributeValueMAP:
+		entry.Payload = &loggingpb.LogEntry_JsonPayload{JsonPayload: attributeMapToStruct(body.MapVal())}
+	default:
+		entry.Payload = &loggingpb.LogEntry_TextPayload{TextPayload: tracetranslator.AttributeValueToString(body, false)}
+	}
+
+	return entry
+}
+
+func attributeMapToStruct(m pdata.AttributeMap) *structpb.Struct {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, m.Len())}
+	m.ForEach(func(k string, v pdata.AttributeValue) {
+		s.Fields[k] = attributeValueToStructValue(v)
+	})
+	return s
+}
+
+func attributeValueToStructValue(v pdata.AttributeValue) *structpb.Value {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: v.StringVal()}}
+	case pdata.AttributeValueINT:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: float64(v.IntVal())}}
+	case pdata.AttributeValueDOUBLE:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: v.DoubleVal()}}
+	case pdata.AttributeValueBOOL:
+		return &structpb.Value{Kind: &structpb.Value_BoolValue{BoolValue: v.BoolVal()}}
+	case pdata.AttributeValueMAP:
+		return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: attributeMapToStruct(v.MapVal())}}
+	case pdata.AttributeValueARRAY:
+		arr := v.ArrayVal()
+		list := &structpb.ListValue{Values: make([]*structpb.Value, 0, arr.Len())}
+		for i := 0; i < arr.Len(); i++ {
+			list.Values = append(list.Values, attributeValueToStructValue(arr.At(i)))
+		}
+		return &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: list}}
+	default:
+		return &structpb.Value{Kind: &structpb.Value_NullValue{}}
+	}
+}
+
+// severityNumberToLogSeverity maps the OpenTelemetry severity ranges to the closest
+// Cloud Logging severity.
+func severityNumberToLogSeverity(severityNumber pdata.SeverityNumber) logtypepb.LogSeverity {
+	switch {
+	case severityNumber == pdata.SeverityNumberUNDEFINED:
+		return logtypepb.LogSeverity_DEFAULT
+	case severityNumber <= pdata.SeverityNumberDEBUG4:
+		return logtypepb.LogSeverity_DEBUG
+	case severityNumber == pdata.SeverityNumberINFO:
+		return logtypepb.LogSeverity_INFO
+	case severityNumber <= pdata.SeverityNumberINFO4:
+		return logtypepb.LogSeverity_NOTICE
+	case severityNumber <= pdata.SeverityNumberWARN4:
+		return logtypepb.LogSeverity_WARNING
+	case severityNumber <= pdata.SeverityNumberERROR4:
+		return logtypepb.LogSeverity_ERROR
+	case severityNumber <= pdata.SeverityNumberFATAL2:
+		return logtypepb.LogSeverity_CRITICAL
+	case severityNumber == pdata.SeverityNumberFATAL3:
+		return logtypepb.LogSeverity_ALERT
+	default:
+		return logtypepb.LogSeverity_EMERGENCY
+	}
+}
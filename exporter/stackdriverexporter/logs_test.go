@@ -0,0 +1,219 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"google.golang.org/api/option"
+	logtypepb "google.golang.org/genproto/googleapis/logging/type"
+	loggingpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockLoggingServer struct {
+	loggingpb.UnimplementedLoggingServiceV2Server
+
+	mu       sync.Mutex
+	requests []*loggingpb.WriteLogEntriesRequest
+	// invalid rejects the entries with the given text payload
+	invalid string
+}
+
+func (ms *mockLoggingServer) WriteLogEntries(_ context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	ms.mu.Lock()
+	ms.requests = append(ms.requests, req)
+	ms.mu.Unlock()
+
+	partialErrors := &loggingpb.WriteLogEntriesPartialErrors{LogEntryErrors: map[int32]*status.Status{}}
+	for i, entry := range req.Entries {
+		if ms.invalid != "" && entry.GetTextPayload() == ms.invalid {
+			partialErrors.LogEntryErrors[int32(i)] = &status.Status{Code: int32(codes.InvalidArgument)}
+		}
+	}
+	if len(partialErrors.LogEntryErrors) == 0 {
+		return &loggingpb.WriteLogEntriesResponse{}, nil
+	}
+	details, err := anypb.New(partialErrors)
+	if err != nil {
+		return nil, err
+	}
+	return nil, grpcstatus.ErrorProto(&status.Status{
+		Code:    int32(codes.InvalidArgument),
+		Message: "invalid entries",
+		Details: []*anypb.Any{details},
+	})
+}
+
+func startMockLoggingServer(t *testing.T, ms *mockLoggingServer) (string, func()) {
+	srv := grpc.NewServer()
+	loggingpb.RegisterLoggingServiceV2Server(srv, ms)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(lis)
+
+	return lis.Addr().String(), srv.Stop
+}
+
+func newTestLogsConfig(endpoint string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProjectID = "my-project"
+	cfg.Endpoint = endpoint
+	cfg.UseInsecure = true
+	cfg.GetClientOptions = func() []option.ClientOption {
+		return []option.ClientOption{option.WithoutAuthentication()}
+	}
+	return cfg
+}
+
+func TestStackdriverLogsExport(t *testing.T) {
+	ms := &mockLoggingServer{}
+	endpoint, stop := startMockLoggingServer(t, ms)
+	defer stop()
+
+	sde, err := newStackdriverLogsExporter(newTestLogsConfig(endpoint), "v0.0.1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	testTime := time.Now()
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString(conventions.AttributeK8sCluster, "cluster")
+	rl.Resource().Attributes().InsertString(conventions.AttributeK8sNamespace, "namespace")
+	rl.Resource().Attributes().InsertString(conventions.AttributeK8sPod, "pod")
+	rl.Resource().Attributes().InsertString(conventions.AttributeCloudZone, "us-central1-a")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	lr := logs.At(0)
+	lr.SetName("my/log")
+	lr.SetTimestamp(pdata.TimestampUnixNano(testTime.UnixNano()))
+	lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	lr.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	lr.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	lr.SetFlags(1)
+	lr.Body().SetStringVal("hello")
+	lr.Attributes().InsertInt("attempt", 2)
+
+	lr = logs.At(1)
+	body := pdata.NewAttributeMap()
+	body.InsertString("message", "structured")
+	body.InsertInt("count", 3)
+	lr.Body().SetMapVal(body)
+
+	require.NoError(t, sde.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, ms.requests, 1)
+	entries := ms.requests[0].Entries
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "projects/my-project/logs/my%2Flog", entries[0].LogName)
+	assert.Equal(t, "k8s_pod", entries[0].Resource.Type)
+	assert.Equal(t, map[string]string{
+		"project_id":     "my-project",
+		"location":       "us-central1-a",
+		"cluster_name":   "cluster",
+		"namespace_name": "namespace",
+		"pod_name":       "pod",
+	}, entries[0].Resource.Labels)
+	assert.Equal(t, timestamppb.New(testTime), entries[0].Timestamp)
+	assert.Equal(t, logtypepb.LogSeverity_WARNING, entries[0].Severity)
+	assert.Equal(t, "projects/my-project/traces/0102030405060708090a0b0c0d0e0f10", entries[0].Trace)
+	assert.Equal(t, "0102030405060708", entries[0].SpanId)
+	assert.True(t, entries[0].TraceSampled)
+	assert.Equal(t, "hello", entries[0].GetTextPayload())
+	assert.Equal(t, map[string]string{"attempt": "2"}, entries[0].Labels)
+
+	assert.Equal(t, "projects/my-project/logs/opentelemetry-collector", entries[1].LogName)
+	assert.Equal(t, logtypepb.LogSeverity_DEFAULT, entries[1].Severity)
+	assert.Empty(t, entries[1].Trace)
+	assert.Nil(t, entries[1].Timestamp)
+	payload := entries[1].GetJsonPayload().AsMap()
+	assert.Equal(t, map[string]interface{}{"message": "structured", "count": float64(3)}, payload)
+}
+
+func TestStackdriverLogsExportPartialFailure(t *testing.T) {
+	ms := &mockLoggingServer{invalid: "invalid"}
+	endpoint, stop := startMockLoggingServer(t, ms)
+	defer stop()
+
+	cfg := newTestLogsConfig(endpoint)
+	cfg.LogConfig.MaxEntriesPerRequest = 2
+	sde, err := newStackdriverLogsExporter(cfg, "v0.0.1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(3)
+	logs.At(0).Body().SetStringVal("valid")
+	logs.At(1).Body().SetStringVal("invalid")
+	logs.At(2).Body().SetStringVal("valid")
+
+	err = sde.ConsumeLogs(context.Background(), ld)
+	require.Error(t, err)
+
+	// The first batch fails and is retried entry by entry, the second batch succeeds
+	var written []string
+	for _, req := range ms.requests {
+		for _, entry := range req.Entries {
+			written = append(written, entry.GetTextPayload())
+		}
+	}
+	assert.Equal(t, []string{"valid", "invalid", "valid", "invalid", "valid"}, written)
+	require.Len(t, ms.requests, 4)
+	assert.Len(t, ms.requests[0].Entries, 2)
+	assert.Equal(t, "global", ms.requests[3].Entries[0].Resource.Type)
+}
+
+func TestSeverityNumberToLogSeverity(t *testing.T) {
+	tests := []struct {
+		severityNumber pdata.SeverityNumber
+		expected       logtypepb.LogSeverity
+	}{
+		{pdata.SeverityNumberUNDEFINED, logtypepb.LogSeverity_DEFAULT},
+		{pdata.SeverityNumberTRACE, logtypepb.LogSeverity_DEBUG},
+		{pdata.SeverityNumberDEBUG4, logtypepb.LogSeverity_DEBUG},
+		{pdata.SeverityNumberINFO, logtypepb.LogSeverity_INFO},
+		{pdata.SeverityNumberINFO2, logtypepb.LogSeverity_NOTICE},
+		{pdata.SeverityNumberWARN3, logtypepb.LogSeverity_WARNING},
+		{pdata.SeverityNumberERROR, logtypepb.LogSeverity_ERROR},
+		{pdata.SeverityNumberFATAL, logtypepb.LogSeverity_CRITICAL},
+		{pdata.SeverityNumberFATAL3, logtypepb.LogSeverity_ALERT},
+		{pdata.SeverityNumberFATAL4, logtypepb.LogSeverity_EMERGENCY},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, severityNumberToLogSeverity(tt.severityNumber))
+	}
+}
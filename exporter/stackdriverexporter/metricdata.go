@@ -0,0 +1,564 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	distributionpb "google.golang.org/genproto/googleapis/api/distribution"
+	labelpb "google.golang.org/genproto/googleapis/api/label"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxTimeSeriesPerRequest is the maximum number of time series accepted by CreateTimeSeries
+	maxTimeSeriesPerRequest = 200
+)
+
+var (
+	// defaultDomain is the domain of metrics without one, consistent with the OpenCensus exporter
+	defaultDomain = path.Join("custom.googleapis.com", "opencensus")
+	knownDomains  = []string{"googleapis.com", "kubernetes.io", "istio.io", "knative.dev"}
+	// Descriptors can only be created for these metric types, the others are built in
+	customMetricPrefixes = []string{"custom.googleapis.com/", "external.googleapis.com/"}
+)
+
+// metricsToTimeSeries translates metrics to time series holding a single point each, along with
+// the descriptors of the custom metrics.
+func (me *metricsExporter) metricsToTimeSeries(md pdata.Metrics) ([]*monitoringpb.TimeSeries, []*metricpb.MetricDescriptor) {
+	var timeSeries []*monitoringpb.TimeSeries
+	var descriptors []*metricpb.MetricDescriptor
+
+	spans := newExemplarSpans(md)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		resource := me.mapper.mapPdataResource(rm.Resource(), me.projectID)
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+				mt := metricTranslator{
+					projectID:  me.projectID,
+					metricType: metricType(me.prefix, metric.Name()),
+					resource:   resource,
+					spans:      spans,
+					position:   exemplarPosition{resource: i, library: j, metric: k},
+					startTimes: me.startTimes,
+				}
+				ts := mt.translate(metric)
+				if len(ts) == 0 {
+					continue
+				}
+				timeSeries = append(timeSeries, ts...)
+				if isCustomMetric(mt.metricType) {
+					descriptors = append(descriptors, mt.descriptor(metric, ts[0]))
+				}
+			}
+		}
+	}
+
+	return timeSeries, descriptors
+}
+
+// metricTranslator translates the data points of a single metric
+type metricTranslator struct {
+	projectID  string
+	metricType string
+	resource   *monitoredrespb.MonitoredResource
+	spans      exemplarSpans
+	position   exemplarPosition
+	startTimes *startTimeCache
+}
+
+func (mt *metricTranslator) translate(metric pdata.Metric) []*monitoringpb.TimeSeries {
+	var timeSeries []*monitoringpb.TimeSeries
+	add := func(labels pdata.StringMap, kind metricpb.MetricDescriptor_MetricKind, valueType metricpb.MetricDescriptor_ValueType,
+		start, end pdata.TimestampUnixNano, value *monitoringpb.TypedValue) {
+		metricLabels := sanitizeLabels(labels)
+		timeSeries = append(timeSeries, &monitoringpb.TimeSeries{
+			Metric: &metricpb.Metric{
+				Type:   mt.metricType,
+				Labels: metricLabels,
+			},
+			Resource:   mt.resource,
+			MetricKind: kind,
+			ValueType:  valueType,
+			Points: []*monitoringpb.Point{{
+				Interval: mt.timeInterval(kind, metricLabels, start, end),
+				Value:    value,
+			}},
+		})
+	}
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if metric.IntGauge().IsNil() {
+			break
+		}
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), metricpb.MetricDescriptor_GAUGE, metricpb.MetricDescriptor_INT64,
+					dp.StartTime(), dp.Timestamp(), int64Value(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if metric.DoubleGauge().IsNil() {
+			break
+		}
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), metricpb.MetricDescriptor_GAUGE, metricpb.MetricDescriptor_DOUBLE,
+					dp.StartTime(), dp.Timestamp(), doubleValue(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().IsNil() {
+			break
+		}
+		kind := sumMetricKind(metric.IntSum().IsMonotonic())
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), kind, metricpb.MetricDescriptor_INT64,
+					dp.StartTime(), dp.Timestamp(), int64Value(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().IsNil() {
+			break
+		}
+		kind := sumMetricKind(metric.DoubleSum().IsMonotonic())
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); !dp.IsNil() {
+				add(dp.LabelsMap(), kind, metricpb.MetricDescriptor_DOUBLE,
+					dp.StartTime(), dp.Timestamp(), doubleValue(dp.Value()))
+			}
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if metric.IntHistogram().IsNil() {
+			break
+		}
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.IsNil() {
+				continue
+			}
+			distribution := newDistribution(dp.Count(), float64(dp.Sum()), dp.BucketCounts(), dp.ExplicitBounds())
+			exemplars := dp.Exemplars()
+			for e := 0; e < exemplars.Len(); e++ {
+				if ex := exemplars.At(e); !ex.IsNil() {
+					distribution.Exemplars = append(distribution.Exemplars,
+						mt.exemplar(i, e, float64(ex.Value()), ex.Timestamp(), ex.FilteredLabels()))
+				}
+			}
+			add(dp.LabelsMap(), metricpb.MetricDescriptor_CUMULATIVE, metricpb.MetricDescriptor_DISTRIBUTION,
+				dp.StartTime(), dp.Timestamp(), distributionValue(distribution))
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if metric.DoubleHistogram().IsNil() {
+			break
+		}
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.IsNil() {
+				continue
+			}
+			distribution := newDistribution(dp.Count(), dp.Sum(), dp.BucketCounts(), dp.ExplicitBounds())
+			exemplars := dp.Exemplars()
+			for e := 0; e < exemplars.Len(); e++ {
+				if ex := exemplars.At(e); !ex.IsNil() {
+					distribution.Exemplars = append(distribution.Exemplars,
+						mt.exemplar(i, e, ex.Value(), ex.Timestamp(), ex.FilteredLabels()))
+				}
+			}
+			add(dp.LabelsMap(), metricpb.MetricDescriptor_CUMULATIVE, metricpb.MetricDescriptor_DISTRIBUTION,
+				dp.StartTime(), dp.Timestamp(), distributionValue(distribution))
+		}
+	}
+
+	return timeSeries
+}
+
+// descriptor returns the descriptor of the metric, with the kind and labels of one of its time series.
+func (mt *metricTranslator) descriptor(metric pdata.Metric, ts *monitoringpb.TimeSeries) *metricpb.MetricDescriptor {
+	descriptor := &metricpb.MetricDescriptor{
+		Name:        fmt.Sprintf("projects/%s/metricDescriptors/%s", mt.projectID, mt.metricType),
+		Type:        mt.metricType,
+		MetricKind:  ts.MetricKind,
+		ValueType:   ts.ValueType,
+		Unit:        metric.Unit(),
+		Description: metric.Description(),
+		DisplayName: metric.Name(),
+	}
+	for key := range ts.Metric.Labels {
+		descriptor.Labels = append(descriptor.Labels, &labelpb.LabelDescriptor{
+			Key:       key,
+			ValueType: labelpb.LabelDescriptor_STRING,
+		})
+	}
+	return descriptor
+}
+
+// exemplar translates the exemplar of the given data point. Its filtered labels are attached as dropped
+// labels and its span, if any, as a span context so that it links to Cloud Trace.
+func (mt *metricTranslator) exemplar(dataPoint, exemplar int, value float64, timestamp pdata.TimestampUnixNano, filteredLabels pdata.StringMap) *distributionpb.Distribution_Exemplar {
+	result := &distributionpb.Distribution_Exemplar{
+		Value:     value,
+		Timestamp: toTimestamp(timestamp),
+	}
+
+	pos := mt.position
+	pos.dataPoint, pos.exemplar = dataPoint, exemplar
+	if span, ok := mt.spans[pos]; ok {
+		spanContext := &monitoringpb.SpanContext{
+			SpanName: fmt.Sprintf("projects/%s/traces/%s/spans/%s", mt.projectID, span.traceID, span.spanID),
+		}
+		if attachment, err := anypb.New(spanContext); err == nil {
+			result.Attachments = append(result.Attachments, attachment)
+		}
+	}
+
+	if filteredLabels.Len() > 0 {
+		droppedLabels := &monitoringpb.DroppedLabels{Label: make(map[string]string, filteredLabels.Len())}
+		filteredLabels.ForEach(func(k string, v pdata.StringValue) { droppedLabels.Label[k] = v.Value() })
+		if attachment, err := anypb.New(droppedLabels); err == nil {
+			result.Attachments = append(result.Attachments, attachment)
+		}
+	}
+
+	return result
+}
+
+// exemplarPosition locates an exemplar within pdata.Metrics
+type exemplarPosition struct {
+	resource, library, metric, dataPoint, exemplar int
+}
+
+type exemplarSpan struct {
+	traceID, spanID string
+}
+
+// exemplarSpans holds the span of the histogram exemplars recorded in a sampled trace. pdata doesn't
+// expose the trace and span ids of exemplars, so they are read from the underlying OTLP messages.
+type exemplarSpans map[exemplarPosition]exemplarSpan
+
+func newExemplarSpans(md pdata.Metrics) exemplarSpans {
+	spans := make(exemplarSpans)
+	add := func(pos exemplarPosition, traceID, spanID string) {
+		if traceID != "" && spanID != "" {
+			spans[pos] = exemplarSpan{traceID: traceID, spanID: spanID}
+		}
+	}
+
+	for i, rm := range pdata.MetricsToOtlp(md) {
+		if rm == nil {
+			continue
+		}
+		for j, ilm := range rm.InstrumentationLibraryMetrics {
+			if ilm == nil {
+				continue
+			}
+			for k, metric := range ilm.Metrics {
+				if histogram := metric.GetIntHistogram(); histogram != nil {
+					for l, dp := range histogram.DataPoints {
+						if dp == nil {
+							continue
+						}
+						for e, ex := range dp.Exemplars {
+							if ex != nil {
+								add(exemplarPosition{i, j, k, l, e}, ex.TraceId.HexString(), ex.SpanId.HexString())
+							}
+						}
+					}
+				}
+				if histogram := metric.GetDoubleHistogram(); histogram != nil {
+					for l, dp := range histogram.DataPoints {
+						if dp == nil {
+							continue
+						}
+						for e, ex := range dp.Exemplars {
+							if ex != nil {
+								add(exemplarPosition{i, j, k, l, e}, ex.TraceId.HexString(), ex.SpanId.HexString())
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return spans
+}
+
+// newDistribution translates an explicit bucket histogram to a distribution. Histograms without valid
+// bounds are translated to a distribution made of a single bucket.
+func newDistribution(count uint64, sum float64, bucketCounts []uint64, explicitBounds []float64) *distributionpb.Distribution {
+	distribution := &distributionpb.Distribution{
+		Count: int64(count),
+	}
+	if count > 0 {
+		distribution.Mean = sum / float64(count)
+	}
+
+	if len(explicitBounds) == 0 || len(bucketCounts) != len(explicitBounds)+1 {
+		explicitBounds = nil
+		bucketCounts = []uint64{count}
+	}
+	distribution.BucketOptions = &distributionpb.Distribution_BucketOptions{
+		Options: &distributionpb.Distribution_BucketOptions_ExplicitBuckets{
+			ExplicitBuckets: &distributionpb.Distribution_BucketOptions_Explicit{Bounds: explicitBounds},
+		},
+	}
+	distribution.BucketCounts = make([]int64, len(bucketCounts))
+	for i, bucketCount := range bucketCounts {
+		distribution.BucketCounts[i] = int64(bucketCount)
+	}
+	return distribution
+}
+
+// sumMetricKind returns the kind of sums. Cloud Monitoring doesn't support delta custom metrics, so
+// monotonic sums are written as cumulative metrics using the start time of their points, non monotonic
+// sums as gauges.
+func sumMetricKind(monotonic bool) metricpb.MetricDescriptor_MetricKind {
+	if monotonic {
+		return metricpb.MetricDescriptor_CUMULATIVE
+	}
+	return metricpb.MetricDescriptor_GAUGE
+}
+
+// timeInterval returns the interval of a point. Gauges are instantaneous, cumulative points keep the
+// start time reported by their source so that resets are only detected when the source restarts.
+// Cloud Monitoring requires the start time of cumulative points to be before their end time, points
+// without a valid one get the start time of the first point of their series.
+func (mt *metricTranslator) timeInterval(kind metricpb.MetricDescriptor_MetricKind, labels map[string]string, start, end pdata.TimestampUnixNano) *monitoringpb.TimeInterval {
+	interval := &monitoringpb.TimeInterval{EndTime: toTimestamp(end)}
+	if kind != metricpb.MetricDescriptor_CUMULATIVE {
+		return interval
+	}
+	if start == 0 || start >= end {
+		start = mt.startTimes.get(seriesKey(mt.metricType, mt.resource, labels), end)
+	}
+	interval.StartTime = toTimestamp(start)
+	return interval
+}
+
+// startTimeExpiry is how long the start time of a series that is no longer written is kept.
+const startTimeExpiry = time.Hour
+
+// startTimeCache holds the start time of the cumulative series whose points have no start time.
+// It is set when the first point of a series is seen, 1ms before its end time. Series that haven't
+// been seen for startTimeExpiry are removed, they get a new start time if they come back.
+type startTimeCache struct {
+	mu        sync.Mutex
+	times     map[string]*startTimeEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type startTimeEntry struct {
+	start    pdata.TimestampUnixNano
+	lastSeen time.Time
+}
+
+func newStartTimeCache() *startTimeCache {
+	return &startTimeCache{times: make(map[string]*startTimeEntry), now: time.Now}
+}
+
+// get returns the start time of the series for a point ending at end. Points older than the first
+// point of the series get a start time of their own.
+func (c *startTimeCache) get(series string, end pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= startTimeExpiry {
+		c.evict(now.Add(-startTimeExpiry))
+		c.lastSweep = now
+	}
+
+	if entry, ok := c.times[series]; ok {
+		entry.lastSeen = now
+		if entry.start < end {
+			return entry.start
+		}
+		return end - pdata.TimestampUnixNano(time.Millisecond)
+	}
+	start := end - pdata.TimestampUnixNano(time.Millisecond)
+	c.times[series] = &startTimeEntry{start: start, lastSeen: now}
+	return start
+}
+
+// evict removes the series last seen before the given time.
+func (c *startTimeCache) evict(before time.Time) {
+	for series, entry := range c.times {
+		if entry.lastSeen.Before(before) {
+			delete(c.times, series)
+		}
+	}
+}
+
+// seriesKey identifies a time series by its metric type, resource and labels.
+func seriesKey(metricType string, resource *monitoredrespb.MonitoredResource, labels map[string]string) string {
+	var b strings.Builder
+	b.WriteString(metricType)
+	b.WriteByte(0)
+	if resource != nil {
+		b.WriteString(resource.Type)
+		writeSortedLabels(&b, resource.Labels)
+	}
+	b.WriteByte(0)
+	writeSortedLabels(&b, labels)
+	return b.String()
+}
+
+func writeSortedLabels(b *strings.Builder, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteByte(0)
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(labels[k])
+	}
+}
+
+func toTimestamp(t pdata.TimestampUnixNano) *timestamppb.Timestamp {
+	return timestamppb.New(time.Unix(0, int64(t)))
+}
+
+func int64Value(v int64) *monitoringpb.TypedValue {
+	return &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_Int64Value{Int64Value: v}}
+}
+
+func doubleValue(v float64) *monitoringpb.TypedValue {
+	return &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_DoubleValue{DoubleValue: v}}
+}
+
+func distributionValue(v *distributionpb.Distribution) *monitoringpb.TypedValue {
+	return &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_DistributionValue{DistributionValue: v}}
+}
+
+// metricType returns the type of a metric, consistent with the OpenCensus exporter: the prefix is
+// prepended to the name, and metrics without a known domain are written as custom metrics.
+func metricType(prefix, name string) string {
+	if prefix != "" {
+		name = path.Join(prefix, name)
+	}
+	for _, domain := range knownDomains {
+		if strings.Contains(name, domain) {
+			return name
+		}
+	}
+	return path.Join(defaultDomain, name)
+}
+
+func isCustomMetric(metricType string) bool {
+	for _, prefix := range customMetricPrefixes {
+		if strings.HasPrefix(metricType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeLabels converts labels to metric labels, whose keys may only contain ASCII letters, digits
+// and underscores and must start with a letter.
+func sanitizeLabels(labels pdata.StringMap) map[string]string {
+	if labels.Len() == 0 {
+		return nil
+	}
+	result := make(map[string]string, labels.Len())
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		result[sanitizeLabelKey(k)] = v.Value()
+	})
+	return result
+}
+
+func sanitizeLabelKey(key string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if isASCIILetter(r) || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+	if sanitized == "" || !isASCIILetter(rune(sanitized[0])) {
+		sanitized = "key_" + sanitized
+	}
+	return sanitized
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// batchTimeSeries splits time series in batches accepted by CreateTimeSeries: at most
+// maxTimeSeriesPerRequest time series, and no more than one point per time series. The batches are
+// grouped in rounds: the batches of a round hold distinct time series and can be written
+// concurrently, while a round must be written after the previous one since Cloud Monitoring rejects
+// points older than the last point written to their series.
+func batchTimeSeries(timeSeries []*monitoringpb.TimeSeries) [][][]*monitoringpb.TimeSeries {
+	var rounds [][][]*monitoringpb.TimeSeries
+	var round [][]*monitoringpb.TimeSeries
+	var batch []*monitoringpb.TimeSeries
+	seen := make(map[string]bool)
+	for _, ts := range timeSeries {
+		// fmt prints maps sorted by key, so equal label sets have the same key
+		key := fmt.Sprint(ts.Metric.Type, ts.Metric.Labels, ts.Resource.Type, ts.Resource.Labels)
+		if seen[key] {
+			rounds = append(rounds, append(round, batch))
+			round, batch = nil, nil
+			seen = make(map[string]bool)
+		} else if len(batch) == maxTimeSeriesPerRequest {
+			round = append(round, batch)
+			batch = nil
+		}
+		batch = append(batch, ts)
+		seen[key] = true
+	}
+	if len(batch) > 0 {
+		rounds = append(rounds, append(round, batch))
+	}
+	return rounds
+}
@@ -0,0 +1,263 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+	distributionpb "google.golang.org/genproto/googleapis/api/distribution"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestMetricsExporter() *metricsExporter {
+	return &metricsExporter{projectID: "my-project", descriptors: make(map[string]*descriptorState), startTimes: newStartTimeCache(), logger: zap.NewNop()}
+}
+
+func newTestMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+	for _, metric := range metrics {
+		md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().Append(metric)
+	}
+	return md
+}
+
+func TestMetricsToTimeSeriesSums(t *testing.T) {
+	start := time.Unix(1000, 0)
+	end := time.Unix(1060, 0)
+
+	counter := pdata.NewMetric()
+	counter.InitEmpty()
+	counter.SetName("requests")
+	counter.SetUnit("1")
+	counter.SetDescription("Number of requests")
+	counter.SetDataType(pdata.MetricDataTypeIntSum)
+	counter.IntSum().InitEmpty()
+	counter.IntSum().SetIsMonotonic(true)
+	counter.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	counter.IntSum().DataPoints().Resize(1)
+	dp := counter.IntSum().DataPoints().At(0)
+	dp.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+	dp.SetTimestamp(pdata.TimestampUnixNano(end.UnixNano()))
+	dp.SetValue(42)
+	dp.LabelsMap().Insert("http.method", "GET")
+
+	upDown := pdata.NewMetric()
+	upDown.InitEmpty()
+	upDown.SetName("queue_size")
+	upDown.SetDataType(pdata.MetricDataTypeDoubleSum)
+	upDown.DoubleSum().InitEmpty()
+	upDown.DoubleSum().DataPoints().Resize(1)
+	upDown.DoubleSum().DataPoints().At(0).SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+	upDown.DoubleSum().DataPoints().At(0).SetTimestamp(pdata.TimestampUnixNano(end.UnixNano()))
+	upDown.DoubleSum().DataPoints().At(0).SetValue(1.5)
+
+	timeSeries, descriptors := newTestMetricsExporter().metricsToTimeSeries(newTestMetrics(counter, upDown))
+	require.Len(t, timeSeries, 2)
+
+	assert.Equal(t, "custom.googleapis.com/opencensus/requests", timeSeries[0].Metric.Type)
+	assert.Equal(t, map[string]string{"http_method": "GET"}, timeSeries[0].Metric.Labels)
+	assert.Equal(t, &monitoredrespb.MonitoredResource{Type: "global", Labels: map[string]string{"project_id": "my-project"}}, timeSeries[0].Resource)
+	assert.Equal(t, metricpb.MetricDescriptor_CUMULATIVE, timeSeries[0].MetricKind)
+	assert.Equal(t, metricpb.MetricDescriptor_INT64, timeSeries[0].ValueType)
+	require.Len(t, timeSeries[0].Points, 1)
+	assert.Equal(t, timestamppb.New(start), timeSeries[0].Points[0].Interval.StartTime)
+	assert.Equal(t, timestamppb.New(end), timeSeries[0].Points[0].Interval.EndTime)
+	assert.Equal(t, int64(42), timeSeries[0].Points[0].Value.GetInt64Value())
+
+	assert.Equal(t, metricpb.MetricDescriptor_GAUGE, timeSeries[1].MetricKind)
+	assert.Equal(t, metricpb.MetricDescriptor_DOUBLE, timeSeries[1].ValueType)
+	assert.Nil(t, timeSeries[1].Points[0].Interval.StartTime)
+	assert.Equal(t, 1.5, timeSeries[1].Points[0].Value.GetDoubleValue())
+
+	require.Len(t, descriptors, 2)
+	assert.Equal(t, "projects/my-project/metricDescriptors/custom.googleapis.com/opencensus/requests", descriptors[0].Name)
+	assert.Equal(t, "requests", descriptors[0].DisplayName)
+	assert.Equal(t, "1", descriptors[0].Unit)
+	assert.Equal(t, "Number of requests", descriptors[0].Description)
+	assert.Equal(t, metricpb.MetricDescriptor_CUMULATIVE, descriptors[0].MetricKind)
+	require.Len(t, descriptors[0].Labels, 1)
+	assert.Equal(t, "http_method", descriptors[0].Labels[0].Key)
+}
+
+func TestMetricsToTimeSeriesHistogram(t *testing.T) {
+	end := time.Unix(1060, 0)
+
+	histogram := pdata.NewMetric()
+	histogram.InitEmpty()
+	histogram.SetName("latency")
+	histogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	histogram.DoubleHistogram().InitEmpty()
+	histogram.DoubleHistogram().DataPoints().Resize(1)
+	dp := histogram.DoubleHistogram().DataPoints().At(0)
+	dp.SetTimestamp(pdata.TimestampUnixNano(end.UnixNano()))
+	dp.SetCount(4)
+	dp.SetSum(10)
+	dp.SetExplicitBounds([]float64{1, 5})
+	dp.SetBucketCounts([]uint64{1, 2, 1})
+	dp.Exemplars().Resize(1)
+	exemplar := dp.Exemplars().At(0)
+	exemplar.SetValue(4)
+	exemplar.SetTimestamp(pdata.TimestampUnixNano(end.UnixNano()))
+	exemplar.FilteredLabels().Insert("user", "alice")
+
+	md := newTestMetrics(histogram)
+	// pdata doesn't expose the span of exemplars yet
+	otlpExemplar := pdata.MetricsToOtlp(md)[0].InstrumentationLibraryMetrics[0].Metrics[0].GetDoubleHistogram().DataPoints[0].Exemplars[0]
+	require.NoError(t, otlpExemplar.TraceId.Unmarshal([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	require.NoError(t, otlpExemplar.SpanId.Unmarshal([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	timeSeries, _ := newTestMetricsExporter().metricsToTimeSeries(md)
+	require.Len(t, timeSeries, 1)
+	assert.Equal(t, metricpb.MetricDescriptor_CUMULATIVE, timeSeries[0].MetricKind)
+	assert.Equal(t, metricpb.MetricDescriptor_DISTRIBUTION, timeSeries[0].ValueType)
+
+	interval := timeSeries[0].Points[0].Interval
+	assert.Equal(t, timestamppb.New(end.Add(-time.Millisecond)), interval.StartTime, "a missing start time must be replaced")
+
+	distribution := timeSeries[0].Points[0].Value.GetDistributionValue()
+	assert.Equal(t, int64(4), distribution.Count)
+	assert.Equal(t, 2.5, distribution.Mean)
+	assert.Equal(t, []float64{1, 5}, distribution.BucketOptions.GetExplicitBuckets().Bounds)
+	assert.Equal(t, []int64{1, 2, 1}, distribution.BucketCounts)
+
+	require.Len(t, distribution.Exemplars, 1)
+	assert.Equal(t, 4.0, distribution.Exemplars[0].Value)
+	require.Len(t, distribution.Exemplars[0].Attachments, 2)
+
+	spanContext := &monitoringpb.SpanContext{}
+	require.NoError(t, distribution.Exemplars[0].Attachments[0].UnmarshalTo(spanContext))
+	assert.Equal(t, "projects/my-project/traces/0102030405060708090a0b0c0d0e0f10/spans/0102030405060708", spanContext.SpanName)
+	droppedLabels := &monitoringpb.DroppedLabels{}
+	require.NoError(t, distribution.Exemplars[0].Attachments[1].UnmarshalTo(droppedLabels))
+	assert.Equal(t, map[string]string{"user": "alice"}, droppedLabels.Label)
+}
+
+func TestNewDistributionWithoutBounds(t *testing.T) {
+	distribution := newDistribution(3, 6, nil, nil)
+	assert.Equal(t, &distributionpb.Distribution{
+		Count: 3,
+		Mean:  2,
+		BucketOptions: &distributionpb.Distribution_BucketOptions{
+			Options: &distributionpb.Distribution_BucketOptions_ExplicitBuckets{
+				ExplicitBuckets: &distributionpb.Distribution_BucketOptions_Explicit{},
+			},
+		},
+		BucketCounts: []int64{3},
+	}, distribution)
+}
+
+func TestBatchTimeSeries(t *testing.T) {
+	newTimeSeries := func(name string) *monitoringpb.TimeSeries {
+		return &monitoringpb.TimeSeries{
+			Metric:   &metricpb.Metric{Type: name},
+			Resource: &monitoredrespb.MonitoredResource{Type: "global"},
+		}
+	}
+
+	// The same time series can't be written twice in a request, nor concurrently
+	rounds := batchTimeSeries([]*monitoringpb.TimeSeries{newTimeSeries("a"), newTimeSeries("b"), newTimeSeries("a")})
+	require.Len(t, rounds, 2)
+	require.Len(t, rounds[0], 1)
+	assert.Len(t, rounds[0][0], 2)
+	require.Len(t, rounds[1], 1)
+	assert.Len(t, rounds[1][0], 1)
+
+	var timeSeries []*monitoringpb.TimeSeries
+	for i := 0; i < maxTimeSeriesPerRequest+1; i++ {
+		timeSeries = append(timeSeries, newTimeSeries(string(rune('a'+i%26))+string(rune('a'+i/26))))
+	}
+	rounds = batchTimeSeries(timeSeries)
+	require.Len(t, rounds, 1)
+	require.Len(t, rounds[0], 2)
+	assert.Len(t, rounds[0][0], maxTimeSeriesPerRequest)
+	assert.Len(t, rounds[0][1], 1)
+}
+
+func TestStartTimeCacheEviction(t *testing.T) {
+	now := time.Now()
+	cache := newStartTimeCache()
+	cache.now = func() time.Time { return now }
+	end := pdata.TimestampUnixNano(now.UnixNano())
+	start := end - pdata.TimestampUnixNano(time.Millisecond)
+
+	assert.Equal(t, start, cache.get("a", end))
+	assert.Equal(t, start, cache.get("b", end))
+
+	// "a" is written again before it expires, "b" isn't.
+	now = now.Add(startTimeExpiry / 2)
+	assert.Equal(t, start, cache.get("a", end+1))
+	now = now.Add(startTimeExpiry/2 + time.Second)
+	assert.Equal(t, start, cache.get("a", end+2))
+	assert.Len(t, cache.times, 1)
+
+	// "b" gets a new start time.
+	later := end + pdata.TimestampUnixNano(time.Hour)
+	assert.Equal(t, later-pdata.TimestampUnixNano(time.Millisecond), cache.get("b", later))
+}
+
+func TestMetricType(t *testing.T) {
+	assert.Equal(t, "custom.googleapis.com/opencensus/name", metricType("", "name"))
+	assert.Equal(t, "custom.googleapis.com/opencensus/prefix/name", metricType("prefix", "name"))
+	assert.Equal(t, "external.googleapis.com/prometheus/name", metricType("external.googleapis.com/prometheus", "name"))
+	assert.Equal(t, "kubernetes.io/container/cpu", metricType("", "kubernetes.io/container/cpu"))
+	assert.False(t, isCustomMetric("kubernetes.io/container/cpu"))
+	assert.True(t, isCustomMetric("external.googleapis.com/prometheus/name"))
+}
+
+func TestSanitizeLabelKey(t *testing.T) {
+	assert.Equal(t, "http_method", sanitizeLabelKey("http.method"))
+	assert.Equal(t, "key_1st", sanitizeLabelKey("1st"))
+	assert.Equal(t, "key__private", sanitizeLabelKey("_private"))
+}
+
+func TestMetricsToTimeSeriesMissingStartTime(t *testing.T) {
+	me := newTestMetricsExporter()
+	newCounter := func(end time.Time, method string) pdata.Metric {
+		counter := pdata.NewMetric()
+		counter.InitEmpty()
+		counter.SetName("requests")
+		counter.SetDataType(pdata.MetricDataTypeIntSum)
+		counter.IntSum().InitEmpty()
+		counter.IntSum().SetIsMonotonic(true)
+		counter.IntSum().DataPoints().Resize(1)
+		dp := counter.IntSum().DataPoints().At(0)
+		dp.SetTimestamp(pdata.TimestampUnixNano(end.UnixNano()))
+		dp.LabelsMap().Insert("http_method", method)
+		return counter
+	}
+
+	first := time.Unix(1000, 0)
+	timeSeries, _ := me.metricsToTimeSeries(newTestMetrics(newCounter(first, "GET")))
+	require.Len(t, timeSeries, 1)
+	start := timestamppb.New(first.Add(-time.Millisecond))
+	assert.Equal(t, start, timeSeries[0].Points[0].Interval.StartTime)
+
+	// Later points of the series keep the start time of the first one, other series get their own.
+	second := first.Add(time.Minute)
+	timeSeries, _ = me.metricsToTimeSeries(newTestMetrics(newCounter(second, "GET"), newCounter(second, "POST")))
+	require.Len(t, timeSeries, 2)
+	assert.Equal(t, start, timeSeries[0].Points[0].Interval.StartTime)
+	assert.Equal(t, timestamppb.New(second.Add(-time.Millisecond)), timeSeries[1].Points[0].Interval.StartTime)
+}
@@ -17,6 +17,8 @@ package stackdriverexporter
 import (
 	"contrib.go.opencensus.io/exporter/stackdriver"
 	"go.opencensus.io/resource"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
 	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
 )
 
@@ -49,6 +51,28 @@ func (mr *resourceMapper) mapResource(res *resource.Resource) *monitoredrespb.Mo
 	return stackdriver.DefaultMapResource(res)
 }
 
+// mapPdataResource maps the resource to a monitored resource of the given project. The resource is
+// converted to OpenCensus first, so that its type is inferred the same way for all signals.
+func (mr *resourceMapper) mapPdataResource(res pdata.Resource, projectID string) *monitoredrespb.MonitoredResource {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	res.CopyTo(md.ResourceMetrics().At(0).Resource())
+	ocRes := &resource.Resource{}
+	if mds := internaldata.MetricsToOC(md); len(mds) == 1 && mds[0].Resource != nil {
+		ocRes.Type = mds[0].Resource.Type
+		ocRes.Labels = mds[0].Resource.Labels
+	}
+
+	result := mr.mapResource(ocRes)
+	if _, ok := result.Labels["project_id"]; !ok {
+		if result.Labels == nil {
+			result.Labels = make(map[string]string, 1)
+		}
+		result.Labels["project_id"] = projectID
+	}
+	return result
+}
+
 // transformLabels transforms labels according to the configured mappings.
 // Returns true if all required labels in match are found.
 func transformLabels(labelMappings []LabelMapping, input map[string]string) (map[string]string, bool) {
@@ -18,34 +18,73 @@ package stackdriverexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
-	"contrib.go.opencensus.io/exporter/stackdriver"
 	cloudtrace "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	traceexport "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.uber.org/zap"
 	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+	gtransport "google.golang.org/api/transport/grpc"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
 	"google.golang.org/grpc"
 )
 
-const name = "stackdriver"
+const (
+	name = "stackdriver"
+
+	defaultMonitoringEndpoint = "monitoring.googleapis.com:443"
+	monitoringWriteScope      = "https://www.googleapis.com/auth/monitoring.write"
+)
 
 // traceExporter is a wrapper struct of OT cloud trace exporter
 type traceExporter struct {
 	texporter *cloudtrace.Exporter
 }
 
-// metricsExporter is a wrapper struct of OC stackdriver exporter
+// metricsExporter writes pdata metrics to Cloud Monitoring
 type metricsExporter struct {
-	mexporter *stackdriver.Exporter
+	client                     monitoringpb.MetricServiceClient
+	conn                       *grpc.ClientConn
+	projectID                  string
+	prefix                     string
+	skipCreateMetricDescriptor bool
+	mapper                     resourceMapper
+	logger                     *zap.Logger
+	// numWorkers is the maximum number of CreateTimeSeries requests sent concurrently.
+	numWorkers int
+
+	// descriptors holds the state of the creation of the metric descriptors, by type
+	descriptorsMu sync.Mutex
+	descriptors   map[string]*descriptorState
+
+	startTimes *startTimeCache
 }
 
+// descriptorState tracks the creation of a metric descriptor. A descriptor that couldn't be
+// created is retried with an exponential backoff rather than on every push.
+type descriptorState struct {
+	created bool
+	// nextAttempt is the earliest time of the next attempt to create the descriptor, it also
+	// keeps concurrent pushes from creating the same descriptor.
+	nextAttempt time.Time
+	backoff     time.Duration
+}
+
+const (
+	initialDescriptorBackoff = 10 * time.Second
+	maxDescriptorBackoff     = 30 * time.Minute
+)
+
 func (*traceExporter) Name() string {
 	return name
 }
@@ -60,9 +99,7 @@ func (te *traceExporter) Shutdown(context.Context) error {
 }
 
 func (me *metricsExporter) Shutdown(context.Context) error {
-	me.mexporter.Flush()
-	me.mexporter.StopMetricsExporter()
-	return nil
+	return me.conn.Close()
 }
 
 func generateClientOptions(cfg *Config, version string) ([]option.ClientOption, error) {
@@ -93,6 +130,38 @@ func generateClientOptions(cfg *Config, version string) ([]option.ClientOption,
 	return copts, nil
 }
 
+// dialGoogleAPI opens a connection to a Google Cloud API, and returns it along with the project to
+// write to. The project is detected from the credentials when it is not configured.
+func dialGoogleAPI(cfg *Config, version, defaultEndpoint, scope string) (*grpc.ClientConn, string, error) {
+	copts, err := generateClientOptions(cfg, version)
+	if err != nil {
+		return nil, "", err
+	}
+	// Options given last take precedence, so the defaults can be overridden by the configuration
+	copts = append([]option.ClientOption{
+		option.WithEndpoint(defaultEndpoint),
+		option.WithScopes(scope),
+	}, copts...)
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		creds, err := transport.Creds(context.Background(), copts...)
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot find credentials: %w", err)
+		}
+		if creds.ProjectID == "" {
+			return nil, "", errors.New("cannot determine the project, set it with the project option")
+		}
+		projectID = creds.ProjectID
+	}
+
+	conn, err := gtransport.Dial(context.Background(), copts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return conn, projectID, nil
+}
+
 func newStackdriverTraceExporter(cfg *Config, version string) (component.TraceExporter, error) {
 	topts := []cloudtrace.Option{
 		cloudtrace.WithProjectID(cfg.ProjectID),
@@ -121,78 +190,135 @@ func newStackdriverTraceExporter(cfg *Config, version string) (component.TraceEx
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}))
 }
 
-func newStackdriverMetricsExporter(cfg *Config, version string) (component.MetricsExporter, error) {
-	// TODO:  For each ProjectID, create a different exporter
-	// or at least a unique Stackdriver client per ProjectID.
-	options := stackdriver.Options{
-		// If the project ID is an empty string, it will be set by default based on
-		// the project this is running on in GCP.
-		ProjectID: cfg.ProjectID,
-
-		MetricPrefix: cfg.Prefix,
-
-		// Set DefaultMonitoringLabels to an empty map to avoid getting the "opencensus_task" label
-		DefaultMonitoringLabels: &stackdriver.Labels{},
-
-		Timeout: cfg.Timeout,
-	}
-
-	copts, err := generateClientOptions(cfg, version)
+func newStackdriverMetricsExporter(cfg *Config, version string, logger *zap.Logger) (component.MetricsExporter, error) {
+	conn, projectID, err := dialGoogleAPI(cfg, version, defaultMonitoringEndpoint, monitoringWriteScope)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot configure Stackdriver metric exporter: %w", err)
 	}
-	options.TraceClientOptions = copts
-	options.MonitoringClientOptions = copts
 
-	if cfg.NumOfWorkers > 0 {
-		options.NumberOfWorkers = cfg.NumOfWorkers
+	mExp := &metricsExporter{
+		client:                     monitoringpb.NewMetricServiceClient(conn),
+		conn:                       conn,
+		projectID:                  projectID,
+		prefix:                     cfg.Prefix,
+		skipCreateMetricDescriptor: cfg.SkipCreateMetricDescriptor,
+		mapper:                     resourceMapper{mappings: cfg.ResourceMappings},
+		logger:                     logger,
+		numWorkers:                 cfg.NumOfWorkers,
+		descriptors:                make(map[string]*descriptorState),
+		startTimes:                 newStartTimeCache(),
 	}
-	if cfg.SkipCreateMetricDescriptor {
-		options.SkipCMD = true
-	}
-	if len(cfg.ResourceMappings) > 0 {
-		rm := resourceMapper{
-			mappings: cfg.ResourceMappings,
-		}
-		options.MapResource = rm.mapResource
-	}
-
-	sde, serr := stackdriver.NewExporter(options)
-	if serr != nil {
-		return nil, fmt.Errorf("cannot configure Stackdriver metric exporter: %w", serr)
-	}
-	mExp := &metricsExporter{mexporter: sde}
 
 	return exporterhelper.NewMetricsExporter(
 		cfg,
 		mExp.pushMetrics,
 		exporterhelper.WithShutdown(mExp.Shutdown),
-		// Disable exporterhelper Timeout, since we are using a custom mechanism
-		// within exporter itself
-		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}))
+		exporterhelper.WithTimeout(cfg.TimeoutSettings))
 }
 
-// pushMetrics calls StackdriverExporter.PushMetricsProto on each element of the given metrics
+// pushMetrics translates the given metrics to time series, creates the descriptors of the metrics
+// seen for the first time, and writes the time series in batches.
 func (me *metricsExporter) pushMetrics(ctx context.Context, m pdata.Metrics) (int, error) {
-	var errors []error
-	var totalDropped int
-
-	mds := internaldata.MetricsToOC(m)
-	for _, md := range mds {
-		points := numPoints(md)
-		dropped, err := me.mexporter.PushMetricsProto(ctx, md.Node, md.Resource, md.Metrics)
-		recordPointCount(ctx, points-dropped, dropped, err)
-		totalDropped += dropped
-		if err != nil {
-			errors = append(errors, err)
+	var errs []error
+
+	timeSeries, descriptors := me.metricsToTimeSeries(m)
+
+	if !me.skipCreateMetricDescriptor {
+		for _, descriptor := range descriptors {
+			// The time series are written even if the descriptor can't be created,
+			// Cloud Monitoring infers the descriptors of custom metrics from their first point.
+			// The failure isn't returned so that the written time series aren't retried.
+			if err := me.createMetricDescriptor(ctx, descriptor); err != nil {
+				me.logger.Warn("Failed to create metric descriptor", zap.Error(err))
+			}
 		}
 	}
 
-	if len(errors) > 0 {
-		return totalDropped, componenterror.CombineErrors(errors)
+	dropped := 0
+	for _, round := range batchTimeSeries(timeSeries) {
+		roundDropped, roundErrs := me.createTimeSeries(ctx, round)
+		dropped += roundDropped
+		errs = append(errs, roundErrs...)
 	}
 
-	return totalDropped, nil
+	return dropped, componenterror.CombineErrors(errs)
+}
+
+// createTimeSeries writes the batches, with up to numWorkers requests in flight, and returns the
+// number of time series that couldn't be written.
+func (me *metricsExporter) createTimeSeries(ctx context.Context, batches [][]*monitoringpb.TimeSeries) (int, []error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		dropped int
+		errs    []error
+	)
+	numWorkers := me.numWorkers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	workers := make(chan struct{}, numWorkers)
+	for _, batch := range batches {
+		batch := batch
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			_, err := me.client.CreateTimeSeries(ctx, &monitoringpb.CreateTimeSeriesRequest{
+				Name:       "projects/" + me.projectID,
+				TimeSeries: batch,
+			})
+			if err != nil {
+				recordPointCount(ctx, 0, len(batch), err)
+				mu.Lock()
+				dropped += len(batch)
+				errs = append(errs, err)
+				mu.Unlock()
+				return
+			}
+			recordPointCount(ctx, len(batch), 0, nil)
+		}()
+	}
+	wg.Wait()
+	return dropped, errs
+}
+
+// createMetricDescriptor creates the descriptor unless it was already created by this exporter, or
+// its creation failed recently. The lock isn't held during the request.
+func (me *metricsExporter) createMetricDescriptor(ctx context.Context, descriptor *metricpb.MetricDescriptor) error {
+	me.descriptorsMu.Lock()
+	state, ok := me.descriptors[descriptor.Type]
+	if !ok {
+		state = &descriptorState{backoff: initialDescriptorBackoff}
+		me.descriptors[descriptor.Type] = state
+	}
+	now := time.Now()
+	if state.created || now.Before(state.nextAttempt) {
+		me.descriptorsMu.Unlock()
+		return nil
+	}
+	state.nextAttempt = now.Add(state.backoff)
+	me.descriptorsMu.Unlock()
+
+	_, err := me.client.CreateMetricDescriptor(ctx, &monitoringpb.CreateMetricDescriptorRequest{
+		Name:             "projects/" + me.projectID,
+		MetricDescriptor: descriptor,
+	})
+
+	me.descriptorsMu.Lock()
+	defer me.descriptorsMu.Unlock()
+	if err != nil {
+		state.nextAttempt = time.Now().Add(state.backoff)
+		if state.backoff *= 2; state.backoff > maxDescriptorBackoff {
+			state.backoff = maxDescriptorBackoff
+		}
+		return fmt.Errorf("cannot create metric descriptor %q: %w", descriptor.Type, err)
+	}
+	state.created = true
+	return nil
 }
 
 // pushTraces calls texporter.ExportSpan for each span in the given traces
@@ -219,14 +345,3 @@ func (te *traceExporter) pushTraces(ctx context.Context, td pdata.Traces) (int,
 
 	return numSpans - goodSpans, componenterror.CombineErrors(errs)
 }
-
-func numPoints(md consumerdata.MetricsData) int {
-	numPoints := 0
-	for _, metric := range md.Metrics {
-		tss := metric.GetTimeseries()
-		for _, ts := range tss {
-			numPoints += len(ts.GetPoints())
-		}
-	}
-	return numPoints
-}
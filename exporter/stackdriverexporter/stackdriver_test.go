@@ -16,8 +16,11 @@ package stackdriverexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +32,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/testutil/metricstestutil"
 	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
 	"google.golang.org/api/option"
 	cloudmetricpb "google.golang.org/genproto/googleapis/api/metric"
 	cloudtracepb "google.golang.org/genproto/googleapis/devtools/cloudtrace/v2"
@@ -162,7 +166,7 @@ func TestStackdriverMetricExport(t *testing.T) {
 		GetClientOptions: func() []option.ClientOption {
 			return clientOptions
 		},
-	}, "v0.0.1")
+	}, "v0.0.1", zap.NewNop())
 	require.NoError(t, err)
 	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
 
@@ -199,3 +203,102 @@ func TestStackdriverMetricExport(t *testing.T) {
 	require.Len(t, tr.TimeSeries[0].Points, 1)
 	assert.Equal(t, float64(123), tr.TimeSeries[0].Points[0].Value.GetDoubleValue())
 }
+
+type fakeMetricServiceClient struct {
+	cloudmonitoringpb.MetricServiceClient
+
+	mu             sync.Mutex
+	descriptorReqs []*cloudmonitoringpb.CreateMetricDescriptorRequest
+	timeSeriesReqs []*cloudmonitoringpb.CreateTimeSeriesRequest
+	descriptorErr  error
+}
+
+func (c *fakeMetricServiceClient) CreateMetricDescriptor(_ context.Context, req *cloudmonitoringpb.CreateMetricDescriptorRequest, _ ...grpc.CallOption) (*cloudmetricpb.MetricDescriptor, error) {
+	c.descriptorReqs = append(c.descriptorReqs, req)
+	if c.descriptorErr != nil {
+		return nil, c.descriptorErr
+	}
+	return req.MetricDescriptor, nil
+}
+
+func (c *fakeMetricServiceClient) CreateTimeSeries(_ context.Context, req *cloudmonitoringpb.CreateTimeSeriesRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeSeriesReqs = append(c.timeSeriesReqs, req)
+	return &emptypb.Empty{}, nil
+}
+
+func TestStackdriverMetricExportCachesDescriptors(t *testing.T) {
+	client := &fakeMetricServiceClient{}
+	me := &metricsExporter{client: client, projectID: "idk", descriptors: make(map[string]*descriptorState), startTimes: newStartTimeCache(), logger: zap.NewNop()}
+
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{
+			metricstestutil.Gauge(
+				"test_gauge",
+				[]string{"k0"},
+				metricstestutil.Timeseries(time.Now(), []string{"v0"}, metricstestutil.Double(time.Now(), 1)),
+				metricstestutil.Timeseries(time.Now(), []string{"v1"}, metricstestutil.Double(time.Now(), 2))),
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		dropped, err := me.pushMetrics(context.Background(), internaldata.OCToMetrics(md))
+		require.NoError(t, err)
+		assert.Equal(t, 0, dropped)
+	}
+
+	require.Len(t, client.descriptorReqs, 1)
+	assert.Equal(t, "projects/idk", client.descriptorReqs[0].Name)
+	require.Len(t, client.timeSeriesReqs, 2)
+	assert.Len(t, client.timeSeriesReqs[0].TimeSeries, 2)
+}
+
+func TestStackdriverMetricExportWorkers(t *testing.T) {
+	client := &fakeMetricServiceClient{}
+	me := &metricsExporter{client: client, projectID: "idk", skipCreateMetricDescriptor: true, descriptors: make(map[string]*descriptorState), startTimes: newStartTimeCache(), logger: zap.NewNop(), numWorkers: 3}
+
+	var timeSeries []*metricspb.TimeSeries
+	for i := 0; i < 2*maxTimeSeriesPerRequest+1; i++ {
+		timeSeries = append(timeSeries, metricstestutil.Timeseries(time.Now(), []string{strconv.Itoa(i)}, metricstestutil.Double(time.Now(), 1)))
+	}
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{metricstestutil.Gauge("test_gauge", []string{"k0"}, timeSeries...)},
+	}
+
+	dropped, err := me.pushMetrics(context.Background(), internaldata.OCToMetrics(md))
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, client.timeSeriesReqs, 3)
+	written := 0
+	for _, req := range client.timeSeriesReqs {
+		written += len(req.TimeSeries)
+	}
+	assert.Equal(t, 2*maxTimeSeriesPerRequest+1, written)
+}
+
+func TestStackdriverMetricExportDescriptorFailure(t *testing.T) {
+	client := &fakeMetricServiceClient{descriptorErr: errors.New("permission denied")}
+	me := &metricsExporter{client: client, projectID: "idk", descriptors: make(map[string]*descriptorState), startTimes: newStartTimeCache(), logger: zap.NewNop()}
+
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{
+			metricstestutil.Gauge(
+				"test_gauge",
+				[]string{"k0"},
+				metricstestutil.Timeseries(time.Now(), []string{"v0"}, metricstestutil.Double(time.Now(), 1))),
+		},
+	}
+
+	// The time series are written, the failure isn't reported so that they aren't retried.
+	for i := 0; i < 2; i++ {
+		dropped, err := me.pushMetrics(context.Background(), internaldata.OCToMetrics(md))
+		require.NoError(t, err)
+		assert.Equal(t, 0, dropped)
+	}
+
+	// The creation isn't attempted again before the backoff elapsed.
+	assert.Len(t, client.descriptorReqs, 1)
+	assert.Len(t, client.timeSeriesReqs, 2)
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"os"
 
+	"k8s.io/client-go/dynamic"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -136,3 +137,18 @@ func MakeClient(apiConf APIConfig) (k8s.Interface, error) {
 
 	return client, nil
 }
+
+// MakeDynamicClient creates a client for the resources unknown to the typed
+// client, like custom resources.
+func MakeDynamicClient(apiConf APIConfig) (dynamic.Interface, error) {
+	if err := apiConf.Validate(); err != nil {
+		return nil, err
+	}
+
+	authConf, err := createRestConfig(apiConf)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(authConf)
+}
@@ -15,39 +15,83 @@
 package awsecscontainermetrics
 
 import (
+	"strings"
 	"time"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 // metricDataAccumulator defines the accumulator
 type metricDataAccumulator struct {
-	md []*consumerdata.MetricsData
+	md pdata.Metrics
+
+	// previous holds the container samples of the preceding collection, keyed by docker id,
+	// and current collects the samples of this one.
+	previous map[string]containerSample
+	current  map[string]containerSample
+}
+
+func newMetricDataAccumulator(previous map[string]containerSample) *metricDataAccumulator {
+	return &metricDataAccumulator{
+		md:       pdata.NewMetrics(),
+		previous: previous,
+		current:  make(map[string]containerSample),
+	}
 }
 
 // getMetricsData generates OT Metrics data from task metadata and docker stats
-func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]ContainerStats, metadata TaskMetadata) {
+func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*ContainerStats, metadata TaskMetadata, now time.Time) {
 
 	taskMetrics := ECSMetrics{}
-	timestamp := timestampProto(time.Now())
+	timestamp := pdata.TimestampUnixNano(uint64(now.UnixNano()))
 	taskResource := taskResource(metadata)
 
+	containerStates := make(map[string]uint64)
+	taskVCPUs := float64(0)
+	taskRated := false
+
 	for _, containerMetadata := range metadata.Containers {
+		containerStates[containerState(containerMetadata.KnownStatus)]++
+		taskMetrics.Restarts = addUint(taskMetrics.Restarts, containerMetadata.RestartCount)
+
+		// Stopped containers have no stats
 		stats := containerStatsMap[containerMetadata.DockerID]
+		if stats == nil {
+			continue
+		}
+
 		containerMetrics := getContainerMetrics(stats)
-		containerMetrics.MemoryReserved = *containerMetadata.Limits.Memory
-		containerMetrics.CPUReserved = *containerMetadata.Limits.CPU
+		if containerMetadata.Limits.Memory != nil {
+			containerMetrics.MemoryReserved = *containerMetadata.Limits.Memory
+		}
+		if containerMetadata.Limits.CPU != nil {
+			containerMetrics.CPUReserved = *containerMetadata.Limits.CPU
+		}
+		containerMetrics.Restarts = containerMetadata.RestartCount
 
-		containerResource := containerResource(containerMetadata)
-		for k, v := range taskResource.Labels {
-			containerResource.Labels[k] = v
+		read := stats.Read
+		if read.IsZero() {
+			read = now
 		}
+		sample := newContainerSample(read, containerMetrics)
+		acc.current[containerMetadata.DockerID] = sample
+		if prev, ok := acc.previous[containerMetadata.DockerID]; ok {
+			if vCPUs, ok := applyRates(&containerMetrics, prev, sample); ok {
+				// Container CPU is reserved in CPU units
+				containerMetrics.CPUUtilization = cpuUtilization(vCPUs, containerMetrics.CPUReserved/CPUsInVCpu)
+				taskVCPUs += vCPUs
+				taskRated = true
+			}
+		}
+
+		containerResource := containerResource(containerMetadata)
+		taskResource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+			containerResource.Attributes().Upsert(k, v)
+		})
 
 		acc.accumulate(
 			containerResource,
-			convertToOCMetrics(ContainerPrefix, containerMetrics, nil, nil, timestamp),
+			convertToOTLPMetrics(ContainerPrefix, containerMetrics, timestamp),
 		)
 
 		aggregateTaskMetrics(&taskMetrics, containerMetrics)
@@ -65,27 +109,30 @@ func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]Co
 		taskMetrics.CPUReserved = *metadata.Limits.CPU
 	}
 
-	acc.accumulate(
-		taskResource,
-		convertToOCMetrics(TaskPrefix, taskMetrics, nil, nil, timestamp),
-	)
+	if taskRated {
+		taskMetrics.CPUUtilization = cpuUtilization(taskVCPUs, taskMetrics.CPUReserved)
+	}
+
+	metrics := convertToOTLPMetrics(TaskPrefix, taskMetrics, timestamp)
+	containerStateMetric(metrics, containerStates, timestamp)
+	acc.accumulate(taskResource, metrics)
 }
 
-func (acc *metricDataAccumulator) accumulate(
-	r *resourcepb.Resource,
-	m ...[]*metricspb.Metric,
-) {
-	var resourceMetrics []*metricspb.Metric
-	for _, metrics := range m {
-		for _, metric := range metrics {
-			if metric != nil {
-				resourceMetrics = append(resourceMetrics, metric)
-			}
-		}
-	}
+func (acc *metricDataAccumulator) accumulate(r pdata.Resource, metrics pdata.MetricSlice) {
+	rms := acc.md.ResourceMetrics()
+	rms.Resize(rms.Len() + 1)
+	rm := rms.At(rms.Len() - 1)
+	r.CopyTo(rm.Resource())
 
-	acc.md = append(acc.md, &consumerdata.MetricsData{
-		Metrics:  resourceMetrics,
-		Resource: r,
-	})
+	ilms := rm.InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics.MoveAndAppendTo(ilms.At(0).Metrics())
+}
+
+// containerState returns the lower case known status of a container, "unknown" when it isn't reported.
+func containerState(knownStatus string) string {
+	if knownStatus == "" {
+		return "unknown"
+	}
+	return strings.ToLower(knownStatus)
 }
@@ -16,9 +16,10 @@ package awsecscontainermetrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestGetMetricsData(t *testing.T) {
@@ -98,14 +99,119 @@ func TestGetMetricsData(t *testing.T) {
 		Limits: Limit{CPU: &f, Memory: &v},
 	}
 
-	cstats := make(map[string]ContainerStats)
-	cstats["001"] = containerStats
+	cstats := make(map[string]*ContainerStats)
+	cstats["001"] = &containerStats
 
-	var mds []*consumerdata.MetricsData
-	acc := metricDataAccumulator{
-		md: mds,
+	acc := newMetricDataAccumulator(nil)
+
+	acc.getMetricsData(cstats, tm, time.Now())
+	require.EqualValues(t, 2, acc.md.ResourceMetrics().Len())
+	require.Contains(t, acc.current, "001")
+}
+
+func TestGetMetricsDataRates(t *testing.T) {
+	cpu := float64(512)
+	limit := Limit{CPU: &cpu}
+	restarts := uint64(2)
+	tm := TaskMetadata{
+		Containers: []ContainerMetadata{
+			{DockerID: "001", KnownStatus: "RUNNING", Limits: limit},
+			{DockerID: "002", KnownStatus: "RUNNING", Limits: limit, RestartCount: &restarts},
+			{DockerID: "003", KnownStatus: "STOPPED", Limits: limit},
+		},
+	}
+	read := time.Date(2020, 7, 31, 6, 47, 30, 0, time.UTC)
+	cstats := map[string]*ContainerStats{
+		"001": testContainerStats(read, 1000000000, 1000),
+		"002": testContainerStats(read, 1000000000, 1000),
+	}
+
+	acc := newMetricDataAccumulator(nil)
+	acc.getMetricsData(cstats, tm, time.Now())
+	require.EqualValues(t, 3, acc.md.ResourceMetrics().Len())
+	require.NotContains(t, testMetrics(acc.md.ResourceMetrics().At(2)), TaskPrefix+AttributeCPUUtilization)
+
+	read = read.Add(10 * time.Second)
+	cstats = map[string]*ContainerStats{
+		"001": testContainerStats(read, 3500000000, 2000),
+		// restarted
+		"002": testContainerStats(read, 100, 10),
+	}
+
+	acc = newMetricDataAccumulator(acc.current)
+	acc.getMetricsData(cstats, tm, time.Now())
+	require.EqualValues(t, 3, acc.md.ResourceMetrics().Len())
+
+	container := testMetrics(acc.md.ResourceMetrics().At(0))
+	require.InDelta(t, 50, container[ContainerPrefix+AttributeCPUUtilization].DoubleGauge().DataPoints().At(0).Value(), 0.0001)
+	require.InDelta(t, 100, container[ContainerPrefix+AttributeNetworkRxBytesPerSecond].DoubleGauge().DataPoints().At(0).Value(), 0.0001)
+	require.InDelta(t, 100, container[ContainerPrefix+AttributeStorageReadBytesPerSecond].DoubleGauge().DataPoints().At(0).Value(), 0.0001)
+
+	restarted := testMetrics(acc.md.ResourceMetrics().At(1))
+	require.NotContains(t, restarted, ContainerPrefix+AttributeCPUUtilization)
+	require.NotContains(t, restarted, ContainerPrefix+AttributeNetworkRxBytesPerSecond)
+	require.EqualValues(t, 2, restarted[ContainerPrefix+AttributeRestarts].IntSum().DataPoints().At(0).Value())
+
+	task := testMetrics(acc.md.ResourceMetrics().At(2))
+	// 0.25 vCPU used out of 1 vCPU reserved by the two running containers
+	require.InDelta(t, 25, task[TaskPrefix+AttributeCPUUtilization].DoubleGauge().DataPoints().At(0).Value(), 0.0001)
+	require.InDelta(t, 100, task[TaskPrefix+AttributeNetworkRxBytesPerSecond].DoubleGauge().DataPoints().At(0).Value(), 0.0001)
+	require.EqualValues(t, 2, task[TaskPrefix+AttributeRestarts].IntSum().DataPoints().At(0).Value())
+
+	states := map[string]int64{}
+	dps := task[TaskPrefix+AttributeContainers].IntGauge().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		state, _ := dps.At(i).LabelsMap().Get(LabelContainerState)
+		states[state.Value()] = dps.At(i).Value()
 	}
+	require.Equal(t, map[string]int64{"running": 2, "stopped": 1}, states)
+}
 
-	acc.getMetricsData(cstats, tm)
-	require.Less(t, 0, len(acc.md))
+func testContainerStats(read time.Time, cpuTotal uint64, bytes uint64) *ContainerStats {
+	v := uint64(1)
+	return &ContainerStats{
+		Read: read,
+		Memory: MemoryStats{
+			Usage:    &v,
+			MaxUsage: &v,
+			Limit:    &v,
+		},
+		Disk: DiskStats{
+			IoServiceBytesRecursives: []IoServiceBytesRecursive{
+				{Op: "Read", Value: &bytes},
+				{Op: "Write", Value: &bytes},
+			},
+		},
+		Network: map[string]NetworkStats{
+			"eth0": {
+				RxBytes:   &bytes,
+				RxPackets: &v,
+				RxErrors:  &v,
+				RxDropped: &v,
+				TxBytes:   &bytes,
+				TxPackets: &v,
+				TxErrors:  &v,
+				TxDropped: &v,
+			},
+		},
+		CPU: CPUStats{
+			CPUUsage: CPUUsage{
+				TotalUsage:        &cpuTotal,
+				UsageInKernelmode: &v,
+				UsageInUserMode:   &v,
+				PerCPUUsage:       []*uint64{&v},
+			},
+			OnlineCpus:     &v,
+			SystemCPUUsage: &v,
+		},
+	}
+}
+
+func testMetrics(rm pdata.ResourceMetrics) map[string]pdata.Metric {
+	metrics := make(map[string]pdata.Metric)
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		metrics[ms.At(i).Name()] = ms.At(i)
+	}
+	return metrics
 }
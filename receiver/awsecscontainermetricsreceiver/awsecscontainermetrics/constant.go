@@ -15,20 +15,21 @@
 package awsecscontainermetrics
 
 const (
-	AttributeECSDockerName   = "ecs.docker-name"
-	AttributeECSCluster      = "ecs.cluster"
-	AttributeECSTaskARN      = "ecs.task-arn"
-	AttributeECSTaskID       = "ecs.task-id"
-	AttributeECSTaskFamily   = "ecs.task-definition-family"
-	AttributeECSTaskRevesion = "ecs.task-definition-version"
-	AttributeECSServiceName  = "ecs.service"
+	AttributeECSDockerName           = "aws.ecs.docker.name"
+	AttributeECSContainerKnownStatus = "aws.ecs.container.known_status"
+	AttributeECSCluster              = "aws.ecs.cluster.name"
+	AttributeECSTaskARN              = "aws.ecs.task.arn"
+	AttributeECSTaskID               = "aws.ecs.task.id"
+	AttributeECSTaskFamily           = "aws.ecs.task.family"
+	AttributeECSTaskRevesion         = "aws.ecs.task.revision"
+	AttributeECSTaskKnownStatus      = "aws.ecs.task.known_status"
+	AttributeECSServiceName          = "aws.ecs.service.name"
 
 	CPUsInVCpu = 1024
 	BytesInMiB = 1024 * 1024
 
-	TaskPrefix         = "ecs.task."
-	ContainerPrefix    = "container."
-	MetricResourceType = "aoc.ecs"
+	TaskPrefix      = "ecs.task."
+	ContainerPrefix = "container."
 
 	EndpointEnvKey   = "ECS_CONTAINER_METADATA_URI_V4"
 	TaskStatsPath    = "/task/stats"
@@ -48,10 +49,14 @@ const (
 	AttributeCPUOnlines         = "cpu.onlines"
 	AttributeCPUReserved        = "cpu.reserved"
 	AttributeCPUUtilized        = "cpu.utilized"
+	AttributeCPUUtilization     = "cpu.utilization"
 
 	AttributeNetworkRateRx = "network.rate.rx"
 	AttributeNetworkRateTx = "network.rate.tx"
 
+	AttributeNetworkRxBytesPerSecond = "network.io.rate.rx_bytes"
+	AttributeNetworkTxBytesPerSecond = "network.io.rate.tx_bytes"
+
 	AttributeNetworkRxBytes   = "network.io.usage.rx_bytes"
 	AttributeNetworkRxPackets = "network.io.usage.rx_packets"
 	AttributeNetworkRxErrors  = "network.io.usage.rx_errors"
@@ -64,10 +69,19 @@ const (
 	AttributeStorageRead  = "storage.read_bytes"
 	AttributeStorageWrite = "storage.write_bytes"
 
+	AttributeStorageReadBytesPerSecond  = "storage.rate.read_bytes"
+	AttributeStorageWriteBytesPerSecond = "storage.rate.write_bytes"
+
+	AttributeRestarts   = "restarts"
+	AttributeContainers = "containers"
+
+	LabelContainerState = "state"
+
 	UnitBytes       = "Bytes"
 	UnitMegaBytes   = "MB"
 	UnitNanoSecond  = "NS"
 	UnitBytesPerSec = "Bytes/Sec"
 	UnitCount       = "Count"
 	UnitVCpu        = "vCPU"
+	UnitPercent     = "Percent"
 )
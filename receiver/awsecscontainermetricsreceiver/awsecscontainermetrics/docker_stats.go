@@ -14,11 +14,16 @@
 
 package awsecscontainermetrics
 
+import "time"
+
 // ContainerStats defines the structure for container stats
 type ContainerStats struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
 
+	// Read is the time at which docker collected the stats.
+	Read time.Time `json:"read"`
+
 	Memory      MemoryStats             `json:"memory_stats,omitempty"`
 	Disk        DiskStats               `json:"blkio_stats,omitempty"`
 	Network     map[string]NetworkStats `json:"networks,omitempty"`
@@ -41,7 +46,7 @@ type DiskStats struct {
 	IoServiceBytesRecursives []IoServiceBytesRecursive `json:"io_service_bytes_recursive,omitempty"`
 }
 
-//IoServiceBytesRecursive defines the IO device stats
+// IoServiceBytesRecursive defines the IO device stats
 type IoServiceBytesRecursive struct {
 	Major *uint64 `json:"major,omitempty"`
 	Minor *uint64 `json:"minor,omitempty"`
@@ -30,6 +30,7 @@ type ECSMetrics struct {
 	NumOfCPUCores        uint64
 	CPUReserved          float64
 	CPUUtilized          float64
+	CPUUtilization       *float64
 
 	NetworkRateRxBytesPerSecond float64
 	NetworkRateTxBytesPerSecond float64
@@ -43,6 +44,14 @@ type ECSMetrics struct {
 	NetworkTxErrors  uint64
 	NetworkTxDropped uint64
 
+	NetworkRxBytesPerSecond *float64
+	NetworkTxBytesPerSecond *float64
+
 	StorageReadBytes  uint64
 	StorageWriteBytes uint64
+
+	StorageReadBytesPerSecond  *float64
+	StorageWriteBytesPerSecond *float64
+
+	Restarts *uint64
 }
@@ -21,6 +21,8 @@ type TaskMetadata struct {
 	Family   string `json:"Family,omitempty"`
 	Revision string `json:"Revision,omitempty"`
 
+	KnownStatus string `json:"KnownStatus,omitempty"`
+
 	Limits     Limit               `json:"Limits,omitempty"`
 	Containers []ContainerMetadata `json:"Containers,omitempty"`
 }
@@ -33,6 +35,8 @@ type ContainerMetadata struct {
 	Image         string            `json:"Image,omitempty"`
 	Labels        map[string]string `json:"Labels,omitempty"`
 	Limits        Limit             `json:"Limits,omitempty"`
+	KnownStatus   string            `json:"KnownStatus,omitempty"`
+	RestartCount  *uint64           `json:"RestartCount,omitempty"`
 }
 
 // Limit defines the Cpu and Memory limts
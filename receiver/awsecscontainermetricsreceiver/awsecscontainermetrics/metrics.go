@@ -15,12 +15,28 @@
 package awsecscontainermetrics
 
 import (
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
-func MetricsData(containerStatsMap map[string]ContainerStats, metadata TaskMetadata) []*consumerdata.MetricsData {
-	acc := &metricDataAccumulator{}
-	acc.getMetricsData(containerStatsMap, metadata)
+// MetricsBuilder generates OT metrics from successive task metadata and docker stats.
+// It remembers the previous sample of every container so that rates can be computed.
+type MetricsBuilder struct {
+	samples map[string]containerSample
+}
+
+// NewMetricsBuilder returns a new metrics builder
+func NewMetricsBuilder() *MetricsBuilder {
+	return &MetricsBuilder{samples: make(map[string]containerSample)}
+}
+
+// MetricsData generates OT metrics with one resource per container and one for the task.
+// Rates are only reported for the containers that were also present in the previous call.
+func (b *MetricsBuilder) MetricsData(containerStatsMap map[string]*ContainerStats, metadata TaskMetadata) pdata.Metrics {
+	acc := newMetricDataAccumulator(b.samples)
+	acc.getMetricsData(containerStatsMap, metadata, time.Now())
+	b.samples = acc.current
 
 	return acc.md
 }
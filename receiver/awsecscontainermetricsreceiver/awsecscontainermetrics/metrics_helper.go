@@ -15,7 +15,7 @@
 package awsecscontainermetrics
 
 // getContainerMetrics generate ECS Container metrics from Container stats
-func getContainerMetrics(stats ContainerStats) ECSMetrics {
+func getContainerMetrics(stats *ContainerStats) ECSMetrics {
 	memoryUtilizedInMb := (*stats.Memory.Usage - stats.Memory.Stats["cache"]) / BytesInMiB
 
 	numOfCores := (uint64)(len(stats.CPU.CPUUsage.PerCPUUsage))
@@ -42,8 +42,13 @@ func getContainerMetrics(stats ContainerStats) ECSMetrics {
 	m.SystemCPUUsage = *stats.CPU.SystemCPUUsage
 	m.CPUUtilized = cpuUtilized
 
-	m.NetworkRateRxBytesPerSecond = *stats.NetworkRate.TxBytesPerSecond
-	m.NetworkRateTxBytesPerSecond = *stats.NetworkRate.TxBytesPerSecond
+	// The rates are computed by the ECS agent and are missing until it has two samples
+	if stats.NetworkRate.RxBytesPerSecond != nil {
+		m.NetworkRateRxBytesPerSecond = *stats.NetworkRate.RxBytesPerSecond
+	}
+	if stats.NetworkRate.TxBytesPerSecond != nil {
+		m.NetworkRateTxBytesPerSecond = *stats.NetworkRate.TxBytesPerSecond
+	}
 
 	m.NetworkRxBytes = netStatArray[0]
 	m.NetworkRxPackets = netStatArray[1]
@@ -131,4 +136,36 @@ func aggregateTaskMetrics(taskMetrics *ECSMetrics, conMetrics ECSMetrics) {
 
 	taskMetrics.StorageReadBytes += conMetrics.StorageReadBytes
 	taskMetrics.StorageWriteBytes += conMetrics.StorageWriteBytes
+
+	taskMetrics.NetworkRxBytesPerSecond = addFloat(taskMetrics.NetworkRxBytesPerSecond, conMetrics.NetworkRxBytesPerSecond)
+	taskMetrics.NetworkTxBytesPerSecond = addFloat(taskMetrics.NetworkTxBytesPerSecond, conMetrics.NetworkTxBytesPerSecond)
+	taskMetrics.StorageReadBytesPerSecond = addFloat(taskMetrics.StorageReadBytesPerSecond, conMetrics.StorageReadBytesPerSecond)
+	taskMetrics.StorageWriteBytesPerSecond = addFloat(taskMetrics.StorageWriteBytesPerSecond, conMetrics.StorageWriteBytesPerSecond)
+}
+
+// addFloat adds an optional container value to an optional task value, the sum
+// being present as soon as one of the containers reported the value.
+func addFloat(sum *float64, v *float64) *float64 {
+	if v == nil {
+		return sum
+	}
+	if sum == nil {
+		s := *v
+		return &s
+	}
+	s := *sum + *v
+	return &s
+}
+
+// addUint adds an optional container value to an optional task value.
+func addUint(sum *uint64, v *uint64) *uint64 {
+	if v == nil {
+		return sum
+	}
+	if sum == nil {
+		s := *v
+		return &s
+	}
+	s := *sum + *v
+	return &s
 }
@@ -56,9 +56,10 @@ func TestGetContainerAndTaskMetrics(t *testing.T) {
 		TxDropped: &v,
 	}
 
+	tx := float64(2.0)
 	netRate := NetworkRateStats{
 		RxBytesPerSecond: &f,
-		TxBytesPerSecond: &f,
+		TxBytesPerSecond: &tx,
 	}
 
 	percpu := []*uint64{&v, &v}
@@ -86,8 +87,10 @@ func TestGetContainerAndTaskMetrics(t *testing.T) {
 		CPU:         cpuStats,
 	}
 
-	containerMetrics := getContainerMetrics(containerStats)
+	containerMetrics := getContainerMetrics(&containerStats)
 	require.NotNil(t, containerMetrics)
+	require.EqualValues(t, f, containerMetrics.NetworkRateRxBytesPerSecond)
+	require.EqualValues(t, tx, containerMetrics.NetworkRateTxBytesPerSecond)
 
 	taskMetrics := ECSMetrics{}
 	aggregateTaskMetrics(&taskMetrics, containerMetrics)
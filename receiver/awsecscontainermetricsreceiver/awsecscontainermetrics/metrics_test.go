@@ -103,9 +103,9 @@ func TestMetricData(t *testing.T) {
 		Limits: Limit{CPU: &f, Memory: &v},
 	}
 
-	cstats := make(map[string]ContainerStats)
-	cstats["001"] = containerStats
+	cstats := make(map[string]*ContainerStats)
+	cstats["001"] = &containerStats
 
-	md := MetricsData(cstats, tm)
-	require.Less(t, 0, len(md))
+	md := NewMetricsBuilder().MetricsData(cstats, tm)
+	require.EqualValues(t, 2, md.ResourceMetrics().Len())
 }
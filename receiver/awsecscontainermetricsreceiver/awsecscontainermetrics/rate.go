@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"time"
+)
+
+// containerSample holds the cumulative counters of a container at the time docker read them.
+// Two successive samples of the same container are used to compute rates.
+type containerSample struct {
+	read              time.Time
+	cpuTotalUsage     uint64
+	networkRxBytes    uint64
+	networkTxBytes    uint64
+	storageReadBytes  uint64
+	storageWriteBytes uint64
+}
+
+// newContainerSample creates a sample from the metrics of a container read at the given time.
+func newContainerSample(read time.Time, m ECSMetrics) containerSample {
+	return containerSample{
+		read:              read,
+		cpuTotalUsage:     m.CPUTotalUsage,
+		networkRxBytes:    m.NetworkRxBytes,
+		networkTxBytes:    m.NetworkTxBytes,
+		storageReadBytes:  m.StorageReadBytes,
+		storageWriteBytes: m.StorageWriteBytes,
+	}
+}
+
+// applyRates sets the rate metrics of m from the difference between the previous and the current
+// sample and returns the vCPUs used by the container over that period. Nothing is set when the
+// samples are not ordered in time or a counter went backwards, e.g. after the container restarted.
+func applyRates(m *ECSMetrics, prev, cur containerSample) (float64, bool) {
+	elapsed := cur.read.Sub(prev.read).Seconds()
+	if elapsed <= 0 ||
+		cur.cpuTotalUsage < prev.cpuTotalUsage ||
+		cur.networkRxBytes < prev.networkRxBytes ||
+		cur.networkTxBytes < prev.networkTxBytes ||
+		cur.storageReadBytes < prev.storageReadBytes ||
+		cur.storageWriteBytes < prev.storageWriteBytes {
+		return 0, false
+	}
+
+	m.NetworkRxBytesPerSecond = rate(prev.networkRxBytes, cur.networkRxBytes, elapsed)
+	m.NetworkTxBytesPerSecond = rate(prev.networkTxBytes, cur.networkTxBytes, elapsed)
+	m.StorageReadBytesPerSecond = rate(prev.storageReadBytes, cur.storageReadBytes, elapsed)
+	m.StorageWriteBytesPerSecond = rate(prev.storageWriteBytes, cur.storageWriteBytes, elapsed)
+
+	vCPUs := float64(cur.cpuTotalUsage-prev.cpuTotalUsage) / float64(time.Second) / elapsed
+	return vCPUs, true
+}
+
+// cpuUtilization returns the used vCPUs as a percentage of the reserved vCPUs,
+// or nil when nothing is reserved.
+func cpuUtilization(used float64, reserved float64) *float64 {
+	if reserved <= 0 {
+		return nil
+	}
+	utilization := used / reserved * 100
+	return &utilization
+}
+
+func rate(prev, cur uint64, elapsed float64) *float64 {
+	r := float64(cur-prev) / elapsed
+	return &r
+}
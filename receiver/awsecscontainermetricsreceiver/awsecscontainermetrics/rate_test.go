@@ -0,0 +1,83 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRates(t *testing.T) {
+	now := time.Now()
+	prev := containerSample{
+		read:              now,
+		cpuTotalUsage:     1000000000,
+		networkRxBytes:    100,
+		networkTxBytes:    200,
+		storageReadBytes:  300,
+		storageWriteBytes: 400,
+	}
+	cur := containerSample{
+		read:              now.Add(2 * time.Second),
+		cpuTotalUsage:     2000000000,
+		networkRxBytes:    300,
+		networkTxBytes:    600,
+		storageReadBytes:  900,
+		storageWriteBytes: 1200,
+	}
+
+	m := ECSMetrics{}
+	vCPUs, ok := applyRates(&m, prev, cur)
+	require.True(t, ok)
+	require.InDelta(t, 0.5, vCPUs, 0.0001)
+	require.InDelta(t, 100, *m.NetworkRxBytesPerSecond, 0.0001)
+	require.InDelta(t, 200, *m.NetworkTxBytesPerSecond, 0.0001)
+	require.InDelta(t, 300, *m.StorageReadBytesPerSecond, 0.0001)
+	require.InDelta(t, 400, *m.StorageWriteBytesPerSecond, 0.0001)
+}
+
+func TestApplyRatesSkipped(t *testing.T) {
+	now := time.Now()
+	prev := containerSample{read: now, cpuTotalUsage: 100, networkRxBytes: 100}
+
+	tests := []struct {
+		name string
+		cur  containerSample
+	}{
+		{
+			name: "same-read-time",
+			cur:  containerSample{read: now, cpuTotalUsage: 200, networkRxBytes: 200},
+		},
+		{
+			name: "counter-reset",
+			cur:  containerSample{read: now.Add(time.Second), cpuTotalUsage: 10, networkRxBytes: 200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ECSMetrics{}
+			_, ok := applyRates(&m, prev, tt.cur)
+			require.False(t, ok)
+			require.Nil(t, m.NetworkRxBytesPerSecond)
+		})
+	}
+}
+
+func TestCPUUtilization(t *testing.T) {
+	require.InDelta(t, 25, *cpuUtilization(0.5, 2), 0.0001)
+	require.Nil(t, cpuUtilization(0.5, 0))
+}
@@ -17,35 +17,35 @@ package awsecscontainermetrics
 import (
 	"strings"
 
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
 
-func containerResource(cm ContainerMetadata) *resourcepb.Resource {
-	labels := map[string]string{}
+func containerResource(cm ContainerMetadata) pdata.Resource {
+	resource := pdata.NewResource()
+	resource.InitEmpty()
 
-	labels[conventions.AttributeContainerName] = cm.ContainerName
-	labels[conventions.AttributeContainerID] = cm.DockerID
-	labels[AttributeECSDockerName] = cm.DockerName
-	return &resourcepb.Resource{
-		Type:   MetricResourceType,
-		Labels: labels,
-	}
+	attributes := resource.Attributes()
+	attributes.InsertString(conventions.AttributeContainerName, cm.ContainerName)
+	attributes.InsertString(conventions.AttributeContainerID, cm.DockerID)
+	attributes.InsertString(AttributeECSDockerName, cm.DockerName)
+	attributes.InsertString(AttributeECSContainerKnownStatus, cm.KnownStatus)
+	return resource
 }
 
-func taskResource(tm TaskMetadata) *resourcepb.Resource {
-	labels := map[string]string{}
-
-	labels[AttributeECSCluster] = tm.Cluster
-	labels[AttributeECSTaskARN] = tm.TaskARN
-	labels[AttributeECSTaskID] = getTaskIDFromARN(tm.TaskARN)
-	labels[AttributeECSTaskFamily] = tm.Family
-	labels[AttributeECSTaskRevesion] = tm.Revision
-	labels[AttributeECSServiceName] = "undefined"
-	return &resourcepb.Resource{
-		Type:   MetricResourceType,
-		Labels: labels,
-	}
+func taskResource(tm TaskMetadata) pdata.Resource {
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+
+	attributes := resource.Attributes()
+	attributes.InsertString(AttributeECSCluster, tm.Cluster)
+	attributes.InsertString(AttributeECSTaskARN, tm.TaskARN)
+	attributes.InsertString(AttributeECSTaskID, getTaskIDFromARN(tm.TaskARN))
+	attributes.InsertString(AttributeECSTaskFamily, tm.Family)
+	attributes.InsertString(AttributeECSTaskRevesion, tm.Revision)
+	attributes.InsertString(AttributeECSTaskKnownStatus, tm.KnownStatus)
+	attributes.InsertString(AttributeECSServiceName, "undefined")
+	return resource
 }
 
 func getTaskIDFromARN(arn string) string {
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
 
@@ -26,16 +27,18 @@ func TestContainerResource(t *testing.T) {
 		ContainerName: "container-1",
 		DockerID:      "001",
 		DockerName:    "docker-container-1",
+		KnownStatus:   "RUNNING",
 	}
 	r := containerResource(cm)
-	require.NotNil(t, r)
+	require.False(t, r.IsNil())
 
-	labels := r.Labels
-	require.EqualValues(t, 3, len(labels))
+	attributes := r.Attributes()
+	require.EqualValues(t, 4, attributes.Len())
 
-	require.EqualValues(t, "container-1", labels[conventions.AttributeContainerName])
-	require.EqualValues(t, "001", labels[conventions.AttributeContainerID])
-	require.EqualValues(t, "docker-container-1", labels[AttributeECSDockerName])
+	requireAttribute(t, attributes, conventions.AttributeContainerName, "container-1")
+	requireAttribute(t, attributes, conventions.AttributeContainerID, "001")
+	requireAttribute(t, attributes, AttributeECSDockerName, "docker-container-1")
+	requireAttribute(t, attributes, AttributeECSContainerKnownStatus, "RUNNING")
 }
 
 func TestTaskResource(t *testing.T) {
@@ -44,18 +47,27 @@ func TestTaskResource(t *testing.T) {
 		TaskARN:  "arn:aws:some-value/001",
 		Family:   "task-def-family-1",
 		Revision: "task-def-version-1",
+
+		KnownStatus: "RUNNING",
 	}
 	r := taskResource(tm)
-	require.NotNil(t, r)
+	require.False(t, r.IsNil())
+
+	attributes := r.Attributes()
+	require.EqualValues(t, 7, attributes.Len())
 
-	labels := r.Labels
-	require.EqualValues(t, 6, len(labels))
+	requireAttribute(t, attributes, AttributeECSCluster, "cluster-1")
+	requireAttribute(t, attributes, AttributeECSTaskARN, "arn:aws:some-value/001")
+	requireAttribute(t, attributes, AttributeECSTaskID, "001")
+	requireAttribute(t, attributes, AttributeECSTaskFamily, "task-def-family-1")
+	requireAttribute(t, attributes, AttributeECSTaskRevesion, "task-def-version-1")
+	requireAttribute(t, attributes, AttributeECSTaskKnownStatus, "RUNNING")
+}
 
-	require.EqualValues(t, "cluster-1", labels[AttributeECSCluster])
-	require.EqualValues(t, "arn:aws:some-value/001", labels[AttributeECSTaskARN])
-	require.EqualValues(t, "001", labels[AttributeECSTaskID])
-	require.EqualValues(t, "task-def-family-1", labels[AttributeECSTaskFamily])
-	require.EqualValues(t, "task-def-version-1", labels[AttributeECSTaskRevesion])
+func requireAttribute(t *testing.T, attributes pdata.AttributeMap, key string, value string) {
+	v, ok := attributes.Get(key)
+	require.True(t, ok, key)
+	require.EqualValues(t, value, v.StringVal())
 }
 
 func TestGetTaskIDFromARN(t *testing.T) {
@@ -15,107 +15,117 @@
 package awsecscontainermetrics
 
 import (
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	"github.com/golang/protobuf/ptypes/timestamp"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
-func convertToOCMetrics(prefix string, m ECSMetrics, labelKeys []*metricspb.LabelKey, labelValues []*metricspb.LabelValue, timestamp *timestamp.Timestamp) []*metricspb.Metric {
-
-	return applyTimestamp([]*metricspb.Metric{
-		intGauge(prefix+AttributeMemoryUsage, UnitBytes, &m.MemoryUsage, labelKeys, labelValues),
-		intGauge(prefix+AttributeMemoryMaxUsage, UnitBytes, &m.MemoryMaxUsage, labelKeys, labelValues),
-		intGauge(prefix+AttributeMemoryLimit, UnitBytes, &m.MemoryLimit, labelKeys, labelValues),
-		intGauge(prefix+AttributeMemoryUtilized, UnitMegaBytes, &m.MemoryUtilized, labelKeys, labelValues),
-		intGauge(prefix+AttributeMemoryReserved, UnitMegaBytes, &m.MemoryReserved, labelKeys, labelValues),
-
-		intCumulative(prefix+AttributeCPUTotalUsage, UnitNanoSecond, &m.CPUTotalUsage, labelKeys, labelValues),
-		intCumulative(prefix+AttributeCPUKernelModeUsage, UnitNanoSecond, &m.CPUUsageInKernelmode, labelKeys, labelValues),
-		intCumulative(prefix+AttributeCPUUserModeUsage, UnitNanoSecond, &m.CPUUsageInUserMode, labelKeys, labelValues),
-		intGauge(prefix+AttributeCPUCores, UnitCount, &m.NumOfCPUCores, labelKeys, labelValues),
-		intGauge(prefix+AttributeCPUOnlines, UnitCount, &m.CPUOnlineCpus, labelKeys, labelValues),
-		intCumulative(prefix+AttributeCPUSystemUsage, UnitNanoSecond, &m.SystemCPUUsage, labelKeys, labelValues),
-		doubleGauge(prefix+AttributeCPUUtilized, UnitVCpu, &m.CPUUtilized, labelKeys, labelValues),
-		doubleGauge(prefix+AttributeCPUReserved, UnitVCpu, &m.CPUReserved, labelKeys, labelValues),
-
-		doubleGauge(prefix+AttributeNetworkRateRx, UnitBytesPerSec, &m.NetworkRateRxBytesPerSecond, labelKeys, labelValues),
-		doubleGauge(prefix+AttributeNetworkRateTx, UnitBytesPerSec, &m.NetworkRateTxBytesPerSecond, labelKeys, labelValues),
-
-		intCumulative(prefix+AttributeNetworkRxBytes, UnitBytes, &m.NetworkRxBytes, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkRxPackets, UnitCount, &m.NetworkRxPackets, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkRxErrors, UnitCount, &m.NetworkRxErrors, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkRxDropped, UnitCount, &m.NetworkRxDropped, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkTxBytes, UnitBytes, &m.NetworkTxBytes, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkTxPackets, UnitCount, &m.NetworkTxPackets, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkTxErrors, UnitCount, &m.NetworkTxErrors, labelKeys, labelValues),
-		intCumulative(prefix+AttributeNetworkTxDropped, UnitCount, &m.NetworkTxDropped, labelKeys, labelValues),
-
-		intCumulative(prefix+AttributeStorageRead, UnitBytes, &m.StorageReadBytes, labelKeys, labelValues),
-		intCumulative(prefix+AttributeStorageWrite, UnitBytes, &m.StorageWriteBytes, labelKeys, labelValues),
-	}, timestamp)
+func convertToOTLPMetrics(prefix string, m ECSMetrics, timestamp pdata.TimestampUnixNano) pdata.MetricSlice {
+	metrics := pdata.NewMetricSlice()
+
+	intGauge(metrics, prefix+AttributeMemoryUsage, UnitBytes, &m.MemoryUsage, timestamp)
+	intGauge(metrics, prefix+AttributeMemoryMaxUsage, UnitBytes, &m.MemoryMaxUsage, timestamp)
+	intGauge(metrics, prefix+AttributeMemoryLimit, UnitBytes, &m.MemoryLimit, timestamp)
+	intGauge(metrics, prefix+AttributeMemoryUtilized, UnitMegaBytes, &m.MemoryUtilized, timestamp)
+	intGauge(metrics, prefix+AttributeMemoryReserved, UnitMegaBytes, &m.MemoryReserved, timestamp)
+
+	intCumulative(metrics, prefix+AttributeCPUTotalUsage, UnitNanoSecond, &m.CPUTotalUsage, timestamp)
+	intCumulative(metrics, prefix+AttributeCPUKernelModeUsage, UnitNanoSecond, &m.CPUUsageInKernelmode, timestamp)
+	intCumulative(metrics, prefix+AttributeCPUUserModeUsage, UnitNanoSecond, &m.CPUUsageInUserMode, timestamp)
+	intGauge(metrics, prefix+AttributeCPUCores, UnitCount, &m.NumOfCPUCores, timestamp)
+	intGauge(metrics, prefix+AttributeCPUOnlines, UnitCount, &m.CPUOnlineCpus, timestamp)
+	intCumulative(metrics, prefix+AttributeCPUSystemUsage, UnitNanoSecond, &m.SystemCPUUsage, timestamp)
+	doubleGauge(metrics, prefix+AttributeCPUUtilized, UnitVCpu, &m.CPUUtilized, timestamp)
+	doubleGauge(metrics, prefix+AttributeCPUReserved, UnitVCpu, &m.CPUReserved, timestamp)
+	doubleGauge(metrics, prefix+AttributeCPUUtilization, UnitPercent, m.CPUUtilization, timestamp)
+
+	doubleGauge(metrics, prefix+AttributeNetworkRateRx, UnitBytesPerSec, &m.NetworkRateRxBytesPerSecond, timestamp)
+	doubleGauge(metrics, prefix+AttributeNetworkRateTx, UnitBytesPerSec, &m.NetworkRateTxBytesPerSecond, timestamp)
+
+	intCumulative(metrics, prefix+AttributeNetworkRxBytes, UnitBytes, &m.NetworkRxBytes, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkRxPackets, UnitCount, &m.NetworkRxPackets, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkRxErrors, UnitCount, &m.NetworkRxErrors, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkRxDropped, UnitCount, &m.NetworkRxDropped, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkTxBytes, UnitBytes, &m.NetworkTxBytes, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkTxPackets, UnitCount, &m.NetworkTxPackets, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkTxErrors, UnitCount, &m.NetworkTxErrors, timestamp)
+	intCumulative(metrics, prefix+AttributeNetworkTxDropped, UnitCount, &m.NetworkTxDropped, timestamp)
+	doubleGauge(metrics, prefix+AttributeNetworkRxBytesPerSecond, UnitBytesPerSec, m.NetworkRxBytesPerSecond, timestamp)
+	doubleGauge(metrics, prefix+AttributeNetworkTxBytesPerSecond, UnitBytesPerSec, m.NetworkTxBytesPerSecond, timestamp)
+
+	intCumulative(metrics, prefix+AttributeStorageRead, UnitBytes, &m.StorageReadBytes, timestamp)
+	intCumulative(metrics, prefix+AttributeStorageWrite, UnitBytes, &m.StorageWriteBytes, timestamp)
+	doubleGauge(metrics, prefix+AttributeStorageReadBytesPerSecond, UnitBytesPerSec, m.StorageReadBytesPerSecond, timestamp)
+	doubleGauge(metrics, prefix+AttributeStorageWriteBytesPerSecond, UnitBytesPerSec, m.StorageWriteBytesPerSecond, timestamp)
+
+	intCumulative(metrics, prefix+AttributeRestarts, UnitCount, m.Restarts, timestamp)
+
+	return metrics
 }
 
-func intGauge(metricName string, unit string, value *uint64, labelKeys []*metricspb.LabelKey, labelValues []*metricspb.LabelValue) *metricspb.Metric {
-	if value == nil {
-		return nil
+// containerStateMetric reports the number of containers of a task in every known state.
+func containerStateMetric(metrics pdata.MetricSlice, states map[string]uint64, timestamp pdata.TimestampUnixNano) {
+	keys := make([]string, 0, len(states))
+	for state := range states {
+		keys = append(keys, state)
 	}
-	return &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name:      metricName,
-			Unit:      unit,
-			Type:      metricspb.MetricDescriptor_GAUGE_INT64,
-			LabelKeys: labelKeys,
-		},
-		Timeseries: []*metricspb.TimeSeries{{
-			LabelValues: labelValues,
-			Points: []*metricspb.Point{{
-				Value: &metricspb.Point_Int64Value{
-					Int64Value: int64(*value),
-				},
-			}},
-		}},
+	sort.Strings(keys)
+
+	metric := newMetric(metrics, TaskPrefix+AttributeContainers, UnitCount, pdata.MetricDataTypeIntGauge)
+	metric.IntGauge().InitEmpty()
+	dataPoints := metric.IntGauge().DataPoints()
+	dataPoints.Resize(len(keys))
+	for i, state := range keys {
+		dataPoint := dataPoints.At(i)
+		dataPoint.LabelsMap().Insert(LabelContainerState, state)
+		dataPoint.SetValue(int64(states[state]))
+		dataPoint.SetTimestamp(timestamp)
 	}
 }
 
-func doubleGauge(metricName string, unit string, value *float64, labelKeys []*metricspb.LabelKey, labelValues []*metricspb.LabelValue) *metricspb.Metric {
+func intGauge(metrics pdata.MetricSlice, metricName string, unit string, value *uint64, timestamp pdata.TimestampUnixNano) {
 	if value == nil {
-		return nil
-	}
-	return &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name:      metricName,
-			Unit:      unit,
-			Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
-			LabelKeys: labelKeys,
-		},
-		Timeseries: []*metricspb.TimeSeries{{
-			LabelValues: labelValues,
-			Points: []*metricspb.Point{{
-				Value: &metricspb.Point_DoubleValue{
-					DoubleValue: *value,
-				},
-			}},
-		}},
+		return
 	}
+	metric := newMetric(metrics, metricName, unit, pdata.MetricDataTypeIntGauge)
+	metric.IntGauge().InitEmpty()
+	intDataPoint(metric.IntGauge().DataPoints(), *value, timestamp)
 }
 
-func intCumulative(metricName string, unit string, value *uint64, labelKeys []*metricspb.LabelKey, labelValues []*metricspb.LabelValue) *metricspb.Metric {
+func doubleGauge(metrics pdata.MetricSlice, metricName string, unit string, value *float64, timestamp pdata.TimestampUnixNano) {
 	if value == nil {
-		return nil
+		return
 	}
-	return &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name:      metricName,
-			Unit:      unit,
-			Type:      metricspb.MetricDescriptor_CUMULATIVE_INT64,
-			LabelKeys: labelKeys,
-		},
-		Timeseries: []*metricspb.TimeSeries{{
-			LabelValues: labelValues,
-			Points: []*metricspb.Point{{
-				Value: &metricspb.Point_Int64Value{
-					Int64Value: int64(*value),
-				},
-			}},
-		}},
+	metric := newMetric(metrics, metricName, unit, pdata.MetricDataTypeDoubleGauge)
+	metric.DoubleGauge().InitEmpty()
+	dataPoints := metric.DoubleGauge().DataPoints()
+	dataPoints.Resize(1)
+	dataPoints.At(0).SetValue(*value)
+	dataPoints.At(0).SetTimestamp(timestamp)
+}
+
+func intCumulative(metrics pdata.MetricSlice, metricName string, unit string, value *uint64, timestamp pdata.TimestampUnixNano) {
+	if value == nil {
+		return
 	}
+	metric := newMetric(metrics, metricName, unit, pdata.MetricDataTypeIntSum)
+	metric.IntSum().InitEmpty()
+	metric.IntSum().SetIsMonotonic(true)
+	metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	intDataPoint(metric.IntSum().DataPoints(), *value, timestamp)
+}
+
+func newMetric(metrics pdata.MetricSlice, metricName string, unit string, dataType pdata.MetricDataType) pdata.Metric {
+	metrics.Resize(metrics.Len() + 1)
+	metric := metrics.At(metrics.Len() - 1)
+	metric.SetName(metricName)
+	metric.SetUnit(unit)
+	metric.SetDataType(dataType)
+	return metric
+}
+
+func intDataPoint(dataPoints pdata.IntDataPointSlice, value uint64, timestamp pdata.TimestampUnixNano) {
+	dataPoints.Resize(1)
+	dataPoints.At(0).SetValue(int64(value))
+	dataPoints.At(0).SetTimestamp(timestamp)
 }
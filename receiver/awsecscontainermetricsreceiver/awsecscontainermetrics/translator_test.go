@@ -11,18 +11,19 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
+
 package awsecscontainermetrics
 
 import (
 	"testing"
 	"time"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestConvertToOTMetrics(t *testing.T) {
-	timestamp := timestampProto(time.Now())
+	timestamp := pdata.TimestampUnixNano(uint64(time.Now().UnixNano()))
 	m := ECSMetrics{}
 
 	m.MemoryUsage = 100
@@ -31,66 +32,79 @@ func TestConvertToOTMetrics(t *testing.T) {
 	m.MemoryReserved = 100
 	m.CPUTotalUsage = 100
 
-	labelKeys := []*metricspb.LabelKey{
-		{Key: "label_key_1"},
-	}
-	labelValues := []*metricspb.LabelValue{
-		{Value: "label_value_1"},
-	}
+	metrics := convertToOTLPMetrics("container.", m, timestamp)
+	require.EqualValues(t, 25, metrics.Len())
+
+	rate := float64(1)
+	restarts := uint64(1)
+	m.CPUUtilization = &rate
+	m.NetworkRxBytesPerSecond = &rate
+	m.NetworkTxBytesPerSecond = &rate
+	m.StorageReadBytesPerSecond = &rate
+	m.StorageWriteBytesPerSecond = &rate
+	m.Restarts = &restarts
 
-	metrics := convertToOCMetrics("container.", m, labelKeys, labelValues, timestamp)
-	require.EqualValues(t, 25, len(metrics))
+	metrics = convertToOTLPMetrics("container.", m, timestamp)
+	require.EqualValues(t, 31, metrics.Len())
 }
 
 func TestIntGauge(t *testing.T) {
 	intValue := uint64(100)
+	timestamp := pdata.TimestampUnixNano(1)
 
-	labelKeys := []*metricspb.LabelKey{
-		{Key: "label_key_1"},
-	}
-	labelValues := []*metricspb.LabelValue{
-		{Value: "label_value_1"},
-	}
-
-	m := intGauge("cpu_utilized", "Count", &intValue, labelKeys, labelValues)
-	require.NotNil(t, m)
+	metrics := pdata.NewMetricSlice()
+	intGauge(metrics, "cpu_utilized", "Count", &intValue, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
+	require.EqualValues(t, pdata.MetricDataTypeIntGauge, metrics.At(0).DataType())
+	require.EqualValues(t, 100, metrics.At(0).IntGauge().DataPoints().At(0).Value())
+	require.EqualValues(t, timestamp, metrics.At(0).IntGauge().DataPoints().At(0).Timestamp())
 
-	m = intGauge("cpu_utilized", "Count", nil, labelKeys, labelValues)
-	require.Nil(t, m)
+	intGauge(metrics, "cpu_utilized", "Count", nil, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
 }
 
 func TestDoubleGauge(t *testing.T) {
 	floatValue := float64(100.01)
+	timestamp := pdata.TimestampUnixNano(1)
 
-	labelKeys := []*metricspb.LabelKey{
-		{Key: "label_key_1"},
-	}
-	labelValues := []*metricspb.LabelValue{
-		{Value: "label_value_1"},
-	}
-
-	m := doubleGauge("cpu_utilized", "Count", &floatValue, labelKeys, labelValues)
-	require.NotNil(t, m)
-
-	m = doubleGauge("cpu_utilized", "Count", nil, labelKeys, labelValues)
-	require.Nil(t, m)
+	metrics := pdata.NewMetricSlice()
+	doubleGauge(metrics, "cpu_utilized", "Count", &floatValue, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
+	require.EqualValues(t, pdata.MetricDataTypeDoubleGauge, metrics.At(0).DataType())
+	require.EqualValues(t, floatValue, metrics.At(0).DoubleGauge().DataPoints().At(0).Value())
 
+	doubleGauge(metrics, "cpu_utilized", "Count", nil, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
 }
 
 func TestIntCumulative(t *testing.T) {
-	floatValue := uint64(100)
-
-	labelKeys := []*metricspb.LabelKey{
-		{Key: "label_key_1"},
-	}
-	labelValues := []*metricspb.LabelValue{
-		{Value: "label_value_1"},
-	}
-
-	m := intCumulative("cpu_utilized", "Count", &floatValue, labelKeys, labelValues)
-	require.NotNil(t, m)
-
-	m = intCumulative("cpu_utilized", "Count", nil, labelKeys, labelValues)
-	require.Nil(t, m)
+	intValue := uint64(100)
+	timestamp := pdata.TimestampUnixNano(1)
+
+	metrics := pdata.NewMetricSlice()
+	intCumulative(metrics, "cpu_utilized", "Count", &intValue, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
+	require.EqualValues(t, pdata.MetricDataTypeIntSum, metrics.At(0).DataType())
+	require.True(t, metrics.At(0).IntSum().IsMonotonic())
+	require.EqualValues(t, pdata.AggregationTemporalityCumulative, metrics.At(0).IntSum().AggregationTemporality())
+	require.EqualValues(t, 100, metrics.At(0).IntSum().DataPoints().At(0).Value())
+
+	intCumulative(metrics, "cpu_utilized", "Count", nil, timestamp)
+	require.EqualValues(t, 1, metrics.Len())
+}
 
+func TestContainerStateMetric(t *testing.T) {
+	metrics := pdata.NewMetricSlice()
+	containerStateMetric(metrics, map[string]uint64{"stopped": 1, "running": 2}, pdata.TimestampUnixNano(1))
+	require.EqualValues(t, 1, metrics.Len())
+	require.EqualValues(t, TaskPrefix+AttributeContainers, metrics.At(0).Name())
+
+	dps := metrics.At(0).IntGauge().DataPoints()
+	require.EqualValues(t, 2, dps.Len())
+	state, _ := dps.At(0).LabelsMap().Get(LabelContainerState)
+	require.EqualValues(t, "running", state.Value())
+	require.EqualValues(t, 2, dps.At(0).Value())
+	state, _ = dps.At(1).LabelsMap().Get(LabelContainerState)
+	require.EqualValues(t, "stopped", state.Value())
+	require.EqualValues(t, 1, dps.At(1).Value())
 }
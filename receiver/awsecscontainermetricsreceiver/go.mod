@@ -3,8 +3,6 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecs
 go 1.14
 
 require (
-	github.com/census-instrumentation/opencensus-proto v0.3.0
-	github.com/golang/protobuf v1.4.2
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.11.1-0.20201001213035-035aa5cf6c92
 	go.uber.org/zap v1.16.0
@@ -22,7 +22,6 @@ import (
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
@@ -36,8 +35,8 @@ type awsEcsContainerMetricsReceiver struct {
 	nextConsumer consumer.MetricsConsumer
 	config       *Config
 	cancel       context.CancelFunc
-	restClient   awsecscontainermetrics.RestClient
 	provider     *awsecscontainermetrics.StatsProvider
+	builder      *awsecscontainermetrics.MetricsBuilder
 }
 
 // New creates the aws ecs container metrics receiver with the given parameters.
@@ -54,7 +53,8 @@ func New(
 		logger:       logger,
 		nextConsumer: nextConsumer,
 		config:       config,
-		restClient:   rest,
+		provider:     awsecscontainermetrics.NewStatsProvider(rest),
+		builder:      awsecscontainermetrics.NewMetricsBuilder(),
 	}
 	return r, nil
 }
@@ -86,7 +86,6 @@ func (aecmr *awsEcsContainerMetricsReceiver) Shutdown(context.Context) error {
 
 // collectDataFromEndpoint collects container stats from Amazon ECS Task Metadata Endpoint
 func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context.Context, typeStr string) error {
-	aecmr.provider = awsecscontainermetrics.NewStatsProvider(aecmr.restClient)
 	stats, metadata, err := aecmr.provider.GetStats()
 
 	if err != nil {
@@ -95,14 +94,6 @@ func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context
 	}
 
 	// TODO: report self metrics using obsreport
-	mds := awsecscontainermetrics.MetricsData(stats, metadata)
-	for _, md := range mds {
-		metrics := internaldata.OCToMetrics(*md)
-		err = aecmr.nextConsumer.ConsumeMetrics(ctx, metrics)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	md := aecmr.builder.MetricsData(stats, metadata)
+	return aecmr.nextConsumer.ConsumeMetrics(ctx, md)
 }
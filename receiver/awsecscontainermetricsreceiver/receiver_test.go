@@ -18,12 +18,17 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
 type fakeRestClient struct {
@@ -152,3 +157,112 @@ func TestCollectDataFromEndpointWithEndpointError(t *testing.T) {
 	err = r.collectDataFromEndpoint(ctx, "")
 	require.Error(t, err)
 }
+
+// replayRestClient replays the task stats and task metadata captured in testdata/replay,
+// one sample per call.
+type replayRestClient struct {
+	samples []string
+	next    int
+}
+
+func (c *replayRestClient) EndpointResponse() ([]byte, []byte, error) {
+	if c.next >= len(c.samples) {
+		return nil, nil, fmt.Errorf("no sample left to replay")
+	}
+	sample := c.samples[c.next]
+	c.next++
+
+	taskStats, err := ioutil.ReadFile(path.Join("testdata", "replay", "task_stats_"+sample+".json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	taskMetadata, err := ioutil.ReadFile(path.Join("testdata", "replay", "task_metadata_"+sample+".json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return taskStats, taskMetadata, nil
+}
+
+func TestCollectDataFromReplayedEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
+	metricsReceiver, err := New(
+		zap.NewNop(),
+		cfg,
+		sink,
+		&replayRestClient{samples: []string{"0", "1"}},
+	)
+	require.NoError(t, err)
+
+	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	ctx := context.Background()
+
+	require.NoError(t, r.collectDataFromEndpoint(ctx, ""))
+	require.NoError(t, r.collectDataFromEndpoint(ctx, ""))
+	require.Error(t, r.collectDataFromEndpoint(ctx, ""))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 2)
+
+	// The first sample has nothing to compute rates against
+	first := replayedResources(mds[0])
+	require.Len(t, first, 4)
+	for _, metrics := range first {
+		require.NotContains(t, metrics, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeCPUUtilization)
+		require.NotContains(t, metrics, awsecscontainermetrics.TaskPrefix+awsecscontainermetrics.AttributeCPUUtilization)
+	}
+
+	// nginx200 stopped and nginx300 restarted between the two samples
+	second := replayedResources(mds[1])
+	require.Len(t, second, 3)
+	require.NotContains(t, second, "fffb51bc2ca1f0205be9579b893372e728cd3bf6823c006f417323565b8cb7d1")
+
+	nginx100 := second["5302b3fac16c62951717f444030cb1b8f233f40c03fe5507fc127ca1a70597da"]
+	requireDoubleValue(t, nginx100, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeCPUUtilization, 52.4288)
+	requireDoubleValue(t, nginx100, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeNetworkRxBytesPerSecond, 100)
+	requireDoubleValue(t, nginx100, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeNetworkTxBytesPerSecond, 50)
+	requireDoubleValue(t, nginx100, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeStorageReadBytesPerSecond, 204.8)
+	requireDoubleValue(t, nginx100, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeStorageWriteBytesPerSecond, 409.6)
+
+	nginx300 := second["4a984770705c4f4f95e1267af3623ab0923c602b7cd4ed7d77b7f8356537337f"]
+	require.NotContains(t, nginx300, awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeNetworkRxBytesPerSecond)
+	require.EqualValues(t, 1, nginx300[awsecscontainermetrics.ContainerPrefix+awsecscontainermetrics.AttributeRestarts].IntSum().DataPoints().At(0).Value())
+
+	task := second[""]
+	requireDoubleValue(t, task, awsecscontainermetrics.TaskPrefix+awsecscontainermetrics.AttributeCPUUtilization, 52.4288)
+	requireDoubleValue(t, task, awsecscontainermetrics.TaskPrefix+awsecscontainermetrics.AttributeNetworkRxBytesPerSecond, 100)
+	require.EqualValues(t, 1, task[awsecscontainermetrics.TaskPrefix+awsecscontainermetrics.AttributeRestarts].IntSum().DataPoints().At(0).Value())
+
+	states := task[awsecscontainermetrics.TaskPrefix+awsecscontainermetrics.AttributeContainers].IntGauge().DataPoints()
+	require.EqualValues(t, 2, states.Len())
+	require.EqualValues(t, 2, states.At(0).Value())
+	require.EqualValues(t, 1, states.At(1).Value())
+}
+
+// replayedResources indexes the metrics of every resource by name, the resources being
+// keyed by container id, the task being the resource without one.
+func replayedResources(md pdata.Metrics) map[string]map[string]pdata.Metric {
+	resources := make(map[string]map[string]pdata.Metric)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		id := ""
+		if v, ok := rm.Resource().Attributes().Get(conventions.AttributeContainerID); ok {
+			id = v.StringVal()
+		}
+
+		metrics := make(map[string]pdata.Metric)
+		ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			metrics[ms.At(j).Name()] = ms.At(j)
+		}
+		resources[id] = metrics
+	}
+	return resources
+}
+
+func requireDoubleValue(t *testing.T, metrics map[string]pdata.Metric, name string, expected float64) {
+	metric, ok := metrics[name]
+	require.True(t, ok, name)
+	require.InDelta(t, expected, metric.DoubleGauge().DataPoints().At(0).Value(), 0.0001, name)
+}
@@ -29,6 +29,12 @@ type Config struct {
 	// emitted by the X-Ray SDK.
 	confignet.NetAddr `mapstructure:",squash"`
 
+	// TCPEndpoint is the TCP address and port on which this receiver
+	// listens for X-Ray segment documents, each sent as a header line
+	// followed by the segment document on a single line. The TCP listener
+	// is disabled when empty.
+	TCPEndpoint string `mapstructure:"tcp_endpoint"`
+
 	// ProxyServer defines configurations related to the local TCP proxy server.
 	ProxyServer *proxy.Config `mapstructure:"proxy_server"`
 }
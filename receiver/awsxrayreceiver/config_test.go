@@ -17,6 +17,7 @@ package awsxrayreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,7 +44,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 4)
 
 	// ensure default configurations are generated when users provide
 	// nothing.
@@ -74,12 +75,18 @@ func TestLoadConfig(t *testing.T) {
 				Region:      "",
 				RoleARN:     "",
 				AWSEndpoint: "",
+
+				SamplingRulesCacheTTL: time.Minute,
 			},
 		},
 		r1)
 
+	// ensure the TCP listener can be enabled
+	r2 := cfg.Receivers[awsxray.TypeStr+"/tcp_endpoint"].(*Config)
+	assert.Equal(t, "0.0.0.0:2001", r2.TCPEndpoint)
+
 	// ensure the fields under proxy_server are properly overwritten
-	r2 := cfg.Receivers[awsxray.TypeStr+"/proxy_server"].(*Config)
+	r3 := cfg.Receivers[awsxray.TypeStr+"/proxy_server"].(*Config)
 	assert.Equal(t,
 		&Config{
 			ReceiverSettings: configmodels.ReceiverSettings{
@@ -103,7 +110,10 @@ func TestLoadConfig(t *testing.T) {
 				RoleARN:     "arn:aws:iam::123456789012:role/awesome_role",
 				AWSEndpoint: "https://another.aws.endpoint.com",
 				LocalMode:   true,
+
+				SamplingRulesCacheTTL: 5 * time.Minute,
+				SamplingRulesFile:     "/etc/xray/sampling_rules.json",
 			},
 		},
-		r2)
+		r3)
 }
@@ -15,6 +15,8 @@
 package proxy
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 )
@@ -49,6 +51,17 @@ type Config struct {
 	// will be called or not. Set to `true` to skip EC2 instance
 	// metadata check.
 	LocalMode bool `mapstructure:"local_mode"`
+
+	// SamplingRulesCacheTTL is how long the sampling rules fetched from
+	// AWS X-Ray are served from the local cache instead of being fetched
+	// again for each SDK. The rules aren't cached when set to 0.
+	SamplingRulesCacheTTL time.Duration `mapstructure:"sampling_rules_cache_ttl"`
+
+	// SamplingRulesFile is the path of a JSON file, in the format of the
+	// GetSamplingRules API response, whose rules are served to the SDKs
+	// instead of the ones from AWS X-Ray. Sampling targets aren't
+	// requested from AWS X-Ray either when it is set.
+	SamplingRulesFile string `mapstructure:"sampling_rules_file"`
 }
 
 func DefaultConfig() *Config {
@@ -64,5 +77,7 @@ func DefaultConfig() *Config {
 		Region:      "",
 		RoleARN:     "",
 		AWSEndpoint: "",
+
+		SamplingRulesCacheTTL: time.Minute,
 	}
 }
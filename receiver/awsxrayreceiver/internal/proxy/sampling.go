@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// paths of the X-Ray sampling APIs called by the SDKs.
+	getSamplingRulesPath = "/GetSamplingRules"
+	samplingTargetsPath  = "/SamplingTargets"
+)
+
+// samplingHandler serves the sampling rules from a cache, or from a static
+// rules file, and forwards the other requests to the X-Ray proxy handler.
+type samplingHandler struct {
+	next   http.Handler
+	logger *zap.Logger
+
+	// staticRules is the GetSamplingRules response read from the sampling
+	// rules file, nil when the rules are fetched from AWS X-Ray.
+	staticRules []byte
+
+	ttl   time.Duration
+	now   func() time.Time
+	mu    sync.Mutex
+	cache map[string]*cachedResponse
+}
+
+// cachedResponse is a successful GetSamplingRules response, cached per
+// request body since the rules are paginated with NextToken.
+type cachedResponse struct {
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func newSamplingHandler(cfg *Config, next http.Handler, logger *zap.Logger) (http.Handler, error) {
+	h := &samplingHandler{
+		next:   next,
+		logger: logger,
+		ttl:    cfg.SamplingRulesCacheTTL,
+		now:    time.Now,
+		cache:  make(map[string]*cachedResponse),
+	}
+
+	if cfg.SamplingRulesFile != "" {
+		rules, err := ioutil.ReadFile(cfg.SamplingRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read sampling rules file: %w", err)
+		}
+		if !json.Valid(rules) {
+			return nil, fmt.Errorf("sampling rules file %q is not valid JSON", cfg.SamplingRulesFile)
+		}
+		h.staticRules = rules
+		logger.Info("Serving static sampling rules", zap.String("file", cfg.SamplingRulesFile))
+	}
+
+	if h.staticRules == nil && h.ttl <= 0 {
+		return next, nil
+	}
+	return h, nil
+}
+
+func (h *samplingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	switch req.URL.Path {
+	case getSamplingRulesPath:
+		if h.staticRules != nil {
+			writeJSON(w, h.staticRules)
+			return
+		}
+		h.serveCachedRules(w, req)
+	case samplingTargetsPath:
+		if h.staticRules != nil {
+			// Without targets the SDKs keep applying the fixed rate and
+			// reservoir of the static rules.
+			writeJSON(w, []byte(`{"SamplingTargetDocuments":[],"UnprocessedStatistics":[]}`))
+			return
+		}
+		h.next.ServeHTTP(w, req)
+	default:
+		h.next.ServeHTTP(w, req)
+	}
+}
+
+func (h *samplingHandler) serveCachedRules(w http.ResponseWriter, req *http.Request) {
+	var key string
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			h.logger.Error("Unable to consume request body", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key = string(body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	h.mu.Lock()
+	cached, ok := h.cache[key]
+	if ok && h.now().After(cached.expires) {
+		delete(h.cache, key)
+		ok = false
+	}
+	h.mu.Unlock()
+
+	if ok {
+		h.logger.Debug("Serving cached sampling rules")
+		copyHeader(w.Header(), cached.header)
+		w.WriteHeader(http.StatusOK)
+		w.Write(cached.body)
+		return
+	}
+
+	rec := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+	h.next.ServeHTTP(rec, req)
+
+	if rec.status == http.StatusOK {
+		h.mu.Lock()
+		h.cache[key] = &cachedResponse{
+			header:  rec.header,
+			body:    rec.body.Bytes(),
+			expires: h.now().Add(h.ttl),
+		}
+		h.mu.Unlock()
+	}
+
+	copyHeader(w.Header(), rec.header)
+	w.WriteHeader(rec.status)
+	w.Write(rec.body.Bytes())
+}
+
+func writeJSON(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, vv := range src {
+		dst[k] = append([]string(nil), vv...)
+	}
+}
+
+// responseRecorder records the response of the proxied request so it can be
+// cached.
+type responseRecorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingHandler mocks the X-Ray proxy handler.
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (c *countingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	c.calls++
+	body, _ := ioutil.ReadAll(req.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c.status)
+	w.Write([]byte(`{"call":` + strconv.Itoa(c.calls) + `,"request":` + string(body) + `}`))
+}
+
+func serve(h http.Handler, urlPath, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:2000"+urlPath, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSamplingHandlerDisabled(t *testing.T) {
+	next := &countingHandler{status: http.StatusOK}
+	h, err := newSamplingHandler(&Config{}, next, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, next, h, "the proxy handler should be used directly")
+}
+
+func TestSamplingHandlerCachesRules(t *testing.T) {
+	next := &countingHandler{status: http.StatusOK}
+	h, err := newSamplingHandler(&Config{SamplingRulesCacheTTL: time.Minute}, next, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Now()
+	h.(*samplingHandler).now = func() time.Time { return now }
+
+	rec := serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"call":1,"request":{}}`, rec.Body.String())
+
+	// served from the cache
+	rec = serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, `{"call":1,"request":{}}`, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 1, next.calls)
+
+	// the pages of the rules are cached separately
+	rec = serve(h, getSamplingRulesPath, `{"NextToken":"abc"}`)
+	assert.Equal(t, `{"call":2,"request":{"NextToken":"abc"}}`, rec.Body.String())
+
+	// the sampling targets are never cached
+	serve(h, samplingTargetsPath, `{}`)
+	serve(h, samplingTargetsPath, `{}`)
+	assert.Equal(t, 4, next.calls)
+
+	// the rules are fetched again once expired
+	now = now.Add(2 * time.Minute)
+	rec = serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, `{"call":5,"request":{}}`, rec.Body.String())
+}
+
+func TestSamplingHandlerDoesntCacheErrors(t *testing.T) {
+	next := &countingHandler{status: http.StatusForbidden}
+	h, err := newSamplingHandler(&Config{SamplingRulesCacheTTL: time.Minute}, next, zap.NewNop())
+	require.NoError(t, err)
+
+	rec := serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestSamplingHandlerStaticRules(t *testing.T) {
+	next := &countingHandler{status: http.StatusOK}
+	rulesFile := path.Join("testdata", "sampling_rules.json")
+	h, err := newSamplingHandler(&Config{SamplingRulesFile: rulesFile}, next, zap.NewNop())
+	require.NoError(t, err)
+
+	rules, err := ioutil.ReadFile(rulesFile)
+	require.NoError(t, err)
+
+	rec := serve(h, getSamplingRulesPath, `{}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, string(rules), rec.Body.String())
+
+	rec = serve(h, samplingTargetsPath, `{}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"SamplingTargetDocuments":[],"UnprocessedStatistics":[]}`, rec.Body.String())
+
+	assert.Equal(t, 0, next.calls, "AWS X-Ray should not be called")
+
+	// other APIs are still forwarded
+	serve(h, "/TraceSegments", `{}`)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestSamplingHandlerInvalidRulesFile(t *testing.T) {
+	_, err := newSamplingHandler(&Config{SamplingRulesFile: "testdata/missing.json"}, nil, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = newSamplingHandler(&Config{SamplingRulesFile: "sampling.go"}, nil, zap.NewNop())
+	assert.EqualError(t, err, `sampling rules file "sampling.go" is not valid JSON`)
+}
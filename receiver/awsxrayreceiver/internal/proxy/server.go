@@ -117,9 +117,14 @@ func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
 		},
 	}
 
+	samplingHandler, err := newSamplingHandler(cfg, handler, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	return &http.Server{
 		Addr:    cfg.Endpoint,
-		Handler: handler,
+		Handler: samplingHandler,
 	}, nil
 }
 
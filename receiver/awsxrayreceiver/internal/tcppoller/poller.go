@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcppoller
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+const (
+	// Transport is the network transport protocol used
+	// by the poller
+	Transport = "tcp"
+
+	// maximum size of a segment header or body, the same
+	// as the size of the buffer of the UDP poller.
+	maxLineSize = 64 * 1024
+
+	// the size of the channel between the TCP poller
+	// and OT consumer
+	segChanSize = 30
+)
+
+// Config represents the configurations needed to
+// start the TCP poller
+type Config struct {
+	ReceiverInstanceName string
+	Endpoint             string
+}
+
+// poller accepts TCP connections from the X-Ray SDKs, each
+// connection streams segments as a header line followed by
+// a body line, as the UDP packets do.
+type poller struct {
+	receiverInstanceName string
+	listener             net.Listener
+	logger               *zap.Logger
+	wg                   sync.WaitGroup
+	receiverLongLivedCtx context.Context
+
+	// connections currently opened, closed on shutdown
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	shutDown bool
+
+	// all segments read by the poller will be sent to this channel
+	segChan chan udppoller.RawSegment
+}
+
+// New creates a new TCP poller
+func New(cfg *Config, logger *zap.Logger) (udppoller.Poller, error) {
+	listener, err := net.Listen(Transport, cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Listening on endpoint for X-Ray segments",
+		zap.String(Transport, listener.Addr().String()))
+
+	return &poller{
+		receiverInstanceName: cfg.ReceiverInstanceName,
+		listener:             listener,
+		logger:               logger,
+		conns:                make(map[net.Conn]struct{}),
+		segChan:              make(chan udppoller.RawSegment, segChanSize),
+	}, nil
+}
+
+func (p *poller) Start(receiverLongTermCtx context.Context) {
+	p.receiverLongLivedCtx = receiverLongTermCtx
+	p.wg.Add(1)
+	go p.accept()
+}
+
+func (p *poller) Close() error {
+	err := p.listener.Close()
+
+	p.mu.Lock()
+	p.shutDown = true
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+
+	// inform the consumers of segChan that the poller is stopped
+	close(p.segChan)
+	return err
+}
+
+func (p *poller) SegmentsChan() <-chan udppoller.RawSegment {
+	return p.segChan
+}
+
+func (p *poller) accept() {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				p.logger.Error("Recoverable TCP accept error", zap.Error(err))
+				continue
+			}
+			p.mu.Lock()
+			shutDown := p.shutDown
+			p.mu.Unlock()
+			if !shutDown {
+				p.logger.Error("Irrecoverable TCP accept error. Exiting poller", zap.Error(err))
+			}
+			return
+		}
+
+		if !p.track(conn) {
+			conn.Close()
+			return
+		}
+		p.wg.Add(1)
+		go p.poll(conn)
+	}
+}
+
+// track registers the connection so it is closed on shutdown, it returns
+// false if the poller is already shut down.
+func (p *poller) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.shutDown {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+func (p *poller) untrack(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.conns, conn)
+}
+
+func (p *poller) poll(conn net.Conn) {
+	defer p.wg.Done()
+	defer p.untrack(conn)
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+
+	for scanner.Scan() {
+		// the header and body are split by tracesegment.ProtocolSeparator
+		header := append([]byte(nil), scanner.Bytes()...)
+		if len(header) == 0 {
+			continue
+		}
+
+		ctx := obsreport.StartTraceDataReceiveOp(
+			p.receiverLongLivedCtx,
+			p.receiverInstanceName,
+			Transport,
+			obsreport.WithLongLivedCtx())
+
+		if !scanner.Scan() {
+			p.logger.Warn("Connection closed before the segment body was received")
+			obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, 1,
+				errors.New("dropped span due to missing body that contains segment"))
+			break
+		}
+
+		message := append(append(header, tracesegment.ProtocolSeparator), scanner.Bytes()...)
+		_, body, err := tracesegment.SplitHeaderBody(message)
+		if err != nil {
+			p.logger.Error("Failed to split segment header and body",
+				zap.Error(err))
+			obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, 1, err)
+			continue
+		}
+
+		if len(body) == 0 {
+			p.logger.Warn("Missing body")
+			obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, 1,
+				errors.New("dropped span due to missing body that contains segment"))
+			continue
+		}
+
+		p.segChan <- udppoller.RawSegment{
+			Payload: body,
+			Ctx:     ctx,
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		p.mu.Lock()
+		shutDown := p.shutDown
+		p.mu.Unlock()
+		if !shutDown {
+			p.logger.Error("TCP connection read error", zap.Error(err))
+		}
+	}
+}
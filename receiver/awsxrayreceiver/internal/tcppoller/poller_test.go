@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcppoller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+const segmentHeader = `{"format": "json", "version": 1}`
+
+func TestInvalidEndpoint(t *testing.T) {
+	_, err := New(&Config{Endpoint: "invalidAddr"}, zap.NewNop())
+	assert.EqualError(t, err, "listen tcp: address invalidAddr: missing port in address")
+}
+
+func TestCloseStopsPoller(t *testing.T) {
+	receiverName := "TestCloseStopsPoller"
+	addr, p := createAndStartPoller(t, receiverName)
+
+	// an open connection must not prevent the poller from closing
+	conn, err := net.Dial(Transport, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	segChan := p.SegmentsChan()
+	assert.NoError(t, p.Close(), "should be able to close the poller")
+
+	testutil.WaitFor(t, func() bool {
+		select {
+		case _, open := <-segChan:
+			return !open
+		default:
+			return false
+		}
+	}, "output channel should be closed")
+}
+
+func TestSuccessfullyPollSegments(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
+	defer doneFn()
+
+	const receiverName = "TestSuccessfullyPollSegments"
+	addr, p := createAndStartPoller(t, receiverName)
+	defer p.Close()
+
+	conn, err := net.Dial(Transport, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%s\n{\"name\":\"first\"}\n%s\n{\"name\":\"second\"}\n", segmentHeader, segmentHeader)
+	require.NoError(t, err)
+
+	var payloads []string
+	testutil.WaitFor(t, func() bool {
+		select {
+		case seg := <-p.SegmentsChan():
+			obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, 1, nil)
+			payloads = append(payloads, string(seg.Payload))
+		default:
+		}
+		return len(payloads) == 2
+	}, "poller should return both segments")
+	assert.Equal(t, []string{`{"name":"first"}`, `{"name":"second"}`}, payloads)
+
+	obsreporttest.CheckReceiverTracesViews(t, receiverName, Transport, 2, 0)
+}
+
+func TestInvalidHeader(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
+	defer doneFn()
+
+	const receiverName = "TestInvalidHeader"
+	addr, p := createAndStartPoller(t, receiverName)
+	defer p.Close()
+
+	conn, err := net.Dial(Transport, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	// the invalid segment is dropped, the following one is still received
+	_, err = fmt.Fprintf(conn, "{\"format\": \"xml\"}\n{}\n%s\n{\"name\":\"valid\"}\n", segmentHeader)
+	require.NoError(t, err)
+
+	testutil.WaitFor(t, func() bool {
+		select {
+		case seg := <-p.SegmentsChan():
+			obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, 1, nil)
+			return string(seg.Payload) == `{"name":"valid"}`
+		default:
+			return false
+		}
+	}, "poller should return the valid segment")
+
+	obsreporttest.CheckReceiverTracesViews(t, receiverName, Transport, 1, 1)
+}
+
+func createAndStartPoller(t *testing.T, receiverName string) (string, udppoller.Poller) {
+	p, err := New(&Config{
+		ReceiverInstanceName: receiverName,
+		Endpoint:             "localhost:0",
+	}, zap.NewNop())
+	require.NoError(t, err, "poller should be created")
+
+	longLivedCtx := obsreport.ReceiverContext(context.Background(), receiverName, Transport, "")
+	p.Start(longLivedCtx)
+	return p.(*poller).listener.Addr().String(), p
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tcppoller"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
@@ -42,6 +43,7 @@ const (
 type xrayReceiver struct {
 	instanceName string
 	poller       udppoller.Poller
+	tcpPoller    udppoller.Poller // nil when the TCP listener is disabled
 	server       proxy.Server
 	logger       *zap.Logger
 	consumer     consumer.TraceConsumer
@@ -73,14 +75,31 @@ func newReceiver(config *Config,
 	logger.Info("Listening on endpoint for X-Ray segments",
 		zap.String(udppoller.Transport, config.Endpoint))
 
+	var tcpPoller udppoller.Poller
+	if config.TCPEndpoint != "" {
+		tcpPoller, err = tcppoller.New(&tcppoller.Config{
+			ReceiverInstanceName: config.Name(),
+			Endpoint:             config.TCPEndpoint,
+		}, logger)
+		if err != nil {
+			poller.Close()
+			return nil, err
+		}
+	}
+
 	srv, err := proxy.NewServer(config.ProxyServer, logger)
 	if err != nil {
+		poller.Close()
+		if tcpPoller != nil {
+			tcpPoller.Close()
+		}
 		return nil, err
 	}
 
 	return &xrayReceiver{
 		instanceName: config.Name(),
 		poller:       poller,
+		tcpPoller:    tcpPoller,
 		server:       srv,
 		logger:       logger,
 		consumer:     consumer,
@@ -93,7 +112,11 @@ func (x *xrayReceiver) Start(ctx context.Context, host component.Host) error {
 	x.startOnce.Do(func() {
 		x.longLivedCtx = obsreport.ReceiverContext(ctx, x.instanceName, udppoller.Transport, "")
 		x.poller.Start(x.longLivedCtx)
-		go x.start()
+		go x.start(x.poller)
+		if x.tcpPoller != nil {
+			x.tcpPoller.Start(obsreport.ReceiverContext(ctx, x.instanceName, tcppoller.Transport, ""))
+			go x.start(x.tcpPoller)
+		}
 		go x.server.ListenAndServe()
 		x.logger.Info("X-Ray TCP proxy server started")
 		err = nil
@@ -110,6 +133,12 @@ func (x *xrayReceiver) Shutdown(_ context.Context) error {
 			err = pollerErr
 		}
 
+		if x.tcpPoller != nil {
+			if tcpPollerErr := x.tcpPoller.Close(); tcpPollerErr != nil && err == nil {
+				err = tcpPollerErr
+			}
+		}
+
 		proxyErr := x.server.Close()
 		if proxyErr != nil {
 			if err == nil {
@@ -123,8 +152,8 @@ func (x *xrayReceiver) Shutdown(_ context.Context) error {
 	return err
 }
 
-func (x *xrayReceiver) start() {
-	incomingSegments := x.poller.SegmentsChan()
+func (x *xrayReceiver) start(poller udppoller.Poller) {
+	incomingSegments := poller.SegmentsChan()
 	for seg := range incomingSegments {
 		traces, totalSpansCount, err := translator.ToTraces(seg.Payload)
 		if err != nil {
@@ -15,7 +15,9 @@
 package awsxrayreceiver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -43,6 +45,7 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tcppoller"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
 
@@ -234,6 +237,65 @@ func TestSegmentsPassedToConsumer(t *testing.T) {
 	obsreporttest.CheckReceiverTracesViews(t, receiverName, udppoller.Transport, 18, 0)
 }
 
+func TestSegmentsReceivedOverTCP(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
+	defer doneFn()
+
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(defaultRegionEnvName, mockRegion)
+
+	const receiverName = "TestSegmentsReceivedOverTCP"
+
+	addr, err := findAvailableUDPAddress()
+	assert.NoError(t, err, "there should be address available")
+	tcpSegmentsAddr := testutil.GetAvailableLocalAddress(t)
+
+	sink := new(exportertest.SinkTraceExporter)
+	rcvr, err := newReceiver(
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				NameVal: receiverName,
+			},
+			NetAddr: confignet.NetAddr{
+				Endpoint:  addr,
+				Transport: udppoller.Transport,
+			},
+			TCPEndpoint: tcpSegmentsAddr,
+			ProxyServer: &proxy.Config{
+				TCPAddr: confignet.TCPAddr{
+					Endpoint: testutil.GetAvailableLocalAddress(t),
+				},
+			},
+		},
+		sink,
+		zap.NewNop(),
+	)
+	assert.NoError(t, err, "receiver should be created")
+	assert.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	content, err := ioutil.ReadFile(path.Join("../../internal/awsxray", "testdata", "ddbSample.txt"))
+	assert.NoError(t, err, "can not read raw segment")
+
+	// segments are sent on a single line over TCP
+	var segment bytes.Buffer
+	assert.NoError(t, json.Compact(&segment, content))
+
+	conn, err := net.Dial(tcppoller.Transport, tcpSegmentsAddr)
+	assert.NoError(t, err, "can not connect to the TCP endpoint")
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%s%s\n", segmentHeader, segment.String())
+	assert.NoError(t, err, "can not write segment")
+
+	testutil.WaitFor(t, func() bool {
+		return len(sink.AllTraces()) == 1
+	}, "consumer should eventually get the X-Ray span")
+
+	obsreporttest.CheckReceiverTracesViews(t, receiverName, tcppoller.Transport, 18, 0)
+}
+
 func TestTranslatorErrorsOut(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
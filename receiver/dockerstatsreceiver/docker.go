@@ -24,9 +24,10 @@ import (
 	"time"
 
 	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
 	dfilters "github.com/docker/docker/api/types/filters"
 	docker "github.com/docker/docker/client"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
 
@@ -131,7 +132,7 @@ func (dc *dockerClient) LoadContainerList(ctx context.Context) error {
 func (dc *dockerClient) FetchContainerStatsAndConvertToMetrics(
 	ctx context.Context,
 	container DockerContainer,
-) (*consumerdata.MetricsData, error) {
+) (pdata.Metrics, error) {
 	dc.logger.Debug("Fetching container stats.", zap.String("id", container.ID))
 	statsCtx, cancel := context.WithTimeout(ctx, dc.config.Timeout)
 	containerStats, err := dc.client.ContainerStats(statsCtx, container.ID, false)
@@ -151,12 +152,12 @@ func (dc *dockerClient) FetchContainerStatsAndConvertToMetrics(
 			)
 		}
 
-		return nil, err
+		return pdata.NewMetrics(), err
 	}
 
 	statsJSON, err := dc.toStatsJSON(containerStats, &container)
 	if err != nil {
-		return nil, err
+		return pdata.NewMetrics(), err
 	}
 
 	md, err := ContainerStatsToMetrics(statsJSON, &container, dc.config)
@@ -166,7 +167,7 @@ func (dc *dockerClient) FetchContainerStatsAndConvertToMetrics(
 			zap.String("id", container.ID),
 			zap.Error(err),
 		)
-		return nil, err
+		return pdata.NewMetrics(), err
 	}
 	return md, nil
 }
@@ -205,6 +206,29 @@ func (dc *dockerClient) ContainerEventLoop(ctx context.Context) {
 		{Key: "event", Value: "unpause"},
 		{Key: "event", Value: "update"},
 	}...)
+
+	dc.watchEvents(ctx, filters, func(event events.Message) {
+		switch event.Action {
+		case "destroy":
+			dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
+			dc.removeContainer(event.ID)
+		default:
+			dc.logger.Debug(
+				"Docker container update:",
+				zap.String("id", event.ID),
+				zap.String("action", event.Action),
+			)
+
+			if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
+				dc.persistContainer(container)
+			}
+		}
+	})
+}
+
+// watchEvents calls handle for every event of the daemon events stream matching filters
+// until ctx is done. The stream is resumed from the last seen event after an error.
+func (dc *dockerClient) watchEvents(ctx context.Context, filters dfilters.Args, handle func(events.Message)) {
 	lastTime := time.Now()
 
 EVENT_LOOP:
@@ -220,21 +244,7 @@ EVENT_LOOP:
 			case <-ctx.Done():
 				return
 			case event := <-eventCh:
-				switch event.Action {
-				case "destroy":
-					dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
-					dc.removeContainer(event.ID)
-				default:
-					dc.logger.Debug(
-						"Docker container update:",
-						zap.String("id", event.ID),
-						zap.String("action", event.Action),
-					)
-
-					if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
-						dc.persistContainer(container)
-					}
-				}
+				handle(event)
 
 				if event.TimeNano > lastTime.UnixNano() {
 					lastTime = time.Unix(0, event.TimeNano)
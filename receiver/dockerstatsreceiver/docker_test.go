@@ -150,7 +150,7 @@ func TestFetchingTimeouts(t *testing.T) {
 		},
 	)
 
-	assert.Nil(t, md)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
 	require.Error(t, err)
 
 	assert.Contains(t, err.Error(), expectedError)
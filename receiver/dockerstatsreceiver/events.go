@@ -0,0 +1,130 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	eventActionAttribute       = "docker.event.action"
+	eventExitCodeAttribute     = "container.exit_code"
+	eventHealthStatusAttribute = "container.health_status"
+)
+
+// containerEventFilters selects the container lifecycle events reported as logs.
+func containerEventFilters() dfilters.Args {
+	return dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "create"},
+		{Key: "event", Value: "start"},
+		{Key: "event", Value: "restart"},
+		{Key: "event", Value: "pause"},
+		{Key: "event", Value: "unpause"},
+		{Key: "event", Value: "health_status"},
+		{Key: "event", Value: "oom"},
+		{Key: "event", Value: "kill"},
+		{Key: "event", Value: "stop"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "destroy"},
+	}...)
+}
+
+// ContainerEventToLogs converts a docker container event into a log record whose resource
+// describes the container the same way the stats metrics do.
+func ContainerEventToLogs(event events.Message, config *Config) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+
+	resource := rl.Resource()
+	resource.InitEmpty()
+	attributes := resource.Attributes()
+	attributes.InsertString(conventions.AttributeContainerID, event.Actor.ID)
+	if image := event.Actor.Attributes["image"]; image != "" {
+		imageName, imageTag := parseImageName(image)
+		attributes.InsertString(conventions.AttributeContainerImage, imageName)
+		if imageTag != "" {
+			attributes.InsertString(conventions.AttributeContainerTag, imageTag)
+		}
+	}
+	if name := event.Actor.Attributes["name"]; name != "" {
+		attributes.InsertString(conventions.AttributeContainerName, name)
+	}
+
+	// Container labels are part of the actor attributes
+	for k, label := range config.ContainerLabelsToMetricLabels {
+		if v := event.Actor.Attributes[k]; v != "" {
+			attributes.UpsertString(label, v)
+		}
+	}
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	logs := ills.At(0).Logs()
+	logs.Resize(1)
+	lr := logs.At(0)
+
+	// Health checks are reported as "health_status: <status>"
+	action, status := event.Action, ""
+	if i := strings.Index(action, ":"); i != -1 {
+		action, status = action[:i], strings.TrimSpace(action[i+1:])
+	}
+
+	lr.SetTimestamp(pdata.TimestampUnixNano(event.TimeNano))
+	lr.SetName(metricPrefix + action)
+	lr.Body().SetStringVal(event.Action)
+	lr.Attributes().InsertString(eventActionAttribute, action)
+
+	severity := pdata.SeverityNumberINFO
+	switch action {
+	case "die":
+		if exitCode, err := strconv.ParseInt(event.Actor.Attributes["exitCode"], 10, 64); err == nil {
+			lr.Attributes().InsertInt(eventExitCodeAttribute, exitCode)
+			if exitCode != 0 {
+				severity = pdata.SeverityNumberWARN
+			}
+		}
+	case "oom":
+		severity = pdata.SeverityNumberERROR
+	case "health_status":
+		lr.Attributes().InsertString(eventHealthStatusAttribute, status)
+		if status == "unhealthy" {
+			severity = pdata.SeverityNumberWARN
+		}
+	}
+	lr.SetSeverityNumber(severity)
+	lr.SetSeverityText(severityText(severity))
+
+	return ld
+}
+
+func severityText(severity pdata.SeverityNumber) string {
+	switch severity {
+	case pdata.SeverityNumberWARN:
+		return "WARN"
+	case pdata.SeverityNumberERROR:
+		return "ERROR"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/events"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+)
+
+var _ component.LogsReceiver = (*EventsReceiver)(nil)
+
+// EventsReceiver reports the container lifecycle events of the Docker daemon events stream as logs.
+type EventsReceiver struct {
+	config       *Config
+	logger       *zap.Logger
+	nextConsumer consumer.LogsConsumer
+	client       *dockerClient
+	cancel       context.CancelFunc
+}
+
+// NewEventsReceiver creates a receiver reporting the container events of the
+// Docker daemon to nextConsumer. The events are watched once started.
+func NewEventsReceiver(
+	_ context.Context,
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	receiver := EventsReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+		logger:       logger,
+	}
+
+	return &receiver, nil
+}
+
+func (r *EventsReceiver) Start(ctx context.Context, host component.Host) error {
+	var err error
+	r.client, err = newDockerClient(r.config, r.logger)
+	if err != nil {
+		return err
+	}
+
+	var watchCtx context.Context
+	watchCtx, r.cancel = context.WithCancel(context.Background())
+	go r.client.watchEvents(watchCtx, containerEventFilters(), func(event events.Message) {
+		r.consumeEvent(watchCtx, event)
+	})
+
+	return nil
+}
+
+func (r *EventsReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *EventsReceiver) consumeEvent(ctx context.Context, event events.Message) {
+	if image := event.Actor.Attributes["image"]; r.client.shouldBeExcluded(image) {
+		r.logger.Debug(
+			"Not reporting container event per ExcludedImages",
+			zap.String("image", image),
+			zap.String("id", event.Actor.ID),
+		)
+		return
+	}
+
+	ld := ContainerEventToLogs(event, r.config)
+	if err := r.nextConsumer.ConsumeLogs(ctx, ld); err != nil {
+		r.logger.Error("Could not consume container event", zap.String("id", event.Actor.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestNewEventsReceiverErrors(t *testing.T) {
+	logger := zap.NewNop()
+
+	r, err := NewEventsReceiver(context.Background(), logger, &Config{}, nil)
+	assert.Nil(t, r)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+
+	r, err = NewEventsReceiver(context.Background(), logger, &Config{}, &exportertest.SinkLogsExporter{})
+	assert.Nil(t, r)
+	require.Error(t, err)
+	assert.Equal(t, "config.Endpoint must be specified", err.Error())
+}
+
+func TestEventsReceiver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/events") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		encoder := json.NewEncoder(w)
+		encoder.Encode(containerEvent("start", nil))
+		encoder.Encode(containerEvent("die", map[string]string{"image": "undesired-container", "exitCode": "1"}))
+		encoder.Encode(containerEvent("die", map[string]string{"exitCode": "1"}))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	config := &Config{
+		Endpoint:           srv.URL,
+		CollectionInterval: time.Second,
+		Timeout:            time.Second,
+		ExcludedImages:     []string{"undesired-container"},
+	}
+	sink := &exportertest.SinkLogsExporter{}
+	r, err := NewEventsReceiver(context.Background(), zap.NewNop(), config, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllLogs()) == 2
+	}, 5*time.Second, 10*time.Millisecond, "failed to receive container events")
+
+	// The excluded image event is never reported
+	time.Sleep(50 * time.Millisecond)
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "container.start", logRecord(t, logs[0]).Name())
+	assert.Equal(t, "container.die", logRecord(t, logs[1]).Name())
+}
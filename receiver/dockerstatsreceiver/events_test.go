@@ -0,0 +1,116 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func containerEvent(action string, attributes map[string]string) events.Message {
+	actorAttributes := map[string]string{
+		"image":              "nginx:1.17",
+		"name":               "my-container-name",
+		"my.container.label": "my_label_value",
+	}
+	for k, v := range attributes {
+		actorAttributes[k] = v
+	}
+	return events.Message{
+		Type:   "container",
+		Action: action,
+		Actor: events.Actor{
+			ID:         "a2596076ca048f02bcd16a8acd12a7ea2d3bc430d1cde095357239dd3925a4c3",
+			Attributes: actorAttributes,
+		},
+		TimeNano: 1600000000000000000,
+	}
+}
+
+func logRecord(t *testing.T, ld pdata.Logs) pdata.LogRecord {
+	require.Equal(t, 1, ld.LogRecordCount())
+	return ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+}
+
+func TestContainerEventToLogsResource(t *testing.T) {
+	config := &Config{
+		ContainerLabelsToMetricLabels: map[string]string{
+			"my.container.label": "my.metric.label",
+		},
+	}
+	ld := ContainerEventToLogs(containerEvent("start", nil), config)
+
+	attributes := ld.ResourceLogs().At(0).Resource().Attributes()
+	assert.Equal(t, 5, attributes.Len())
+	for k, v := range map[string]string{
+		conventions.AttributeContainerID:    "a2596076ca048f02bcd16a8acd12a7ea2d3bc430d1cde095357239dd3925a4c3",
+		conventions.AttributeContainerName:  "my-container-name",
+		conventions.AttributeContainerImage: "nginx",
+		conventions.AttributeContainerTag:   "1.17",
+		"my.metric.label":                   "my_label_value",
+	} {
+		attr, ok := attributes.Get(k)
+		require.True(t, ok, k)
+		assert.Equal(t, v, attr.StringVal(), k)
+	}
+
+	lr := logRecord(t, ld)
+	assert.Equal(t, "container.start", lr.Name())
+	assert.Equal(t, "start", lr.Body().StringVal())
+	assert.Equal(t, pdata.TimestampUnixNano(1600000000000000000), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+	assert.Equal(t, "INFO", lr.SeverityText())
+}
+
+func TestContainerEventToLogsDie(t *testing.T) {
+	lr := logRecord(t, ContainerEventToLogs(containerEvent("die", map[string]string{"exitCode": "137"}), &Config{}))
+	assert.Equal(t, "container.die", lr.Name())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	exitCode, ok := lr.Attributes().Get(eventExitCodeAttribute)
+	require.True(t, ok)
+	assert.EqualValues(t, 137, exitCode.IntVal())
+
+	lr = logRecord(t, ContainerEventToLogs(containerEvent("die", map[string]string{"exitCode": "0"}), &Config{}))
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+}
+
+func TestContainerEventToLogsOOM(t *testing.T) {
+	lr := logRecord(t, ContainerEventToLogs(containerEvent("oom", nil), &Config{}))
+	assert.Equal(t, "container.oom", lr.Name())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "ERROR", lr.SeverityText())
+}
+
+func TestContainerEventToLogsHealthStatus(t *testing.T) {
+	lr := logRecord(t, ContainerEventToLogs(containerEvent("health_status: unhealthy", nil), &Config{}))
+	assert.Equal(t, "container.health_status", lr.Name())
+	assert.Equal(t, "health_status: unhealthy", lr.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+
+	action, ok := lr.Attributes().Get(eventActionAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "health_status", action.StringVal())
+	status, ok := lr.Attributes().Get(eventHealthStatusAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "unhealthy", status.StringVal())
+
+	lr = logRecord(t, ContainerEventToLogs(containerEvent("health_status: healthy", nil), &Config{}))
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+}
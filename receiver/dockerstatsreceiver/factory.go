@@ -32,7 +32,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -62,3 +63,19 @@ func createMetricsReceiver(
 
 	return dsr, nil
 }
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	config configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	dockerConfig := config.(*Config)
+
+	der, err := NewEventsReceiver(ctx, params.Logger, dockerConfig, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	return der, nil
+}
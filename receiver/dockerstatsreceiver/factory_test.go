@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
 )
@@ -47,6 +48,10 @@ func TestCreateReceiver(t *testing.T) {
 	metricReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, config, &testbed.MockMetricConsumer{})
 	assert.NoError(t, err, "Metric receiver creation failed")
 	assert.NotNil(t, metricReceiver, "Receiver creation failed")
+
+	logsReceiver, err := factory.CreateLogsReceiver(context.Background(), params, config, &exportertest.SinkLogsExporter{})
+	assert.NoError(t, err, "Logs receiver creation failed")
+	assert.NotNil(t, logsReceiver, "Receiver creation failed")
 }
 
 func TestCreateInvalidHTTPEndpoint(t *testing.T) {
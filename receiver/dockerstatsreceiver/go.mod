@@ -3,10 +3,8 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/docker
 go 1.14
 
 require (
-	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/docker/docker v17.12.0-ce-rc1.0.20200706150819-a40b877fbb9e+incompatible
 	github.com/gobwas/glob v0.2.3
-	github.com/golang/protobuf v1.4.2
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
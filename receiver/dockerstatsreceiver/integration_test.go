@@ -206,7 +206,7 @@ func TestRemovedContainerRemovesRecordsIntegration(t *testing.T) {
 
 	// Confirm missing container paths
 	md, err := r.client.FetchContainerStatsAndConvertToMetrics(ctx, containers[0])
-	assert.Nil(t, md)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
 	require.Error(t, err)
 	assert.Equal(t, fmt.Sprintf("Error response from daemon: No such container: %s", containers[0].ID), err.Error())
 
@@ -216,3 +216,34 @@ func TestRemovedContainerRemovesRecordsIntegration(t *testing.T) {
 
 	assert.NoError(t, r.Shutdown(ctx))
 }
+
+func TestContainerEventsIntegration(t *testing.T) {
+	params, ctx, cancel := paramsAndContext(t)
+	defer cancel()
+	consumer := &exportertest.SinkLogsExporter{}
+	f, config := factory()
+	config.ExcludedImages = append(config.ExcludedImages, "!*nginx*")
+	receiver, err := f.CreateLogsReceiver(ctx, params, config, consumer)
+	require.NoError(t, err, "failed creating logs Receiver")
+
+	require.NoError(t, receiver.Start(ctx, &testHost{
+		t: t,
+	}))
+
+	d := container.New(t)
+	nginx := d.StartImage("docker.io/library/nginx:1.17", container.WithPortReady(80))
+	d.RemoveContainer(nginx)
+
+	assert.Eventuallyf(t, func() bool {
+		actions := map[string]bool{}
+		for _, ld := range consumer.AllLogs() {
+			lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+			if action, ok := lr.Attributes().Get(eventActionAttribute); ok {
+				actions[action.StringVal()] = true
+			}
+		}
+		return actions["start"] && actions["die"] && actions["destroy"]
+	}, 10*time.Second, 100*time.Millisecond, "failed to receive container events")
+
+	assert.NoError(t, receiver.Shutdown(ctx))
+}
@@ -21,12 +21,8 @@ import (
 	"strings"
 	"time"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	dtypes "github.com/docker/docker/api/types"
-	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
 
@@ -46,51 +42,68 @@ func ContainerStatsToMetrics(
 	containerStats *dtypes.StatsJSON,
 	container *DockerContainer,
 	config *Config,
-) (*consumerdata.MetricsData, error) {
-	now, _ := ptypes.TimestampProto(time.Now())
+) (pdata.Metrics, error) {
+	now := pdata.TimestampUnixNano(uint64(time.Now().UnixNano()))
 
-	var metrics []*metricspb.Metric
-	metrics = append(metrics, blockioMetrics(&containerStats.BlkioStats, now)...)
-	metrics = append(metrics, cpuMetrics(&containerStats.CPUStats, &containerStats.PreCPUStats, now, config.ProvidePerCoreCPUMetrics)...)
-	metrics = append(metrics, memoryMetrics(&containerStats.MemoryStats, now)...)
-	metrics = append(metrics, networkMetrics(&containerStats.Networks, now)...)
+	md := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	rms.Resize(1)
+	rm := rms.At(0)
 
-	if len(metrics) == 0 {
-		return nil, nil
-	}
+	resource := rm.Resource()
+	resource.InitEmpty()
+	updateResourceAttributes(resource.Attributes(), container, config)
 
-	md := &consumerdata.MetricsData{
-		Metrics: metrics,
-		Resource: &resourcepb.Resource{
-			Type: "container",
-			Labels: map[string]string{
-				"container.hostname":                container.Config.Hostname,
-				conventions.AttributeContainerID:    container.ID,
-				conventions.AttributeContainerImage: container.Config.Image,
-				conventions.AttributeContainerName:  strings.TrimPrefix(container.Name, "/"),
-			},
-		},
-	}
+	ilms := rm.InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics := ilms.At(0).Metrics()
 
-	updateConfiguredResourceLabels(md, container, config)
+	blockioMetrics(metrics, &containerStats.BlkioStats, now)
+	cpuMetrics(metrics, &containerStats.CPUStats, &containerStats.PreCPUStats, now, config.ProvidePerCoreCPUMetrics)
+	memoryMetrics(metrics, &containerStats.MemoryStats, now)
+	networkMetrics(metrics, &containerStats.Networks, now)
 
 	return md, nil
 }
 
-func updateConfiguredResourceLabels(md *consumerdata.MetricsData, container *DockerContainer, config *Config) {
+func updateResourceAttributes(attributes pdata.AttributeMap, container *DockerContainer, config *Config) {
+	imageName, imageTag := parseImageName(container.Config.Image)
+
+	attributes.InsertString("container.hostname", container.Config.Hostname)
+	attributes.InsertString(conventions.AttributeContainerID, container.ID)
+	attributes.InsertString(conventions.AttributeContainerImage, imageName)
+	if imageTag != "" {
+		attributes.InsertString(conventions.AttributeContainerTag, imageTag)
+	}
+	attributes.InsertString(conventions.AttributeContainerName, strings.TrimPrefix(container.Name, "/"))
+
 	for k, label := range config.EnvVarsToMetricLabels {
 		if v := container.EnvMap[k]; v != "" {
-			md.Resource.Labels[label] = v
+			attributes.UpsertString(label, v)
 		}
 	}
 
 	for k, label := range config.ContainerLabelsToMetricLabels {
 		if v := container.Config.Labels[k]; v != "" {
-			md.Resource.Labels[label] = v
+			attributes.UpsertString(label, v)
 		}
 	}
 }
 
+// parseImageName splits a docker image reference into its name and tag. The tag defaults
+// to "latest" and is empty for references pinned by digest.
+func parseImageName(image string) (string, string) {
+	if i := strings.Index(image, "@"); i != -1 {
+		return image[:i], ""
+	}
+
+	// A colon before the last slash separates a registry host from its port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
 type blkioStat struct {
 	name    string
 	unit    string
@@ -99,11 +112,10 @@ type blkioStat struct {
 
 // metrics for https://www.kernel.org/doc/Documentation/cgroup-v1/blkio-controller.txt
 func blockioMetrics(
+	metrics pdata.MetricSlice,
 	blkioStats *dtypes.BlkioStats,
-	ts *timestamp.Timestamp,
-) []*metricspb.Metric {
-	var metrics []*metricspb.Metric
-
+	ts pdata.TimestampUnixNano,
+) {
 	for _, blkiostat := range []blkioStat{
 		{"io_merged_recursive", "1", blkioStats.IoMergedRecursive},
 		{"io_queued_recursive", "1", blkioStats.IoQueuedRecursive},
@@ -123,41 +135,32 @@ func blockioMetrics(
 			statName := fmt.Sprintf("%s.%s", blkiostat.name, strings.ToLower(stat.Op))
 			metricName := fmt.Sprintf("blockio.%s", statName)
 			labelValues := [][]string{{strconv.FormatUint(stat.Major, 10), strconv.FormatUint(stat.Minor, 10)}}
-			metrics = append(metrics, Cumulative(metricName, []int64{int64(stat.Value)}, ts, blkiostat.unit, labelKeys, labelValues))
+			Cumulative(metrics, metricName, []int64{int64(stat.Value)}, ts, blkiostat.unit, labelKeys, labelValues)
 		}
 	}
-
-	return metrics
 }
 
 func cpuMetrics(
+	metrics pdata.MetricSlice,
 	cpuStats *dtypes.CPUStats,
 	previousCPUStats *dtypes.CPUStats,
-	ts *timestamp.Timestamp,
+	ts pdata.TimestampUnixNano,
 	providePerCoreMetrics bool,
-) []*metricspb.Metric {
-	var metrics []*metricspb.Metric
-
-	metrics = append(metrics, []*metricspb.Metric{
-		Cumulative("cpu.usage.system", []int64{int64(cpuStats.SystemUsage)}, ts, "ns", nil, nil),
-		Cumulative("cpu.usage.total", []int64{int64(cpuStats.CPUUsage.TotalUsage)}, ts, "ns", nil, nil),
-	}...)
+) {
+	Cumulative(metrics, "cpu.usage.system", []int64{int64(cpuStats.SystemUsage)}, ts, "ns", nil, nil)
+	Cumulative(metrics, "cpu.usage.total", []int64{int64(cpuStats.CPUUsage.TotalUsage)}, ts, "ns", nil, nil)
 
-	metrics = append(metrics, []*metricspb.Metric{
-		Cumulative("cpu.usage.kernelmode", []int64{int64(cpuStats.CPUUsage.UsageInKernelmode)}, ts, "ns", nil, nil),
-		Cumulative("cpu.usage.usermode", []int64{int64(cpuStats.CPUUsage.UsageInUsermode)}, ts, "ns", nil, nil),
-	}...)
+	Cumulative(metrics, "cpu.usage.kernelmode", []int64{int64(cpuStats.CPUUsage.UsageInKernelmode)}, ts, "ns", nil, nil)
+	Cumulative(metrics, "cpu.usage.usermode", []int64{int64(cpuStats.CPUUsage.UsageInUsermode)}, ts, "ns", nil, nil)
 
-	metrics = append(metrics, []*metricspb.Metric{
-		Cumulative("cpu.throttling_data.periods", []int64{int64(cpuStats.ThrottlingData.Periods)}, ts, "1", nil, nil),
-		Cumulative("cpu.throttling_data.throttled_periods", []int64{int64(cpuStats.ThrottlingData.ThrottledPeriods)}, ts, "1", nil, nil),
-		Cumulative("cpu.throttling_data.throttled_time", []int64{int64(cpuStats.ThrottlingData.ThrottledTime)}, ts, "ns", nil, nil),
-	}...)
+	Cumulative(metrics, "cpu.throttling_data.periods", []int64{int64(cpuStats.ThrottlingData.Periods)}, ts, "1", nil, nil)
+	Cumulative(metrics, "cpu.throttling_data.throttled_periods", []int64{int64(cpuStats.ThrottlingData.ThrottledPeriods)}, ts, "1", nil, nil)
+	Cumulative(metrics, "cpu.throttling_data.throttled_time", []int64{int64(cpuStats.ThrottlingData.ThrottledTime)}, ts, "ns", nil, nil)
 
-	metrics = append(metrics, GaugeF("cpu.percent", []float64{calculateCPUPercent(previousCPUStats, cpuStats)}, ts, "1", nil, nil))
+	GaugeF(metrics, "cpu.percent", []float64{calculateCPUPercent(previousCPUStats, cpuStats)}, ts, "1", nil, nil)
 
 	if !providePerCoreMetrics {
-		return metrics
+		return
 	}
 
 	percpuValues := make([]int64, 0, len(cpuStats.CPUUsage.PercpuUsage))
@@ -167,8 +170,7 @@ func cpuMetrics(
 		percpuValues = append(percpuValues, int64(v))
 		percpuLabelValues = append(percpuLabelValues, []string{fmt.Sprintf("cpu%s", strconv.Itoa(coreNum))})
 	}
-	metrics = append(metrics, Cumulative("cpu.usage.percpu", percpuValues, ts, "ns", percpuLabelKeys, percpuLabelValues))
-	return metrics
+	Cumulative(metrics, "cpu.usage.percpu", percpuValues, ts, "ns", percpuLabelKeys, percpuLabelValues)
 }
 
 // From container.calculateCPUPercentUnix()
@@ -213,16 +215,13 @@ var memoryStatsThatAreCumulative = map[string]bool{
 }
 
 func memoryMetrics(
+	metrics pdata.MetricSlice,
 	memoryStats *dtypes.MemoryStats,
-	ts *timestamp.Timestamp,
-) []*metricspb.Metric {
-	var metrics []*metricspb.Metric
-
+	ts pdata.TimestampUnixNano,
+) {
 	totalUsage := int64(memoryStats.Usage - memoryStats.Stats["total_cache"])
-	metrics = append(metrics, []*metricspb.Metric{
-		Gauge("memory.usage.limit", []int64{int64(memoryStats.Limit)}, ts, "By", nil, nil),
-		Gauge("memory.usage.total", []int64{totalUsage}, ts, "By", nil, nil),
-	}...)
+	Gauge(metrics, "memory.usage.limit", []int64{int64(memoryStats.Limit)}, ts, "By", nil, nil)
+	Gauge(metrics, "memory.usage.total", []int64{totalUsage}, ts, "By", nil, nil)
 
 	var pctUsed float64
 	if float64(memoryStats.Limit) == 0 {
@@ -231,10 +230,8 @@ func memoryMetrics(
 		pctUsed = 100.0 * (float64(memoryStats.Usage) - float64(memoryStats.Stats["cache"])) / float64(memoryStats.Limit)
 	}
 
-	metrics = append(metrics, []*metricspb.Metric{
-		GaugeF("memory.percent", []float64{pctUsed}, ts, "1", nil, nil),
-		Gauge("memory.usage.max", []int64{int64(memoryStats.MaxUsage)}, ts, "By", nil, nil),
-	}...)
+	GaugeF(metrics, "memory.percent", []float64{pctUsed}, ts, "1", nil, nil)
+	Gauge(metrics, "memory.usage.max", []int64{int64(memoryStats.MaxUsage)}, ts, "By", nil, nil)
 
 	// Sorted iteration for reproducibility, largely for testing
 	sortedNames := make([]string, 0, len(memoryStats.Stats))
@@ -247,139 +244,107 @@ func memoryMetrics(
 		v := memoryStats.Stats[statName]
 		metricName := fmt.Sprintf("memory.%s", statName)
 		if _, exists := memoryStatsThatAreCumulative[statName]; exists {
-			metrics = append(metrics, Cumulative(metricName, []int64{int64(v)}, ts, "1", nil, nil))
+			Cumulative(metrics, metricName, []int64{int64(v)}, ts, "1", nil, nil)
 		} else {
-			metrics = append(metrics, Gauge(metricName, []int64{int64(v)}, ts, "By", nil, nil))
+			Gauge(metrics, metricName, []int64{int64(v)}, ts, "By", nil, nil)
 		}
 	}
-
-	return metrics
 }
 
 func networkMetrics(
+	metrics pdata.MetricSlice,
 	networks *map[string]dtypes.NetworkStats,
-	ts *timestamp.Timestamp,
-) []*metricspb.Metric {
+	ts pdata.TimestampUnixNano,
+) {
 	if networks == nil || *networks == nil {
-		return nil
+		return
 	}
 
-	var metrics []*metricspb.Metric
-
 	labelKeys := []string{"interface"}
 	for nic, stats := range *networks {
 		labelValues := [][]string{{nic}}
 
-		metrics = append(metrics, []*metricspb.Metric{
-			Cumulative("network.io.usage.rx_bytes", []int64{int64(stats.RxBytes)}, ts, "By", labelKeys, labelValues),
-			Cumulative("network.io.usage.tx_bytes", []int64{int64(stats.TxBytes)}, ts, "By", labelKeys, labelValues),
-		}...)
-
-		metrics = append(metrics, []*metricspb.Metric{
-			Cumulative("network.io.usage.rx_dropped", []int64{int64(stats.RxDropped)}, ts, "1", labelKeys, labelValues),
-			Cumulative("network.io.usage.rx_errors", []int64{int64(stats.RxErrors)}, ts, "1", labelKeys, labelValues),
-			Cumulative("network.io.usage.rx_packets", []int64{int64(stats.RxPackets)}, ts, "1", labelKeys, labelValues),
-			Cumulative("network.io.usage.tx_dropped", []int64{int64(stats.TxDropped)}, ts, "1", labelKeys, labelValues),
-			Cumulative("network.io.usage.tx_errors", []int64{int64(stats.TxErrors)}, ts, "1", labelKeys, labelValues),
-			Cumulative("network.io.usage.tx_packets", []int64{int64(stats.TxPackets)}, ts, "1", labelKeys, labelValues),
-		}...)
-	}
+		Cumulative(metrics, "network.io.usage.rx_bytes", []int64{int64(stats.RxBytes)}, ts, "By", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.tx_bytes", []int64{int64(stats.TxBytes)}, ts, "By", labelKeys, labelValues)
 
-	return metrics
+		Cumulative(metrics, "network.io.usage.rx_dropped", []int64{int64(stats.RxDropped)}, ts, "1", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.rx_errors", []int64{int64(stats.RxErrors)}, ts, "1", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.rx_packets", []int64{int64(stats.RxPackets)}, ts, "1", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.tx_dropped", []int64{int64(stats.TxDropped)}, ts, "1", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.tx_errors", []int64{int64(stats.TxErrors)}, ts, "1", labelKeys, labelValues)
+		Cumulative(metrics, "network.io.usage.tx_packets", []int64{int64(stats.TxPackets)}, ts, "1", labelKeys, labelValues)
+	}
 }
 
-func Cumulative(name string, vals []int64, ts *timestamp.Timestamp, unit string, labelKeys []string, labelValues [][]string) *metricspb.Metric {
-	return metric(name, metricspb.MetricDescriptor_CUMULATIVE_INT64, ts, unit, labelKeys, labelValues, vals, nil)
+// Cumulative appends a monotonic cumulative int metric with one data point per value to metrics.
+func Cumulative(metrics pdata.MetricSlice, name string, vals []int64, ts pdata.TimestampUnixNano, unit string, labelKeys []string, labelValues [][]string) {
+	m := metric(metrics, name, pdata.MetricDataTypeIntSum, unit)
+	sum := m.IntSum()
+	sum.InitEmpty()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	intDataPoints(sum.DataPoints(), vals, ts, labelKeys, labelValues)
 }
 
-func Gauge(name string, vals []int64, ts *timestamp.Timestamp, unit string, labelKeys []string, labelValues [][]string) *metricspb.Metric {
-	return metric(name, metricspb.MetricDescriptor_GAUGE_INT64, ts, unit, labelKeys, labelValues, vals, nil)
+// Gauge appends an int gauge metric with one data point per value to metrics.
+func Gauge(metrics pdata.MetricSlice, name string, vals []int64, ts pdata.TimestampUnixNano, unit string, labelKeys []string, labelValues [][]string) {
+	m := metric(metrics, name, pdata.MetricDataTypeIntGauge, unit)
+	gauge := m.IntGauge()
+	gauge.InitEmpty()
+	intDataPoints(gauge.DataPoints(), vals, ts, labelKeys, labelValues)
 }
 
-func GaugeF(name string, vals []float64, ts *timestamp.Timestamp, unit string, labelKeys []string, labelValues [][]string) *metricspb.Metric {
-	return metric(name, metricspb.MetricDescriptor_GAUGE_DOUBLE, ts, unit, labelKeys, labelValues, nil, vals)
+// GaugeF appends a double gauge metric with one data point per value to metrics.
+func GaugeF(metrics pdata.MetricSlice, name string, vals []float64, ts pdata.TimestampUnixNano, unit string, labelKeys []string, labelValues [][]string) {
+	m := metric(metrics, name, pdata.MetricDataTypeDoubleGauge, unit)
+	gauge := m.DoubleGauge()
+	gauge.InitEmpty()
+
+	dps := gauge.DataPoints()
+	dps.Resize(len(vals))
+	for i, v := range vals {
+		dp := dps.At(i)
+		dp.SetTimestamp(ts)
+		dp.SetValue(v)
+		setLabels(dp.LabelsMap(), labelKeys, labelValues, i)
+	}
 }
 
 func metric(
+	metrics pdata.MetricSlice,
 	name string,
-	mdType metricspb.MetricDescriptor_Type,
-	ts *timestamp.Timestamp,
+	dataType pdata.MetricDataType,
 	unit string,
-	labelKeys []string,
-	labelValues [][]string,
-	intVals []int64,
-	floatVals []float64,
-) *metricspb.Metric {
-	metric := &metricspb.Metric{}
-	pts := points(intVals, floatVals, ts)
-	lKeys, lValues := labelKeysAndValues(labelKeys, labelValues)
-
-	metric.MetricDescriptor = &metricspb.MetricDescriptor{
-		Name:      fmt.Sprintf("%s%s", metricPrefix, name),
-		Type:      mdType,
-		Unit:      unit,
-		LabelKeys: lKeys,
-	}
-
-	metric.Timeseries = make([]*metricspb.TimeSeries, len(pts))
-	for i, p := range pts {
-		series := &metricspb.TimeSeries{
-			Points: []*metricspb.Point{p},
-		}
-
-		if len(lValues) != 0 {
-			series.LabelValues = lValues[i]
-		}
-
-		metric.Timeseries[i] = series
-	}
-
-	return metric
+) pdata.Metric {
+	metrics.Resize(metrics.Len() + 1)
+	m := metrics.At(metrics.Len() - 1)
+	m.SetName(fmt.Sprintf("%s%s", metricPrefix, name))
+	m.SetUnit(unit)
+	m.SetDataType(dataType)
+	return m
 }
 
-func points(
-	intVals []int64,
-	floatVals []float64,
-	ts *timestamp.Timestamp,
-) []*metricspb.Point {
-	if intVals != nil {
-		points := make([]*metricspb.Point, len(intVals))
-		for i, v := range intVals {
-			point := &metricspb.Point{
-				Timestamp: ts,
-				Value:     &metricspb.Point_Int64Value{Int64Value: v},
-			}
-			points[i] = point
-		}
-		return points
-	}
-
-	if floatVals != nil {
-		points := make([]*metricspb.Point, len(floatVals))
-		for i, v := range floatVals {
-			point := &metricspb.Point{
-				Timestamp: ts,
-				Value:     &metricspb.Point_DoubleValue{DoubleValue: v},
-			}
-			points[i] = point
-		}
-		return points
+func intDataPoints(
+	dps pdata.IntDataPointSlice,
+	vals []int64,
+	ts pdata.TimestampUnixNano,
+	labelKeys []string,
+	labelValues [][]string,
+) {
+	dps.Resize(len(vals))
+	for i, v := range vals {
+		dp := dps.At(i)
+		dp.SetTimestamp(ts)
+		dp.SetValue(v)
+		setLabels(dp.LabelsMap(), labelKeys, labelValues, i)
 	}
-
-	return nil
 }
 
-func labelKeysAndValues(keys []string, values [][]string) ([]*metricspb.LabelKey, [][]*metricspb.LabelValue) {
-	retKeys := make([]*metricspb.LabelKey, len(keys))
-	retValues := make([][]*metricspb.LabelValue, len(values))
-	for i, k := range keys {
-		retKeys[i] = &metricspb.LabelKey{Key: k}
+func setLabels(labels pdata.StringMap, keys []string, values [][]string, i int) {
+	if len(values) == 0 {
+		return
 	}
-	for i, vs := range values {
-		retValues[i] = make([]*metricspb.LabelValue, len(vs))
-		for j, v := range vs {
-			retValues[i][j] = &metricspb.LabelValue{Value: v, HasValue: true}
-		}
+	for j, k := range keys {
+		labels.Insert(k, values[i][j])
 	}
-	return retKeys, retValues
 }
@@ -20,17 +20,14 @@ import (
 	"path"
 	"testing"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	dtypes "github.com/docker/docker/api/types"
-	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 type Metric struct {
 	name      string
-	mtype     metricspb.MetricDescriptor_Type
+	mtype     pdata.MetricDataType
 	unit      string
 	labelKeys []string
 	values    []Value
@@ -43,139 +40,147 @@ type Value struct {
 }
 
 func metricsData(
-	ts *timestamp.Timestamp,
+	ts pdata.TimestampUnixNano,
 	resourceLabels map[string]string,
 	metrics ...Metric,
-) *consumerdata.MetricsData {
-	rlabels := mergeMaps(defaultLabels(), resourceLabels)
-	md := &consumerdata.MetricsData{
-		Resource: &resourcepb.Resource{
-			Type:   "container",
-			Labels: rlabels,
-		},
+) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+
+	rm.Resource().InitEmpty()
+	for k, v := range mergeMaps(defaultLabels(), resourceLabels) {
+		rm.Resource().Attributes().InsertString(k, v)
 	}
-
-	var mdMetrics []*metricspb.Metric
-	for _, m := range metrics {
-		labelKeys := make([]*metricspb.LabelKey, len(m.labelKeys))
-		for i, k := range m.labelKeys {
-			labelKeys[i] = &metricspb.LabelKey{Key: k}
-		}
-
-		metric := &metricspb.Metric{
-			MetricDescriptor: &metricspb.MetricDescriptor{
-				Name:      m.name,
-				Unit:      m.unit,
-				Type:      m.mtype,
-				LabelKeys: labelKeys,
-			},
-		}
-
-		var timeseries []*metricspb.TimeSeries
-		for _, v := range m.values {
-			seriesItem := &metricspb.TimeSeries{
-				Points: []*metricspb.Point{{Timestamp: ts}},
-			}
-
-			if len(v.labelValues) != 0 {
-				labelValues := make([]*metricspb.LabelValue, len(v.labelValues))
-				for i, k := range v.labelValues {
-					labelValues[i] = &metricspb.LabelValue{Value: k, HasValue: true}
+	rm.Resource().Attributes().Sort()
+
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	mdMetrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	mdMetrics.Resize(len(metrics))
+	for i, m := range metrics {
+		metric := mdMetrics.At(i)
+		metric.SetName(m.name)
+		metric.SetUnit(m.unit)
+		metric.SetDataType(m.mtype)
+
+		switch m.mtype {
+		case pdata.MetricDataTypeDoubleGauge:
+			metric.DoubleGauge().InitEmpty()
+			dps := metric.DoubleGauge().DataPoints()
+			dps.Resize(len(m.values))
+			for j, v := range m.values {
+				dps.At(j).SetTimestamp(ts)
+				dps.At(j).SetValue(v.doubleValue)
+				for k, key := range m.labelKeys {
+					dps.At(j).LabelsMap().Insert(key, v.labelValues[k])
 				}
-				seriesItem.LabelValues = labelValues
 			}
+			continue
+		case pdata.MetricDataTypeIntGauge:
+			metric.IntGauge().InitEmpty()
+		case pdata.MetricDataTypeIntSum:
+			metric.IntSum().InitEmpty()
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		}
 
-			if m.mtype == metricspb.MetricDescriptor_GAUGE_DOUBLE {
-				seriesItem.Points[0].Value = &metricspb.Point_DoubleValue{DoubleValue: v.doubleValue}
-			} else {
-				seriesItem.Points[0].Value = &metricspb.Point_Int64Value{Int64Value: v.value}
+		dps := intDataPointsOf(metric)
+		dps.Resize(len(m.values))
+		for j, v := range m.values {
+			dps.At(j).SetTimestamp(ts)
+			dps.At(j).SetValue(v.value)
+			for k, key := range m.labelKeys {
+				dps.At(j).LabelsMap().Insert(key, v.labelValues[k])
 			}
-
-			timeseries = append(timeseries, seriesItem)
 		}
-		metric.Timeseries = timeseries
-		mdMetrics = append(mdMetrics, metric)
 	}
 
-	md.Metrics = mdMetrics
 	return md
 }
 
+func intDataPointsOf(metric pdata.Metric) pdata.IntDataPointSlice {
+	if metric.DataType() == pdata.MetricDataTypeIntGauge {
+		return metric.IntGauge().DataPoints()
+	}
+	return metric.IntSum().DataPoints()
+}
+
 func defaultLabels() map[string]string {
 	return map[string]string{
 		"container.hostname":   "abcdef012345",
 		"container.id":         "a2596076ca048f02bcd16a8acd12a7ea2d3bc430d1cde095357239dd3925a4c3",
 		"container.image.name": "myImage",
+		"container.image.tag":  "latest",
 		"container.name":       "my-container-name",
 	}
 }
 
 func defaultMetrics() []Metric {
 	return []Metric{
-		{name: "container.blockio.io_service_bytes_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 56500224}}},
-		{name: "container.blockio.io_service_bytes_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 12103680}}},
-		{name: "container.blockio.io_service_bytes_recursive.sync", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 65314816}}},
-		{name: "container.blockio.io_service_bytes_recursive.async", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3289088}}},
-		{name: "container.blockio.io_service_bytes_recursive.discard", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
-		{name: "container.blockio.io_service_bytes_recursive.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 68603904}}},
-		{name: "container.blockio.io_serviced_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 985}}},
-		{name: "container.blockio.io_serviced_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2073}}},
-		{name: "container.blockio.io_serviced_recursive.sync", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2902}}},
-		{name: "container.blockio.io_serviced_recursive.async", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 156}}},
-		{name: "container.blockio.io_serviced_recursive.discard", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
-		{name: "container.blockio.io_serviced_recursive.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3058}}},
-		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 4525290000000}}},
-		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 8043152341}}},
-		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 970000000}}},
-		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 3510000000}}},
-		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.19316}}},
-		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 1026359296}}},
-		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 75915264}}},
-		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 7.396558329608582}}},
-		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 325246976}}},
-		{name: "container.memory.active_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
-		{name: "container.memory.active_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
-		{name: "container.memory.cache", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
-		{name: "container.memory.dirty", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.hierarchical_memory_limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 9223372036854771712}}},
-		{name: "container.memory.hierarchical_memsw_limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.inactive_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.inactive_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
-		{name: "container.memory.mapped_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
-		{name: "container.memory.pgfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
-		{name: "container.memory.pgmajfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
-		{name: "container.memory.pgpgin", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
-		{name: "container.memory.pgpgout", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
-		{name: "container.memory.rss", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
-		{name: "container.memory.rss_huge", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_active_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
-		{name: "container.memory.total_active_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
-		{name: "container.memory.total_cache", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
-		{name: "container.memory.total_dirty", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_inactive_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_inactive_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
-		{name: "container.memory.total_mapped_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
-		{name: "container.memory.total_pgfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
-		{name: "container.memory.total_pgmajfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
-		{name: "container.memory.total_pgpgin", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
-		{name: "container.memory.total_pgpgout", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
-		{name: "container.memory.total_rss", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
-		{name: "container.memory.total_rss_huge", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_unevictable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_writeback", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.unevictable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.writeback", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.network.io.usage.rx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2787669}}},
-		{name: "container.network.io.usage.tx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2275281}}},
-		{name: "container.network.io.usage.rx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.rx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.rx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 16598}}},
-		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
+		{name: "container.blockio.io_service_bytes_recursive.read", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 56500224}}},
+		{name: "container.blockio.io_service_bytes_recursive.write", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 12103680}}},
+		{name: "container.blockio.io_service_bytes_recursive.sync", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 65314816}}},
+		{name: "container.blockio.io_service_bytes_recursive.async", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3289088}}},
+		{name: "container.blockio.io_service_bytes_recursive.discard", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
+		{name: "container.blockio.io_service_bytes_recursive.total", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 68603904}}},
+		{name: "container.blockio.io_serviced_recursive.read", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 985}}},
+		{name: "container.blockio.io_serviced_recursive.write", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2073}}},
+		{name: "container.blockio.io_serviced_recursive.sync", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2902}}},
+		{name: "container.blockio.io_serviced_recursive.async", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 156}}},
+		{name: "container.blockio.io_serviced_recursive.discard", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
+		{name: "container.blockio.io_serviced_recursive.total", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3058}}},
+		{name: "container.cpu.usage.system", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 4525290000000}}},
+		{name: "container.cpu.usage.total", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 8043152341}}},
+		{name: "container.cpu.usage.kernelmode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 970000000}}},
+		{name: "container.cpu.usage.usermode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 3510000000}}},
+		{name: "container.cpu.throttling_data.periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.19316}}},
+		{name: "container.memory.usage.limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 1026359296}}},
+		{name: "container.memory.usage.total", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 75915264}}},
+		{name: "container.memory.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 7.396558329608582}}},
+		{name: "container.memory.usage.max", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 325246976}}},
+		{name: "container.memory.active_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
+		{name: "container.memory.active_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
+		{name: "container.memory.cache", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
+		{name: "container.memory.dirty", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.hierarchical_memory_limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 9223372036854771712}}},
+		{name: "container.memory.hierarchical_memsw_limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.inactive_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.inactive_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
+		{name: "container.memory.mapped_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
+		{name: "container.memory.pgfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
+		{name: "container.memory.pgmajfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
+		{name: "container.memory.pgpgin", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
+		{name: "container.memory.pgpgout", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
+		{name: "container.memory.rss", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
+		{name: "container.memory.rss_huge", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_active_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
+		{name: "container.memory.total_active_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
+		{name: "container.memory.total_cache", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
+		{name: "container.memory.total_dirty", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_inactive_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_inactive_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
+		{name: "container.memory.total_mapped_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
+		{name: "container.memory.total_pgfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
+		{name: "container.memory.total_pgmajfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
+		{name: "container.memory.total_pgpgin", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
+		{name: "container.memory.total_pgpgout", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
+		{name: "container.memory.total_rss", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
+		{name: "container.memory.total_rss_huge", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_unevictable", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_writeback", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.unevictable", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.writeback", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.network.io.usage.rx_bytes", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2787669}}},
+		{name: "container.network.io.usage.tx_bytes", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2275281}}},
+		{name: "container.network.io.usage.rx_dropped", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_errors", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_packets", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 16598}}},
+		{name: "container.network.io.usage.tx_dropped", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_errors", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_packets", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
 	}
 }
 
@@ -193,16 +198,18 @@ func assertMetricsDataEqual(
 	t *testing.T,
 	expected []Metric,
 	labels map[string]string,
-	actual *consumerdata.MetricsData,
+	actual pdata.Metrics,
 ) {
 	// Timestamps are generated per ContainerStatsToMetrics call so should be conserved
-	ts := actual.Metrics[0].Timeseries[0].Points[0].Timestamp
+	ts := intDataPointsOf(actual.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)).At(0).Timestamp()
 	expectedMd := metricsData(ts, labels, expected...)
 
+	// Configured labels are added in map order
+	actual.ResourceMetrics().At(0).Resource().Attributes().Sort()
+
 	// Separate for debuggability
-	assert.Equal(t, expectedMd.Node, actual.Node)
-	assert.Equal(t, expectedMd.Resource, actual.Resource)
-	assert.Equal(t, expectedMd.Metrics, actual.Metrics)
+	assert.Equal(t, expectedMd.ResourceMetrics().At(0).Resource(), actual.ResourceMetrics().At(0).Resource())
+	assert.Equal(t, expectedMd.ResourceMetrics().At(0).InstrumentationLibraryMetrics(), actual.ResourceMetrics().At(0).InstrumentationLibraryMetrics())
 	// To fully confirm
 	assert.Equal(t, expectedMd, actual)
 }
@@ -221,21 +228,20 @@ func TestZeroValueStats(t *testing.T) {
 
 	md, err := ContainerStatsToMetrics(stats, containers, config)
 	assert.Nil(t, err)
-	assert.NotNil(t, md)
 
 	metrics := []Metric{
-		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
-		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
-		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.system", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.total", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.kernelmode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.usermode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
+		{name: "container.memory.usage.limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.usage.total", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
+		{name: "container.memory.usage.max", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 	}
 	assertMetricsDataEqual(t, metrics, nil, md)
 }
@@ -278,7 +284,6 @@ func TestStatsToDefaultMetrics(t *testing.T) {
 
 	md, err := ContainerStatsToMetrics(stats, containers, config)
 	assert.Nil(t, err)
-	assert.NotNil(t, md)
 
 	assertMetricsDataEqual(t, defaultMetrics(), nil, md)
 }
@@ -292,32 +297,31 @@ func TestStatsToAllMetrics(t *testing.T) {
 
 	md, err := ContainerStatsToMetrics(stats, containers, config)
 	assert.Nil(t, err)
-	assert.NotNil(t, md)
 
 	metrics := []Metric{
-		{name: "container.blockio.io_service_bytes_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 56500224}}},
-		{name: "container.blockio.io_service_bytes_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 12103680}}},
-		{name: "container.blockio.io_service_bytes_recursive.sync", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 65314816}}},
-		{name: "container.blockio.io_service_bytes_recursive.async", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3289088}}},
-		{name: "container.blockio.io_service_bytes_recursive.discard", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
-		{name: "container.blockio.io_service_bytes_recursive.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 68603904}}},
-		{name: "container.blockio.io_serviced_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 985}}},
-		{name: "container.blockio.io_serviced_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2073}}},
-		{name: "container.blockio.io_serviced_recursive.sync", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2902}}},
-		{name: "container.blockio.io_serviced_recursive.async", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 156}}},
-		{name: "container.blockio.io_serviced_recursive.discard", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
-		{name: "container.blockio.io_serviced_recursive.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3058}}},
-		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 4525290000000}}},
-		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 8043152341}}},
-		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 970000000}}},
-		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 3510000000}}},
-		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.19316}}},
+		{name: "container.blockio.io_service_bytes_recursive.read", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 56500224}}},
+		{name: "container.blockio.io_service_bytes_recursive.write", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 12103680}}},
+		{name: "container.blockio.io_service_bytes_recursive.sync", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 65314816}}},
+		{name: "container.blockio.io_service_bytes_recursive.async", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3289088}}},
+		{name: "container.blockio.io_service_bytes_recursive.discard", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
+		{name: "container.blockio.io_service_bytes_recursive.total", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 68603904}}},
+		{name: "container.blockio.io_serviced_recursive.read", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 985}}},
+		{name: "container.blockio.io_serviced_recursive.write", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2073}}},
+		{name: "container.blockio.io_serviced_recursive.sync", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 2902}}},
+		{name: "container.blockio.io_serviced_recursive.async", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 156}}},
+		{name: "container.blockio.io_serviced_recursive.discard", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 0}}},
+		{name: "container.blockio.io_serviced_recursive.total", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"202", "0"}, value: 3058}}},
+		{name: "container.cpu.usage.system", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 4525290000000}}},
+		{name: "container.cpu.usage.total", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 8043152341}}},
+		{name: "container.cpu.usage.kernelmode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 970000000}}},
+		{name: "container.cpu.usage.usermode", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 3510000000}}},
+		{name: "container.cpu.throttling_data.periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: pdata.MetricDataTypeIntSum, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.19316}}},
 		{
 			name:      "container.cpu.usage.percpu",
-			mtype:     metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			mtype:     pdata.MetricDataTypeIntSum,
 			unit:      "ns",
 			labelKeys: []string{"core"},
 			values: []Value{
@@ -331,50 +335,50 @@ func TestStatsToAllMetrics(t *testing.T) {
 				{labelValues: []string{"cpu7"}, value: 0},
 			},
 		},
-		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 1026359296}}},
-		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 75915264}}},
-		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 7.396558329608582}}},
-		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 325246976}}},
-		{name: "container.memory.active_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
-		{name: "container.memory.active_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
-		{name: "container.memory.cache", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
-		{name: "container.memory.dirty", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.hierarchical_memory_limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 9223372036854771712}}},
-		{name: "container.memory.hierarchical_memsw_limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.inactive_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.inactive_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
-		{name: "container.memory.mapped_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
-		{name: "container.memory.pgfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
-		{name: "container.memory.pgmajfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
-		{name: "container.memory.pgpgin", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
-		{name: "container.memory.pgpgout", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
-		{name: "container.memory.rss", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
-		{name: "container.memory.rss_huge", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_active_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
-		{name: "container.memory.total_active_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
-		{name: "container.memory.total_cache", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
-		{name: "container.memory.total_dirty", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_inactive_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_inactive_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
-		{name: "container.memory.total_mapped_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
-		{name: "container.memory.total_pgfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
-		{name: "container.memory.total_pgmajfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
-		{name: "container.memory.total_pgpgin", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
-		{name: "container.memory.total_pgpgout", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
-		{name: "container.memory.total_rss", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
-		{name: "container.memory.total_rss_huge", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_unevictable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.total_writeback", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.unevictable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.memory.writeback", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
-		{name: "container.network.io.usage.rx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2787669}}},
-		{name: "container.network.io.usage.tx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2275281}}},
-		{name: "container.network.io.usage.rx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.rx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.rx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 16598}}},
-		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
-		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
+		{name: "container.memory.usage.limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 1026359296}}},
+		{name: "container.memory.usage.total", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 75915264}}},
+		{name: "container.memory.percent", mtype: pdata.MetricDataTypeDoubleGauge, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 7.396558329608582}}},
+		{name: "container.memory.usage.max", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 325246976}}},
+		{name: "container.memory.active_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
+		{name: "container.memory.active_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
+		{name: "container.memory.cache", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
+		{name: "container.memory.dirty", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.hierarchical_memory_limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 9223372036854771712}}},
+		{name: "container.memory.hierarchical_memsw_limit", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.inactive_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.inactive_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
+		{name: "container.memory.mapped_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
+		{name: "container.memory.pgfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
+		{name: "container.memory.pgmajfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
+		{name: "container.memory.pgpgin", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
+		{name: "container.memory.pgpgout", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
+		{name: "container.memory.rss", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
+		{name: "container.memory.rss_huge", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_active_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72585216}}},
+		{name: "container.memory.total_active_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40316928}}},
+		{name: "container.memory.total_cache", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 80760832}}},
+		{name: "container.memory.total_dirty", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_inactive_anon", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_inactive_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 40579072}}},
+		{name: "container.memory.total_mapped_file", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 37711872}}},
+		{name: "container.memory.total_pgfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 21714}}},
+		{name: "container.memory.total_pgmajfault", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 396}}},
+		{name: "container.memory.total_pgpgin", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 85140}}},
+		{name: "container.memory.total_pgpgout", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 47694}}},
+		{name: "container.memory.total_rss", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 72568832}}},
+		{name: "container.memory.total_rss_huge", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_unevictable", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.total_writeback", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.unevictable", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.writeback", mtype: pdata.MetricDataTypeIntGauge, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.network.io.usage.rx_bytes", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2787669}}},
+		{name: "container.network.io.usage.tx_bytes", mtype: pdata.MetricDataTypeIntSum, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2275281}}},
+		{name: "container.network.io.usage.rx_dropped", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_errors", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_packets", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 16598}}},
+		{name: "container.network.io.usage.tx_dropped", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_errors", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_packets", mtype: pdata.MetricDataTypeIntSum, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
 	}
 
 	assertMetricsDataEqual(t, metrics, nil, md)
@@ -392,7 +396,6 @@ func TestEnvVarToMetricLabels(t *testing.T) {
 
 	md, err := ContainerStatsToMetrics(stats, containers, config)
 	assert.Nil(t, err)
-	assert.NotNil(t, md)
 
 	expectedLabels := map[string]string{
 		"my.env.to.metric.label":       "my_env_var_value",
@@ -414,7 +417,6 @@ func TestContainerLabelToMetricLabels(t *testing.T) {
 
 	md, err := ContainerStatsToMetrics(stats, containers, config)
 	assert.Nil(t, err)
-	assert.NotNil(t, md)
 
 	expectedLabels := map[string]string{
 		"my.docker.to.metric.label":       "my_specified_docker_label_value",
@@ -423,3 +425,24 @@ func TestContainerLabelToMetricLabels(t *testing.T) {
 
 	assertMetricsDataEqual(t, defaultMetrics(), expectedLabels, md)
 }
+
+func TestParseImageName(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{image: "nginx", name: "nginx", tag: "latest"},
+		{image: "nginx:1.17", name: "nginx", tag: "1.17"},
+		{image: "localhost:5000/my/image", name: "localhost:5000/my/image", tag: "latest"},
+		{image: "localhost:5000/my/image:v2", name: "localhost:5000/my/image", tag: "v2"},
+		{image: "nginx@sha256:8cf1bfb43ff5d9b05af9b6b63983440f137c6a08320fa7592197c1474ef30241", name: "nginx", tag: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := parseImageName(tt.image)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
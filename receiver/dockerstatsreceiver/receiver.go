@@ -18,13 +18,14 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
@@ -111,7 +112,7 @@ func (r *Receiver) Setup() error {
 }
 
 type result struct {
-	md  *consumerdata.MetricsData
+	md  pdata.Metrics
 	err error
 }
 
@@ -142,16 +143,13 @@ func (r *Receiver) Run() error {
 	numTimeSeries := 0
 	var lastErr error
 	for result := range results {
-		var err error
-		if result.md != nil {
-			nts, np := obsreport.CountMetricPoints(*result.md)
+		err := result.err
+		if err == nil {
+			nts, np := countTimeSeriesAndPoints(result.md)
 			numTimeSeries += nts
 			numPoints += np
 
-			md := internaldata.OCToMetrics(*result.md)
-			err = r.nextConsumer.ConsumeMetrics(r.runnerCtx, md)
-		} else {
-			err = result.err
+			err = r.nextConsumer.ConsumeMetrics(r.runnerCtx, result.md)
 		}
 
 		if err != nil {
@@ -162,3 +160,64 @@ func (r *Receiver) Run() error {
 	obsreport.EndMetricsReceiveOp(c, typeStr, numPoints, numTimeSeries, lastErr)
 	return nil
 }
+
+// countTimeSeriesAndPoints returns the number of time series and data points
+// of the metrics. The points of a metric having the same labels belong to the
+// same time series.
+func countTimeSeriesAndPoints(md pdata.Metrics) (numTimeSeries int, numPoints int) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				series := make(map[string]bool)
+				for _, labels := range metricLabels(metrics.At(k)) {
+					series[labelsSignature(labels)] = true
+					numPoints++
+				}
+				numTimeSeries += len(series)
+			}
+		}
+	}
+	return numTimeSeries, numPoints
+}
+
+// metricLabels returns the labels of the data points of the metrics emitted
+// by the receiver.
+func metricLabels(m pdata.Metric) []pdata.StringMap {
+	var labels []pdata.StringMap
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if data := m.IntGauge(); !data.IsNil() {
+			dps := data.DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				labels = append(labels, dps.At(i).LabelsMap())
+			}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if data := m.IntSum(); !data.IsNil() {
+			dps := data.DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				labels = append(labels, dps.At(i).LabelsMap())
+			}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if data := m.DoubleGauge(); !data.IsNil() {
+			dps := data.DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				labels = append(labels, dps.At(i).LabelsMap())
+			}
+		}
+	}
+	return labels
+}
+
+func labelsSignature(labels pdata.StringMap) string {
+	pairs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		pairs = append(pairs, k+"="+v.Value())
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
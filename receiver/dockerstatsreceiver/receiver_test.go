@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
 )
@@ -91,3 +92,18 @@ func TestErrorsInStart(t *testing.T) {
 
 	require.Nil(t, receiver.Shutdown(context.Background()))
 }
+
+func TestCountTimeSeriesAndPoints(t *testing.T) {
+	md := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	rms.Resize(1)
+	rms.At(0).InstrumentationLibraryMetrics().Resize(1)
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	// Two points of the same series and a point of another one.
+	Cumulative(metrics, "blockio.io_serviced_recursive.read", []int64{1, 2, 3}, pdata.TimestampUnixNano(1), "1",
+		[]string{"device_minor"}, [][]string{{"0"}, {"0"}, {"1"}})
+
+	numTimeSeries, numPoints := countTimeSeriesAndPoints(md)
+	assert.Equal(t, 2, numTimeSeries)
+	assert.Equal(t, 3, numPoints)
+}
@@ -26,6 +26,7 @@ import (
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 )
@@ -73,6 +74,7 @@ type DataCollector struct {
 	metricsStore           *metricsStore
 	metadataStore          *metadataStore
 	nodeConditionsToReport []string
+	customResources        map[schema.GroupVersionResource]*customResourceMetrics
 }
 
 // newDataCollector returns a DataCollector.
@@ -145,6 +147,12 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		rm = getMetricsForHPA(o)
+	case *CustomResource:
+		rm = dc.getMetricsForCustomResource(o)
+		if len(rm) == 0 {
+			// Drop the values the object doesn't have anymore.
+			dc.RemoveFromMetricsStore(o)
+		}
 	default:
 		return
 	}
@@ -180,6 +188,8 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		km = getMetadataForHPA(o)
+	case *CustomResource:
+		km = getMetadataForCustomResource(o)
 	}
 
 	return km
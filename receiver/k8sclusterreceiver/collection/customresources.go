@@ -0,0 +1,319 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+// CustomResourceConfig describes the metrics to report for the objects of a
+// custom resource. The values are extracted from the objects with JSONPath
+// expressions, using the syntax of `kubectl get -o jsonpath`.
+type CustomResourceConfig struct {
+	// Group of the custom resource, e.g. argoproj.io.
+	Group string `mapstructure:"group"`
+	// Version of the custom resource, e.g. v1alpha1.
+	Version string `mapstructure:"version"`
+	// Resource is the plural name of the custom resource, e.g. rollouts.
+	Resource string `mapstructure:"resource"`
+	// Metrics to report for every object of the custom resource.
+	Metrics []CustomResourceMetricConfig `mapstructure:"metrics"`
+	// ResourceAttributes maps the keys of additional resource labels to the
+	// JSONPath expressions of their values.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+}
+
+// CustomResourceMetricConfig describes a gauge reported for the objects of a
+// custom resource.
+type CustomResourceMetricConfig struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Unit        string `mapstructure:"unit"`
+	// Value is the JSONPath expression of the value of the metric. Numbers,
+	// booleans and condition statuses (True, False and Unknown) are supported.
+	Value string `mapstructure:"value"`
+	// Labels maps the keys of the metric labels to the JSONPath expressions
+	// of their values.
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+// GroupVersionResource returns the identifier of the custom resource.
+func (c CustomResourceConfig) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: c.Resource}
+}
+
+// CustomResource is an object of a custom resource watched with a dynamic
+// informer.
+type CustomResource struct {
+	*unstructured.Unstructured
+	GroupVersionResource schema.GroupVersionResource
+}
+
+// GetObjectMeta implements metav1.ObjectMetaAccessor so that custom resources
+// are keyed by UID in the metrics store like the other objects.
+func (cr *CustomResource) GetObjectMeta() v1.Object {
+	return cr.Unstructured
+}
+
+// customResourceMetrics holds the compiled expressions of a CustomResourceConfig.
+// JSONPath expressions aren't safe for concurrent use, they are evaluated by the
+// handlers of a single informer.
+type customResourceMetrics struct {
+	metrics            []*customResourceMetric
+	resourceAttributes []*jsonPathField
+}
+
+type customResourceMetric struct {
+	descriptor *metricspb.MetricDescriptor
+	value      *jsonpath.JSONPath
+	labels     []*jsonPathField
+}
+
+type jsonPathField struct {
+	key  string
+	path *jsonpath.JSONPath
+}
+
+// SetupCustomResources validates the custom resources configuration and
+// compiles its expressions.
+func (dc *DataCollector) SetupCustomResources(configs []CustomResourceConfig) error {
+	customResources := make(map[schema.GroupVersionResource]*customResourceMetrics, len(configs))
+	for _, c := range configs {
+		gvr := c.GroupVersionResource()
+		if _, ok := customResources[gvr]; ok {
+			return fmt.Errorf("custom resource %s is configured more than once", gvr)
+		}
+		crm, err := newCustomResourceMetrics(c)
+		if err != nil {
+			return fmt.Errorf("invalid custom resource %s: %w", gvr, err)
+		}
+		customResources[gvr] = crm
+	}
+
+	dc.customResources = customResources
+	return nil
+}
+
+func newCustomResourceMetrics(c CustomResourceConfig) (*customResourceMetrics, error) {
+	if c.Version == "" || c.Resource == "" {
+		return nil, errors.New("version and resource are required")
+	}
+	if len(c.Metrics) == 0 {
+		return nil, errors.New("no metrics configured")
+	}
+
+	crm := &customResourceMetrics{}
+	for _, m := range c.Metrics {
+		if m.Name == "" {
+			return nil, errors.New("metric without name")
+		}
+		value, err := parseJSONPath(m.Name, m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of metric %q: %w", m.Name, err)
+		}
+		labels, err := parseJSONPathFields(m.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label of metric %q: %w", m.Name, err)
+		}
+
+		labelKeys := make([]*metricspb.LabelKey, len(labels))
+		for i, l := range labels {
+			labelKeys[i] = &metricspb.LabelKey{Key: l.key}
+		}
+		crm.metrics = append(crm.metrics, &customResourceMetric{
+			descriptor: &metricspb.MetricDescriptor{
+				Name:        m.Name,
+				Description: m.Description,
+				Unit:        m.Unit,
+				Type:        metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				LabelKeys:   labelKeys,
+			},
+			value:  value,
+			labels: labels,
+		})
+	}
+
+	resourceAttributes, err := parseJSONPathFields(c.ResourceAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource attribute: %w", err)
+	}
+	crm.resourceAttributes = resourceAttributes
+
+	return crm, nil
+}
+
+// parseJSONPathFields parses the expressions of a map of fields, sorted by key.
+func parseJSONPathFields(expressions map[string]string) ([]*jsonPathField, error) {
+	fields := make([]*jsonPathField, 0, len(expressions))
+	for key, expression := range expressions {
+		path, err := parseJSONPath(key, expression)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		fields = append(fields, &jsonPathField{key: key, path: path})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+	return fields, nil
+}
+
+func parseJSONPath(name, expression string) (*jsonpath.JSONPath, error) {
+	if expression == "" {
+		return nil, errors.New("empty JSONPath expression")
+	}
+	path := jsonpath.New(name).AllowMissingKeys(true)
+	if err := path.Parse(expression); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// jsonPathValue returns the first value matched by path in obj, nil if there is none.
+func jsonPathValue(path *jsonpath.JSONPath, obj map[string]interface{}) interface{} {
+	results, err := path.FindResults(obj)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+	value := results[0][0]
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func (dc *DataCollector) getMetricsForCustomResource(cr *CustomResource) []*resourceMetrics {
+	crm, ok := dc.customResources[cr.GroupVersionResource]
+	if !ok {
+		return nil
+	}
+
+	var metrics []*metricspb.Metric
+	for _, m := range crm.metrics {
+		value, ok := customResourceMetricValue(jsonPathValue(m.value, cr.Object))
+		if !ok {
+			continue
+		}
+
+		labelValues := make([]*metricspb.LabelValue, len(m.labels))
+		for i, l := range m.labels {
+			labelValue, ok := customResourceLabelValue(jsonPathValue(l.path, cr.Object))
+			labelValues[i] = &metricspb.LabelValue{Value: labelValue, HasValue: ok}
+		}
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: m.descriptor,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetDoubleTimeSeriesWithLabels(value, labelValues),
+			},
+		})
+	}
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForCustomResource(cr, crm),
+			metrics:  metrics,
+		},
+	}
+}
+
+// customResourceMetricValue converts a value of an object into a metric value.
+// Condition statuses are converted as node conditions are.
+func customResourceMetricValue(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case bool:
+		if value {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f, true
+		}
+		if c, ok := nodeConditionValues[corev1.ConditionStatus(value)]; ok {
+			return float64(c), true
+		}
+	}
+	return 0, false
+}
+
+func customResourceLabelValue(v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	default:
+		return fmt.Sprint(value), true
+	}
+}
+
+func getResourceForCustomResource(cr *CustomResource, crm *customResourceMetrics) *resourcepb.Resource {
+	rType := strings.ToLower(cr.GetKind())
+	labels := map[string]string{}
+	for _, a := range crm.resourceAttributes {
+		if value, ok := customResourceLabelValue(jsonPathValue(a.path, cr.Object)); ok {
+			labels[a.key] = value
+		}
+	}
+
+	// The identifying labels can't be overridden, metadata is synced by UID.
+	labels[getResourceIDKey(rType)] = string(cr.GetUID())
+	labels[fmt.Sprintf("k8s.%s.name", rType)] = cr.GetName()
+	labels[conventions.AttributeK8sCluster] = cr.GetClusterName()
+	if cr.GetNamespace() != "" {
+		labels[conventions.AttributeK8sNamespace] = cr.GetNamespace()
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+func getMetadataForCustomResource(cr *CustomResource) map[ResourceID]*KubernetesMetadata {
+	om := v1.ObjectMeta{
+		Name:              cr.GetName(),
+		UID:               cr.GetUID(),
+		Labels:            cr.GetLabels(),
+		CreationTimestamp: cr.GetCreationTimestamp(),
+		OwnerReferences:   cr.GetOwnerReferences(),
+	}
+	return map[ResourceID]*KubernetesMetadata{
+		ResourceID(cr.GetUID()): getGenericMetadata(&om, cr.GetKind()),
+	}
+}
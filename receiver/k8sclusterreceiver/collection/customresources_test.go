@@ -0,0 +1,279 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+var rolloutsConfig = CustomResourceConfig{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+	Metrics: []CustomResourceMetricConfig{
+		{
+			Name:        "argo.rollout.available_replicas",
+			Description: "Number of available replicas of the rollout",
+			Unit:        "1",
+			Value:       "{.status.availableReplicas}",
+		},
+		{
+			Name:  "argo.rollout.paused",
+			Value: "{.spec.paused}",
+		},
+		{
+			Name:  "argo.rollout.progressing",
+			Value: `{.status.conditions[?(@.type=="Progressing")].status}`,
+			Labels: map[string]string{
+				"reason":  `{.status.conditions[?(@.type=="Progressing")].reason}`,
+				"message": `{.status.conditions[?(@.type=="Progressing")].message}`,
+			},
+		},
+		{
+			Name:  "argo.rollout.canary_weight",
+			Value: "{.status.canary.weight}",
+		},
+	},
+	ResourceAttributes: map[string]string{
+		"argo.rollout.phase": "{.status.phase}",
+		"argo.rollout.step":  "{.status.currentStepIndex}",
+	},
+}
+
+func TestCustomResourceMetrics(t *testing.T) {
+	dc := NewDataCollector(zap.NewNop(), []string{})
+	require.NoError(t, dc.SetupCustomResources([]CustomResourceConfig{rolloutsConfig}))
+
+	actualResourceMetrics := dc.getMetricsForCustomResource(newRollout("1"))
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, rm.resource, k8sType,
+		map[string]string{
+			"k8s.rollout.uid":    "test-rollout-1-uid",
+			"k8s.rollout.name":   "test-rollout-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "",
+			"argo.rollout.phase": "Healthy",
+			"argo.rollout.step":  "2",
+		},
+	)
+
+	// The canary weight isn't set.
+	require.Equal(t, 3, len(rm.metrics))
+	assertDoubleMetric(t, rm.metrics[0], "argo.rollout.available_replicas", nil, 3)
+	assert.Equal(t, "1", rm.metrics[0].MetricDescriptor.Unit)
+	assertDoubleMetric(t, rm.metrics[1], "argo.rollout.paused", nil, 0)
+	assertDoubleMetric(t, rm.metrics[2], "argo.rollout.progressing", map[string]string{
+		"message": "ReplicaSet has successfully progressed.",
+		"reason":  "NewReplicaSetAvailable",
+	}, 1)
+}
+
+func TestCustomResourceMetricsWithoutValues(t *testing.T) {
+	dc := NewDataCollector(zap.NewNop(), []string{})
+	require.NoError(t, dc.SetupCustomResources([]CustomResourceConfig{rolloutsConfig}))
+
+	rollout := newRollout("1")
+	dc.SyncMetrics(rollout)
+	require.Equal(t, 1, len(dc.CollectMetricData(time.Now())))
+
+	// Values that are no longer set aren't reported anymore.
+	delete(rollout.Object, "status")
+	delete(rollout.Object, "spec")
+	require.Nil(t, dc.getMetricsForCustomResource(rollout))
+	dc.SyncMetrics(rollout)
+	require.Equal(t, 0, len(dc.CollectMetricData(time.Now())))
+
+	// Unknown custom resources are ignored.
+	rollout = newRollout("2")
+	rollout.GroupVersionResource.Version = "v1"
+	require.Nil(t, dc.getMetricsForCustomResource(rollout))
+}
+
+func TestCustomResourceMetricValue(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected float64
+		ok       bool
+	}{
+		{int64(5), 5, true},
+		{0.25, 0.25, true},
+		{true, 1, true},
+		{false, 0, true},
+		{"1.5", 1.5, true},
+		{"True", 1, true},
+		{"False", 0, true},
+		{"Unknown", -1, true},
+		{"Healthy", 0, false},
+		{nil, 0, false},
+		{map[string]interface{}{}, 0, false},
+	}
+	for _, tt := range tests {
+		value, ok := customResourceMetricValue(tt.value)
+		assert.Equal(t, tt.ok, ok, "%v", tt.value)
+		assert.Equal(t, tt.expected, value, "%v", tt.value)
+	}
+}
+
+func TestCustomResourceMetadata(t *testing.T) {
+	rollout := newRollout("1")
+	rollout.SetLabels(map[string]string{"app": "test"})
+
+	actualMetadata := getMetadataForCustomResource(rollout)
+
+	require.Equal(t, 1, len(actualMetadata))
+	km := actualMetadata[ResourceID("test-rollout-1-uid")]
+	require.NotNil(t, km)
+	assert.Equal(t, "k8s.rollout.uid", km.resourceIDKey)
+	assert.Equal(t, map[string]string{
+		"app":                        "test",
+		"k8s.workload.kind":          "Rollout",
+		"k8s.workload.name":          "test-rollout-1",
+		"rollout.creation_timestamp": "0001-01-01T00:00:00Z",
+		"analysisrun":                "test-analysis",
+		"analysisrun_uid":            "test-analysis-uid",
+	}, km.metadata)
+}
+
+func TestSetupCustomResourcesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []CustomResourceConfig
+	}{
+		{
+			name:    "missing resource",
+			configs: []CustomResourceConfig{{Version: "v1", Metrics: rolloutsConfig.Metrics}},
+		},
+		{
+			name:    "no metrics",
+			configs: []CustomResourceConfig{{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}},
+		},
+		{
+			name:    "duplicate",
+			configs: []CustomResourceConfig{rolloutsConfig, rolloutsConfig},
+		},
+		{
+			name: "metric without name",
+			configs: []CustomResourceConfig{{
+				Version: "v1", Resource: "rollouts",
+				Metrics: []CustomResourceMetricConfig{{Value: "{.status.replicas}"}},
+			}},
+		},
+		{
+			name: "invalid value",
+			configs: []CustomResourceConfig{{
+				Version: "v1", Resource: "rollouts",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas", Value: "{.status.replicas"}},
+			}},
+		},
+		{
+			name: "missing value",
+			configs: []CustomResourceConfig{{
+				Version: "v1", Resource: "rollouts",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas"}},
+			}},
+		},
+		{
+			name: "invalid label",
+			configs: []CustomResourceConfig{{
+				Version: "v1", Resource: "rollouts",
+				Metrics: []CustomResourceMetricConfig{{
+					Name: "replicas", Value: "{.status.replicas}", Labels: map[string]string{"phase": "{.status.phase"},
+				}},
+			}},
+		},
+		{
+			name: "invalid resource attribute",
+			configs: []CustomResourceConfig{{
+				Version: "v1", Resource: "rollouts",
+				Metrics:            []CustomResourceMetricConfig{{Name: "replicas", Value: "{.status.replicas}"}},
+				ResourceAttributes: map[string]string{"phase": "{.status.phase"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := NewDataCollector(zap.NewNop(), []string{})
+			require.Error(t, dc.SetupCustomResources(tt.configs))
+		})
+	}
+}
+
+func assertDoubleMetric(t *testing.T, actualMetric *metricspb.Metric, expectedMetric string,
+	expectedLabels map[string]string, expectedValue float64) {
+	require.Equal(t, expectedMetric, actualMetric.MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, actualMetric.MetricDescriptor.Type)
+	require.Equal(t, len(expectedLabels), len(actualMetric.MetricDescriptor.LabelKeys))
+	for i, k := range actualMetric.MetricDescriptor.LabelKeys {
+		require.Equal(t, expectedLabels[k.Key], actualMetric.Timeseries[0].LabelValues[i].Value)
+	}
+	require.Equal(t, expectedValue, actualMetric.Timeseries[0].Points[0].GetDoubleValue())
+}
+
+func newRollout(id string) *CustomResource {
+	return &CustomResource{
+		GroupVersionResource: schema.GroupVersionResource{
+			Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts",
+		},
+		Unstructured: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Rollout",
+			"metadata": map[string]interface{}{
+				"name":      "test-rollout-" + id,
+				"namespace": "test-namespace",
+				"uid":       "test-rollout-" + id + "-uid",
+				"ownerReferences": []interface{}{
+					map[string]interface{}{
+						"apiVersion": "argoproj.io/v1alpha1",
+						"kind":       "AnalysisRun",
+						"name":       "test-analysis",
+						"uid":        "test-analysis-uid",
+					},
+				},
+			},
+			"spec": map[string]interface{}{
+				"paused": false,
+			},
+			"status": map[string]interface{}{
+				"availableReplicas": int64(3),
+				"currentStepIndex":  int64(2),
+				"phase":             "Healthy",
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":   "Available",
+						"status": "True",
+					},
+					map[string]interface{}{
+						"type":    "Progressing",
+						"status":  "True",
+						"reason":  "NewReplicaSetAvailable",
+						"message": "ReplicaSet has successfully progressed.",
+					},
+				},
+			},
+		}},
+	}
+}
@@ -18,9 +18,11 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"k8s.io/client-go/dynamic"
 	k8s "k8s.io/client-go/kubernetes"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
 // Config defines configuration for kubernetes cluster receiver.
@@ -36,9 +38,12 @@ type Config struct {
 	NodeConditionTypesToReport []string `mapstructure:"node_conditions_to_report"`
 	// List of exporters to which metadata from this receiver should be forwarded to.
 	MetadataExporters []string `mapstructure:"metadata_exporters"`
+	// Custom resources to watch and report metrics for.
+	CustomResources []collection.CustomResourceConfig `mapstructure:"custom_resources"`
 
 	// For mocking.
-	makeClient func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
+	makeClient        func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
+	makeDynamicClient func(apiConf k8sconfig.APIConfig) (dynamic.Interface, error)
 }
 
 func (cfg *Config) getK8sClient() (k8s.Interface, error) {
@@ -47,3 +52,10 @@ func (cfg *Config) getK8sClient() (k8s.Interface, error) {
 	}
 	return cfg.makeClient(cfg.APIConfig)
 }
+
+func (cfg *Config) getDynamicClient() (dynamic.Interface, error) {
+	if cfg.makeDynamicClient == nil {
+		cfg.makeDynamicClient = k8sconfig.MakeDynamicClient
+	}
+	return cfg.makeDynamicClient(cfg.APIConfig)
+}
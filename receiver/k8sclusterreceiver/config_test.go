@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/config/configtest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -57,6 +58,31 @@ func TestLoadConfig(t *testing.T) {
 			CollectionInterval:         30 * time.Second,
 			NodeConditionTypesToReport: []string{"Ready", "MemoryPressure"},
 			MetadataExporters:          []string{"exampleexporter"},
+			CustomResources: []collection.CustomResourceConfig{
+				{
+					Group:    "argoproj.io",
+					Version:  "v1alpha1",
+					Resource: "rollouts",
+					Metrics: []collection.CustomResourceMetricConfig{
+						{
+							Name:        "argo.rollout.available_replicas",
+							Description: "Number of available replicas of the rollout",
+							Unit:        "1",
+							Value:       "{.status.availableReplicas}",
+						},
+						{
+							Name:  "argo.rollout.progressing",
+							Value: `{.status.conditions[?(@.type=="Progressing")].status}`,
+							Labels: map[string]string{
+								"reason": `{.status.conditions[?(@.type=="Progressing")].reason}`,
+							},
+						},
+					},
+					ResourceAttributes: map[string]string{
+						"argo.rollout.phase": "{.status.phase}",
+					},
+				},
+			},
 			APIConfig: k8sconfig.APIConfig{
 				AuthType: k8sconfig.AuthTypeServiceAccount,
 			},
@@ -0,0 +1,112 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	eventNameAttribute            = "k8s.event.name"
+	eventUIDAttribute             = "k8s.event.uid"
+	eventReasonAttribute          = "k8s.event.reason"
+	eventCountAttribute           = "k8s.event.count"
+	eventSourceComponentAttribute = "k8s.event.source.component"
+	objectKindAttribute           = "k8s.object.kind"
+	objectNameAttribute           = "k8s.object.name"
+	objectUIDAttribute            = "k8s.object.uid"
+)
+
+// eventToLogs converts a Kubernetes event into a log record whose body is the
+// event message and whose attributes describe the event and its involved object.
+func eventToLogs(event *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	rl.Resource().InitEmpty()
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	logs := ills.At(0).Logs()
+	logs.Resize(1)
+	lr := logs.At(0)
+
+	lr.SetTimestamp(pdata.TimestampUnixNano(uint64(eventTimestamp(event).UnixNano())))
+	lr.SetName(event.Reason)
+	lr.Body().SetStringVal(event.Message)
+	lr.SetSeverityText(event.Type)
+	lr.SetSeverityNumber(eventSeverity(event.Type))
+
+	attributes := lr.Attributes()
+	attributes.InsertString(eventNameAttribute, event.Name)
+	attributes.InsertString(eventUIDAttribute, string(event.UID))
+	attributes.InsertString(eventReasonAttribute, event.Reason)
+	attributes.InsertInt(eventCountAttribute, int64(eventCount(event)))
+	if event.Source.Component != "" {
+		attributes.InsertString(eventSourceComponentAttribute, event.Source.Component)
+	}
+
+	object := event.InvolvedObject
+	attributes.InsertString(objectKindAttribute, object.Kind)
+	attributes.InsertString(objectNameAttribute, object.Name)
+	attributes.InsertString(objectUIDAttribute, string(object.UID))
+	if object.Namespace != "" {
+		attributes.InsertString(conventions.AttributeK8sNamespace, object.Namespace)
+	}
+
+	return ld
+}
+
+// eventTimestamp returns the time of the last occurrence of an event. Events
+// created with the events.k8s.io API only set the event time and the series.
+func eventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// eventCount returns the number of occurrences of an event.
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+func eventSeverity(eventType string) pdata.SeverityNumber {
+	switch eventType {
+	case corev1.EventTypeNormal:
+		return pdata.SeverityNumberINFO
+	case corev1.EventTypeWarning:
+		return pdata.SeverityNumberWARN
+	default:
+		return pdata.SeverityNumberUNDEFINED
+	}
+}
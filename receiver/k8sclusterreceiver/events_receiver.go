@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var _ component.LogsReceiver = (*eventsReceiver)(nil)
+
+// eventsReceiver reports the Kubernetes events as logs.
+type eventsReceiver struct {
+	resourceWatcher *resourceWatcher
+
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.LogsConsumer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	// Events last seen before startTime were already reported or are
+	// stale, the informer lists them when it starts.
+	startTime time.Time
+}
+
+func (er *eventsReceiver) Start(ctx context.Context, host component.Host) error {
+	er.ctx, er.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, transport, er.config.Name()))
+	// Event timestamps have a precision of a second.
+	er.startTime = time.Now().Truncate(time.Second)
+
+	go func() {
+		er.logger.Info("Starting events informer and wait for initial cache sync.")
+		er.resourceWatcher.startWatchingResources(er.ctx)
+
+		<-er.resourceWatcher.timedContextForInitialSync.Done()
+
+		if er.resourceWatcher.timedContextForInitialSync.Err() == context.DeadlineExceeded {
+			er.resourceWatcher.initialSyncTimedOut.Store(true)
+			er.logger.Error("Timed out waiting for initial cache sync.")
+			host.ReportFatalError(fmt.Errorf("failed to start receiver: %s", er.config.NameVal))
+			return
+		}
+
+		er.logger.Info("Completed syncing events informer cache.")
+		er.resourceWatcher.initialSyncDone.Store(true)
+	}()
+
+	return nil
+}
+
+func (er *eventsReceiver) Shutdown(context.Context) error {
+	if er.cancel != nil {
+		er.cancel()
+	}
+	return nil
+}
+
+func (er *eventsReceiver) consumeEvent(event *corev1.Event) {
+	if eventTimestamp(event).Before(er.startTime) {
+		return
+	}
+
+	if err := er.consumer.ConsumeLogs(er.ctx, eventToLogs(event)); err != nil {
+		er.logger.Error("Could not consume Kubernetes event",
+			zap.String("namespace", event.Namespace), zap.String("name", event.Name), zap.Error(err))
+	}
+}
+
+func newEventsReceiver(
+	logger *zap.Logger, config *Config, consumer consumer.LogsConsumer,
+	client kubernetes.Interface) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	er := &eventsReceiver{
+		logger:   logger,
+		config:   config,
+		consumer: consumer,
+	}
+	er.resourceWatcher = newEventWatcher(logger, client, defaultInitialSyncTimeout, er.consumeEvent)
+	return er, nil
+}
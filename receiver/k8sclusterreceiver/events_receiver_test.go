@@ -0,0 +1,103 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEventsReceiver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	consumer := &exportertest.SinkLogsExporter{}
+
+	r, err := newEventsReceiver(zap.NewNop(), &Config{}, consumer, client)
+	require.NoError(t, err)
+	er := r.(*eventsReceiver)
+
+	// Stale events listed by the informer when it starts aren't reported.
+	createEvent(t, client, newEvent("stale", "Scheduled", corev1.EventTypeNormal, time.Now().Add(-time.Hour)))
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return er.resourceWatcher.initialSyncDone.Load()
+	}, 10*time.Second, 100*time.Millisecond, "informer not synced")
+
+	backOff := newEvent("backoff", "BackOff", corev1.EventTypeWarning, time.Now())
+	backOff.ResourceVersion = "1"
+	createEvent(t, client, backOff)
+	createEvent(t, client, newEvent("evicted", "Evicted", corev1.EventTypeWarning, time.Now()))
+
+	require.Eventually(t, func() bool {
+		return consumer.LogRecordsCount() == 2
+	}, 10*time.Second, 100*time.Millisecond, "events not collected")
+
+	// The fake clientset doesn't pass on updates, mock a new occurrence of the
+	// event and a resync.
+	recurring := backOff.DeepCopy()
+	recurring.ResourceVersion = "2"
+	recurring.Count++
+	er.resourceWatcher.onEventUpdate(backOff, recurring)
+	er.resourceWatcher.onEventUpdate(recurring, recurring)
+	require.Equal(t, 3, consumer.LogRecordsCount())
+
+	reasons := map[string]int{}
+	for _, ld := range consumer.AllLogs() {
+		lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+		reasons[lr.Name()]++
+	}
+	assert.Equal(t, map[string]int{"BackOff": 2, "Evicted": 1}, reasons)
+
+	require.NoError(t, r.Shutdown(ctx))
+}
+
+func TestEventsReceiverConsumerError(t *testing.T) {
+	core, observedLogs := observer.New(zap.ErrorLevel)
+	consumer := &exportertest.SinkLogsExporter{}
+	consumer.SetConsumeLogError(errors.New("failed"))
+
+	r, err := newEventsReceiver(zap.New(core), &Config{}, consumer, fake.NewSimpleClientset())
+	require.NoError(t, err)
+	er := r.(*eventsReceiver)
+	er.ctx = context.Background()
+
+	er.consumeEvent(newEvent("backoff", "BackOff", corev1.EventTypeWarning, time.Now()))
+	require.Equal(t, 1, observedLogs.Len())
+}
+
+func TestEventsReceiverNilConsumer(t *testing.T) {
+	r, err := newEventsReceiver(zap.NewNop(), &Config{}, nil, fake.NewSimpleClientset())
+	require.Equal(t, componenterror.ErrNilNextConsumer, err)
+	require.Nil(t, r)
+}
+
+func createEvent(t *testing.T, client *fake.Clientset, event *corev1.Event) {
+	_, err := client.CoreV1().Events(event.Namespace).Create(context.Background(), event, v1.CreateOptions{})
+	require.NoError(t, err, "error creating event")
+}
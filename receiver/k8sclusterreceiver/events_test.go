@@ -0,0 +1,108 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEvent(name, reason, eventType string, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+			UID:       "test-event-uid",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "test-pod",
+			Namespace: "test-namespace",
+			UID:       "test-pod-uid",
+		},
+		Reason:        reason,
+		Message:       "0/3 nodes are available: 3 Insufficient cpu.",
+		Type:          eventType,
+		Count:         3,
+		LastTimestamp: v1.NewTime(lastTimestamp),
+		Source:        corev1.EventSource{Component: "default-scheduler"},
+	}
+}
+
+func TestEventToLogs(t *testing.T) {
+	timestamp := time.Unix(1601571318, 0)
+	ld := eventToLogs(newEvent("test-pod.163a5d4c0c4b1a1e", "FailedScheduling", corev1.EventTypeWarning, timestamp))
+
+	require.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	assert.False(t, rl.Resource().IsNil())
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+
+	assert.Equal(t, "FailedScheduling", lr.Name())
+	assert.Equal(t, "0/3 nodes are available: 3 Insufficient cpu.", lr.Body().StringVal())
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(timestamp.UnixNano())), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "Warning", lr.SeverityText())
+
+	attributes := map[string]interface{}{}
+	lr.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		if v.Type() == pdata.AttributeValueINT {
+			attributes[k] = v.IntVal()
+			return
+		}
+		attributes[k] = v.StringVal()
+	})
+	assert.Equal(t, map[string]interface{}{
+		"k8s.event.name":             "test-pod.163a5d4c0c4b1a1e",
+		"k8s.event.uid":              "test-event-uid",
+		"k8s.event.reason":           "FailedScheduling",
+		"k8s.event.count":            int64(3),
+		"k8s.event.source.component": "default-scheduler",
+		"k8s.object.kind":            "Pod",
+		"k8s.object.name":            "test-pod",
+		"k8s.object.uid":             "test-pod-uid",
+		"k8s.namespace.name":         "test-namespace",
+	}, attributes)
+}
+
+func TestEventSeverity(t *testing.T) {
+	assert.Equal(t, pdata.SeverityNumberINFO, eventSeverity(corev1.EventTypeNormal))
+	assert.Equal(t, pdata.SeverityNumberWARN, eventSeverity(corev1.EventTypeWarning))
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, eventSeverity("Unknown"))
+}
+
+func TestEventTimestampAndCount(t *testing.T) {
+	created := time.Unix(1601571000, 0)
+	eventTime := time.Unix(1601571100, 0)
+	lastObserved := time.Unix(1601571200, 0)
+
+	event := &corev1.Event{ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(created)}}
+	assert.Equal(t, created, eventTimestamp(event))
+	assert.Equal(t, int32(1), eventCount(event))
+
+	// Events of the events.k8s.io API
+	event.EventTime = v1.NewMicroTime(eventTime)
+	assert.Equal(t, eventTime, eventTimestamp(event))
+
+	event.Series = &corev1.EventSeries{Count: 5, LastObservedTime: v1.NewMicroTime(lastObserved)}
+	assert.Equal(t, lastObserved, eventTimestamp(event))
+	assert.Equal(t, int32(5), eventCount(event))
+}
@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
@@ -59,7 +60,27 @@ func createMetricsReceiver(
 	if err != nil {
 		return nil, err
 	}
-	return newReceiver(params.Logger, rCfg, consumer, k8sClient)
+
+	// The dynamic client is only needed to watch custom resources.
+	var dynamicClient dynamic.Interface
+	if len(rCfg.CustomResources) > 0 {
+		if dynamicClient, err = rCfg.getDynamicClient(); err != nil {
+			return nil, err
+		}
+	}
+	return newReceiver(params.Logger, rCfg, consumer, k8sClient, dynamicClient)
+}
+
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	rCfg := cfg.(*Config)
+
+	k8sClient, err := rCfg.getK8sClient()
+	if err != nil {
+		return nil, err
+	}
+	return newEventsReceiver(params.Logger, rCfg, consumer, k8sClient)
 }
 
 // NewFactory creates a factory for k8s_cluster receiver.
@@ -67,5 +88,6 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
@@ -21,12 +21,18 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
 func TestFactory(t *testing.T) {
@@ -83,6 +89,80 @@ func TestFactory(t *testing.T) {
 	require.Error(t, r.Start(context.Background(), nopHostWithExporters{}))
 }
 
+func TestFactoryWithCustomResources(t *testing.T) {
+	f := NewFactory()
+	rCfg := f.CreateDefaultConfig().(*Config)
+	rCfg.makeClient = func(apiConf k8sconfig.APIConfig) (kubernetes.Interface, error) {
+		return fake.NewSimpleClientset(), nil
+	}
+	rCfg.CustomResources = []collection.CustomResourceConfig{
+		{
+			Group:    "keda.sh",
+			Version:  "v1alpha1",
+			Resource: "scaledobjects",
+			Metrics: []collection.CustomResourceMetricConfig{
+				{Name: "keda.scaledobject.active", Value: `{.status.conditions[?(@.type=="Active")].status}`},
+			},
+		},
+	}
+
+	// Fails with bad K8s Config.
+	r, err := f.CreateMetricsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkMetricsExporter{},
+	)
+	require.Error(t, err)
+	require.Nil(t, r)
+
+	// Override for tests.
+	rCfg.makeDynamicClient = func(apiConf k8sconfig.APIConfig) (dynamic.Interface, error) {
+		return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil
+	}
+	r, err = f.CreateMetricsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkMetricsExporter{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	// Fails with invalid custom resources.
+	rCfg.CustomResources[0].Metrics[0].Value = "{.status"
+	r, err = f.CreateMetricsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkMetricsExporter{},
+	)
+	require.Error(t, err)
+	require.Nil(t, r)
+}
+
+func TestFactoryLogsReceiver(t *testing.T) {
+	f := NewFactory()
+	rCfg := f.CreateDefaultConfig().(*Config)
+
+	// Fails with bad K8s Config.
+	r, err := f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.Error(t, err)
+	require.Nil(t, r)
+
+	// Override for tests.
+	rCfg.makeClient = func(apiConf k8sconfig.APIConfig) (kubernetes.Interface, error) {
+		return fake.NewSimpleClientset(), nil
+	}
+	r, err = f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(ctx))
+}
+
 // nopHostWithExporters mocks a receiver.ReceiverHost for test purposes.
 type nopHostWithExporters struct {
 }
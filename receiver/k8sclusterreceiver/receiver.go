@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -112,8 +113,11 @@ func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context) {
 // newReceiver creates the Kubernetes cluster receiver with the given configuration.
 func newReceiver(
 	logger *zap.Logger, config *Config, consumer consumer.MetricsConsumer,
-	client kubernetes.Interface) (component.MetricsReceiver, error) {
+	client kubernetes.Interface, dynamicClient dynamic.Interface) (component.MetricsReceiver, error) {
 	resourceWatcher := newResourceWatcher(logger, client, config.NodeConditionTypesToReport, defaultInitialSyncTimeout)
+	if err := resourceWatcher.setupCustomResources(dynamicClient, config.CustomResources); err != nil {
+		return nil, err
+	}
 
 	return &kubernetesReceiver{
 		resourceWatcher: resourceWatcher,
@@ -25,10 +25,19 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
 )
 
@@ -113,6 +122,111 @@ func TestReceiverWithManyResources(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
+func TestReceiverWithCustomResources(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	consumer := &exportertest.SinkMetricsExporter{}
+
+	r, err := setupReceiver(client, consumer, 10*time.Second)
+	require.NoError(t, err)
+
+	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      "test-certificate",
+			"namespace": "test-namespace",
+			"uid":       "test-certificate-uid",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), certificate)
+	require.NoError(t, r.resourceWatcher.setupCustomResources(dynamicClient, []collection.CustomResourceConfig{
+		{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+			Metrics: []collection.CustomResourceMetricConfig{
+				{Name: "cert_manager.certificate.ready", Value: `{.status.conditions[?(@.type=="Ready")].status}`},
+			},
+		},
+	}))
+
+	createNodes(t, client, 1)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+
+	// Expects metric data from the node and the certificate.
+	require.Eventually(t, func() bool {
+		return consumer.MetricsCount() == 2
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+
+	var names []string
+	for _, md := range consumer.AllMetrics() {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ms := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+			for j := 0; j < ms.Len(); j++ {
+				names = append(names, ms.At(j).Name())
+			}
+		}
+	}
+	require.Contains(t, names, "cert_manager.certificate.ready")
+
+	r.Shutdown(ctx)
+}
+
+func TestReceiverWithMissingCustomResource(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	consumer := &exportertest.SinkMetricsExporter{}
+
+	r, err := setupReceiver(client, consumer, 1*time.Second)
+	require.NoError(t, err)
+	observedLogger, logs := observer.New(zapcore.WarnLevel)
+	r.resourceWatcher.logger = zap.New(observedLogger)
+
+	// The cluster doesn't serve the custom resource, so its informer never syncs.
+	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicClient.PrependReactor("list", gvr.Resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(gvr.GroupResource(), "")
+	})
+	require.NoError(t, r.resourceWatcher.setupCustomResources(dynamicClient, []collection.CustomResourceConfig{
+		{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+			Metrics: []collection.CustomResourceMetricConfig{
+				{Name: "cert_manager.certificate.ready", Value: `{.status.conditions[?(@.type=="Ready")].status}`},
+			},
+		},
+	}))
+
+	createNodes(t, client, 1)
+
+	ctx := context.Background()
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, r.Start(ctx, host))
+
+	// The built-in resources are still reported.
+	require.Eventually(t, func() bool {
+		return consumer.MetricsCount() == 1
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+
+	receivedError, _ := host.WaitForFatalError(100 * time.Millisecond)
+	require.False(t, receivedError)
+	require.Equal(t, 1, logs.FilterField(zap.String("resource", gvr.String())).Len())
+
+	r.Shutdown(ctx)
+}
+
 var numCalls *atomic.Int32
 var consumeMetadataInvocation = func() {
 	if numCalls != nil {
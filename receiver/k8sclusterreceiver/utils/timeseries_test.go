@@ -37,3 +37,13 @@ func TestGetInt64TimeSeriesWithLabels(t *testing.T) {
 	require.Equal(t, dpVal, ts.Points[0].GetInt64Value())
 	require.Equal(t, labelVals, ts.LabelValues)
 }
+
+func TestGetDoubleTimeSeriesWithLabels(t *testing.T) {
+	dpVal := 0.5
+	labelVals := []*v1.LabelValue{{Value: "value1"}, {Value: "value2"}}
+
+	ts := GetDoubleTimeSeriesWithLabels(dpVal, labelVals)
+
+	require.Equal(t, dpVal, ts.Points[0].GetDoubleValue())
+	require.Equal(t, labelVals, ts.LabelValues)
+}
@@ -28,7 +28,11 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -40,9 +44,11 @@ import (
 type resourceWatcher struct {
 	client                     kubernetes.Interface
 	sharedInformerFactory      informers.SharedInformerFactory
+	dynamicInformerFactory     dynamicinformer.DynamicSharedInformerFactory
 	dataCollector              *collection.DataCollector
 	logger                     *zap.Logger
 	metadataConsumers          []metadataConsumer
+	eventConsumer              eventConsumer
 	initialTimeout             time.Duration
 	timedContextForInitialSync context.Context
 	initialSyncDone            *atomic.Bool
@@ -51,6 +57,8 @@ type resourceWatcher struct {
 
 type metadataConsumer func(metadata []*collection.MetadataUpdate) error
 
+type eventConsumer func(event *corev1.Event)
+
 // newResourceWatcher creates a Kubernetes resource watcher.
 func newResourceWatcher(
 	logger *zap.Logger, client kubernetes.Interface,
@@ -69,6 +77,30 @@ func newResourceWatcher(
 	return rw
 }
 
+// newEventWatcher creates a resourceWatcher that only watches the Kubernetes
+// events, passing the new ones and their new occurrences to consume.
+func newEventWatcher(
+	logger *zap.Logger, client kubernetes.Interface,
+	initialSyncTimeout time.Duration, consume eventConsumer) *resourceWatcher {
+	rw := &resourceWatcher{
+		client:              client,
+		logger:              logger,
+		eventConsumer:       consume,
+		initialSyncDone:     atomic.NewBool(false),
+		initialSyncTimedOut: atomic.NewBool(false),
+		initialTimeout:      initialSyncTimeout,
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(rw.client, 0)
+	factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rw.onEventAdd,
+		UpdateFunc: rw.onEventUpdate,
+	})
+	rw.sharedInformerFactory = factory
+
+	return rw
+}
+
 func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	factory := informers.NewSharedInformerFactoryWithOptions(rw.client, 0)
 
@@ -94,6 +126,60 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	rw.sharedInformerFactory = factory
 }
 
+// setupCustomResources adds a dynamic informer for each custom resource to
+// report metrics for.
+func (rw *resourceWatcher) setupCustomResources(
+	client dynamic.Interface, configs []collection.CustomResourceConfig) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	if err := rw.dataCollector.SetupCustomResources(configs); err != nil {
+		return fmt.Errorf("failed to configure custom_resources: %w", err)
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	for _, c := range configs {
+		gvr := c.GroupVersionResource()
+		factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if cr, ok := customResource(gvr, obj); ok {
+					rw.onAdd(cr)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldCR, ok := customResource(gvr, oldObj)
+				if !ok {
+					return
+				}
+				if newCR, ok := customResource(gvr, newObj); ok {
+					rw.onUpdate(oldCR, newCR)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if cr, ok := customResource(gvr, obj); ok {
+					rw.onDelete(cr)
+				}
+			},
+		})
+	}
+	rw.dynamicInformerFactory = factory
+
+	return nil
+}
+
+// customResource wraps the objects of dynamic informers, which don't carry
+// the resource they belong to.
+func customResource(gvr schema.GroupVersionResource, obj interface{}) (*collection.CustomResource, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false
+	}
+	return &collection.CustomResource{Unstructured: u, GroupVersionResource: gvr}, true
+}
+
 // startWatchingResources starts up all informers.
 func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 	var cancel context.CancelFunc
@@ -101,6 +187,9 @@ func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 
 	// Start off individual informers in the factory.
 	rw.sharedInformerFactory.Start(ctx.Done())
+	if rw.dynamicInformerFactory != nil {
+		rw.dynamicInformerFactory.Start(ctx.Done())
+	}
 
 	// Ensure cache is synced with initial state, once informers are started up.
 	// Note that the event handler can start receiving events as soon as the informers
@@ -109,7 +198,30 @@ func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 	// This method will block either till the timeout set on the context, until
 	// the initial sync is complete or the parent context is cancelled.
 	rw.sharedInformerFactory.WaitForCacheSync(rw.timedContextForInitialSync.Done())
+	cancel()
+
+	if rw.dynamicInformerFactory != nil &&
+		rw.timedContextForInitialSync.Err() != context.DeadlineExceeded {
+		rw.waitForCustomResourcesSync(ctx)
+	}
+}
+
+// waitForCustomResourcesSync waits for the initial sync of the custom resource
+// informers. Unlike the built-in resources, a custom resource may not be served
+// by the cluster (e.g. its CRD isn't installed), so an informer that doesn't
+// sync in time only gets a warning. It keeps retrying in the background and its
+// resources are reported once it syncs.
+func (rw *resourceWatcher) waitForCustomResourcesSync(ctx context.Context) {
+	timedCtx, cancel := context.WithTimeout(ctx, rw.initialTimeout)
 	defer cancel()
+
+	for gvr, synced := range rw.dynamicInformerFactory.WaitForCacheSync(timedCtx.Done()) {
+		if !synced {
+			rw.logger.Warn("Timed out waiting for initial cache sync of custom resource.",
+				zap.String("resource", gvr.String()),
+			)
+		}
+	}
 }
 
 // setupInformers adds event handlers to informers and setups a metadataStore.
@@ -156,6 +268,30 @@ func (rw *resourceWatcher) onUpdate(oldObj, newObj interface{}) {
 	rw.syncMetadataUpdate(oldMetadata, newMetadata)
 }
 
+func (rw *resourceWatcher) onEventAdd(obj interface{}) {
+	if event, ok := obj.(*corev1.Event); ok {
+		rw.eventConsumer(event)
+	}
+}
+
+func (rw *resourceWatcher) onEventUpdate(oldObj, newObj interface{}) {
+	oldEvent, ok := oldObj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	newEvent, ok := newObj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	// Recurring events are updated with a new count and timestamp, resyncs
+	// deliver the same version of the event again.
+	if oldEvent.ResourceVersion == newEvent.ResourceVersion {
+		return
+	}
+	rw.eventConsumer(newEvent)
+}
+
 func (rw *resourceWatcher) waitForInitialInformerSync() {
 	if rw.initialSyncDone.Load() || rw.initialSyncTimedOut.Load() {
 		return
@@ -45,6 +45,18 @@ type Config struct {
 	// "container", "pod", "node" and "volume" are the only valid groups.
 	MetricGroupsToCollect []kubelet.MetricGroup `mapstructure:"metric_groups"`
 
+	// PrometheusEndpoints lists the kubelet endpoints exposing Prometheus metrics to scrape
+	// in addition to /stats/summary. "cadvisor" and "resource" are the only valid endpoints.
+	PrometheusEndpoints []kubelet.PrometheusEndpoint `mapstructure:"prometheus_endpoints"`
+
+	// CollectPodStatusMetrics enables the container restart and state metrics taken
+	// from the /pods endpoint.
+	CollectPodStatusMetrics bool `mapstructure:"collect_pod_status_metrics"`
+
+	// CollectUtilizationMetrics enables the CPU and memory utilization metrics of containers
+	// and pods relative to the requests and limits of the pod specs taken from the /pods endpoint.
+	CollectUtilizationMetrics bool `mapstructure:"collect_utilization_metrics"`
+
 	// Configuration of the Kubernetes API client.
 	K8sAPIConfig *k8sconfig.APIConfig `mapstructure:"k8s_api_config"`
 }
@@ -62,6 +74,12 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		return nil, err
 	}
 
+	for _, endpoint := range cfg.PrometheusEndpoints {
+		if !kubelet.ValidPrometheusEndpoints[endpoint] {
+			return nil, fmt.Errorf("invalid entry in prometheus_endpoints: %q", endpoint)
+		}
+	}
+
 	var k8sAPIClient kubernetes.Interface
 	if cfg.K8sAPIConfig != nil {
 		k8sAPIClient, err = k8sconfig.MakeClient(*cfg.K8sAPIConfig)
@@ -75,6 +93,9 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		collectionInterval:    cfg.CollectionInterval,
 		extraMetadataLabels:   cfg.ExtraMetadataLabels,
 		metricGroupsToCollect: mgs,
+		prometheusEndpoints:   cfg.PrometheusEndpoints,
+		podStatusMetrics:      cfg.CollectPodStatusMetrics,
+		utilizationMetrics:    cfg.CollectUtilizationMetrics,
 		k8sAPIClient:          k8sAPIClient,
 	}, nil
 }
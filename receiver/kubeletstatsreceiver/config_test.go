@@ -174,12 +174,38 @@ func TestLoadConfig(t *testing.T) {
 		},
 		K8sAPIConfig: &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
 	}, metadataWithK8sAPICfg)
+
+	prometheusEndpointsCfg := cfg.Receivers["kubeletstats/prometheus_endpoints"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/prometheus_endpoints",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "serviceAccount",
+			},
+		},
+		CollectionInterval: duration,
+		MetricGroupsToCollect: []kubelet.MetricGroup{
+			kubelet.ContainerMetricGroup,
+			kubelet.PodMetricGroup,
+			kubelet.NodeMetricGroup,
+		},
+		PrometheusEndpoints: []kubelet.PrometheusEndpoint{
+			kubelet.CadvisorEndpoint,
+			kubelet.ResourceEndpoint,
+		},
+		CollectPodStatusMetrics:   true,
+		CollectUtilizationMetrics: true,
+	}, prometheusEndpointsCfg)
 }
 
 func TestGetReceiverOptions(t *testing.T) {
 	type fields struct {
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect []kubelet.MetricGroup
+		prometheusEndpoints   []kubelet.PrometheusEndpoint
 		k8sAPIConfig          *k8sconfig.APIConfig
 	}
 	tests := []struct {
@@ -231,6 +257,32 @@ func TestGetReceiverOptions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Prometheus endpoints",
+			fields: fields{
+				prometheusEndpoints: []kubelet.PrometheusEndpoint{
+					kubelet.CadvisorEndpoint,
+				},
+			},
+			want: &receiverOptions{
+				name:                  typeStr,
+				metricGroupsToCollect: map[kubelet.MetricGroup]bool{},
+				prometheusEndpoints: []kubelet.PrometheusEndpoint{
+					kubelet.CadvisorEndpoint,
+				},
+				collectionInterval: 10 * time.Second,
+			},
+		},
+		{
+			name: "Invalid prometheus endpoint",
+			fields: fields{
+				prometheusEndpoints: []kubelet.PrometheusEndpoint{
+					"unsupported",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Fails to create k8s API client",
 			fields: fields{
@@ -249,6 +301,7 @@ func TestGetReceiverOptions(t *testing.T) {
 				CollectionInterval:    10 * time.Second,
 				ExtraMetadataLabels:   tt.fields.extraMetadataLabels,
 				MetricGroupsToCollect: tt.fields.metricGroupsToCollect,
+				PrometheusEndpoints:   tt.fields.prometheusEndpoints,
 				K8sAPIConfig:          tt.fields.k8sAPIConfig,
 			}
 			got, err := cfg.getReceiverOptions()
@@ -15,14 +15,17 @@
 package kubelet
 
 import (
+	"sort"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	dto "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	v1 "k8s.io/api/core/v1"
 	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 )
 
@@ -55,7 +58,9 @@ const (
 	nodePrefix      = k8sPrefix + "node."
 	podPrefix       = k8sPrefix + "pod."
 	containerPrefix = "container."
-	volumePrefix    = k8sPrefix + "volume."
+	// k8sContainerPrefix prefixes the container metrics derived from the pod specs.
+	k8sContainerPrefix = k8sPrefix + "container."
+	volumePrefix       = k8sPrefix + "volume."
 )
 
 func (a *metricDataAccumulator) nodeStats(s stats.NodeStats) {
@@ -88,6 +93,7 @@ func (a *metricDataAccumulator) podStats(podResource *resourcepb.Resource, s sta
 		fsMetrics(podPrefix, s.EphemeralStorage),
 		memMetrics(podPrefix, s.Memory),
 		networkMetrics(podPrefix, s.Network),
+		utilizationMetrics(podPrefix, s.CPU, s.Memory, a.metadata.podResourceLimits(s.PodRef.UID)),
 	)
 }
 
@@ -96,7 +102,7 @@ func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource,
 		return
 	}
 
-	resource, err := containerResource(podResource, s, a.metadata)
+	resource, err := containerResource(podResource, s.Name, a.metadata)
 	if err != nil {
 		a.logger.Warn("failed to fetch container metrics", zap.String("pod", podResource.Labels[conventions.AttributeK8sPod]),
 			zap.String("container", podResource.Labels[conventions.AttributeK8sContainer]), zap.Error(err))
@@ -111,6 +117,10 @@ func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource,
 		cpuMetrics(containerPrefix, s.CPU),
 		memMetrics(containerPrefix, s.Memory),
 		fsMetrics(containerPrefix, s.Rootfs),
+		utilizationMetrics(
+			k8sContainerPrefix, s.CPU, s.Memory,
+			a.metadata.containerResourceLimits(podResource.Labels[conventions.AttributeK8sPodUID], s.Name),
+		),
 	)
 }
 
@@ -137,6 +147,190 @@ func (a *metricDataAccumulator) volumeStats(podResource *resourcepb.Resource, s
 	)
 }
 
+func (a *metricDataAccumulator) podStatus(pod v1.Pod) {
+	if !a.metricGroupsToCollect[ContainerMetricGroup] {
+		return
+	}
+
+	podResource := &resourcepb.Resource{
+		Type: "k8s", // k8s/pod
+		Labels: map[string]string{
+			conventions.AttributeK8sPodUID:    string(pod.UID),
+			conventions.AttributeK8sPod:       pod.Name,
+			conventions.AttributeK8sNamespace: pod.Namespace,
+		},
+	}
+	var startTime *timestamppb.Timestamp
+	if pod.Status.StartTime != nil {
+		startTime = timestamppb.New(pod.Status.StartTime.Time)
+	}
+
+	for _, s := range pod.Status.ContainerStatuses {
+		resource, err := containerResource(podResource, s.Name, a.metadata)
+		if err != nil {
+			a.logger.Warn("failed to fetch container status metrics", zap.String("pod", pod.Name),
+				zap.String("container", s.Name), zap.Error(err))
+			continue
+		}
+
+		a.accumulate(
+			startTime,
+			resource,
+			containerStatusMetrics(containerPrefix, s),
+		)
+	}
+}
+
+// prometheusResource groups the translated Prometheus series of a node, pod or container.
+type prometheusResource struct {
+	startTime *timestamppb.Timestamp
+	resource  *resourcepb.Resource
+	metrics   []*metricspb.Metric
+}
+
+// prometheusStats translates the metric families scraped from a kubelet Prometheus endpoint.
+// Series are attributed to the node, pods and containers of the summary, series of pods the
+// summary doesn't report and of cgroups other than those of the containers are dropped. The
+// network series of the pod cgroup and pause container are attributed to the pod.
+func (a *metricDataAccumulator) prometheusStats(
+	endpoint PrometheusEndpoint,
+	families map[string]*dto.MetricFamily,
+	summary *stats.Summary,
+) {
+	pods := make(map[string]stats.PodStats, len(summary.Pods))
+	for _, p := range summary.Pods {
+		pods[p.PodRef.Namespace+"/"+p.PodRef.Name] = p
+	}
+
+	var keys []string
+	resources := map[string]*prometheusResource{}
+	// series holds the translated series, cAdvisor reports the network of a pod
+	// for both its cgroup and its pause container.
+	series := map[string]bool{}
+	for name, family := range families {
+		pm, ok := prometheusMetrics[endpoint][name]
+		if !ok || !a.metricGroupsToCollect[pm.group] {
+			continue
+		}
+
+		for _, m := range family.Metric {
+			seriesLabels := prometheusLabels(m)
+			key, ok := a.prometheusResourceKey(pm, seriesLabels, pods)
+			if !ok {
+				continue
+			}
+			seriesKey := prometheusSeriesKey(key, name, pm, seriesLabels)
+			if series[seriesKey] {
+				continue
+			}
+			metric := prometheusSeriesMetric(pm, m, seriesLabels)
+			if metric == nil {
+				continue
+			}
+			series[seriesKey] = true
+
+			r, seen := resources[key]
+			if !seen {
+				r = a.prometheusResource(pm.group, seriesLabels, summary.Node, pods)
+				resources[key] = r
+				if r != nil {
+					keys = append(keys, key)
+				}
+			}
+			if r != nil {
+				r.metrics = append(r.metrics, metric)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		r := resources[key]
+		a.accumulate(r.startTime, r.resource, r.metrics)
+	}
+}
+
+// prometheusResourceKey identifies the node, pod or container a series belongs to.
+func (a *metricDataAccumulator) prometheusResourceKey(
+	pm prometheusMetric,
+	seriesLabels map[string]string,
+	pods map[string]stats.PodStats,
+) (string, bool) {
+	if pm.group == NodeMetricGroup {
+		return "", true
+	}
+
+	pod := seriesLabels[promLabelNamespace] + "/" + seriesLabels[promLabelPod]
+	p, ok := pods[pod]
+	if !ok {
+		return "", false
+	}
+	if pm.podNetwork && p.Network != nil && seriesLabels[promLabelInterface] == p.Network.Name {
+		return "", false
+	}
+	if pm.group == PodMetricGroup {
+		return pod, true
+	}
+
+	container := seriesLabels[promLabelContainer]
+	if container == "" || container == podContainerName {
+		return "", false
+	}
+	return pod + "/" + container, true
+}
+
+// prometheusSeriesKey identifies the translated series of a resource.
+func prometheusSeriesKey(resourceKey string, family string, pm prometheusMetric, seriesLabels map[string]string) string {
+	key := resourceKey + "/" + family
+	for _, l := range pm.sourceLabels {
+		key += "/" + seriesLabels[l]
+	}
+	return key
+}
+
+// prometheusResource returns the resource of a series, or nil when its container isn't
+// reported by the summary.
+func (a *metricDataAccumulator) prometheusResource(
+	group MetricGroup,
+	seriesLabels map[string]string,
+	node stats.NodeStats,
+	pods map[string]stats.PodStats,
+) *prometheusResource {
+	if group == NodeMetricGroup {
+		return &prometheusResource{
+			startTime: timestamppb.New(node.StartTime.Time),
+			resource:  nodeResource(node),
+		}
+	}
+
+	pod := pods[seriesLabels[promLabelNamespace]+"/"+seriesLabels[promLabelPod]]
+	podResource := podResource(pod)
+	if group == PodMetricGroup {
+		return &prometheusResource{
+			startTime: timestamppb.New(pod.StartTime.Time),
+			resource:  podResource,
+		}
+	}
+
+	name := seriesLabels[promLabelContainer]
+	for _, s := range pod.Containers {
+		if s.Name != name {
+			continue
+		}
+		resource, err := containerResource(podResource, name, a.metadata)
+		if err != nil {
+			a.logger.Warn("failed to fetch container metrics", zap.String("pod", pod.PodRef.Name),
+				zap.String("container", name), zap.Error(err))
+			return nil
+		}
+		return &prometheusResource{
+			startTime: timestamppb.New(s.StartTime.Time),
+			resource:  resource,
+		}
+	}
+	return nil
+}
+
 func (a *metricDataAccumulator) accumulate(
 	startTime *timestamppb.Timestamp,
 	r *resourcepb.Resource,
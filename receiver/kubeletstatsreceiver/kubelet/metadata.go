@@ -56,6 +56,22 @@ type Metadata struct {
 	Labels                  map[MetadataLabel]bool
 	PodsMetadata            *v1.PodList
 	DetailedPVCLabelsSetter func(volCacheID, volumeClaim, namespace string, labels map[string]string) error
+	podResources            map[string]podResources
+}
+
+// resourceLimits holds the CPU requests and limits in cores and the memory
+// requests and limits in bytes, nil when they aren't set.
+type resourceLimits struct {
+	cpuRequest    *float64
+	cpuLimit      *float64
+	memoryRequest *float64
+	memoryLimit   *float64
+}
+
+// podResources holds the requests and limits of a pod and of its containers.
+type podResources struct {
+	pod        resourceLimits
+	containers map[string]resourceLimits
 }
 
 func NewMetadata(
@@ -68,6 +84,87 @@ func NewMetadata(
 	}
 }
 
+// SetResourceLimits indexes the requests and limits of the containers in the
+// fetched pods metadata, enabling the utilization metrics.
+func (m *Metadata) SetResourceLimits() {
+	if m.PodsMetadata == nil {
+		return
+	}
+	m.podResources = make(map[string]podResources, len(m.PodsMetadata.Items))
+	for _, pod := range m.PodsMetadata.Items {
+		m.podResources[string(pod.UID)] = getPodResources(pod)
+	}
+}
+
+// getPodResources returns the requests and limits of the containers of a pod. A
+// pod request or limit is only set when all of its containers set it, since
+// the usage of the others is unbounded.
+func getPodResources(pod v1.Pod) podResources {
+	out := podResources{containers: make(map[string]resourceLimits, len(pod.Spec.Containers))}
+	var containers []resourceLimits
+	for _, container := range pod.Spec.Containers {
+		limits := resourceLimits{
+			cpuRequest:    cpuQuantity(container.Resources.Requests),
+			cpuLimit:      cpuQuantity(container.Resources.Limits),
+			memoryRequest: memoryQuantity(container.Resources.Requests),
+			memoryLimit:   memoryQuantity(container.Resources.Limits),
+		}
+		out.containers[container.Name] = limits
+		containers = append(containers, limits)
+	}
+
+	out.pod = resourceLimits{
+		cpuRequest:    sumQuantities(containers, func(l resourceLimits) *float64 { return l.cpuRequest }),
+		cpuLimit:      sumQuantities(containers, func(l resourceLimits) *float64 { return l.cpuLimit }),
+		memoryRequest: sumQuantities(containers, func(l resourceLimits) *float64 { return l.memoryRequest }),
+		memoryLimit:   sumQuantities(containers, func(l resourceLimits) *float64 { return l.memoryLimit }),
+	}
+	return out
+}
+
+func cpuQuantity(resources v1.ResourceList) *float64 {
+	q, ok := resources[v1.ResourceCPU]
+	if !ok {
+		return nil
+	}
+	cores := float64(q.MilliValue()) / 1000
+	return &cores
+}
+
+func memoryQuantity(resources v1.ResourceList) *float64 {
+	q, ok := resources[v1.ResourceMemory]
+	if !ok {
+		return nil
+	}
+	bytes := float64(q.Value())
+	return &bytes
+}
+
+func sumQuantities(containers []resourceLimits, quantity func(resourceLimits) *float64) *float64 {
+	if len(containers) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, c := range containers {
+		q := quantity(c)
+		if q == nil {
+			return nil
+		}
+		sum += *q
+	}
+	return &sum
+}
+
+// podResourceLimits returns the requests and limits of a pod.
+func (m *Metadata) podResourceLimits(podUID string) resourceLimits {
+	return m.podResources[podUID].pod
+}
+
+// containerResourceLimits returns the requests and limits of a container.
+func (m *Metadata) containerResourceLimits(podUID string, containerName string) resourceLimits {
+	return m.podResources[podUID].containers[containerName]
+}
+
 func getLabelsMap(metadataLabels []MetadataLabel) map[MetadataLabel]bool {
 	out := make(map[MetadataLabel]bool, len(metadataLabels))
 	for _, l := range metadataLabels {
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -330,3 +331,57 @@ func TestSetExtraLabelsForVolumeTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestSetResourceLimits(t *testing.T) {
+	container := func(name string, requests, limits v1.ResourceList) v1.Container {
+		return v1.Container{
+			Name:      name,
+			Resources: v1.ResourceRequirements{Requests: requests, Limits: limits},
+		}
+	}
+	metadata := NewMetadata(nil, &v1.PodList{
+		Items: []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{UID: "uid-1234"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					container("app",
+						v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("64Mi")},
+						v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("128Mi")},
+					),
+					container("sidecar",
+						v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+						v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Mi")},
+					),
+				},
+			},
+		}},
+	}, nil)
+
+	// Nothing is indexed until the limits are set.
+	assert.Equal(t, resourceLimits{}, metadata.podResourceLimits("uid-1234"))
+	metadata.SetResourceLimits()
+
+	assert.Equal(t, resourceLimits{
+		cpuRequest:    float64Ptr(0.25),
+		cpuLimit:      float64Ptr(1),
+		memoryRequest: float64Ptr(64 << 20),
+		memoryLimit:   float64Ptr(128 << 20),
+	}, metadata.containerResourceLimits("uid-1234", "app"))
+	assert.Equal(t, resourceLimits{
+		cpuRequest:  float64Ptr(0.05),
+		memoryLimit: float64Ptr(32 << 20),
+	}, metadata.containerResourceLimits("uid-1234", "sidecar"))
+
+	// The pod sets a request or a limit only when all its containers do.
+	assert.Equal(t, resourceLimits{
+		cpuRequest:  float64Ptr(0.3),
+		memoryLimit: float64Ptr(160 << 20),
+	}, metadata.podResourceLimits("uid-1234"))
+
+	assert.Equal(t, resourceLimits{}, metadata.containerResourceLimits("uid-1234", "unknown"))
+	assert.Equal(t, resourceLimits{}, metadata.podResourceLimits("unknown"))
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
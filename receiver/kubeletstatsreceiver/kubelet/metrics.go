@@ -17,8 +17,10 @@ package kubelet
 import (
 	"time"
 
+	dto "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
 	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 )
 
@@ -26,12 +28,7 @@ func MetricsData(
 	logger *zap.Logger, summary *stats.Summary,
 	metadata Metadata, typeStr string,
 	metricGroupsToCollect map[MetricGroup]bool) []consumerdata.MetricsData {
-	acc := &metricDataAccumulator{
-		metadata:              metadata,
-		logger:                logger,
-		metricGroupsToCollect: metricGroupsToCollect,
-		time:                  time.Now(),
-	}
+	acc := newMetricDataAccumulator(logger, metadata, metricGroupsToCollect)
 
 	acc.nodeStats(summary.Node)
 	for _, podStats := range summary.Pods {
@@ -46,9 +43,49 @@ func MetricsData(
 			acc.volumeStats(podResource, volumeStats)
 		}
 	}
-	for _, md := range acc.m {
+	return acc.metricsData(typeStr)
+}
+
+// PodStatusMetricsData returns the restart counts and the waiting and terminated
+// states of the containers listed in the /pods response.
+func PodStatusMetricsData(
+	logger *zap.Logger, pods *v1.PodList,
+	metadata Metadata, typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool) []consumerdata.MetricsData {
+	acc := newMetricDataAccumulator(logger, metadata, metricGroupsToCollect)
+	for _, pod := range pods.Items {
+		acc.podStatus(pod)
+	}
+	return acc.metricsData(typeStr)
+}
+
+// PrometheusMetricsData translates the metric families scraped from the given
+// kubelet Prometheus endpoint, using the summary to identify their resources.
+func PrometheusMetricsData(
+	logger *zap.Logger, endpoint PrometheusEndpoint,
+	families map[string]*dto.MetricFamily, summary *stats.Summary,
+	metadata Metadata, typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool) []consumerdata.MetricsData {
+	acc := newMetricDataAccumulator(logger, metadata, metricGroupsToCollect)
+	acc.prometheusStats(endpoint, families, summary)
+	return acc.metricsData(typeStr)
+}
+
+func newMetricDataAccumulator(
+	logger *zap.Logger, metadata Metadata,
+	metricGroupsToCollect map[MetricGroup]bool) *metricDataAccumulator {
+	return &metricDataAccumulator{
+		metadata:              metadata,
+		logger:                logger,
+		metricGroupsToCollect: metricGroupsToCollect,
+		time:                  time.Now(),
+	}
+}
+
+func (a *metricDataAccumulator) metricsData(typeStr string) []consumerdata.MetricsData {
+	for _, md := range a.m {
 		// TODO this should prob go in core
 		md.Resource.Labels["receiver"] = typeStr
 	}
-	return acc.m
+	return a.m
 }
@@ -36,6 +36,14 @@ func (f fakeRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f fakeRestClient) CadvisorMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/metrics-cadvisor.txt")
+}
+
+func (f fakeRestClient) ResourceMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/metrics-resource.txt")
+}
+
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
 	statsProvider := NewStatsProvider(rc)
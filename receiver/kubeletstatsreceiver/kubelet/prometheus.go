@@ -0,0 +1,222 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	promLabelContainer = "container"
+	promLabelPod       = "pod"
+	promLabelNamespace = "namespace"
+	promLabelInterface = "interface"
+
+	// podContainerName is the name cAdvisor reports for the pause container of a pod.
+	podContainerName = "POD"
+)
+
+// prometheusMetric describes how the series of a Prometheus metric family
+// scraped from the kubelet are translated.
+type prometheusMetric struct {
+	group MetricGroup
+	name  string
+	unit  string
+	typ   metricspb.MetricDescriptor_Type
+	// labels are set on every translated series.
+	labels map[string]string
+	// sourceLabels are copied from the Prometheus series.
+	sourceLabels []string
+	// podNetwork is set for the network families, which cAdvisor reports for the
+	// pod cgroup and the pause container of the pod. The series of the interface
+	// /stats/summary reports for the pod are skipped.
+	podNetwork bool
+}
+
+// resourceMetricsPrefix distinguishes the metrics of /metrics/resource, the values
+// served by the Kubernetes metrics API, from the ones /stats/summary reports.
+const resourceMetricsPrefix = "resource_metrics."
+
+// prometheusMetrics contains the translated metric families by endpoint. The
+// families that /stats/summary already reports are left out of the cAdvisor ones.
+var prometheusMetrics = map[PrometheusEndpoint]map[string]prometheusMetric{
+	CadvisorEndpoint: {
+		"container_cpu_cfs_periods_total": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + "cpu.periods",
+			unit:  "1",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		"container_cpu_cfs_throttled_periods_total": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + "cpu.throttled_periods",
+			unit:  "1",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		"container_cpu_cfs_throttled_seconds_total": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + "cpu.throttled_time",
+			unit:  "s",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		},
+		"container_memory_failcnt": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + "memory.failures",
+			unit:  "1",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		"container_oom_events_total": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + "memory.oom_events",
+			unit:  "1",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		"container_network_receive_bytes_total": {
+			group:        PodMetricGroup,
+			name:         podPrefix + "network.io",
+			unit:         "By",
+			typ:          metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labels:       map[string]string{directionLabel: "receive"},
+			sourceLabels: []string{promLabelInterface},
+			podNetwork:   true,
+		},
+		"container_network_transmit_bytes_total": {
+			group:        PodMetricGroup,
+			name:         podPrefix + "network.io",
+			unit:         "By",
+			typ:          metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labels:       map[string]string{directionLabel: "transmit"},
+			sourceLabels: []string{promLabelInterface},
+			podNetwork:   true,
+		},
+		"container_network_receive_errors_total": {
+			group:        PodMetricGroup,
+			name:         podPrefix + "network.errors",
+			unit:         "1",
+			typ:          metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labels:       map[string]string{directionLabel: "receive"},
+			sourceLabels: []string{promLabelInterface},
+			podNetwork:   true,
+		},
+		"container_network_transmit_errors_total": {
+			group:        PodMetricGroup,
+			name:         podPrefix + "network.errors",
+			unit:         "1",
+			typ:          metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labels:       map[string]string{directionLabel: "transmit"},
+			sourceLabels: []string{promLabelInterface},
+			podNetwork:   true,
+		},
+	},
+	ResourceEndpoint: {
+		"node_cpu_usage_seconds_total": {
+			group: NodeMetricGroup,
+			name:  nodePrefix + resourceMetricsPrefix + "cpu.time",
+			unit:  "s",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		},
+		"node_memory_working_set_bytes": {
+			group: NodeMetricGroup,
+			name:  nodePrefix + resourceMetricsPrefix + "memory.working_set",
+			unit:  "By",
+			typ:   metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		"pod_cpu_usage_seconds_total": {
+			group: PodMetricGroup,
+			name:  podPrefix + resourceMetricsPrefix + "cpu.time",
+			unit:  "s",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		},
+		"pod_memory_working_set_bytes": {
+			group: PodMetricGroup,
+			name:  podPrefix + resourceMetricsPrefix + "memory.working_set",
+			unit:  "By",
+			typ:   metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		"container_cpu_usage_seconds_total": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + resourceMetricsPrefix + "cpu.time",
+			unit:  "s",
+			typ:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		},
+		"container_memory_working_set_bytes": {
+			group: ContainerMetricGroup,
+			name:  containerPrefix + resourceMetricsPrefix + "memory.working_set",
+			unit:  "By",
+			typ:   metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+	},
+}
+
+// prometheusSeriesMetric translates a single Prometheus series, returning nil
+// when it has no value.
+func prometheusSeriesMetric(pm prometheusMetric, m *dto.Metric, seriesLabels map[string]string) *metricspb.Metric {
+	value, ok := prometheusValue(m)
+	if !ok {
+		return nil
+	}
+
+	var metric *metricspb.Metric
+	switch pm.typ {
+	case metricspb.MetricDescriptor_CUMULATIVE_INT64, metricspb.MetricDescriptor_GAUGE_INT64:
+		if value < 0 {
+			return nil
+		}
+		intValue := uint64(value)
+		if pm.typ == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+			metric = cumulativeInt(pm.name, pm.unit, &intValue)
+		} else {
+			metric = intGauge(pm.name, pm.unit, &intValue)
+		}
+	case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+		metric = cumulativeDouble(pm.name, pm.unit, &value)
+	default:
+		metric = doubleGauge(pm.name, pm.unit, &value)
+	}
+
+	attrs := make(map[string]string, len(pm.labels)+len(pm.sourceLabels))
+	for k, v := range pm.labels {
+		attrs[k] = v
+	}
+	for _, l := range pm.sourceLabels {
+		attrs[l] = seriesLabels[l]
+	}
+	if len(attrs) > 0 {
+		applyLabels(metric, attrs)
+	}
+	return metric
+}
+
+func prometheusValue(m *dto.Metric) (float64, bool) {
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue(), true
+	case m.Gauge != nil:
+		return m.Gauge.GetValue(), true
+	case m.Untyped != nil:
+		return m.Untyped.GetValue(), true
+	default:
+		return 0, false
+	}
+}
+
+func prometheusLabels(m *dto.Metric) map[string]string {
+	out := make(map[string]string, len(m.Label))
+	for _, l := range m.Label {
+		out[l.GetName()] = l.GetValue()
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"bytes"
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+type PrometheusEndpoint string
+
+const (
+	CadvisorEndpoint = PrometheusEndpoint("cadvisor")
+	ResourceEndpoint = PrometheusEndpoint("resource")
+)
+
+var ValidPrometheusEndpoints = map[PrometheusEndpoint]bool{
+	CadvisorEndpoint: true,
+	ResourceEndpoint: true,
+}
+
+// PrometheusProvider wraps a RestClient, returning the parsed metric
+// families of the kubelet endpoints exposing Prometheus text.
+type PrometheusProvider struct {
+	rc RestClient
+}
+
+func NewPrometheusProvider(rc RestClient) *PrometheusProvider {
+	return &PrometheusProvider{rc: rc}
+}
+
+// Metrics calls the /metrics/<endpoint> kubelet endpoint and parses the
+// results into metric families indexed by name.
+func (p *PrometheusProvider) Metrics(endpoint PrometheusEndpoint) (map[string]*dto.MetricFamily, error) {
+	var body []byte
+	var err error
+	switch endpoint {
+	case CadvisorEndpoint:
+		body, err = p.rc.CadvisorMetrics()
+	case ResourceEndpoint:
+		body, err = p.rc.ResourceMetrics()
+	default:
+		return nil, fmt.Errorf("prometheus endpoint %q is not supported", endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(bytes.NewReader(body))
+}
@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type prometheusRestClient struct {
+	fakeRestClient
+	fail bool
+	body string
+}
+
+func (f prometheusRestClient) CadvisorMetrics() ([]byte, error) {
+	if f.fail {
+		return nil, errors.New("failed")
+	}
+	if f.body != "" {
+		return []byte(f.body), nil
+	}
+	return f.fakeRestClient.CadvisorMetrics()
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       RestClient
+		endpoint     PrometheusEndpoint
+		wantFamilies []string
+		wantError    string
+	}{
+		{
+			name:     "cadvisor",
+			client:   &prometheusRestClient{},
+			endpoint: CadvisorEndpoint,
+			wantFamilies: []string{
+				"cadvisor_version_info",
+				"container_cpu_cfs_periods_total",
+				"container_cpu_cfs_throttled_periods_total",
+				"container_cpu_cfs_throttled_seconds_total",
+				"container_cpu_usage_seconds_total",
+				"container_memory_failcnt",
+				"container_network_receive_bytes_total",
+				"container_network_receive_errors_total",
+				"container_network_transmit_bytes_total",
+				"container_oom_events_total",
+			},
+		},
+		{
+			name:     "resource",
+			client:   &prometheusRestClient{},
+			endpoint: ResourceEndpoint,
+			wantFamilies: []string{
+				"container_cpu_usage_seconds_total",
+				"container_memory_working_set_bytes",
+				"node_cpu_usage_seconds_total",
+				"node_memory_working_set_bytes",
+				"scrape_error",
+			},
+		},
+		{
+			name:      "failure",
+			client:    &prometheusRestClient{fail: true},
+			endpoint:  CadvisorEndpoint,
+			wantError: "failed",
+		},
+		{
+			name:      "invalid-text",
+			client:    &prometheusRestClient{body: "metric{label=} 1"},
+			endpoint:  CadvisorEndpoint,
+			wantError: "text format parsing error in line 1: expected '\"' at start of label value, found '}'",
+		},
+		{
+			name:      "unsupported-endpoint",
+			client:    &prometheusRestClient{},
+			endpoint:  PrometheusEndpoint("probes"),
+			wantError: `prometheus endpoint "probes" is not supported`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			families, err := NewPrometheusProvider(tt.client).Metrics(tt.endpoint)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for name := range families {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tt.wantFamilies, names)
+		})
+	}
+}
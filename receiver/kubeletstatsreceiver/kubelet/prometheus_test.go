@@ -0,0 +1,169 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+)
+
+func prometheusMetricsData(t *testing.T, endpoint PrometheusEndpoint, mgs map[MetricGroup]bool) []consumerdata.MetricsData {
+	rc := &fakeRestClient{}
+	summary, err := NewStatsProvider(rc).StatsSummary()
+	require.NoError(t, err)
+	families, err := NewPrometheusProvider(rc).Metrics(endpoint)
+	require.NoError(t, err)
+	return PrometheusMetricsData(zap.NewNop(), endpoint, families, summary, Metadata{}, "foo", mgs)
+}
+
+func TestPrometheusMetricsDataCadvisor(t *testing.T) {
+	mds := prometheusMetricsData(t, CadvisorEndpoint, ValidMetricGroups)
+	requireMetricsDataOk(t, mds)
+
+	// The series of the pod cgroups other than network ones and of the pod
+	// missing from the summary are dropped.
+	require.Len(t, mds, 4)
+
+	pod := mds[0]
+	assert.Equal(t, map[string]string{
+		"k8s.pod.uid":        "42ad382b-ed0b-446d-9aab-3fdce8b4f9e2",
+		"k8s.pod.name":       "go-hello-world-5456b4b8cd-99vxc",
+		"k8s.namespace.name": "default",
+		"receiver":           "foo",
+	}, pod.Resource.Labels)
+	assertPodNetworkMetrics(t, pod.Metrics)
+
+	server := mds[1]
+	assert.Equal(t, map[string]string{
+		"k8s.pod.uid":        "42ad382b-ed0b-446d-9aab-3fdce8b4f9e2",
+		"k8s.pod.name":       "go-hello-world-5456b4b8cd-99vxc",
+		"k8s.namespace.name": "default",
+		"k8s.container.name": "server",
+		"receiver":           "foo",
+	}, server.Resource.Labels)
+	assert.Equal(t, map[string]int64{
+		"container.cpu.periods":           2104,
+		"container.cpu.throttled_periods": 0,
+		"container.memory.oom_events":     2,
+	}, int64Values(server.Metrics))
+	for _, m := range server.Metrics {
+		assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, m.MetricDescriptor.Type)
+	}
+
+	// The network series of the pod cgroup and of the pause container are the
+	// same series.
+	corednsPod := mds[2]
+	assert.Equal(t, "coredns-66bff467f8-szddj", corednsPod.Resource.Labels["k8s.pod.name"])
+	assert.NotContains(t, corednsPod.Resource.Labels, "k8s.container.name")
+	assertPodNetworkMetrics(t, corednsPod.Metrics)
+
+	coredns := mds[3]
+	assert.Equal(t, "coredns", coredns.Resource.Labels["k8s.container.name"])
+	assert.Equal(t, "kube-system", coredns.Resource.Labels["k8s.namespace.name"])
+	assert.Len(t, coredns.Metrics, 4)
+	for _, m := range coredns.Metrics {
+		if m.MetricDescriptor.Name != "container.cpu.throttled_time" {
+			continue
+		}
+		assert.Equal(t, "s", m.MetricDescriptor.Unit)
+		assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, m.MetricDescriptor.Type)
+		assert.Equal(t, 0.435, m.Timeseries[0].Points[0].GetDoubleValue())
+	}
+}
+
+// assertPodNetworkMetrics checks the cAdvisor network series of a pod, only those of the sit0
+// interface are kept as the summary reports eth0.
+func assertPodNetworkMetrics(t *testing.T, metrics []*metricspb.Metric) {
+	require.Len(t, metrics, 3)
+	names := map[string]int{}
+	for _, m := range metrics {
+		names[m.MetricDescriptor.Name]++
+		require.Len(t, m.Timeseries, 1)
+		assert.Equal(t, "sit0", labelValue(m, promLabelInterface))
+	}
+	assert.Equal(t, map[string]int{"k8s.pod.network.io": 2, "k8s.pod.network.errors": 1}, names)
+}
+
+func labelValue(m *metricspb.Metric, key string) string {
+	for i, k := range m.MetricDescriptor.LabelKeys {
+		if k.Key == key {
+			return m.Timeseries[0].LabelValues[i].Value
+		}
+	}
+	return ""
+}
+
+func TestPrometheusMetricsDataResource(t *testing.T) {
+	mds := prometheusMetricsData(t, ResourceEndpoint, ValidMetricGroups)
+	requireMetricsDataOk(t, mds)
+	require.Len(t, mds, 3)
+
+	node := mds[0]
+	assert.Equal(t, map[string]string{
+		"k8s.node.name": "minikube",
+		"receiver":      "foo",
+	}, node.Resource.Labels)
+	assert.Equal(t, map[string]int64{
+		"k8s.node.resource_metrics.memory.working_set": 1234567890,
+	}, int64Values(node.Metrics))
+
+	for _, md := range mds[1:] {
+		assert.NotEmpty(t, md.Resource.Labels["k8s.container.name"])
+		assert.Len(t, md.Metrics, 2)
+	}
+
+	// Disable the node and container groups
+	mds = prometheusMetricsData(t, ResourceEndpoint, map[MetricGroup]bool{PodMetricGroup: true})
+	require.Len(t, mds, 0)
+}
+
+func TestPrometheusMetricsDataWithContainerID(t *testing.T) {
+	rc := &fakeRestClient{}
+	summary, _ := NewStatsProvider(rc).StatsSummary()
+	podsMetadata, _ := NewMetadataProvider(rc).Pods()
+	families, _ := NewPrometheusProvider(rc).Metrics(CadvisorEndpoint)
+	metadata := NewMetadata([]MetadataLabel{MetadataLabelContainerID}, podsMetadata, nil)
+
+	mds := PrometheusMetricsData(zap.NewNop(), CadvisorEndpoint, families, summary, metadata, "",
+		map[MetricGroup]bool{ContainerMetricGroup: true})
+	require.Len(t, mds, 2)
+	for _, md := range mds {
+		assert.NotEmpty(t, md.Resource.Labels["container.id"])
+	}
+}
+
+// int64Values indexes the values of integer metrics by name and direction label.
+func int64Values(metrics []*metricspb.Metric) map[string]int64 {
+	out := map[string]int64{}
+	for _, m := range metrics {
+		if m.MetricDescriptor.Type != metricspb.MetricDescriptor_CUMULATIVE_INT64 &&
+			m.MetricDescriptor.Type != metricspb.MetricDescriptor_GAUGE_INT64 {
+			continue
+		}
+		name := m.MetricDescriptor.Name
+		for i, k := range m.MetricDescriptor.LabelKeys {
+			if k.Key == directionLabel {
+				name += "/" + m.Timeseries[0].LabelValues[i].Value
+			}
+		}
+		out[name] = m.Timeseries[0].Points[0].GetInt64Value()
+	}
+	return out
+}
@@ -43,13 +43,13 @@ func podResource(s stats.PodStats) *resourcepb.Resource {
 	}
 }
 
-func containerResource(pod *resourcepb.Resource, s stats.ContainerStats, metadata Metadata) (*resourcepb.Resource, error) {
+func containerResource(pod *resourcepb.Resource, containerName string, metadata Metadata) (*resourcepb.Resource, error) {
 	labels := map[string]string{}
 	for k, v := range pod.Labels {
 		labels[k] = v
 	}
 	// augment the container resource with pod labels
-	labels[conventions.AttributeK8sContainer] = s.Name
+	labels[conventions.AttributeK8sContainer] = containerName
 	err := metadata.setExtraLabels(
 		labels, labels[conventions.AttributeK8sPodUID],
 		MetadataLabelContainerID, labels[conventions.AttributeK8sContainer],
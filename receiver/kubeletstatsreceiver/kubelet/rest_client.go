@@ -18,12 +18,14 @@ package kubelet
 type RestClient interface {
 	StatsSummary() ([]byte, error)
 	Pods() ([]byte, error)
+	CadvisorMetrics() ([]byte, error)
+	ResourceMetrics() ([]byte, error)
 }
 
 // RestClient is a thin wrapper around a kubelet client, encapsulating endpoints
 // and their corresponding http methods. The endpoints /stats/container /spec/
-// are excluded because they require cadvisor. The /metrics endpoint is excluded
-// because it returns Prometheus data.
+// are excluded because they require cadvisor. The Prometheus /metrics/cadvisor
+// and /metrics/resource endpoints are parsed by the PrometheusProvider.
 type HTTPRestClient struct {
 	client Client
 }
@@ -39,3 +41,11 @@ func (c *HTTPRestClient) StatsSummary() ([]byte, error) {
 func (c *HTTPRestClient) Pods() ([]byte, error) {
 	return c.client.Get("/pods")
 }
+
+func (c *HTTPRestClient) CadvisorMetrics() ([]byte, error) {
+	return c.client.Get("/metrics/cadvisor")
+}
+
+func (c *HTTPRestClient) ResourceMetrics() ([]byte, error) {
+	return c.client.Get("/metrics/resource")
+}
@@ -26,6 +26,10 @@ func TestRestClient(t *testing.T) {
 	require.Equal(t, "/stats/summary", string(resp))
 	resp, _ = rest.Pods()
 	require.Equal(t, "/pods", string(resp))
+	resp, _ = rest.CadvisorMetrics()
+	require.Equal(t, "/metrics/cadvisor", string(resp))
+	resp, _ = rest.ResourceMetrics()
+	require.Equal(t, "/metrics/resource", string(resp))
 }
 
 var _ Client = (*fakeClient)(nil)
@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"strconv"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	reasonLabel   = "reason"
+	exitCodeLabel = "exit_code"
+)
+
+func containerStatusMetrics(prefix string, s v1.ContainerStatus) []*metricspb.Metric {
+	return []*metricspb.Metric{
+		restartsMetric(prefix, s),
+		waitingMetric(prefix, s.State),
+		terminatedMetric(prefix+"state.terminated", "Whether the container is terminated.", s.State),
+		terminatedMetric(
+			prefix+"last_state.terminated", "Whether the previous execution of the container terminated.",
+			s.LastTerminationState,
+		),
+	}
+}
+
+func restartsMetric(prefix string, s v1.ContainerStatus) *metricspb.Metric {
+	restarts := uint64(s.RestartCount)
+	return intGaugeWithDescription(
+		prefix+"restarts", "1",
+		"The number of times the container has been restarted.",
+		&restarts,
+	)
+}
+
+func waitingMetric(prefix string, s v1.ContainerState) *metricspb.Metric {
+	if s.Waiting == nil {
+		return nil
+	}
+	waiting := uint64(1)
+	metric := intGaugeWithDescription(
+		prefix+"state.waiting", "1",
+		"Whether the container is waiting to start.",
+		&waiting,
+	)
+	applyLabels(metric, map[string]string{reasonLabel: s.Waiting.Reason})
+	return metric
+}
+
+func terminatedMetric(metricName string, description string, s v1.ContainerState) *metricspb.Metric {
+	if s.Terminated == nil {
+		return nil
+	}
+	terminated := uint64(1)
+	metric := intGaugeWithDescription(metricName, "1", description, &terminated)
+	applyLabels(metric, map[string]string{
+		reasonLabel:   s.Terminated.Reason,
+		exitCodeLabel: strconv.Itoa(int(s.Terminated.ExitCode)),
+	})
+	return metric
+}
@@ -0,0 +1,112 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodStatusMetricsData(t *testing.T) {
+	startTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	pods := &v1.PodList{
+		Items: []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-a",
+				Namespace: "default",
+				UID:       "pod-a-uid",
+			},
+			Status: v1.PodStatus{
+				StartTime: &startTime,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         "running",
+						ContainerID:  "docker://running-id",
+						RestartCount: 0,
+						State:        v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+					},
+					{
+						Name:         "crashing",
+						ContainerID:  "docker://crashing-id",
+						RestartCount: 4,
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+						},
+					},
+					{
+						Name:        "completed",
+						ContainerID: "docker://completed-id",
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "Completed"},
+						},
+					},
+				},
+			},
+		}},
+	}
+	metadata := NewMetadata([]MetadataLabel{MetadataLabelContainerID}, pods, nil)
+
+	mds := PodStatusMetricsData(zap.NewNop(), pods, metadata, "foo", ValidMetricGroups)
+	requireMetricsDataOk(t, mds)
+	require.Len(t, mds, 3)
+
+	running := mds[0]
+	assert.Equal(t, map[string]string{
+		"k8s.pod.uid":        "pod-a-uid",
+		"k8s.pod.name":       "pod-a",
+		"k8s.namespace.name": "default",
+		"k8s.container.name": "running",
+		"container.id":       "running-id",
+		"receiver":           "foo",
+	}, running.Resource.Labels)
+	assert.Equal(t, map[string]int64{"container.restarts": 0}, int64Values(running.Metrics))
+
+	crashing := mds[1]
+	assert.Equal(t, map[string]int64{
+		"container.restarts":              4,
+		"container.state.waiting":         1,
+		"container.last_state.terminated": 1,
+	}, int64Values(crashing.Metrics))
+	assert.Equal(t, map[string]string{"reason": "CrashLoopBackOff"}, metricLabels(crashing.Metrics[1]))
+	assert.Equal(t, map[string]string{"reason": "OOMKilled", "exit_code": "137"}, metricLabels(crashing.Metrics[2]))
+
+	completed := mds[2]
+	assert.Equal(t, map[string]int64{
+		"container.restarts":         0,
+		"container.state.terminated": 1,
+	}, int64Values(completed.Metrics))
+	assert.Equal(t, map[string]string{"reason": "Completed", "exit_code": "0"}, metricLabels(completed.Metrics[1]))
+
+	// Disable the container group
+	require.Len(t, PodStatusMetricsData(zap.NewNop(), pods, metadata, "foo", map[MetricGroup]bool{PodMetricGroup: true}), 0)
+}
+
+func metricLabels(m *metricspb.Metric) map[string]string {
+	out := map[string]string{}
+	for i, k := range m.MetricDescriptor.LabelKeys {
+		out[k.Key] = m.Timeseries[0].LabelValues[i].Value
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+)
+
+// utilizationMetrics returns the CPU usage and memory working set as a ratio
+// of the requests and limits.
+func utilizationMetrics(prefix string, cpu *stats.CPUStats, mem *stats.MemoryStats, l resourceLimits) []*metricspb.Metric {
+	var cpuUsage, memUsage *float64
+	if cpu != nil && cpu.UsageNanoCores != nil {
+		cores := float64(*cpu.UsageNanoCores) / 1_000_000_000
+		cpuUsage = &cores
+	}
+	if mem != nil && mem.WorkingSetBytes != nil {
+		bytes := float64(*mem.WorkingSetBytes)
+		memUsage = &bytes
+	}
+	return []*metricspb.Metric{
+		utilizationMetric(prefix+"cpu_limit_utilization", cpuUsage, l.cpuLimit),
+		utilizationMetric(prefix+"cpu_request_utilization", cpuUsage, l.cpuRequest),
+		utilizationMetric(prefix+"memory_limit_utilization", memUsage, l.memoryLimit),
+		utilizationMetric(prefix+"memory_request_utilization", memUsage, l.memoryRequest),
+	}
+}
+
+func utilizationMetric(metricName string, usage *float64, limit *float64) *metricspb.Metric {
+	if usage == nil || limit == nil || *limit <= 0 {
+		return nil
+	}
+	value := *usage / *limit
+	return doubleGauge(metricName, "1", &value)
+}
@@ -0,0 +1,95 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+)
+
+func utilizationMetricsData(t *testing.T, setResourceLimits bool) []consumerdata.MetricsData {
+	rc := &fakeRestClient{}
+	summary, err := NewStatsProvider(rc).StatsSummary()
+	require.NoError(t, err)
+	podsMetadata, err := NewMetadataProvider(rc).Pods()
+	require.NoError(t, err)
+	metadata := NewMetadata(nil, podsMetadata, nil)
+	if setResourceLimits {
+		metadata.SetResourceLimits()
+	}
+	return MetricsData(zap.NewNop(), summary, metadata, "", ValidMetricGroups)
+}
+
+func TestUtilizationMetrics(t *testing.T) {
+	mds := utilizationMetricsData(t, true)
+	requireMetricsDataOk(t, mds)
+
+	// Utilization values by pod, container and metric name for the pods of
+	// testdata/pods.json that set requests or limits.
+	got := map[string]map[string]float64{}
+	for _, md := range mds {
+		for _, m := range md.Metrics {
+			if !strings.HasSuffix(m.MetricDescriptor.Name, "_utilization") {
+				continue
+			}
+			assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, m.MetricDescriptor.Type)
+			assert.Equal(t, "1", m.MetricDescriptor.Unit)
+			key := md.Resource.Labels["k8s.pod.name"] + "/" + md.Resource.Labels["k8s.container.name"]
+			if got[key] == nil {
+				got[key] = map[string]float64{}
+			}
+			got[key][m.MetricDescriptor.Name] = m.Timeseries[0].Points[0].GetDoubleValue()
+		}
+	}
+
+	require.Len(t, got, 4)
+	assert.InDeltaMapValues(t, map[string]float64{
+		"k8s.container.cpu_limit_utilization":      0,
+		"k8s.container.cpu_request_utilization":    0,
+		"k8s.container.memory_limit_utilization":   25083904. / (64 << 20),
+		"k8s.container.memory_request_utilization": 25083904. / (32 << 20),
+	}, got["go-hello-world-5456b4b8cd-99vxc/server"], 1e-9)
+	assert.InDeltaMapValues(t, map[string]float64{
+		"k8s.pod.cpu_limit_utilization":      0,
+		"k8s.pod.cpu_request_utilization":    0,
+		"k8s.pod.memory_limit_utilization":   25722880. / (64 << 20),
+		"k8s.pod.memory_request_utilization": 25722880. / (32 << 20),
+	}, got["go-hello-world-5456b4b8cd-99vxc/"], 1e-9)
+	// The coredns container doesn't set a CPU limit.
+	assert.InDeltaMapValues(t, map[string]float64{
+		"k8s.container.cpu_request_utilization":    0.003508506 / 0.1,
+		"k8s.container.memory_limit_utilization":   6250496. / (170 << 20),
+		"k8s.container.memory_request_utilization": 6250496. / (70 << 20),
+	}, got["coredns-66bff467f8-szddj/coredns"], 1e-9)
+	assert.InDeltaMapValues(t, map[string]float64{
+		"k8s.pod.cpu_request_utilization":    0.003430175 / 0.1,
+		"k8s.pod.memory_limit_utilization":   6934528. / (170 << 20),
+		"k8s.pod.memory_request_utilization": 6934528. / (70 << 20),
+	}, got["coredns-66bff467f8-szddj/"], 1e-9)
+}
+
+func TestUtilizationMetricsWithoutResourceLimits(t *testing.T) {
+	for _, md := range utilizationMetricsData(t, false) {
+		for _, m := range md.Metrics {
+			require.False(t, strings.HasSuffix(m.MetricDescriptor.Name, "_utilization"), m.MetricDescriptor.Name)
+		}
+	}
+}
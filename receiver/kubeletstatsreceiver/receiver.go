@@ -42,6 +42,9 @@ type receiverOptions struct {
 	collectionInterval    time.Duration
 	extraMetadataLabels   []kubelet.MetadataLabel
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	prometheusEndpoints   []kubelet.PrometheusEndpoint
+	podStatusMetrics      bool
+	utilizationMetrics    bool
 	k8sAPIClient          kubernetes.Interface
 }
 
@@ -38,11 +38,15 @@ type runnable struct {
 	receiverName          string
 	statsProvider         *kubelet.StatsProvider
 	metadataProvider      *kubelet.MetadataProvider
+	prometheusProvider    *kubelet.PrometheusProvider
 	consumer              consumer.MetricsConsumer
 	logger                *zap.Logger
 	restClient            kubelet.RestClient
 	extraMetadataLabels   []kubelet.MetadataLabel
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	prometheusEndpoints   []kubelet.PrometheusEndpoint
+	podStatusMetrics      bool
+	utilizationMetrics    bool
 	k8sAPIClient          kubernetes.Interface
 	cachedVolumeLabels    map[string]map[string]string
 }
@@ -62,6 +66,9 @@ func newRunnable(
 		logger:                logger,
 		extraMetadataLabels:   rOptions.extraMetadataLabels,
 		metricGroupsToCollect: rOptions.metricGroupsToCollect,
+		prometheusEndpoints:   rOptions.prometheusEndpoints,
+		podStatusMetrics:      rOptions.podStatusMetrics,
+		utilizationMetrics:    rOptions.utilizationMetrics,
 		k8sAPIClient:          rOptions.k8sAPIClient,
 		cachedVolumeLabels:    make(map[string]map[string]string),
 	}
@@ -71,6 +78,7 @@ func newRunnable(
 func (r *runnable) Setup() error {
 	r.statsProvider = kubelet.NewStatsProvider(r.restClient)
 	r.metadataProvider = kubelet.NewMetadataProvider(r.restClient)
+	r.prometheusProvider = kubelet.NewPrometheusProvider(r.restClient)
 	return nil
 }
 
@@ -83,8 +91,8 @@ func (r *runnable) Run() error {
 	}
 
 	var podsMetadata *v1.PodList
-	// fetch metadata only when extra metadata labels are needed
-	if len(r.extraMetadataLabels) > 0 {
+	// fetch metadata only when extra metadata labels, pod status or utilization metrics are needed
+	if len(r.extraMetadataLabels) > 0 || r.podStatusMetrics || r.utilizationMetrics {
 		podsMetadata, err = r.metadataProvider.Pods()
 		if err != nil {
 			r.logger.Error("call to /pods endpoint failed", zap.Error(err))
@@ -93,7 +101,22 @@ func (r *runnable) Run() error {
 	}
 
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata, r.detailedPVCLabelsSetter())
+	if r.utilizationMetrics {
+		metadata.SetResourceLimits()
+	}
 	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
+	if r.podStatusMetrics {
+		mds = append(mds, kubelet.PodStatusMetricsData(r.logger, podsMetadata, metadata, typeStr, r.metricGroupsToCollect)...)
+	}
+	for _, endpoint := range r.prometheusEndpoints {
+		families, err := r.prometheusProvider.Metrics(endpoint)
+		if err != nil {
+			r.logger.Error("call to /metrics/"+string(endpoint)+" endpoint failed", zap.Error(err))
+			continue
+		}
+		mds = append(mds, kubelet.PrometheusMetricsData(
+			r.logger, endpoint, families, summary, metadata, typeStr, r.metricGroupsToCollect)...)
+	}
 	metrics := internaldata.OCSliceToMetrics(mds)
 
 	var numTimeSeries, numPoints int
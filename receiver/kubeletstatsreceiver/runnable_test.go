@@ -212,6 +212,107 @@ func TestRunnableWithMetricGroups(t *testing.T) {
 	}
 }
 
+func TestRunnableWithPrometheusEndpoints(t *testing.T) {
+	tests := []struct {
+		name                string
+		prometheusEndpoints []kubelet.PrometheusEndpoint
+		podStatusMetrics    bool
+		dataLen             int
+	}{
+		{
+			name:                "cadvisor",
+			prometheusEndpoints: []kubelet.PrometheusEndpoint{kubelet.CadvisorEndpoint},
+			// Mapped series of the two containers in testdata/metrics-cadvisor.txt,
+			// the network series belong to the pods.
+			dataLen: numContainers*containerMetrics + 7,
+		},
+		{
+			name:                "cadvisor and resource",
+			prometheusEndpoints: []kubelet.PrometheusEndpoint{kubelet.CadvisorEndpoint, kubelet.ResourceEndpoint},
+			dataLen:             numContainers*containerMetrics + 7 + 4,
+		},
+		{
+			name:             "pod status",
+			podStatusMetrics: true,
+			// One restart count for each container in testdata/pods.json.
+			dataLen: numContainers*containerMetrics + numContainers,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consumer := &exportertest.SinkMetricsExporter{}
+			r := newRunnable(
+				context.Background(),
+				consumer,
+				&fakeRestClient{},
+				zap.NewNop(),
+				&receiverOptions{
+					metricGroupsToCollect: map[kubelet.MetricGroup]bool{
+						kubelet.ContainerMetricGroup: true,
+					},
+					prometheusEndpoints: test.prometheusEndpoints,
+					podStatusMetrics:    test.podStatusMetrics,
+				},
+			)
+
+			err := r.Setup()
+			require.NoError(t, err)
+
+			err = r.Run()
+			require.NoError(t, err)
+
+			require.Equal(t, test.dataLen, consumer.MetricsCount())
+		})
+	}
+}
+
+func TestRunnableWithUtilizationMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		metricGroups map[kubelet.MetricGroup]bool
+		dataLen      int
+	}{
+		{
+			name: "only container group",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.ContainerMetricGroup: true,
+			},
+			// Utilization of the two containers setting requests or limits in testdata/pods.json.
+			dataLen: numContainers*containerMetrics + 7,
+		},
+		{
+			name: "only pod group",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.PodMetricGroup: true,
+			},
+			dataLen: numPods*podMetrics + 7,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consumer := &exportertest.SinkMetricsExporter{}
+			r := newRunnable(
+				context.Background(),
+				consumer,
+				&fakeRestClient{},
+				zap.NewNop(),
+				&receiverOptions{
+					metricGroupsToCollect: test.metricGroups,
+					utilizationMetrics:    true,
+				},
+			)
+
+			err := r.Setup()
+			require.NoError(t, err)
+
+			err = r.Run()
+			require.NoError(t, err)
+
+			require.Equal(t, test.dataLen, consumer.MetricsCount())
+		})
+	}
+}
+
 type expectedVolume struct {
 	name   string
 	typ    string
@@ -406,7 +507,10 @@ func TestClientErrors(t *testing.T) {
 		name                  string
 		statsSummaryFail      bool
 		podsFail              bool
+		prometheusFail        bool
 		extraMetadataLabels   []kubelet.MetadataLabel
+		podStatusMetrics      bool
+		prometheusEndpoints   []kubelet.PrometheusEndpoint
 		metricGroupsToCollect map[kubelet.MetricGroup]bool
 		numLogs               int
 	}{
@@ -442,12 +546,28 @@ func TestClientErrors(t *testing.T) {
 			metricGroupsToCollect: allMetricGroups,
 			numLogs:               1,
 		},
+		{
+			name:                  "pods_endpoint_error_with_pod_status_metrics",
+			podsFail:              true,
+			podStatusMetrics:      true,
+			metricGroupsToCollect: allMetricGroups,
+			numLogs:               1,
+		},
+		{
+			name:                  "prometheus_endpoints_error",
+			prometheusFail:        true,
+			prometheusEndpoints:   []kubelet.PrometheusEndpoint{kubelet.CadvisorEndpoint, kubelet.ResourceEndpoint},
+			metricGroupsToCollect: allMetricGroups,
+			numLogs:               2,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			core, observedLogs := observer.New(zap.ErrorLevel)
 			options := &receiverOptions{
 				extraMetadataLabels:   test.extraMetadataLabels,
+				podStatusMetrics:      test.podStatusMetrics,
+				prometheusEndpoints:   test.prometheusEndpoints,
 				metricGroupsToCollect: test.metricGroupsToCollect,
 			}
 			r := newRunnable(
@@ -456,6 +576,7 @@ func TestClientErrors(t *testing.T) {
 				&fakeRestClient{
 					statsSummaryFail: test.statsSummaryFail,
 					podsFail:         test.podsFail,
+					prometheusFail:   test.prometheusFail,
 				},
 				zap.New(core),
 				options,
@@ -510,6 +631,7 @@ var _ kubelet.RestClient = (*fakeRestClient)(nil)
 type fakeRestClient struct {
 	statsSummaryFail bool
 	podsFail         bool
+	prometheusFail   bool
 }
 
 func (f *fakeRestClient) StatsSummary() ([]byte, error) {
@@ -525,3 +647,17 @@ func (f *fakeRestClient) Pods() ([]byte, error) {
 	}
 	return ioutil.ReadFile("testdata/pods.json")
 }
+
+func (f *fakeRestClient) CadvisorMetrics() ([]byte, error) {
+	if f.prometheusFail {
+		return nil, errors.New("")
+	}
+	return ioutil.ReadFile("testdata/metrics-cadvisor.txt")
+}
+
+func (f *fakeRestClient) ResourceMetrics() ([]byte, error) {
+	if f.prometheusFail {
+		return nil, errors.New("")
+	}
+	return ioutil.ReadFile("testdata/metrics-resource.txt")
+}
@@ -15,6 +15,8 @@
 package stanzareceiver
 
 import (
+	"time"
+
 	"github.com/observiq/stanza/pipeline"
 	"go.opentelemetry.io/collector/config/configmodels"
 )
@@ -25,4 +27,14 @@ type Config struct {
 	OffsetsFile                   string          `mapstructure:"offsets_file"`
 	PluginDir                     string          `mapstructure:"plugin_dir"`
 	Pipeline                      pipeline.Config `mapstructure:"pipeline"`
+	Converter                     ConverterConfig `mapstructure:"converter"`
+}
+
+// ConverterConfig controls the conversion of the stanza entries into logs
+type ConverterConfig struct {
+	// FlushInterval is the interval at which the converted entries are sent
+	// to the next consumer, batched by resource.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// WorkerCount is the number of goroutines converting the entries.
+	WorkerCount int `mapstructure:"worker_count"`
 }
@@ -0,0 +1,290 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stanzareceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// converter converts stanza entries into pdata logs. The entries are converted
+// by a pool of workers, then batched by resource until the next flush.
+type converter struct {
+	flushInterval time.Duration
+	workerCount   int
+
+	// entryChan receives the entries to convert. It is never closed, the
+	// senders give up once stopChan is closed.
+	entryChan chan *entry.Entry
+	// stopChan is closed when the converter stops.
+	stopChan chan struct{}
+	// aggregationChan receives the converted entries from the workers.
+	aggregationChan chan *convertedEntry
+	// flushChan delivers the batches of logs, it is closed after the last one.
+	flushChan chan pdata.Logs
+
+	workerWg sync.WaitGroup
+	stopOnce sync.Once
+}
+
+type convertedEntry struct {
+	resourceKey string
+	resource    map[string]string
+	record      pdata.LogRecord
+}
+
+func newConverter(cfg ConverterConfig) *converter {
+	return &converter{
+		flushInterval:   cfg.FlushInterval,
+		workerCount:     cfg.WorkerCount,
+		entryChan:       make(chan *entry.Entry),
+		stopChan:        make(chan struct{}),
+		aggregationChan: make(chan *convertedEntry, cfg.WorkerCount),
+		flushChan:       make(chan pdata.Logs),
+	}
+}
+
+// start starts the workers and the aggregation of the converted entries.
+func (c *converter) start() {
+	c.workerWg.Add(c.workerCount)
+	for i := 0; i < c.workerCount; i++ {
+		go c.workerLoop()
+	}
+	go c.aggregationLoop()
+}
+
+// stop converts the entries already received and flushes them. The entries
+// sent to entryChan afterwards are not received.
+func (c *converter) stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+		c.workerWg.Wait()
+		close(c.aggregationChan)
+	})
+}
+
+func (c *converter) workerLoop() {
+	defer c.workerWg.Done()
+
+	for {
+		select {
+		case e := <-c.entryChan:
+			c.aggregationChan <- &convertedEntry{
+				resourceKey: resourceKey(e.Resource),
+				resource:    e.Resource,
+				record:      convert(e),
+			}
+		case <-c.stopChan:
+			return
+		}
+	}
+}
+
+func (c *converter) aggregationLoop() {
+	defer close(c.flushChan)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	batch := newLogsBatch()
+	for {
+		select {
+		case ce, ok := <-c.aggregationChan:
+			if !ok {
+				if batch.len() > 0 {
+					c.flushChan <- batch.logs
+				}
+				return
+			}
+			batch.add(ce)
+		case <-ticker.C:
+			if batch.len() > 0 {
+				c.flushChan <- batch.logs
+				batch = newLogsBatch()
+			}
+		}
+	}
+}
+
+// logsBatch groups log records by resource.
+type logsBatch struct {
+	logs      pdata.Logs
+	resources map[string]pdata.LogSlice
+}
+
+func newLogsBatch() *logsBatch {
+	return &logsBatch{
+		logs:      pdata.NewLogs(),
+		resources: map[string]pdata.LogSlice{},
+	}
+}
+
+func (b *logsBatch) len() int {
+	return b.logs.ResourceLogs().Len()
+}
+
+func (b *logsBatch) add(ce *convertedEntry) {
+	logs, ok := b.resources[ce.resourceKey]
+	if !ok {
+		rls := b.logs.ResourceLogs()
+		rls.Resize(rls.Len() + 1)
+		rl := rls.At(rls.Len() - 1)
+
+		resource := rl.Resource()
+		resource.InitEmpty()
+		attributes := resource.Attributes()
+		for k, v := range ce.resource {
+			attributes.InsertString(k, v)
+		}
+
+		ills := rl.InstrumentationLibraryLogs()
+		ills.Resize(1)
+		logs = ills.At(0).Logs()
+		b.resources[ce.resourceKey] = logs
+	}
+	logs.Append(ce.record)
+}
+
+// resourceKey identifies a resource by its sorted attributes.
+func resourceKey(resource map[string]string) string {
+	keys := make([]string, 0, len(resource))
+	for k := range resource {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(resource[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// convert converts a stanza entry into a log record. The record of the entry
+// becomes the body, its labels the attributes.
+func convert(e *entry.Entry) pdata.LogRecord {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+
+	// A zero time is before 1970, it is left unset rather than wrapped around.
+	if !e.Timestamp.IsZero() {
+		lr.SetTimestamp(pdata.TimestampUnixNano(uint64(e.Timestamp.UnixNano())))
+	}
+	lr.SetSeverityNumber(convertSeverity(e.Severity))
+	if e.Severity != entry.Default {
+		lr.SetSeverityText(e.Severity.String())
+	}
+
+	if e.Record != nil {
+		setAttributeValue(lr.Body(), e.Record)
+	}
+
+	attributes := lr.Attributes()
+	for k, v := range e.Labels {
+		attributes.InsertString(k, v)
+	}
+
+	return lr
+}
+
+// convertSeverity maps the stanza severities, which can take any value
+// between the predefined ones, to the closest lower severity number.
+func convertSeverity(s entry.Severity) pdata.SeverityNumber {
+	switch {
+	case s >= entry.Catastrophe:
+		return pdata.SeverityNumberFATAL4
+	case s >= entry.Emergency:
+		return pdata.SeverityNumberFATAL
+	case s >= entry.Alert:
+		return pdata.SeverityNumberERROR3
+	case s >= entry.Critical:
+		return pdata.SeverityNumberERROR2
+	case s >= entry.Error:
+		return pdata.SeverityNumberERROR
+	case s >= entry.Warning:
+		return pdata.SeverityNumberWARN
+	case s >= entry.Notice:
+		return pdata.SeverityNumberINFO2
+	case s >= entry.Info:
+		return pdata.SeverityNumberINFO
+	case s >= entry.Debug:
+		return pdata.SeverityNumberDEBUG
+	case s >= entry.Trace:
+		return pdata.SeverityNumberTRACE
+	default:
+		return pdata.SeverityNumberUNDEFINED
+	}
+}
+
+func setAttributeValue(dest pdata.AttributeValue, v interface{}) {
+	switch t := v.(type) {
+	case nil:
+	case string:
+		dest.SetStringVal(t)
+	case []byte:
+		dest.SetStringVal(string(t))
+	case bool:
+		dest.SetBoolVal(t)
+	case int:
+		dest.SetIntVal(int64(t))
+	case int32:
+		dest.SetIntVal(int64(t))
+	case int64:
+		dest.SetIntVal(t)
+	case uint:
+		dest.SetIntVal(int64(t))
+	case uint32:
+		dest.SetIntVal(int64(t))
+	case uint64:
+		dest.SetIntVal(int64(t))
+	case float32:
+		dest.SetDoubleVal(float64(t))
+	case float64:
+		dest.SetDoubleVal(t)
+	case map[string]interface{}:
+		m := pdata.NewAttributeMap()
+		for k, v := range t {
+			av := pdata.NewAttributeValueNull()
+			setAttributeValue(av, v)
+			m.Insert(k, av)
+		}
+		dest.SetMapVal(m)
+	case map[string]string:
+		m := pdata.NewAttributeMap()
+		for k, v := range t {
+			m.InsertString(k, v)
+		}
+		dest.SetMapVal(m)
+	case []interface{}:
+		arr := pdata.NewAnyValueArray()
+		for _, v := range t {
+			av := pdata.NewAttributeValueNull()
+			setAttributeValue(av, v)
+			arr.Append(av)
+		}
+		dest.SetArrayVal(arr)
+	default:
+		dest.SetStringVal(fmt.Sprintf("%v", t))
+	}
+}
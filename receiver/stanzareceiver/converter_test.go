@@ -0,0 +1,198 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stanzareceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestConvert(t *testing.T) {
+	timestamp := time.Unix(1601571318, 500)
+	e := &entry.Entry{
+		Timestamp: timestamp,
+		Severity:  entry.Error,
+		Labels:    map[string]string{"file_name": "app.log"},
+		Resource:  map[string]string{"host.name": "test-host"},
+		Record: map[string]interface{}{
+			"message": "connection refused",
+			"bytes":   []byte("raw"),
+			"retries": 3,
+			"latency": 0.5,
+			"fatal":   false,
+			"tags":    []interface{}{"db", int64(1)},
+			"request": map[string]string{"method": "GET"},
+		},
+	}
+
+	lr := convert(e)
+
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(timestamp.UnixNano())), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "error", lr.SeverityText())
+
+	require.Equal(t, 1, lr.Attributes().Len())
+	fileName, ok := lr.Attributes().Get("file_name")
+	require.True(t, ok)
+	assert.Equal(t, "app.log", fileName.StringVal())
+
+	require.Equal(t, pdata.AttributeValueMAP, lr.Body().Type())
+	body := lr.Body().MapVal()
+	assert.Equal(t, 7, body.Len())
+	assertBodyValue(t, body, "message", func(v pdata.AttributeValue) {
+		assert.Equal(t, "connection refused", v.StringVal())
+	})
+	assertBodyValue(t, body, "bytes", func(v pdata.AttributeValue) {
+		assert.Equal(t, "raw", v.StringVal())
+	})
+	assertBodyValue(t, body, "retries", func(v pdata.AttributeValue) {
+		assert.Equal(t, int64(3), v.IntVal())
+	})
+	assertBodyValue(t, body, "latency", func(v pdata.AttributeValue) {
+		assert.Equal(t, 0.5, v.DoubleVal())
+	})
+	assertBodyValue(t, body, "fatal", func(v pdata.AttributeValue) {
+		assert.Equal(t, pdata.AttributeValueBOOL, v.Type())
+		assert.False(t, v.BoolVal())
+	})
+	assertBodyValue(t, body, "tags", func(v pdata.AttributeValue) {
+		tags := v.ArrayVal()
+		require.Equal(t, 2, tags.Len())
+		assert.Equal(t, "db", tags.At(0).StringVal())
+		assert.Equal(t, int64(1), tags.At(1).IntVal())
+	})
+	assertBodyValue(t, body, "request", func(v pdata.AttributeValue) {
+		method, ok := v.MapVal().Get("method")
+		require.True(t, ok)
+		assert.Equal(t, "GET", method.StringVal())
+	})
+}
+
+func TestConvertStringRecord(t *testing.T) {
+	e := entry.New()
+	e.Record = "connection refused"
+
+	lr := convert(e)
+
+	assert.Equal(t, "connection refused", lr.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+	assert.Equal(t, "", lr.SeverityText())
+	assert.Equal(t, 0, lr.Attributes().Len())
+}
+
+func TestConvertZeroTimestamp(t *testing.T) {
+	e := entry.New()
+	e.Timestamp = time.Time{}
+
+	lr := convert(e)
+
+	assert.Equal(t, pdata.TimestampUnixNano(0), lr.Timestamp())
+}
+
+func TestConvertSeverity(t *testing.T) {
+	tests := []struct {
+		severity entry.Severity
+		expected pdata.SeverityNumber
+	}{
+		{entry.Nil, pdata.SeverityNumberUNDEFINED},
+		{entry.Default, pdata.SeverityNumberUNDEFINED},
+		{entry.Trace, pdata.SeverityNumberTRACE},
+		{entry.Debug, pdata.SeverityNumberDEBUG},
+		{entry.Info, pdata.SeverityNumberINFO},
+		{entry.Info + 5, pdata.SeverityNumberINFO},
+		{entry.Notice, pdata.SeverityNumberINFO2},
+		{entry.Warning, pdata.SeverityNumberWARN},
+		{entry.Error, pdata.SeverityNumberERROR},
+		{entry.Critical, pdata.SeverityNumberERROR2},
+		{entry.Alert, pdata.SeverityNumberERROR3},
+		{entry.Emergency, pdata.SeverityNumberFATAL},
+		{entry.Catastrophe, pdata.SeverityNumberFATAL4},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, convertSeverity(tt.severity), "severity %v", tt.severity)
+	}
+}
+
+func TestConverterBatchesByResource(t *testing.T) {
+	c := newConverter(ConverterConfig{FlushInterval: time.Hour, WorkerCount: 2})
+	c.start()
+
+	for i := 0; i < 10; i++ {
+		e := entry.New()
+		e.Record = "message"
+		e.AddResourceKey("host.name", "test-host")
+		e.AddResourceKey("service.name", []string{"a", "b"}[i%2])
+		c.entryChan <- e
+	}
+
+	// Stopping flushes the pending entries.
+	go c.stop()
+
+	var batches []pdata.Logs
+	for ld := range c.flushChan {
+		batches = append(batches, ld)
+	}
+
+	require.Equal(t, 1, len(batches))
+	ld := batches[0]
+	assert.Equal(t, 10, ld.LogRecordCount())
+	rls := ld.ResourceLogs()
+	require.Equal(t, 2, rls.Len())
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		assert.Equal(t, 2, rl.Resource().Attributes().Len())
+		assert.Equal(t, 5, rl.InstrumentationLibraryLogs().At(0).Logs().Len())
+	}
+}
+
+func TestConverterFlushesPeriodically(t *testing.T) {
+	c := newConverter(ConverterConfig{FlushInterval: 10 * time.Millisecond, WorkerCount: 1})
+	c.start()
+	defer c.stop()
+
+	e := entry.New()
+	e.Record = "message"
+	c.entryChan <- e
+
+	select {
+	case ld := <-c.flushChan:
+		assert.Equal(t, 1, ld.LogRecordCount())
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry not flushed")
+	}
+}
+
+func TestResourceKey(t *testing.T) {
+	assert.Equal(t, "", resourceKey(nil))
+	assert.Equal(t,
+		resourceKey(map[string]string{"a": "1", "b": "2"}),
+		resourceKey(map[string]string{"b": "2", "a": "1"}),
+	)
+	assert.NotEqual(t,
+		resourceKey(map[string]string{"a": "1b"}),
+		resourceKey(map[string]string{"a1": "b"}),
+	)
+}
+
+func assertBodyValue(t *testing.T, body pdata.AttributeMap, key string, check func(pdata.AttributeValue)) {
+	v, ok := body.Get(key)
+	require.True(t, ok, "missing %s", key)
+	check(v)
+}
@@ -0,0 +1,66 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stanzareceiver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/observiq/stanza/operator"
+	"github.com/observiq/stanza/operator/helper"
+	"go.uber.org/zap"
+)
+
+const logEmitterType = "log_emitter"
+
+var errConverterStopped = errors.New("log converter stopped")
+
+// logEmitter is the output operator of the stanza pipeline, the last operator
+// of the configured pipeline outputs to it unless another output is set.
+type logEmitter struct {
+	helper.OutputOperator
+	entryChan chan<- *entry.Entry
+	// stopChan is closed when the converter stops receiving entries.
+	stopChan <-chan struct{}
+}
+
+func newLogEmitter(logger *zap.SugaredLogger, entryChan chan<- *entry.Entry, stopChan <-chan struct{}) (*logEmitter, error) {
+	outputOperator, err := helper.NewOutputConfig(logEmitterType, logEmitterType).
+		Build(operator.BuildContext{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+
+	return &logEmitter{
+		OutputOperator: outputOperator,
+		entryChan:      entryChan,
+		stopChan:       stopChan,
+	}, nil
+}
+
+// Process passes the entry on to the converter. It gives up on the entry when
+// the context is done, as the inputs are stopped, or when the converter is
+// stopped, so that a blocked pipeline can't hang the shutdown.
+func (e *logEmitter) Process(ctx context.Context, ent *entry.Entry) error {
+	select {
+	case e.entryChan <- ent:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-e.stopChan:
+		return errConverterStopped
+	}
+}
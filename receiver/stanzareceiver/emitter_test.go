@@ -0,0 +1,46 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stanzareceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/observiq/stanza/entry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLogEmitterGivesUpWhenContextDone(t *testing.T) {
+	// Nothing receives the entries.
+	emitter, err := newLogEmitter(zap.NewNop().Sugar(), make(chan *entry.Entry), make(chan struct{}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, emitter.Process(ctx, entry.New()))
+}
+
+func TestLogEmitterGivesUpWhenConverterStopped(t *testing.T) {
+	c := newConverter(ConverterConfig{FlushInterval: time.Hour, WorkerCount: 1})
+	emitter, err := newLogEmitter(zap.NewNop().Sugar(), c.entryChan, c.stopChan)
+	require.NoError(t, err)
+
+	c.start()
+	c.stop()
+	assert.Equal(t, errConverterStopped, emitter.Process(context.Background(), entry.New()))
+}
@@ -16,6 +16,9 @@ package stanzareceiver
 
 import (
 	"context"
+	"fmt"
+	"runtime"
+	"time"
 
 	stanza "github.com/observiq/stanza/agent"
 	"go.opentelemetry.io/collector/component"
@@ -26,6 +29,8 @@ import (
 
 const (
 	typeStr = "stanza"
+
+	defaultFlushInterval = 100 * time.Millisecond
 )
 
 // NewFactory creates a factory for Stanza receiver.
@@ -42,7 +47,18 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
+		Converter: ConverterConfig{
+			FlushInterval: defaultFlushInterval,
+			WorkerCount:   defaultWorkerCount(),
+		},
+	}
+}
+
+func defaultWorkerCount() int {
+	if n := runtime.NumCPU() / 4; n > 1 {
+		return n
 	}
+	return 1
 }
 
 // CreateLogsReceiver creates a logs receiver based on provided config
@@ -54,18 +70,33 @@ func createLogsReceiver(
 ) (component.LogsReceiver, error) {
 
 	obsConfig := cfg.(*Config)
+	if obsConfig.Converter.FlushInterval <= 0 {
+		return nil, fmt.Errorf("converter flush_interval must be positive, got %v", obsConfig.Converter.FlushInterval)
+	}
+	if obsConfig.Converter.WorkerCount < 1 {
+		return nil, fmt.Errorf("converter worker_count must be at least 1, got %d", obsConfig.Converter.WorkerCount)
+	}
+
+	converter := newConverter(obsConfig.Converter)
+	emitter, err := newLogEmitter(params.Logger.Sugar(), converter.entryChan, converter.stopChan)
+	if err != nil {
+		return nil, err
+	}
 
 	logAgent, err := stanza.NewBuilder(&stanza.Config{Pipeline: obsConfig.Pipeline}, params.Logger.Sugar()).
 		WithPluginDir(obsConfig.PluginDir).
 		WithDatabaseFile(obsConfig.OffsetsFile).
+		WithDefaultOutput(emitter).
 		Build()
 	if err != nil {
 		return nil, err
 	}
 
 	return &stanzareceiver{
-		agent:    logAgent,
-		consumer: nextConsumer,
-		logger:   params.Logger,
+		agent:     logAgent,
+		converter: converter,
+		consumer:  nextConsumer,
+		name:      obsConfig.Name(),
+		logger:    params.Logger,
 	}, nil
 }
@@ -46,6 +46,18 @@ func TestCreateReceiver(t *testing.T) {
 	receiver, err = createLogsReceiver(context.Background(), params, badCfg, &mockLogsConsumer{})
 	require.Error(t, err, "receiver creation should fail if offsets file is invalid")
 	require.Nil(t, receiver, "receiver creation should have failed due to invalid offsets file")
+
+	badCfg = createDefaultConfig().(*Config)
+	badCfg.Converter.FlushInterval = 0
+	receiver, err = createLogsReceiver(context.Background(), params, badCfg, &mockLogsConsumer{})
+	require.Error(t, err, "receiver creation should fail if flush interval is invalid")
+	require.Nil(t, receiver)
+
+	badCfg = createDefaultConfig().(*Config)
+	badCfg.Converter.WorkerCount = 0
+	receiver, err = createLogsReceiver(context.Background(), params, badCfg, &mockLogsConsumer{})
+	require.Error(t, err, "receiver creation should fail if worker count is invalid")
+	require.Nil(t, receiver)
 }
 
 type mockLogsConsumer struct {
@@ -0,0 +1,34 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stanzareceiver
+
+import (
+	// Register the builtin stanza operators available in pipelines.
+	_ "github.com/observiq/stanza/operator/builtin/input/file"
+	_ "github.com/observiq/stanza/operator/builtin/input/generate"
+	_ "github.com/observiq/stanza/operator/builtin/input/tcp"
+	_ "github.com/observiq/stanza/operator/builtin/input/udp"
+	_ "github.com/observiq/stanza/operator/builtin/parser/json"
+	_ "github.com/observiq/stanza/operator/builtin/parser/regex"
+	_ "github.com/observiq/stanza/operator/builtin/parser/severity"
+	_ "github.com/observiq/stanza/operator/builtin/parser/time"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/filter"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/hostmetadata"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/metadata"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/noop"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/ratelimit"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/restructure"
+	_ "github.com/observiq/stanza/operator/builtin/transformer/router"
+)
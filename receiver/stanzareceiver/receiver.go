@@ -16,17 +16,23 @@ package stanzareceiver
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	stanza "github.com/observiq/stanza/agent"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 )
 
 type stanzareceiver struct {
-	agent    *stanza.LogAgent
-	consumer consumer.LogsConsumer
-	logger   *zap.Logger
+	agent     *stanza.LogAgent
+	converter *converter
+	consumer  consumer.LogsConsumer
+	name      string
+	logger    *zap.Logger
+	wg        sync.WaitGroup
 }
 
 // Ensure this factory adheres to required interface
@@ -34,12 +40,44 @@ var _ component.LogsReceiver = (*stanzareceiver)(nil)
 
 // Start tells the receiver to start
 func (r *stanzareceiver) Start(ctx context.Context, host component.Host) error {
-	// TODO
+	r.converter.start()
+
+	r.wg.Add(1)
+	go r.consumerLoop(obsreport.ReceiverContext(ctx, typeStr, "", r.name))
+
+	if err := r.agent.Start(); err != nil {
+		r.converter.stop()
+		r.wg.Wait()
+		return fmt.Errorf("start stanza: %s", err)
+	}
 	return nil
 }
 
+// consumerLoop sends the batches of logs to the next consumer until the
+// converter is stopped.
+func (r *stanzareceiver) consumerLoop(ctx context.Context) {
+	defer r.wg.Done()
+
+	for ld := range r.converter.flushChan {
+		// obsreport has no operations for logs yet, the log records are
+		// accounted for as metrics like the events of the signalfx receiver.
+		obsCtx := obsreport.StartMetricsReceiveOp(ctx, r.name, "")
+		numRecords := ld.LogRecordCount()
+		err := r.consumer.ConsumeLogs(obsCtx, ld)
+		if err != nil {
+			r.logger.Error("ConsumeLogs() error", zap.Error(err))
+		}
+		obsreport.EndMetricsReceiveOp(obsCtx, typeStr, numRecords, numRecords, err)
+	}
+}
+
 // Shutdown is invoked during service shutdown
 func (r *stanzareceiver) Shutdown(context.Context) error {
-	// TODO
-	return nil
+	// Stopping the agent stops the inputs and closes the offsets file. The
+	// entries the converter received are then flushed. The inputs save their
+	// offsets as they read, so the entries not received yet are lost.
+	err := r.agent.Stop()
+	r.converter.stop()
+	r.wg.Wait()
+	return err
 }
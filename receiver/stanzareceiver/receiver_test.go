@@ -16,11 +16,20 @@ package stanzareceiver
 
 import (
 	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/observiq/stanza/pipeline"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -36,3 +45,100 @@ func TestStart(t *testing.T) {
 
 	require.NoError(t, receiver.Shutdown(context.Background()), "receiver shutdown failed")
 }
+
+func TestStartFailureStopsConverter(t *testing.T) {
+	// The address of the TCP input is already in use.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Pipeline = pipeline.Config{
+		pipeline.Params{
+			"type":           "tcp_input",
+			"listen_address": l.Addr().String(),
+		},
+	}
+	params := component.ReceiverCreateParams{
+		Logger: zaptest.NewLogger(t),
+	}
+	receiver, err := createLogsReceiver(context.Background(), params, cfg, &mockLogsConsumer{})
+	require.NoError(t, err)
+
+	require.Error(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	_, open := <-receiver.(*stanzareceiver).converter.flushChan
+	assert.False(t, open, "converter not stopped")
+}
+
+func TestReceiveLogsFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stanzareceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logFile := filepath.Join(dir, "app.log")
+	require.NoError(t, ioutil.WriteFile(logFile, []byte("first line\nsecond line\n"), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.OffsetsFile = filepath.Join(dir, "offsets.db")
+	cfg.Converter.FlushInterval = 10 * time.Millisecond
+	cfg.Pipeline = pipeline.Config{
+		pipeline.Params{
+			"type":          "file_input",
+			"include":       []interface{}{logFile},
+			"start_at":      "beginning",
+			"poll_interval": "10ms",
+		},
+	}
+
+	sink := &exportertest.SinkLogsExporter{}
+	runReceiver(t, cfg, sink, 2)
+	assert.Equal(t, []string{"first line", "second line"}, logBodies(sink.AllLogs()))
+
+	// The offsets were saved as the lines were read, a restarted receiver only
+	// reads the lines written since.
+	f, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString("third line\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	sink = &exportertest.SinkLogsExporter{}
+	runReceiver(t, cfg, sink, 1)
+	assert.Equal(t, []string{"third line"}, logBodies(sink.AllLogs()))
+}
+
+// runReceiver runs a receiver until it received the expected number of log
+// records, plus a few polls to catch duplicates.
+func runReceiver(t *testing.T, cfg *Config, sink *exportertest.SinkLogsExporter, expected int) {
+	params := component.ReceiverCreateParams{
+		Logger: zaptest.NewLogger(t),
+	}
+	receiver, err := createLogsReceiver(context.Background(), params, cfg, sink)
+	require.NoError(t, err, "receiver creation failed")
+
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() >= expected
+	}, 10*time.Second, 10*time.Millisecond, "logs not received")
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	require.Equal(t, expected, sink.LogRecordsCount())
+}
+
+func logBodies(lds []pdata.Logs) []string {
+	var bodies []string
+	for _, ld := range lds {
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			ills := rls.At(i).InstrumentationLibraryLogs()
+			for j := 0; j < ills.Len(); j++ {
+				logs := ills.At(j).Logs()
+				for k := 0; k < logs.Len(); k++ {
+					bodies = append(bodies, logs.At(k).Body().StringVal())
+				}
+			}
+		}
+	}
+	return bodies
+}